@@ -25,6 +25,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/pacer"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 const (
@@ -38,6 +39,7 @@ const (
 	timeFormatOut      = "2006-01-02T15:04:05.000000000Z07:00"
 	maxTotalParts      = 50000   // in multipart upload
 	maxUncommittedSize = 9 << 30 // can't upload bigger than this
+	defaultLinkExpire  = 7 * 24 * time.Hour
 )
 
 // Globals
@@ -98,6 +100,7 @@ type Object struct {
 	size     int64             // Size of the object
 	mimeType string            // Content-Type of the object
 	meta     map[string]string // blob metadata
+	headers  fs.Metadata       // standard HTTP headers other than Content-Type
 }
 
 // ------------------------------------------------------------
@@ -131,6 +134,9 @@ func (f *Fs) Features() *fs.Features {
 // Pattern to match a azure path
 var matcher = regexp.MustCompile(`^([^/]*)(.*)$`)
 
+// Pattern to match a valid metadata key - these must be C# identifiers
+var metadataKeyRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // parseParse parses a azure 'url'
 func parsePath(path string) (container, directory string, err error) {
 	parts := matcher.FindStringSubmatch(path)
@@ -170,6 +176,7 @@ func (f *Fs) shouldRetry(err error) (bool, error) {
 
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, root string) (fs.Fs, error) {
+	ctx := context.Background()
 	if uploadCutoff > maxUploadCutoff {
 		return nil, errors.Errorf("azure: upload cutoff (%v) must be less than or equal to %v", uploadCutoff, maxUploadCutoff)
 	}
@@ -199,7 +206,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to make azure storage client")
 	}
-	client.HTTPClient = fs.Config.Client()
+	client.HTTPClient = fs.Config.ClientRemote(name)
 	bc := client.GetBlobService()
 
 	f := &Fs{
@@ -217,7 +224,10 @@ func NewFs(name, root string) (fs.Fs, error) {
 	f.features = (&fs.Features{
 		ReadMimeType:  true,
 		WriteMimeType: true,
+		ReadMetadata:  true,
+		WriteMetadata: true,
 		BucketBased:   true,
+		RangeRead:     true,
 	}).Fill(f)
 	if f.root != "" {
 		f.root += "/"
@@ -230,7 +240,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 		} else {
 			f.root += "/"
 		}
-		_, err := f.NewObject(remote)
+		_, err := f.NewObject(ctx, remote)
 		if err != nil {
 			if err == fs.ErrorObjectNotFound {
 				// File doesn't exist so return old f
@@ -248,7 +258,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 // Return an Object from a path
 //
 // If it can't be found it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *storage.Blob) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *storage.Blob) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -259,7 +269,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *storage.Blob) (fs.Object, er
 			return nil, err
 		}
 	} else {
-		err := o.readMetaData() // reads info and headers, returning an error
+		err := o.readMetaData(ctx) // reads info and headers, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -269,8 +279,8 @@ func (f *Fs) newObjectWithInfo(remote string, info *storage.Blob) (fs.Object, er
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // getBlobReference creates an empty blob reference with no metadata
@@ -301,7 +311,7 @@ type listFn func(remote string, object *storage.Blob, isDirectory bool) error
 // the container and root supplied
 //
 // dir is the starting directory, "" for root
-func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
+func (f *Fs) list(ctx context.Context, dir string, recurse bool, maxResults uint, fn listFn) error {
 	f.containerOKMu.Lock()
 	deleted := f.containerDeleted
 	f.containerOKMu.Unlock()
@@ -329,7 +339,7 @@ func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
 	}
 	for {
 		var response storage.BlobListResponse
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.Call(ctx, func() (bool, error) {
 			var err error
 			response, err = f.cc.ListBlobs(params)
 			return f.shouldRetry(err)
@@ -386,12 +396,12 @@ func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
 }
 
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(remote string, object *storage.Blob, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *storage.Blob, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		d := fs.NewDir(remote, time.Time{})
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object)
 	if err != nil {
 		return nil, err
 	}
@@ -399,9 +409,9 @@ func (f *Fs) itemToDirEntry(remote string, object *storage.Blob, isDirectory boo
 }
 
 // listDir lists a single directory
-func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
-	err = f.list(dir, false, listChunkSize, func(remote string, object *storage.Blob, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+func (f *Fs) listDir(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	err = f.list(ctx, dir, false, listChunkSize, func(remote string, object *storage.Blob, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -417,11 +427,11 @@ func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
 }
 
 // listContainers returns all the containers to out
-func (f *Fs) listContainers(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listContainers(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if dir != "" {
 		return nil, fs.ErrorListBucketRequired
 	}
-	err = f.listContainersToFn(func(container *storage.Container) error {
+	err = f.listContainersToFn(ctx, func(container *storage.Container) error {
 		t, err := time.Parse(time.RFC1123, container.Properties.LastModified)
 		if err != nil {
 			fs.Debugf(f, "Failed to parse LastModified %q: %v", container.Properties.LastModified, err)
@@ -446,11 +456,11 @@ func (f *Fs) listContainers(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.container == "" {
-		return f.listContainers(dir)
+		return f.listContainers(ctx, dir)
 	}
-	return f.listDir(dir)
+	return f.listDir(ctx, dir)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -469,13 +479,13 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.container == "" {
 		return fs.ErrorListBucketRequired
 	}
 	list := fs.NewListRHelper(callback)
-	err = f.list(dir, true, listChunkSize, func(remote string, object *storage.Blob, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, true, listChunkSize, func(remote string, object *storage.Blob, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -491,11 +501,11 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 type listContainerFn func(*storage.Container) error
 
 // listContainersToFn lists the containers to the function supplied
-func (f *Fs) listContainersToFn(fn listContainerFn) error {
+func (f *Fs) listContainersToFn(ctx context.Context, fn listContainerFn) error {
 	// FIXME page the containers if necessary?
 	params := storage.ListContainersParameters{}
 	var response *storage.ContainerListResponse
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.Call(ctx, func() (bool, error) {
 		var err error
 		response, err = f.bc.ListContainers(params)
 		return f.shouldRetry(err)
@@ -517,17 +527,17 @@ func (f *Fs) listContainersToFn(fn listContainerFn) error {
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	fs := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return fs, fs.Update(in, src, options...)
+	return fs, fs.Update(ctx, in, src, options...)
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	if f.containerOK {
@@ -536,7 +546,7 @@ func (f *Fs) Mkdir(dir string) error {
 	options := storage.CreateContainerOptions{
 		Access: storage.ContainerAccessTypePrivate,
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.Call(ctx, func() (bool, error) {
 		err := f.cc.Create(&options)
 		if err != nil {
 			if storageErr, ok := err.(storage.AzureStorageServiceError); ok {
@@ -563,9 +573,9 @@ func (f *Fs) Mkdir(dir string) error {
 }
 
 // isEmpty checks to see if a given directory is empty and returns an error if not
-func (f *Fs) isEmpty(dir string) (err error) {
+func (f *Fs) isEmpty(ctx context.Context, dir string) (err error) {
 	empty := true
-	err = f.list("", true, 1, func(remote string, object *storage.Blob, isDirectory bool) error {
+	err = f.list(ctx, "", true, 1, func(remote string, object *storage.Blob, isDirectory bool) error {
 		empty = false
 		return nil
 	})
@@ -580,11 +590,11 @@ func (f *Fs) isEmpty(dir string) (err error) {
 
 // deleteContainer deletes the container.  It can delete a full
 // container so use isEmpty if you don't want that.
-func (f *Fs) deleteContainer() error {
+func (f *Fs) deleteContainer(ctx context.Context) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	options := storage.DeleteContainerOptions{}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.Call(ctx, func() (bool, error) {
 		exists, err := f.cc.Exists()
 		if err != nil {
 			return f.shouldRetry(err)
@@ -605,15 +615,15 @@ func (f *Fs) deleteContainer() error {
 // Rmdir deletes the container if the fs is at the root
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
-	err := f.isEmpty(dir)
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	err := f.isEmpty(ctx, dir)
 	if err != nil {
 		return err
 	}
 	if f.root != "" || dir != "" {
 		return nil
 	}
-	return f.deleteContainer()
+	return f.deleteContainer(ctx)
 }
 
 // Precision of the remote
@@ -621,19 +631,45 @@ func (f *Fs) Precision() time.Duration {
 	return time.Nanosecond
 }
 
+// PublicLink generates a read only SAS URL for the blob at remote
+// which lasts for expire, or 7 days if expire is 0
+func (f *Fs) PublicLink(ctx context.Context, remote string, expire time.Duration) (string, error) {
+	if expire <= 0 {
+		expire = defaultLinkExpire
+	}
+	// Check the blob exists
+	_, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+	link, err := f.getBlobReference(remote).GetSASURI(storage.BlobSASOptions{
+		BlobServiceSASPermissions: storage.BlobServiceSASPermissions{
+			Read: true,
+		},
+		SASOptions: storage.SASOptions{
+			Expiry:   time.Now().Add(expire),
+			UseHTTPS: true,
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to make SAS URL")
+	}
+	return link, nil
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() fs.HashSet {
 	return fs.HashSet(fs.HashMD5)
 }
 
 // Purge deletes all the files and directories including the old versions.
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	dir := "" // forward compat!
 	if f.root != "" || dir != "" {
 		// Delegate to caller if not root container
 		return fs.ErrorCantPurge
 	}
-	return f.deleteContainer()
+	return f.deleteContainer(ctx)
 }
 
 // Copy src to this remote using server side copy operations.
@@ -645,8 +681,8 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -659,14 +695,14 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	srcBlob := srcObj.getBlobReference()
 	options := storage.CopyOptions{}
 	sourceBlobURL := srcBlob.GetURL()
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		err = dstBlob.Copy(sourceBlobURL, &options)
 		return f.shouldRetry(err)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return f.NewObject(remote)
+	return f.NewObject(ctx, remote)
 }
 
 // ------------------------------------------------------------
@@ -718,11 +754,23 @@ func (o *Object) Size() int64 {
 //  o.size
 //  o.md5
 //  o.meta
+//  o.headers
 func (o *Object) decodeMetaData(info *storage.Blob) (err error) {
 	o.md5 = info.Properties.ContentMD5
 	o.mimeType = info.Properties.ContentType
 	o.size = info.Properties.ContentLength
 	o.modTime = time.Time(info.Properties.LastModified)
+	o.headers = nil
+	for k, v := range map[string]string{
+		"cache-control":       info.Properties.CacheControl,
+		"content-disposition": info.Properties.ContentDisposition,
+		"content-encoding":    info.Properties.ContentEncoding,
+		"content-language":    info.Properties.ContentLanguage,
+	} {
+		if v != "" {
+			o.headers.Set(k, v)
+		}
+	}
 	if len(info.Metadata) > 0 {
 		o.meta = info.Metadata
 		if modTime, ok := info.Metadata[modTimeKey]; ok {
@@ -755,7 +803,7 @@ func (o *Object) clearMetaData() {
 //  o.modTime
 //  o.size
 //  o.md5
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if !o.modTime.IsZero() {
 		return nil
 	}
@@ -763,7 +811,7 @@ func (o *Object) readMetaData() (err error) {
 
 	// Read metadata (this includes metadata)
 	getPropertiesOptions := storage.GetBlobPropertiesOptions{}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		err = blob.GetProperties(&getPropertiesOptions)
 		return o.fs.shouldRetry(err)
 	})
@@ -805,15 +853,15 @@ func (o *Object) parseTimeString(timeString string) (err error) {
 // LastModified returned in the http headers
 func (o *Object) ModTime() (result time.Time) {
 	// The error is logged in readMetaData
-	_ = o.readMetaData()
+	_ = o.readMetaData(context.TODO())
 	return o.modTime
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	blob := o.getBlobWithModTime(modTime)
 	options := storage.SetBlobMetadataOptions{}
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.Call(ctx, func() (bool, error) {
 		err := blob.SetMetadata(&options)
 		return o.fs.shouldRetry(err)
 	})
@@ -840,7 +888,7 @@ type openFile struct {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	getBlobOptions := storage.GetBlobOptions{}
 	getBlobRangeOptions := storage.GetBlobRangeOptions{
 		GetBlobOptions: &getBlobOptions,
@@ -864,7 +912,7 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 		}
 	}
 	blob := o.getBlobReference()
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		if getBlobRangeOptions.Range == nil {
 			in, err = blob.Get(&getBlobOptions)
 		} else {
@@ -916,7 +964,7 @@ func urlEncode(in string) string {
 // uploadMultipart uploads a file using multipart upload
 //
 // Write a larger blob, using CreateBlockBlob, PutBlock, and PutBlockList.
-func (o *Object) uploadMultipart(in io.Reader, size int64, blob *storage.Blob, putBlobOptions *storage.PutBlobOptions) (err error) {
+func (o *Object) uploadMultipart(ctx context.Context, in io.Reader, size int64, blob *storage.Blob, putBlobOptions *storage.PutBlobOptions) (err error) {
 	// Calculate correct chunkSize
 	chunkSize := int64(chunkSize)
 	var totalParts int64
@@ -939,7 +987,7 @@ func (o *Object) uploadMultipart(in io.Reader, size int64, blob *storage.Blob, p
 	fs.Debugf(o, "Multipart upload session started for %d parts of size %v", totalParts, fs.SizeSuffix(chunkSize))
 
 	// Create an empty blob
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		err := blob.CreateBlockBlob(putBlobOptions)
 		return o.fs.shouldRetry(err)
 	})
@@ -1006,7 +1054,7 @@ outer:
 			putBlockOptions := storage.PutBlockOptions{
 				ContentMD5: base64.StdEncoding.EncodeToString(md5sum[:]),
 			}
-			err = o.fs.pacer.Call(func() (bool, error) {
+			err = o.fs.pacer.Call(ctx, func() (bool, error) {
 				err = blob.PutBlockWithLength(blockID, uint64(len(buf)), bytes.NewBuffer(buf), &putBlockOptions)
 				return o.fs.shouldRetry(err)
 			})
@@ -1038,7 +1086,7 @@ outer:
 
 	// Finalise the upload session
 	putBlockListOptions := storage.PutBlockListOptions{}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		err := blob.PutBlockList(blocks, &putBlockListOptions)
 		return o.fs.shouldRetry(err)
 	})
@@ -1051,14 +1099,36 @@ outer:
 // Update the object with the contents of the io.Reader, modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
-	err = o.fs.Mkdir("")
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
+	err = o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
 	size := src.Size()
 	blob := o.getBlobWithModTime(src.ModTime())
 	blob.Properties.ContentType = fs.MimeType(o)
+	for k, v := range fs.GetMetadataOptions(options) {
+		switch k {
+		case "content-type":
+			blob.Properties.ContentType = v
+		case "cache-control":
+			blob.Properties.CacheControl = v
+		case "content-disposition":
+			blob.Properties.ContentDisposition = v
+		case "content-encoding":
+			blob.Properties.ContentEncoding = v
+		case "content-language":
+			blob.Properties.ContentLanguage = v
+		case modTimeKey:
+			// the modification time is set from src
+		default:
+			if !metadataKeyRegexp.MatchString(k) {
+				fs.Debugf(o, "Ignoring metadata key %q which isn't valid for azure", k)
+				continue
+			}
+			blob.Metadata[k] = v
+		}
+	}
 	if sourceMD5, _ := src.Hash(fs.HashMD5); sourceMD5 != "" {
 		sourceMD5bytes, err := hex.DecodeString(sourceMD5)
 		if err == nil {
@@ -1070,10 +1140,10 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	putBlobOptions := storage.PutBlobOptions{}
 
 	// Don't retry, return a retry error instead
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetry(ctx, func() (bool, error) {
 		if size >= int64(uploadCutoff) {
 			// If a large file upload in chunks
-			err = o.uploadMultipart(in, size, blob, &putBlobOptions)
+			err = o.uploadMultipart(ctx, in, size, blob, &putBlobOptions)
 		} else {
 			// Write a small blob in one transaction
 			if size == 0 {
@@ -1087,14 +1157,14 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		return err
 	}
 	o.clearMetaData()
-	return o.readMetaData()
+	return o.readMetaData(ctx)
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	blob := o.getBlobReference()
 	options := storage.DeleteBlobOptions{}
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.Call(ctx, func() (bool, error) {
 		err := blob.Delete(&options)
 		return o.fs.shouldRetry(err)
 	})
@@ -1105,12 +1175,37 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the blob metadata along with the standard HTTP headers.  The
+// mtime is returned by ModTime so isn't included.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range o.meta {
+		k = strings.ToLower(k)
+		if k == modTimeKey {
+			continue
+		}
+		metadata.Set(k, v)
+	}
+	if o.mimeType != "" {
+		metadata.Set("content-type", o.mimeType)
+	}
+	metadata.Merge(o.headers)
+	return metadata, nil
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs        = &Fs{}
-	_ fs.Copier    = &Fs{}
-	_ fs.Purger    = &Fs{}
-	_ fs.ListRer   = &Fs{}
-	_ fs.Object    = &Object{}
-	_ fs.MimeTyper = &Object{}
+	_ fs.Fs           = &Fs{}
+	_ fs.Copier       = &Fs{}
+	_ fs.Purger       = &Fs{}
+	_ fs.ListRer      = &Fs{}
+	_ fs.PublicLinker = &Fs{}
+	_ fs.Object       = &Object{}
+	_ fs.MimeTyper    = &Object{}
+	_ fs.Metadataer   = &Object{}
 )
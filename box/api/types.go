@@ -86,6 +86,10 @@ type Item struct {
 	ContentCreatedAt  Time   `json:"content_created_at"`
 	ContentModifiedAt Time   `json:"content_modified_at"`
 	ItemStatus        string `json:"item_status"` // active, trashed if the file has been moved to the trash, and deleted if the file has been permanently deleted
+	SharedLink        struct {
+		URL    string `json:"url,omitempty"`
+		Access string `json:"access,omitempty"`
+	} `json:"shared_link"` // only read if asked for in the fields
 }
 
 // ModTime returns the modification time of the item
@@ -145,6 +149,18 @@ type CopyFile struct {
 	Parent Parent `json:"parent"`
 }
 
+// SharedLink is the settings for a shared link
+type SharedLink struct {
+	Access     string `json:"access,omitempty"`      // open, company or collaborators
+	UnsharedAt *Time  `json:"unshared_at,omitempty"` // when the link expires
+}
+
+// CreateSharedLink is the request for Update File or Update Folder to
+// make a shared link
+type CreateSharedLink struct {
+	SharedLink SharedLink `json:"shared_link"`
+}
+
 // UploadSessionRequest is uses in Create Upload Session
 type UploadSessionRequest struct {
 	FolderID string `json:"folder_id,omitempty"` // don't pass for update
@@ -190,3 +206,19 @@ type CommitUpload struct {
 		ContentModifiedAt Time `json:"content_modified_at"`
 	} `json:"attributes"`
 }
+
+// User is a Box user
+type User struct {
+	Type          string `json:"type"`
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Login         string `json:"login"`
+	CreatedAt     Time   `json:"created_at"`
+	ModifiedAt    Time   `json:"modified_at"`
+	Language      string `json:"language"`
+	Timezone      string `json:"timezone"`
+	SpaceAmount   int64  `json:"space_amount"`
+	SpaceUsed     int64  `json:"space_used"`
+	MaxUploadSize int64  `json:"max_upload_size"`
+	Status        string `json:"status"`
+}
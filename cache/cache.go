@@ -369,6 +369,7 @@ func NewFs(name, rpath string) (fs.Fs, error) {
 	f.features = (&fs.Features{
 		CanHaveEmptyDirectories: true,
 		DuplicateFiles:          false, // storage doesn't permit this
+		RangeRead:               true,
 		Purge:                   f.Purge,
 		Copy:                    f.Copy,
 		Move:                    f.Move,
@@ -438,13 +439,13 @@ func (f *Fs) disableWarmUp() {
 }
 
 // NewObject finds the Object at remote.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	co := NewObject(f, remote)
 	err := f.cache.GetObject(co)
 	if err == nil {
 		return co, nil
 	}
-	obj, err := f.Fs.NewObject(remote)
+	obj, err := f.Fs.NewObject(ctx, remote)
 	if err != nil {
 		return nil, err
 	}
@@ -454,7 +455,7 @@ func (f *Fs) NewObject(remote string) (fs.Object, error) {
 }
 
 // List the objects and directories in dir into entries
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	// clean cache
 	go f.CleanUpCache(false)
 
@@ -468,7 +469,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 		return entries, nil
 	}
 
-	entries, err = f.Fs.List(dir)
+	entries, err = f.Fs.List(ctx, dir)
 	if err != nil {
 		return nil, err
 	}
@@ -495,8 +496,8 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 	return cachedEntries, nil
 }
 
-func (f *Fs) recurse(dir string, list *fs.ListRHelper) error {
-	entries, err := f.List(dir)
+func (f *Fs) recurse(ctx context.Context, dir string, list *fs.ListRHelper) error {
+	entries, err := f.List(ctx, dir)
 	if err != nil {
 		return err
 	}
@@ -504,7 +505,7 @@ func (f *Fs) recurse(dir string, list *fs.ListRHelper) error {
 	for i := 0; i < len(entries); i++ {
 		innerDir, ok := entries[i].(fs.Directory)
 		if ok {
-			err := f.recurse(innerDir.Remote(), list)
+			err := f.recurse(ctx, innerDir.Remote(), list)
 			if err != nil {
 				return err
 			}
@@ -521,14 +522,14 @@ func (f *Fs) recurse(dir string, list *fs.ListRHelper) error {
 
 // ListR lists the objects and directories of the Fs starting
 // from dir recursively into out.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	fs.Debugf(f, "list recursively from '%s'", dir)
 
 	// we check if the source FS supports ListR
 	// if it does, we'll use that to get all the entries, cache them and return
 	do := f.Fs.Features().ListR
 	if do != nil {
-		return do(dir, func(entries fs.DirEntries) error {
+		return do(ctx, dir, func(entries fs.DirEntries) error {
 			// we got called back with a set of entries so let's cache them and call the original callback
 			for _, entry := range entries {
 				switch o := entry.(type) {
@@ -548,7 +549,7 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 
 	// if we're here, we're gonna do a standard recursive traversal and cache everything
 	list := fs.NewListRHelper(callback)
-	err = f.recurse(dir, list)
+	err = f.recurse(ctx, dir, list)
 	if err != nil {
 		return err
 	}
@@ -557,8 +558,8 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 }
 
 // Mkdir makes the directory (container, bucket)
-func (f *Fs) Mkdir(dir string) error {
-	err := f.Fs.Mkdir(dir)
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	err := f.Fs.Mkdir(ctx, dir)
 	if err != nil {
 		return err
 	}
@@ -577,8 +578,8 @@ func (f *Fs) Mkdir(dir string) error {
 }
 
 // Rmdir removes the directory (container, bucket) if empty
-func (f *Fs) Rmdir(dir string) error {
-	err := f.Fs.Rmdir(dir)
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	err := f.Fs.Rmdir(ctx, dir)
 	if err != nil {
 		return err
 	}
@@ -592,7 +593,7 @@ func (f *Fs) Rmdir(dir string) error {
 
 // DirMove moves src, srcRemote to this remote at dstRemote
 // using server side move operations.
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	do := f.Fs.Features().DirMove
 	if do == nil {
 		return fs.ErrorCantDirMove
@@ -608,7 +609,7 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	}
 	fs.Infof(f, "move dir '%s'/'%s' -> '%s'", srcRemote, srcFs.Root(), dstRemote)
 
-	err := do(src.Features().UnWrap(), srcRemote, dstRemote)
+	err := do(ctx, src.Features().UnWrap(), srcRemote, dstRemote)
 	if err != nil {
 		return err
 	}
@@ -690,17 +691,17 @@ func (f *Fs) cacheReader(u io.Reader, src fs.ObjectInfo, originalRead func(inn i
 }
 
 // Put in to the remote path with the modTime given of the given size
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	fs.Debugf(f, "put data at '%s'", src.Remote())
 
 	var err error
 	var obj fs.Object
 	if f.cacheWrites {
 		f.cacheReader(in, src, func(inn io.Reader) {
-			obj, err = f.Fs.Put(inn, src, options...)
+			obj, err = f.Fs.Put(ctx, inn, src, options...)
 		})
 	} else {
-		obj, err = f.Fs.Put(in, src, options...)
+		obj, err = f.Fs.Put(ctx, in, src, options...)
 	}
 
 	if err != nil {
@@ -715,7 +716,7 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 }
 
 // PutUnchecked uploads the object
-func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) PutUnchecked(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	do := f.Fs.Features().PutUnchecked
 	if do == nil {
 		return nil, errors.New("can't PutUnchecked")
@@ -726,10 +727,10 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 	var obj fs.Object
 	if f.cacheWrites {
 		f.cacheReader(in, src, func(inn io.Reader) {
-			obj, err = f.Fs.Put(inn, src, options...)
+			obj, err = f.Fs.Put(ctx, inn, src, options...)
 		})
 	} else {
-		obj, err = f.Fs.Put(in, src, options...)
+		obj, err = f.Fs.Put(ctx, in, src, options...)
 	}
 
 	if err != nil {
@@ -744,7 +745,7 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 }
 
 // Copy src to this remote using server side copy operations.
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	do := f.Fs.Features().Copy
 	if do == nil {
 		fs.Errorf(src, "source remote (%v) doesn't support Copy", src.Fs())
@@ -764,11 +765,11 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	fs.Infof(f, "copy obj '%s' -> '%s'", srcObj.abs(), remote)
 
 	// store in cache
-	if err := srcObj.refreshFromSource(); err != nil {
+	if err := srcObj.refreshFromSource(ctx); err != nil {
 		fs.Errorf(f, "can't move %v - %v", src, err)
 		return nil, fs.ErrorCantCopy
 	}
-	obj, err := do(srcObj.Object, remote)
+	obj, err := do(ctx, srcObj.Object, remote)
 	if err != nil {
 		fs.Errorf(srcObj, "error moving in cache: %v", err)
 		return nil, err
@@ -784,7 +785,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 }
 
 // Move src to this remote using server side move operations.
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	do := f.Fs.Features().Move
 	if do == nil {
 		fs.Errorf(src, "source remote (%v) doesn't support Move", src.Fs())
@@ -805,11 +806,11 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 	fs.Infof(f, "moving obj '%s' -> %s", srcObj.abs(), remote)
 
 	// save in cache
-	if err := srcObj.refreshFromSource(); err != nil {
+	if err := srcObj.refreshFromSource(ctx); err != nil {
 		fs.Errorf(f, "can't move %v - %v", src, err)
 		return nil, fs.ErrorCantMove
 	}
-	obj, err := do(srcObj.Object, remote)
+	obj, err := do(ctx, srcObj.Object, remote)
 	if err != nil {
 		fs.Errorf(srcObj, "error moving in cache: %v", err)
 		return nil, err
@@ -835,7 +836,7 @@ func (f *Fs) Hashes() fs.HashSet {
 }
 
 // Purge all files in the root and the root directory
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	fs.Infof(f, "purging cache")
 	f.cache.Purge()
 
@@ -848,7 +849,7 @@ func (f *Fs) Purge() error {
 		return nil
 	}
 
-	err := do()
+	err := do(ctx)
 	if err != nil {
 		return err
 	}
@@ -857,7 +858,7 @@ func (f *Fs) Purge() error {
 }
 
 // CleanUp the trash in the Fs
-func (f *Fs) CleanUp() error {
+func (f *Fs) CleanUp(ctx context.Context) error {
 	f.CleanUpCache(false)
 
 	do := f.Fs.Features().CleanUp
@@ -865,7 +866,25 @@ func (f *Fs) CleanUp() error {
 		return nil
 	}
 
-	return do()
+	return do(ctx)
+}
+
+// About gets quota information from the Fs
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	do := f.Fs.Features().About
+	if do == nil {
+		return nil, errors.New("About not supported")
+	}
+	return do(ctx)
+}
+
+// PublicLink generates a public link to the remote path
+func (f *Fs) PublicLink(ctx context.Context, remote string, expire time.Duration) (string, error) {
+	do := f.Fs.Features().PublicLink
+	if do == nil {
+		return "", errors.New("PublicLink not supported")
+	}
+	return do(ctx, remote, expire)
 }
 
 // Stats returns stats about the cache storage
@@ -965,4 +984,6 @@ var (
 	_ fs.CleanUpper     = (*Fs)(nil)
 	_ fs.UnWrapper      = (*Fs)(nil)
 	_ fs.ListRer        = (*Fs)(nil)
+	_ fs.Abouter        = (*Fs)(nil)
+	_ fs.PublicLinker   = (*Fs)(nil)
 )
@@ -21,6 +21,7 @@ import (
 	"github.com/ncw/rclone/local"
 	flag "github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -75,9 +76,9 @@ func TestInternalInit(t *testing.T) {
 
 	// Instantiate root
 	rootFs, err = fs.NewFs(*RemoteName + ":")
-	_ = rootFs.Features().Purge()
+	_ = rootFs.Features().Purge(context.Background())
 	require.NoError(t, err)
-	err = rootFs.Mkdir("")
+	err = rootFs.Mkdir(context.Background(), "")
 	require.NoError(t, err)
 
 	// flush cache
@@ -88,28 +89,28 @@ func TestInternalInit(t *testing.T) {
 func TestInternalListRootAndInnerRemotes(t *testing.T) {
 	// Instantiate inner fs
 	innerFolder := "inner"
-	err := rootFs.Mkdir(innerFolder)
+	err := rootFs.Mkdir(context.Background(), innerFolder)
 	require.NoError(t, err)
 	innerFs, err := fs.NewFs(*RemoteName + ":" + innerFolder)
 	require.NoError(t, err)
 
 	obj := writeObjectString(t, innerFs, "one", "content")
 
-	listRoot, err := rootFs.List("")
+	listRoot, err := rootFs.List(context.Background(), "")
 	require.NoError(t, err)
-	listRootInner, err := rootFs.List(innerFolder)
+	listRootInner, err := rootFs.List(context.Background(), innerFolder)
 	require.NoError(t, err)
-	listInner, err := innerFs.List("")
+	listInner, err := innerFs.List(context.Background(), "")
 	require.NoError(t, err)
 
 	require.Lenf(t, listRoot, 1, "remote %v should have 1 entry", rootFs.Root())
 	require.Lenf(t, listRootInner, 1, "remote %v should have 1 entry in %v", rootFs.Root(), innerFolder)
 	require.Lenf(t, listInner, 1, "remote %v should have 1 entry", innerFs.Root())
 
-	err = obj.Remove()
+	err = obj.Remove(context.Background())
 	require.NoError(t, err)
 
-	err = innerFs.Features().Purge()
+	err = innerFs.Features().Purge(context.Background())
 	require.NoError(t, err)
 	innerFs = nil
 }
@@ -122,13 +123,13 @@ func TestInternalObjWrapFsFound(t *testing.T) {
 	data := "content"
 	writeObjectString(t, wrappedFs, "second", data)
 
-	listRoot, err := rootFs.List("")
+	listRoot, err := rootFs.List(context.Background(), "")
 	require.NoError(t, err)
 	require.Lenf(t, listRoot, 1, "remote %v should have 1 entry", rootFs.Root())
 
-	co, err := rootFs.NewObject("second")
+	co, err := rootFs.NewObject(context.Background(), "second")
 	require.NoError(t, err)
-	r, err := co.Open()
+	r, err := co.Open(context.Background())
 	require.NoError(t, err)
 	cachedData, err := ioutil.ReadAll(r)
 	require.NoError(t, err)
@@ -138,17 +139,17 @@ func TestInternalObjWrapFsFound(t *testing.T) {
 	strCached := string(cachedData)
 	require.Equal(t, data, strCached)
 
-	err = co.Remove()
+	err = co.Remove(context.Background())
 	require.NoError(t, err)
 
-	listRoot, err = wrappedFs.List("")
+	listRoot, err = wrappedFs.List(context.Background(), "")
 	require.NoError(t, err)
 	require.Lenf(t, listRoot, 0, "remote %v should have 0 entries: %v", wrappedFs.Root(), listRoot)
 }
 
 func TestInternalObjNotFound(t *testing.T) {
 	reset(t)
-	obj, err := rootFs.NewObject("404")
+	obj, err := rootFs.NewObject(context.Background(), "404")
 	require.Error(t, err)
 	require.Nil(t, obj)
 }
@@ -178,6 +179,39 @@ func TestInternalCachedWrittenContentMatches(t *testing.T) {
 	require.Equal(t, checkSample, testSample)
 }
 
+func TestInternalRangeOption(t *testing.T) {
+	reset(t)
+	cfs, err := getCacheFs(rootFs)
+	require.NoError(t, err)
+	chunkSize := cfs.ChunkSize()
+
+	// create some rand test data
+	testData := make([]byte, (chunkSize*2 + chunkSize/2))
+	testSize, err := rand.Read(testData)
+	require.Equal(t, len(testData), testSize, "data size doesn't match")
+	require.NoError(t, err)
+
+	// write the object
+	o := writeObjectBytes(t, rootFs, "data.bin", testData)
+
+	// read a range spanning a chunk boundary
+	start, end := chunkSize/2, chunkSize+chunkSize/2-1
+	reader, err := o.Open(context.Background(), &fs.RangeOption{Start: start, End: end})
+	require.NoError(t, err)
+	checkSample, err := ioutil.ReadAll(reader)
+	_ = reader.Close()
+	require.NoError(t, err)
+	require.Equal(t, testData[start:end+1], checkSample)
+
+	// read the last bytes
+	reader, err = o.Open(context.Background(), &fs.RangeOption{Start: -1, End: 100})
+	require.NoError(t, err)
+	checkSample, err = ioutil.ReadAll(reader)
+	_ = reader.Close()
+	require.NoError(t, err)
+	require.Equal(t, testData[len(testData)-100:], checkSample)
+}
+
 func TestInternalCachedUpdatedContentMatches(t *testing.T) {
 	reset(t)
 
@@ -190,7 +224,7 @@ func TestInternalCachedUpdatedContentMatches(t *testing.T) {
 	require.Equal(t, o.Size(), int64(len(testData2)))
 
 	// check data from in-file
-	reader, err := o.Open()
+	reader, err := o.Open(context.Background())
 	require.NoError(t, err)
 	checkSample, err := ioutil.ReadAll(reader)
 	_ = reader.Close()
@@ -215,7 +249,7 @@ func TestInternalWrappedWrittenContentMatches(t *testing.T) {
 	o := writeObjectBytes(t, cfs.UnWrap(), "data.bin", testData)
 	require.Equal(t, o.Size(), int64(testSize))
 
-	o2, err := rootFs.NewObject("data.bin")
+	o2, err := rootFs.NewObject(context.Background(), "data.bin")
 	require.NoError(t, err)
 	require.Equal(t, o2.Size(), o.Size())
 
@@ -250,7 +284,7 @@ func TestInternalLargeWrittenContentMatches(t *testing.T) {
 	o := writeObjectBytes(t, cfs.UnWrap(), "data.bin", testData)
 	require.Equal(t, o.Size(), int64(testSize))
 
-	o2, err := rootFs.NewObject("data.bin")
+	o2, err := rootFs.NewObject(context.Background(), "data.bin")
 	require.NoError(t, err)
 	require.Equal(t, o2.Size(), o.Size())
 
@@ -273,13 +307,13 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 	co := writeObjectRandomBytes(t, rootFs, (chunkSize*4 + chunkSize/2))
 
 	// update in the wrapped fs
-	o, err := cfs.UnWrap().NewObject(co.Remote())
+	o, err := cfs.UnWrap().NewObject(context.Background(), co.Remote())
 	require.NoError(t, err)
-	err = o.SetModTime(co.ModTime().Truncate(time.Hour))
+	err = o.SetModTime(context.Background(), co.ModTime().Truncate(time.Hour))
 	require.NoError(t, err)
 
 	// get a new instance from the cache
-	co2, err := rootFs.NewObject(o.Remote())
+	co2, err := rootFs.NewObject(context.Background(), o.Remote())
 	require.NoError(t, err)
 
 	require.NotEqual(t, o.ModTime(), co.ModTime())
@@ -293,13 +327,13 @@ func TestInternalChangeSeenAfterDirCacheFlush(t *testing.T) {
 
 	cfs.DirCacheFlush() // flush the cache
 
-	l, err := cfs.UnWrap().List("")
+	l, err := cfs.UnWrap().List(context.Background(), "")
 	require.NoError(t, err)
 	require.Len(t, l, 1)
 	o := l[0]
 
 	// get a new instance from the cache
-	co, err := rootFs.NewObject(o.Remote())
+	co, err := rootFs.NewObject(context.Background(), o.Remote())
 	require.NoError(t, err)
 	require.Equal(t, o.ModTime(), co.ModTime())
 }
@@ -375,7 +409,7 @@ func TestInternalWarmUpInFlight(t *testing.T) {
 	require.WithinDuration(t, expectedExpiry, ts, okDiff)
 
 	checkSample := make([]byte, chunkSize)
-	reader, err := o3.Open(&fs.SeekOption{Offset: 0})
+	reader, err := o3.Open(context.Background(), &fs.SeekOption{Offset: 0})
 	require.NoError(t, err)
 	rs, ok := reader.(*cache.Handle)
 	require.True(t, ok)
@@ -485,10 +519,10 @@ func TestInternalExpiredChunkRemoved(t *testing.T) {
 	// we wait for the cache to expire
 	t.Logf("Waiting %v for cache to expire\n", chunkAge.String())
 	time.Sleep(chunkAge)
-	_, _ = cfs.List("")
+	_, _ = cfs.List(context.Background(), "")
 	time.Sleep(time.Second * 2)
 
-	o, err := cfs.NewObject(remote)
+	o, err := cfs.NewObject(context.Background(), remote)
 	require.NoError(t, err)
 	co2, ok := o.(*cache.Object)
 	require.True(t, ok)
@@ -506,11 +540,11 @@ func TestInternalExpiredEntriesRemoved(t *testing.T) {
 
 	// create some rand test data
 	_ = writeObjectString(t, cfs, "one", "one content")
-	err = cfs.Mkdir("test")
+	err = cfs.Mkdir(context.Background(), "test")
 	require.NoError(t, err)
 	_ = writeObjectString(t, cfs, "test/second", "second content")
 
-	objOne, err := cfs.NewObject("one")
+	objOne, err := cfs.NewObject(context.Background(), "one")
 	require.NoError(t, err)
 	require.Equal(t, int64(len([]byte("one content"))), objOne.Size())
 
@@ -518,7 +552,7 @@ func TestInternalExpiredEntriesRemoved(t *testing.T) {
 	t.Logf("Waiting %v seconds for cache to expire\n", waitTime)
 	time.Sleep(infoAge)
 
-	_, err = cfs.List("test")
+	_, err = cfs.List(context.Background(), "test")
 	require.NoError(t, err)
 	time.Sleep(time.Second * 2)
 	require.False(t, boltDb.HasEntry("one"))
@@ -527,7 +561,7 @@ func TestInternalExpiredEntriesRemoved(t *testing.T) {
 func TestInternalFinalise(t *testing.T) {
 	var err error
 
-	err = rootFs.Features().Purge()
+	err = rootFs.Features().Purge(context.Background())
 	require.NoError(t, err)
 }
 
@@ -555,7 +589,7 @@ func writeObjectBytes(t *testing.T, f fs.Fs, remote string, data []byte) fs.Obje
 	modTime := time.Now()
 	objInfo := fs.NewStaticObjectInfo(remote, modTime, int64(len(data)), true, nil, f)
 
-	obj, err := f.Put(in, objInfo)
+	obj, err := f.Put(context.Background(), in, objInfo)
 	require.NoError(t, err)
 
 	return obj
@@ -567,11 +601,11 @@ func updateObjectBytes(t *testing.T, f fs.Fs, remote string, data1 []byte, data2
 	objInfo1 := fs.NewStaticObjectInfo(remote, time.Now(), int64(len(data1)), true, nil, f)
 	objInfo2 := fs.NewStaticObjectInfo(remote, time.Now(), int64(len(data2)), true, nil, f)
 
-	obj, err := f.Put(in1, objInfo1)
+	obj, err := f.Put(context.Background(), in1, objInfo1)
 	require.NoError(t, err)
-	obj, err = f.NewObject(remote)
+	obj, err = f.NewObject(context.Background(), remote)
 	require.NoError(t, err)
-	err = obj.Update(in2, objInfo2)
+	err = obj.Update(context.Background(), in2, objInfo2)
 
 	return obj
 }
@@ -582,7 +616,7 @@ func readDataFromObj(t *testing.T, co fs.Object, offset, end int64, useSeek bool
 	size := end - offset
 	checkSample := make([]byte, size)
 
-	reader, err = co.Open(&fs.SeekOption{Offset: offset})
+	reader, err = co.Open(context.Background(), &fs.SeekOption{Offset: offset})
 	require.NoError(t, err)
 
 	totalRead, err := io.ReadFull(reader, checkSample)
@@ -612,13 +646,13 @@ func doStuff(t *testing.T, times int, maxDuration time.Duration, stuff func()) {
 func reset(t *testing.T) {
 	var err error
 
-	err = rootFs.Features().Purge()
+	err = rootFs.Features().Purge(context.Background())
 	require.NoError(t, err)
 
 	// Instantiate root
 	rootFs, err = fs.NewFs(*RemoteName + ":")
 	require.NoError(t, err)
-	err = rootFs.Mkdir("")
+	err = rootFs.Mkdir(context.Background(), "")
 	require.NoError(t, err)
 }
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // Handle is managing the read/write/seek operations on an open handle
@@ -33,7 +34,7 @@ type Handle struct {
 }
 
 // NewObjectHandle returns a new Handle for an existing Object
-func NewObjectHandle(o *Object) *Handle {
+func NewObjectHandle(ctx context.Context, o *Object) *Handle {
 	r := &Handle{
 		cachedObject:  o,
 		offset:        0,
@@ -53,7 +54,7 @@ func NewObjectHandle(o *Object) *Handle {
 
 	// create a larger buffer to queue up requests
 	r.preloadQueue = make(chan int64, r.TotalWorkers*10)
-	r.startReadWorkers()
+	r.startReadWorkers(ctx)
 	return r
 }
 
@@ -78,7 +79,7 @@ func (r *Handle) InWarmUp() bool {
 }
 
 // startReadWorkers will start the worker pool
-func (r *Handle) startReadWorkers() {
+func (r *Handle) startReadWorkers(ctx context.Context) {
 	if r.hasAtLeastOneWorker() {
 		return
 	}
@@ -88,7 +89,7 @@ func (r *Handle) startReadWorkers() {
 			ch: r.preloadQueue,
 			id: i,
 		}
-		go w.run()
+		go w.run(ctx)
 
 		r.workers = append(r.workers, w)
 	}
@@ -308,12 +309,12 @@ func (w *worker) String() string {
 //   - if it supports seeking it will seek to the desired offset and return the same reader
 //   - if it doesn't support seeking it will close a possible existing one and open at the desired offset
 //   - if there's no reader associated with this worker, it will create one
-func (w *worker) reader(offset, end int64) (io.ReadCloser, error) {
+func (w *worker) reader(ctx context.Context, offset, end int64) (io.ReadCloser, error) {
 	var err error
 	r := w.rc
 	if w.rc == nil {
 		r, err = w.r.cacheFs().OpenRateLimited(func() (io.ReadCloser, error) {
-			return w.r.cachedObject.Object.Open(&fs.RangeOption{Start: offset, End: end}, &fs.SeekOption{Offset: offset})
+			return w.r.cachedObject.Object.Open(ctx, &fs.RangeOption{Start: offset, End: end}, &fs.SeekOption{Offset: offset})
 		})
 		if err != nil {
 			return nil, err
@@ -329,7 +330,7 @@ func (w *worker) reader(offset, end int64) (io.ReadCloser, error) {
 
 	_ = w.rc.Close()
 	return w.r.cacheFs().OpenRateLimited(func() (io.ReadCloser, error) {
-		r, err = w.r.cachedObject.Object.Open(&fs.RangeOption{Start: offset, End: end}, &fs.SeekOption{Offset: offset})
+		r, err = w.r.cachedObject.Object.Open(ctx, &fs.RangeOption{Start: offset, End: end}, &fs.SeekOption{Offset: offset})
 		if err != nil {
 			return nil, err
 		}
@@ -350,7 +351,7 @@ func (w *worker) setRunning(f bool) {
 }
 
 // run is the main loop for the worker which receives offsets to preload
-func (w *worker) run() {
+func (w *worker) run(ctx context.Context) {
 	var err error
 	var data []byte
 	defer w.setRunning(false)
@@ -395,7 +396,7 @@ func (w *worker) run() {
 		if chunkEnd > w.r.cachedObject.Size() {
 			chunkEnd = w.r.cachedObject.Size()
 		}
-		w.rc, err = w.reader(chunkStart, chunkEnd)
+		w.rc, err = w.reader(ctx, chunkStart, chunkEnd)
 		// we seem to be getting only errors so we abort
 		if err != nil {
 			fs.Errorf(w, "object open failed %v: %v", chunkStart, err)
@@ -14,6 +14,7 @@ import (
 	"strconv"
 
 	"github.com/ncw/rclone/fs"
+	"golang.org/x/net/context"
 )
 
 // Object is a generic file like object that stores basic information about it
@@ -163,7 +164,7 @@ func (o *Object) Storable() bool {
 }
 
 // refreshFromSource requests the original FS for the object in case it comes from a cached entry
-func (o *Object) refreshFromSource() error {
+func (o *Object) refreshFromSource(ctx context.Context) error {
 	o.refreshMutex.Lock()
 	defer o.refreshMutex.Unlock()
 
@@ -171,7 +172,7 @@ func (o *Object) refreshFromSource() error {
 		return nil
 	}
 
-	liveObject, err := o.CacheFs.Fs.NewObject(o.Remote())
+	liveObject, err := o.CacheFs.Fs.NewObject(ctx, o.Remote())
 	if err != nil {
 		fs.Errorf(o, "error refreshing object: %v", err)
 		return err
@@ -183,12 +184,12 @@ func (o *Object) refreshFromSource() error {
 }
 
 // SetModTime sets the ModTime of this object
-func (o *Object) SetModTime(t time.Time) error {
-	if err := o.refreshFromSource(); err != nil {
+func (o *Object) SetModTime(ctx context.Context, t time.Time) error {
+	if err := o.refreshFromSource(ctx); err != nil {
 		return err
 	}
 
-	err := o.Object.SetModTime(t)
+	err := o.Object.SetModTime(ctx, t)
 	if err != nil {
 		return err
 	}
@@ -200,30 +201,57 @@ func (o *Object) SetModTime(t time.Time) error {
 	return nil
 }
 
-// Open is used to request a specific part of the file using fs.RangeOption
-func (o *Object) Open(options ...fs.OpenOption) (io.ReadCloser, error) {
-	if err := o.refreshFromSource(); err != nil {
+// Open is used to request a specific part of the file using fs.SeekOption
+// or fs.RangeOption
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	if err := o.refreshFromSource(ctx); err != nil {
 		return nil, err
 	}
 	o.CacheFs.CheckIfWarmupNeeded(o.Remote())
 
-	cacheReader := NewObjectHandle(o)
+	var offset, limit int64 = 0, -1
+	cacheReader := NewObjectHandle(ctx, o)
 	for _, option := range options {
 		switch x := option.(type) {
 		case *fs.SeekOption:
-			_, err := cacheReader.Seek(x.Offset, os.SEEK_SET)
-			if err != nil {
-				return cacheReader, err
+			offset = x.Offset
+		case *fs.RangeOption:
+			if x.Start >= 0 {
+				offset = x.Start
+				if x.End >= 0 {
+					limit = x.End - x.Start + 1
+				}
+			} else if x.End >= 0 {
+				// read the last End bytes
+				offset = o.Size() - x.End
+				if offset < 0 {
+					offset = 0
+				}
 			}
 		}
 	}
+	if offset != 0 {
+		_, err := cacheReader.Seek(offset, os.SEEK_SET)
+		if err != nil {
+			return cacheReader, err
+		}
+	}
+	if limit >= 0 {
+		return &limitedHandle{Reader: io.LimitReader(cacheReader, limit), Closer: cacheReader}, nil
+	}
 
 	return cacheReader, nil
 }
 
+// limitedHandle reads a limited amount from an open handle
+type limitedHandle struct {
+	io.Reader
+	io.Closer
+}
+
 // Update will change the object data
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	if err := o.refreshFromSource(); err != nil {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	if err := o.refreshFromSource(ctx); err != nil {
 		return err
 	}
 	fs.Infof(o, "updating object contents with size %v", src.Size())
@@ -231,7 +259,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	// deleting cached chunks and info to be replaced with new ones
 	_ = o.CacheFs.cache.RemoveObject(o.abs())
 
-	err := o.Object.Update(in, src, options...)
+	err := o.Object.Update(ctx, in, src, options...)
 	if err != nil {
 		fs.Errorf(o, "error updating source: %v", err)
 		return err
@@ -246,11 +274,11 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove deletes the object from both the cache and the source
-func (o *Object) Remove() error {
-	if err := o.refreshFromSource(); err != nil {
+func (o *Object) Remove(ctx context.Context) error {
+	if err := o.refreshFromSource(ctx); err != nil {
 		return err
 	}
-	err := o.Object.Remove()
+	err := o.Object.Remove(ctx)
 	if err != nil {
 		return err
 	}
@@ -272,7 +300,7 @@ func (o *Object) Hash(ht fs.HashType) (string, error) {
 		return cachedHash, nil
 	}
 
-	if err := o.refreshFromSource(); err != nil {
+	if err := o.refreshFromSource(context.TODO()); err != nil {
 		return "", err
 	}
 
@@ -0,0 +1,66 @@
+package bisync
+
+import (
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// Globals
+var (
+	opt = fs.DefaultBisyncOpt
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	flags := commandDefintion.Flags()
+	flags.BoolVarP(&opt.Resync, "resync", "", opt.Resync, "Ignore the saved listings and copy missing files both ways.")
+	flags.IntVarP(&opt.MaxDeletePercent, "max-delete-percent", "", opt.MaxDeletePercent, "Refuse to run if more than this percentage of files would be deleted on either side.")
+	flags.BoolVarP(&opt.Force, "force", "", opt.Force, "Bypass the --max-delete-percent safety check.")
+	flags.StringVarP(&opt.StateDir, "state-dir", "", opt.StateDir, "Directory to keep the listings in (default cache dir/bisync).")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "bisync path1:path path2:path",
+	Short: `Bidirectional sync between two paths.`,
+	Long: `
+Make path1 and path2 contain the same files, propagating new, changed
+and deleted files in both directions since the last run.
+
+The listings of both paths (name, size, modification time and, with
+` + "`" + `--checksum` + "`" + `, hash) are saved in the cache directory
+after each successful run and compared with the current listings to
+see what has changed on each side.
+
+The first time you run bisync on a pair of paths you must use the
+` + "`" + `--resync` + "`" + ` flag.  This copies any files missing on
+one side to the other, with the version on path1 winning where both
+sides have a file which differs, and saves the listings.  You can use
+` + "`" + `--resync` + "`" + ` again at any time to recover from an
+error.
+
+If a file has changed on both sides since the last run then neither
+version is overwritten.  Instead both are renamed, to
+` + "`" + `file..path1` + "`" + ` and ` + "`" + `file..path2` + "`" + `,
+and both copies are transferred to both sides for you to resolve.
+
+As a safety measure, bisync refuses to run if more than
+` + "`" + `--max-delete-percent` + "`" + ` of the files on either side
+would be deleted, which might happen if one path was accidentally
+emptied.  Use ` + "`" + `--force` + "`" + ` to override this.
+
+If there are any errors the listings aren't updated so the next run
+will try the changes again.
+
+**Important**: Since this can cause data loss, test first with the
+` + "`" + `--dry-run` + "`" + ` flag to see exactly what would be copied and deleted.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		path1, path2 := cmd.NewFsSrcDst(args)
+		cmd.Run(true, true, command, func() error {
+			return fs.Bisync(context.Background(), path1, path2, opt)
+		})
+	},
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 // Globals
@@ -74,7 +75,7 @@ Note that if offset is negative it will count from the end, so
 			w = ioutil.Discard
 		}
 		cmd.Run(false, false, command, func() error {
-			return fs.Cat(fsrc, w, offset, count)
+			return fs.Cat(context.Background(), fsrc, w, offset, count)
 		})
 	},
 }
@@ -4,16 +4,21 @@ import (
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 // Globals
 var (
-	download = false
+	download  = false
+	oneway    = false
+	reportOpt cmd.ReportOpt
 )
 
 func init() {
 	cmd.Root.AddCommand(commandDefintion)
 	commandDefintion.Flags().BoolVarP(&download, "download", "", download, "Check by downloading rather than with hash.")
+	commandDefintion.Flags().BoolVarP(&oneway, "one-way", "", oneway, "Check one way only, source files must exist on destination")
+	reportOpt.AddFlags(commandDefintion.Flags())
 }
 
 var commandDefintion = &cobra.Command{
@@ -27,19 +32,29 @@ match.  It doesn't alter the source or destination.
 If you supply the --size-only flag, it will only compare the sizes not
 the hashes as well.  Use this for a quick check.
 
-If you supply the --download flag, it will download the data from
-both remotes and check them against each other on the fly.  This can
-be useful for remotes that don't support hashes or if you really want
-to check all the data.
-`,
+If the source and destination don't share a hash, check can only
+compare the sizes and it will log how many hashes could not be
+checked.  If you supply the --download flag, it will download the
+data from both remotes and check them against each other byte for
+byte on the fly.  This can be useful for remotes that don't support
+hashes, for remotes which don't have a hash in common (eg crypt to a
+plain remote, or FTP to S3) or if you really want to check all the
+data.  Use --checkers to control how many files are compared at once.
+
+If you supply the --one-way flag, it will only check that files in
+the source match the files in the destination, not the other way
+around.  Files only in the destination aren't reported.  This is
+useful for checking append only archives where the destination has
+files which have since been removed from the source.
+` + cmd.ReportHelp,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
-		cmd.Run(false, false, command, func() error {
+		reportOpt.Run(false, false, command, func(ctx context.Context) error {
 			if download {
-				return fs.CheckDownload(fdst, fsrc)
+				return fs.CheckDownload(ctx, fdst, fsrc, oneway)
 			}
-			return fs.Check(fdst, fsrc)
+			return fs.Check(ctx, fdst, fsrc, oneway)
 		})
 	},
 }
@@ -21,6 +21,8 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/rc"
+	"github.com/ncw/rclone/rc/rcflags"
 )
 
 // Globals
@@ -49,6 +51,7 @@ const (
 	exitCodeRetryError
 	exitCodeNoRetryError
 	exitCodeFatalError
+	exitCodeTransferExceeded
 )
 
 // Root is the main rclone command
@@ -115,6 +118,7 @@ func runRoot(cmd *cobra.Command, args []string) {
 func init() {
 	Root.Run = runRoot
 	Root.Flags().BoolVarP(&version, "version", "V", false, "Print the version number")
+	rcflags.AddFlags(pflag.CommandLine)
 	cobra.OnInitialize(initConfig)
 }
 
@@ -219,6 +223,14 @@ func NewFsSrc(args []string) fs.Fs {
 	return fsrc
 }
 
+// NewFsFile creates a new fs from the arguments which may point to a
+// file.  It returns the file name if it does.
+func NewFsFile(args []string) (fs.Fs, string) {
+	f, fileName := newFsFile(args[0])
+	fs.CalculateModifyWindow(f)
+	return f, fileName
+}
+
 // NewFsDst creates a new dst fs from the arguments
 //
 // Dst fs-es can't point to single files
@@ -390,6 +402,12 @@ func initConfig() {
 		})
 	}
 
+	// Start the remote control server if configured
+	err := rc.Start(&rcflags.Opt)
+	if err != nil {
+		log.Fatalf("Failed to start remote control: %v", err)
+	}
+
 	if m, _ := regexp.MatchString("^(bits|bytes)$", *dataRateUnit); m == false {
 		fs.Errorf(nil, "Invalid unit passed to --stats-unit. Defaulting to bytes.")
 		fs.Config.DataRateUnit = "bytes"
@@ -416,6 +434,8 @@ func resolveExitCode(err error) {
 		os.Exit(exitCodeRetryError)
 	case fs.IsNoRetryError(err):
 		os.Exit(exitCodeNoRetryError)
+	case err == fs.ErrorMaxTransferLimitReached:
+		os.Exit(exitCodeTransferExceeded)
 	case fs.IsFatalError(err):
 		os.Exit(exitCodeFatalError)
 	default:
@@ -274,6 +274,14 @@ func (fsys *FS) Statfs(path string, stat *fuse.Statfs_t) (errc int) {
 	stat.Bsize = blockSize  // Block size
 	stat.Namemax = 255      // Maximum file name length?
 	stat.Frsize = blockSize // Fragment size, smallest addressable data size in the file system.
+	total, _, free := fsys.VFS.Statfs()
+	if total >= 0 {
+		stat.Blocks = uint64(total) / blockSize
+	}
+	if free >= 0 {
+		stat.Bfree = uint64(free) / blockSize
+		stat.Bavail = stat.Bfree
+	}
 	return 0
 }
 
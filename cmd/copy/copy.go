@@ -4,10 +4,17 @@ import (
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// Globals
+var (
+	reportOpt cmd.ReportOpt
 )
 
 func init() {
 	cmd.Root.AddCommand(commandDefintion)
+	reportOpt.AddFlags(commandDefintion.Flags())
 }
 
 var commandDefintion = &cobra.Command{
@@ -52,12 +59,12 @@ source or destination.
 
 See the ` + "`--no-traverse`" + ` option for controlling whether rclone lists
 the destination directory or not.
-`,
+` + cmd.ReportHelp,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
-		cmd.Run(true, true, command, func() error {
-			return fs.CopyDir(fdst, fsrc)
+		reportOpt.Run(true, true, command, func(ctx context.Context) error {
+			return fs.CopyDir(ctx, fdst, fsrc)
 		})
 	},
 }
@@ -0,0 +1,55 @@
+package copyurl
+
+import (
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var (
+	autoFilename = false
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	commandDefintion.Flags().BoolVarP(&autoFilename, "auto-filename", "a", autoFilename, "Get the file name from the url and use it for destination file path")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "copyurl https://example.com dest:path",
+	Short: `Copy url content to dest.`,
+	Long: `
+Download urls content and copy it to destination without saving it in
+tmp storage.
+
+    rclone copyurl https://example.com/file.zip remote:path/to/file.zip
+
+The size and modification time are read from the Content-Length and
+Last-Modified headers of the response.  If the size isn't known then
+the upload is streamed in the same way as rcat.
+
+Setting --auto-filename will cause the file name to be read from the
+Content-Disposition header if present, or else the last element of the
+url.  In this case dest:path is the directory to copy the file into,
+eg
+
+    rclone copyurl -a https://example.com/file.zip remote:path/to/dir
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+
+		var dstFileName string
+		var fdst fs.Fs
+		if autoFilename {
+			fdst = cmd.NewFsDst(args[1:])
+		} else {
+			fdst, dstFileName = cmd.NewFsDstFile(args[1:])
+		}
+
+		cmd.Run(true, true, command, func() error {
+			_, err := fs.CopyURL(context.Background(), fdst, dstFileName, args[0], autoFilename)
+			return err
+		})
+	},
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 func init() {
@@ -43,13 +44,13 @@ After it has run it will log the status of the encryptedremote:.
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
 		cmd.Run(false, true, command, func() error {
-			return cryptCheck(fdst, fsrc)
+			return cryptCheck(context.Background(), fdst, fsrc)
 		})
 	},
 }
 
 // cryptCheck checks the integrity of a crypted remote
-func cryptCheck(fdst, fsrc fs.Fs) error {
+func cryptCheck(ctx context.Context, fdst, fsrc fs.Fs) error {
 	// Check to see fcrypt is a crypt
 	fcrypt, ok := fdst.(*crypt.Fs)
 	if !ok {
@@ -67,7 +68,7 @@ func cryptCheck(fdst, fsrc fs.Fs) error {
 	//
 	// it returns true if differences were found
 	// it also returns whether it couldn't be hashed
-	checkIdentical := func(dst, src fs.Object) (differ bool, noHash bool) {
+	checkIdentical := func(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool) {
 		cryptDst := dst.(*crypt.Object)
 		underlyingDst := cryptDst.UnWrap()
 		underlyingHash, err := underlyingDst.Hash(hashType)
@@ -79,7 +80,7 @@ func cryptCheck(fdst, fsrc fs.Fs) error {
 		if underlyingHash == "" {
 			return false, true
 		}
-		cryptHash, err := fcrypt.ComputeHash(cryptDst, src, hashType)
+		cryptHash, err := fcrypt.ComputeHash(ctx, cryptDst, src, hashType)
 		if err != nil {
 			fs.Stats.Error(err)
 			fs.Errorf(dst, "Error computing hash: %v", err)
@@ -98,5 +99,5 @@ func cryptCheck(fdst, fsrc fs.Fs) error {
 		return false, false
 	}
 
-	return fs.CheckFn(fcrypt, fsrc, checkIdentical)
+	return fs.CheckFn(ctx, fcrypt, fsrc, checkIdentical, false)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -111,7 +112,7 @@ Or
 		}
 		fdst := cmd.NewFsSrc(args)
 		cmd.Run(false, false, command, func() error {
-			return fs.Deduplicate(fdst, dedupeMode)
+			return fs.Deduplicate(context.Background(), fdst, dedupeMode)
 		})
 	},
 }
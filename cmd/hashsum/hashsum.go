@@ -0,0 +1,144 @@
+package hashsum
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// Globals
+var (
+	download   = false
+	outputFile = ""
+	checkFile  = ""
+	reportOpt  cmd.ReportOpt
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	flags := commandDefintion.Flags()
+	flags.BoolVarP(&download, "download", "", download, "Download the file and hash it locally even if the remote supports the hash.")
+	flags.StringVarP(&outputFile, "output-file", "", outputFile, "Write the hashes to this file rather than stdout.")
+	flags.StringVarP(&checkFile, "checkfile", "C", checkFile, "Check the remote against the hashes in this SUM file.")
+	reportOpt.AddFlags(flags)
+}
+
+// hashNames returns the names of the known hashes for the help
+func hashNames() string {
+	var names []string
+	for _, hashType := range fs.HashTypes() {
+		names = append(names, "  * "+hashType.String())
+	}
+	return strings.Join(names, "\n")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "hashsum <hash> remote:path",
+	Short: `Produces a hashsum file for all the objects in the path.`,
+	Long: `
+Produces a hash file for all the objects in the path using the hash
+named.  The output is in the same format as the standard
+md5sum/sha1sum/sha256sum tools produce.
+
+The hash names are case insensitive and can be one of
+
+` + hashNames() + `
+
+If the remote doesn't support the hash, or the --download flag is
+given, the files will be downloaded and hashed locally.
+
+Use --output-file to write the hashes to a file rather than stdout.
+
+Use --checkfile SUMFILE (or "-" for stdin) to check the files in the
+path against an existing SUM file, eg one written by sha256sum, rather
+than producing a new one.  Files missing from the path, files not in
+the SUM file and files whose hashes differ are all reported and make
+the command return an error, eg
+
+    rclone hashsum SHA-256 --checkfile SHA256SUMS mirror:releases/v1.0
+
+The SUM file itself is skipped if it is in the path being checked.
+Files which have no hash on the remote are downloaded to check them.
+` + cmd.ReportHelp + `
+In --checkfile mode the SUM file is treated as the source and the
+path as the destination for the report flags.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		hashType, err := fs.HashTypeFromString(args[0])
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if hashType == fs.HashNone {
+			log.Fatalf("Need a hash type other than %q", args[0])
+		}
+		fsrc := cmd.NewFsSrc(args[1:])
+		reportOpt.Run(false, false, command, func(ctx context.Context) error {
+			if checkFile != "" {
+				return checkSums(ctx, fsrc, hashType, sumFileRemote(args[1]))
+			}
+			return writeSums(ctx, fsrc, hashType)
+		})
+	},
+}
+
+// sumFileRemote returns the path of the SUM file relative to the
+// local directory path so it can be skipped, or "" if it isn't in it
+func sumFileRemote(path string) string {
+	if checkFile == "-" {
+		return ""
+	}
+	fsInfo, _, fsPath, err := fs.ParseRemote(path)
+	if err != nil || fsInfo.Name != "local" {
+		return ""
+	}
+	root, err := filepath.Abs(filepath.FromSlash(fsPath))
+	if err != nil {
+		return ""
+	}
+	file, err := filepath.Abs(checkFile)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// checkSums checks fsrc against the SUM file skipping sumFile if set
+func checkSums(ctx context.Context, fsrc fs.Fs, hashType fs.HashType, sumFile string) (err error) {
+	var in io.Reader = os.Stdin
+	if checkFile != "-" {
+		file, err := os.Open(checkFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to open SUM file")
+		}
+		defer fs.CheckClose(file, &err)
+		in = file
+	}
+	return fs.HashSumCheck(ctx, fsrc, hashType, download, in, sumFile)
+}
+
+// writeSums writes the hashes of fsrc to stdout or the output file
+func writeSums(ctx context.Context, fsrc fs.Fs, hashType fs.HashType) (err error) {
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to create output file")
+		}
+		defer fs.CheckClose(file, &err)
+		out = file
+	}
+	return fs.HashSum(ctx, fsrc, hashType, download, out)
+}
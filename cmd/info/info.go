@@ -17,6 +17,7 @@ import (
 	"github.com/ncw/rclone/fstest"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -104,7 +105,7 @@ func (r *results) Print() {
 func (r *results) writeFile(path string) (fs.Object, error) {
 	contents := fstest.RandomString(50)
 	src := fs.NewStaticObjectInfo(path, time.Now(), int64(len(contents)), true, nil, r.f)
-	return r.f.Put(bytes.NewBufferString(contents), src)
+	return r.f.Put(context.Background(), bytes.NewBufferString(contents), src)
 }
 
 // check whether normalization is enforced and check whether it is
@@ -118,11 +119,11 @@ func (r *results) checkUTF8Normalization() {
 		return
 	}
 	r.canWriteUnnormalized = true
-	_, err = r.f.NewObject(unnormalized)
+	_, err = r.f.NewObject(context.Background(), unnormalized)
 	if err == nil {
 		r.canReadUnnormalized = true
 	}
-	_, err = r.f.NewObject(normalized)
+	_, err = r.f.NewObject(context.Background(), normalized)
 	if err == nil {
 		r.canReadRenormalized = true
 	}
@@ -214,7 +215,7 @@ func (r *results) checkStreaming() {
 	in := io.TeeReader(buf, hashIn)
 
 	objIn := fs.NewStaticObjectInfo("checkStreamingTest", time.Now(), -1, true, nil, r.f)
-	objR, err := putter(in, objIn)
+	objR, err := putter(context.Background(), in, objIn)
 	if err != nil {
 		fs.Infof(r.f, "Streamed file failed to upload (%v)", err)
 		r.canStream = false
@@ -245,7 +246,7 @@ func (r *results) checkStreaming() {
 }
 
 func readInfo(f fs.Fs) error {
-	err := f.Mkdir("")
+	err := f.Mkdir(context.Background(), "")
 	if err != nil {
 		return errors.Wrap(err, "couldn't mkdir")
 	}
@@ -0,0 +1,75 @@
+package link
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var (
+	expire = ""
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	commandDefintion.Flags().StringVarP(&expire, "expire", "", expire, "The amount of time that the link will be valid, eg 1d")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "link remote:path",
+	Short: `Generate public link to file/folder.`,
+	Long: `
+rclone link will create or retrieve a public link to the given file or folder.
+
+    rclone link remote:path/to/file
+    rclone link remote:path/to/folder/
+
+If successful, the output will contain the link.  Exact capabilities
+depend on the remote, but the link will always be created with the
+least constraints, eg no expiry, no password protection and readable
+without an account.
+
+Use the --expire flag to make the link stop working after that amount
+of time, eg --expire 1d.  This uses the same suffixes as --min-age, ie
+ms|s|m|h|d|w|M|y.  Not all remotes support this - some will ignore it
+and some will only support it on paid accounts.
+
+Presigned URLs from s3, google cloud storage and azure blob only work
+for files and expire after 7 days unless --expire is used.  Google
+cloud storage needs service_account_file set to sign them.
+
+The remotes which support this are s3, google cloud storage, azure
+blob, dropbox, drive, onedrive, box, pcloud and yandex.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		fsrc, remote := cmd.NewFsFile(args)
+		cmd.Run(false, false, command, func() error {
+			link, err := Link(fsrc, remote)
+			if err != nil {
+				return err
+			}
+			fmt.Println(link)
+			return nil
+		})
+	},
+}
+
+// Link returns a public link to remote in fsrc which expires after
+// the --expire flag if set.
+func Link(fsrc fs.Fs, remote string) (string, error) {
+	var expireDuration time.Duration
+	if expire != "" {
+		var err error
+		expireDuration, err = fs.ParseDuration(expire)
+		if err != nil {
+			return "", errors.Wrap(err, "bad --expire")
+		}
+	}
+	return fs.PublicLink(context.Background(), fsrc, remote, expireDuration)
+}
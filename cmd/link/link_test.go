@@ -0,0 +1,56 @@
+package link
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/ncw/rclone/local"
+	"golang.org/x/net/context"
+)
+
+// linkFs adds PublicLink to an Fs which doesn't support it
+type linkFs struct {
+	fs.Fs
+	features *fs.Features
+}
+
+func (f *linkFs) Features() *fs.Features { return f.features }
+
+func (f *linkFs) PublicLink(ctx context.Context, remote string, expire time.Duration) (string, error) {
+	return fmt.Sprintf("https://example.com/%s?expire=%v", remote, expire), nil
+}
+
+func TestLink(t *testing.T) {
+	fstest.Initialise()
+	f, err := fs.NewFs("testfiles")
+	require.NoError(t, err)
+	defer func() { expire = "" }()
+
+	// The local backend doesn't support links
+	_, err = Link(f, "file")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "doesn't support public links")
+
+	lf := &linkFs{Fs: f}
+	lf.features = (&fs.Features{}).Fill(lf)
+
+	link, err := Link(lf, "file")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/file?expire=0s", link)
+
+	expire = "1d"
+	link, err = Link(lf, "file")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/file?expire=24h0m0s", link)
+
+	expire = "potato"
+	_, err = Link(lf, "file")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad --expire")
+}
@@ -0,0 +1,305 @@
+package lsf
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var (
+	format    string
+	separator string
+	dirSlash  bool
+	recurse   bool
+	hashType  = fs.HashMD5
+	filesOnly bool
+	dirsOnly  bool
+	csvOutput bool
+	absolute  bool
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	flags := commandDefintion.Flags()
+	flags.StringVarP(&format, "format", "F", "p", "Output format - see help for details")
+	flags.StringVarP(&separator, "separator", "s", ";", "Separator for the items in the format.")
+	flags.BoolVarP(&dirSlash, "dir-slash", "d", true, "Append a slash to directory names.")
+	flags.VarP(&hashType, "hash", "", "Use this hash when h is used in the format MD5|SHA-1|DropboxHash|CRC-32C|SHA-256|XXH64|BLAKE3")
+	flags.BoolVarP(&filesOnly, "files-only", "", false, "Only list files.")
+	flags.BoolVarP(&dirsOnly, "dirs-only", "", false, "Only list directories.")
+	flags.BoolVarP(&csvOutput, "csv", "", false, "Output in CSV format.")
+	flags.BoolVarP(&absolute, "absolute", "", false, "Put a leading / in front of path names.")
+	flags.BoolVarP(&recurse, "recursive", "R", false, "Recurse into the listing.")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "lsf remote:path",
+	Short: `List directories and objects in remote:path formatted for parsing`,
+	Long: `
+List the contents of the source path (directories and objects) to
+standard output in a form which is easy to parse by scripts.  By
+default this will just be the names of the objects and directories,
+one per line.  The directories will have a / suffix.
+
+Eg
+
+    $ rclone lsf swift:bucket
+    bevajer5jef
+    canole
+    diwogej7
+    ferejej3gux/
+    fubuwic
+
+Use the --format option to control what gets listed.  By default this
+is just the path, but you can use these parameters to control the
+output:
+
+    p - path
+    s - size
+    t - modification time
+    h - hash
+    i - ID of object if known
+    m - MimeType of object if known
+    d - "true" if the item is a directory, "false" otherwise
+
+So if you wanted the path, size and modification time, you would use
+--format "pst", or maybe --format "tsp" to put the path last.
+
+Eg
+
+    $ rclone lsf  --format "tsp" swift:bucket
+    2016-06-25 18:55:41;60295;bevajer5jef
+    2016-06-25 18:55:43;90613;canole
+    2016-06-25 18:55:43;94467;diwogej7
+    2018-04-26 08:50:45;0;ferejej3gux/
+    2016-06-25 18:55:40;37600;fubuwic
+
+If you specify "h" in the format you will get the MD5 hash by default,
+use the "--hash" flag to change which hash you want.  Note that this
+can be returned as an empty string if it isn't available on the object
+(and for directories), "ERROR" if there was an error reading it from
+the object and "UNSUPPORTED" if that object does not support that hash
+type.
+
+For example to emulate the md5sum command you can use
+
+    rclone lsf -R --hash MD5 --format hp --separator "  " --files-only .
+
+Eg
+
+    $ rclone lsf -R --hash MD5 --format hp --separator "  " --files-only swift:bucket
+    7908e352297f0f530b84a756f188baa3  bevajer5jef
+    cd65ac234e6fea5925974a51cdd865cc  canole
+    03b5341b4f234b9d984d03ad076bae91  diwogej7
+    8fd37c3810dd660778137ac3a66cc06d  fubuwic
+    99713e14a4c4ff553acaf1930fad985b  gixacuh7ku
+
+(Though "rclone md5sum ." is an easier way of typing this.)
+
+By default the separator is ";" this can be changed with the
+--separator flag.  Note that separators aren't escaped in the path so
+putting it last is a good strategy.
+
+Eg
+
+    $ rclone lsf  --separator "," --format "tshp" swift:bucket
+    2016-06-25 18:55:41,60295,7908e352297f0f530b84a756f188baa3,bevajer5jef
+    2016-06-25 18:55:43,90613,cd65ac234e6fea5925974a51cdd865cc,canole
+    2016-06-25 18:55:43,94467,03b5341b4f234b9d984d03ad076bae91,diwogej7
+    2018-04-26 08:52:53,0,,ferejej3gux/
+    2016-06-25 18:55:40,37600,8fd37c3810dd660778137ac3a66cc06d,fubuwic
+
+You can output in CSV standard format.  This will escape things in "
+if they contain , or " or a newline.  The separator is "," unless you
+set it to a different single character with --separator.
+
+Eg
+
+    $ rclone lsf --csv --files-only --format ps remote:path
+    test.log,22355
+    test.sh,449
+    "this file contains a comma, in the file name.txt",6
+
+Use --absolute to put a leading / in front of each path, so the
+paths are relative to the root of remote:path.
+
+Use --files-only to list only the files and --dirs-only to list only
+the directories.
+
+Note that the --files-from flag works with lsf so you can use it to
+check the listing of a list of files, and the output of lsf -R
+--files-only can be used as the input to --files-from for another
+rclone command, eg
+
+    rclone lsf -R --files-only --include "*.jpg" remote:path > files.txt
+    rclone copy --files-from files.txt remote:path /tmp/photos
+
+lsf uses the same listing as the other ls commands so it can use
+--fast-list to list the whole remote in one go on remotes which
+support it.
+
+Any of the filtering options can be applied to this command.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		if csvOutput && !command.Flags().Changed("separator") {
+			separator = ","
+		}
+		fsrc := cmd.NewFsSrc(args)
+		cmd.Run(false, false, command, func() error {
+			return Lsf(fsrc, os.Stdout)
+		})
+	},
+}
+
+// listFormat writes the fields of each DirEntry according to format
+type listFormat struct {
+	out       io.Writer
+	csv       *csv.Writer
+	format    string
+	separator string
+	hashType  fs.HashType
+}
+
+// newListFormat makes a new listFormat writing to out checking the
+// options are valid
+func newListFormat(out io.Writer) (*listFormat, error) {
+	if filesOnly && dirsOnly {
+		return nil, errors.New("can't use --files-only and --dirs-only together")
+	}
+	for _, char := range format {
+		if !strings.ContainsRune("psthimd", char) {
+			return nil, errors.Errorf("unknown format character %q", char)
+		}
+	}
+	l := &listFormat{
+		out:       out,
+		format:    format,
+		separator: separator,
+		hashType:  hashType,
+	}
+	if csvOutput {
+		comma := []rune(separator)
+		if len(comma) != 1 {
+			return nil, errors.Errorf("--separator must be a single character with --csv, got %q", separator)
+		}
+		l.csv = csv.NewWriter(out)
+		l.csv.Comma = comma[0]
+	}
+	return l, nil
+}
+
+// hash returns the hash for the entry
+func (l *listFormat) hash(entry fs.DirEntry) string {
+	o, ok := entry.(fs.Object)
+	if !ok {
+		return ""
+	}
+	hash, err := o.Hash(l.hashType)
+	if err == fs.ErrHashUnsupported {
+		return "UNSUPPORTED"
+	} else if err != nil {
+		fs.Debugf(o, "Failed to read hash: %v", err)
+		return "ERROR"
+	}
+	return hash
+}
+
+// fields returns the fields of the entry selected by the format
+func (l *listFormat) fields(entry fs.DirEntry) []string {
+	_, isDir := entry.(fs.Directory)
+	fields := make([]string, 0, len(l.format))
+	for _, char := range l.format {
+		var field string
+		switch char {
+		case 'p':
+			field = entry.Remote()
+			if absolute {
+				field = "/" + field
+			}
+			if isDir && dirSlash {
+				field += "/"
+			}
+		case 's':
+			field = strconv.FormatInt(entry.Size(), 10)
+		case 't':
+			field = entry.ModTime().Local().Format("2006-01-02 15:04:05")
+		case 'h':
+			field = l.hash(entry)
+		case 'i':
+			switch x := entry.(type) {
+			case fs.Directory:
+				field = x.ID()
+			case fs.IDer:
+				field = x.ID()
+			}
+		case 'm':
+			if o, ok := entry.(fs.Object); ok {
+				field = fs.MimeType(o)
+			}
+		case 'd':
+			field = strconv.FormatBool(isDir)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// write writes a line for the entry
+func (l *listFormat) write(entry fs.DirEntry) error {
+	fields := l.fields(entry)
+	if l.csv != nil {
+		return l.csv.Write(fields)
+	}
+	_, err := fmt.Fprintln(l.out, strings.Join(fields, l.separator))
+	return err
+}
+
+// flush makes sure all the output has been written
+func (l *listFormat) flush() error {
+	if l.csv != nil {
+		l.csv.Flush()
+		return l.csv.Error()
+	}
+	return nil
+}
+
+// Lsf lists all the objects and dirs in fsrc to out using the
+// options set by the flags.
+func Lsf(fsrc fs.Fs, out io.Writer) error {
+	l, err := newListFormat(out)
+	if err != nil {
+		return err
+	}
+	err = fs.Walk(context.Background(), fsrc, "", false, fs.ConfigMaxDepth(recurse), func(dirPath string, entries fs.DirEntries, err error) error {
+		if err != nil {
+			fs.Stats.Error(err)
+			fs.Errorf(dirPath, "error listing: %v", err)
+			return nil
+		}
+		for _, entry := range entries {
+			_, isDir := entry.(fs.Directory)
+			if (isDir && filesOnly) || (!isDir && dirsOnly) {
+				continue
+			}
+			err := l.write(entry)
+			if err != nil {
+				return errors.Wrap(err, "failed to write to output")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "error in Lsf")
+	}
+	return l.flush()
+}
@@ -0,0 +1,114 @@
+package lsf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/ncw/rclone/local"
+)
+
+// lsf runs Lsf on the testfiles returning the output
+func lsf(t *testing.T) string {
+	f, err := fs.NewFs("testfiles")
+	require.NoError(t, err)
+	buf := new(bytes.Buffer)
+	err = Lsf(f, buf)
+	require.NoError(t, err)
+	return buf.String()
+}
+
+func TestLsf(t *testing.T) {
+	fstest.Initialise()
+
+	assert.Equal(t, `file1
+file2
+file3, with comma
+subdir/
+`, lsf(t))
+
+	format = "psd"
+	defer func() { format = "p" }()
+	assert.Equal(t, `file1;0;false
+file2;5;false
+file3, with comma;4;false
+subdir/;-1;true
+`, lsf(t))
+
+	format = "hp"
+	separator = "  "
+	filesOnly = true
+	defer func() { separator = ";"; filesOnly = false }()
+	assert.Equal(t, `d41d8cd98f00b204e9800998ecf8427e  file1
+5d41402abc4b2a76b9719d911017c592  file2
+51718398f14c2c7248fa166b1c749400  file3, with comma
+`, lsf(t))
+
+	hashType = fs.HashSHA1
+	defer func() { hashType = fs.HashMD5 }()
+	assert.Equal(t, `da39a3ee5e6b4b0d3255bfef95601890afd80709  file1
+aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d  file2
+1bd666a395a45955a30af87360ff4202a5b546c3  file3, with comma
+`, lsf(t))
+}
+
+func TestLsfRecursive(t *testing.T) {
+	fstest.Initialise()
+
+	recurse = true
+	absolute = true
+	dirSlash = false
+	defer func() { recurse = false; absolute = false; dirSlash = true }()
+	assert.Equal(t, `/file1
+/file2
+/file3, with comma
+/subdir
+/subdir/file4
+`, lsf(t))
+
+	dirsOnly = true
+	defer func() { dirsOnly = false }()
+	assert.Equal(t, "/subdir\n", lsf(t))
+
+	filesOnly = true
+	defer func() { filesOnly = false }()
+	f, err := fs.NewFs("testfiles")
+	require.NoError(t, err)
+	err = Lsf(f, new(bytes.Buffer))
+	assert.Error(t, err)
+}
+
+func TestLsfCSV(t *testing.T) {
+	fstest.Initialise()
+
+	csvOutput = true
+	separator = ","
+	format = "ps"
+	defer func() { csvOutput = false; separator = ";"; format = "p" }()
+	assert.Equal(t, `file1,0
+file2,5
+"file3, with comma",4
+subdir/,-1
+`, lsf(t))
+
+	separator = "::"
+	f, err := fs.NewFs("testfiles")
+	require.NoError(t, err)
+	err = Lsf(f, new(bytes.Buffer))
+	assert.Error(t, err)
+}
+
+func TestLsfBadFormat(t *testing.T) {
+	fstest.Initialise()
+
+	format = "px"
+	defer func() { format = "p" }()
+	f, err := fs.NewFs("testfiles")
+	require.NoError(t, err)
+	err = Lsf(f, new(bytes.Buffer))
+	assert.Error(t, err)
+}
@@ -11,11 +11,13 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 var (
 	recurse   bool
 	showHash  bool
+	hashTypes []string
 	noModTime bool
 )
 
@@ -23,6 +25,7 @@ func init() {
 	cmd.Root.AddCommand(commandDefintion)
 	commandDefintion.Flags().BoolVarP(&recurse, "recursive", "R", false, "Recurse into the listing.")
 	commandDefintion.Flags().BoolVarP(&showHash, "hash", "", false, "Include hashes in the output (may take longer).")
+	commandDefintion.Flags().StringArrayVarP(&hashTypes, "hash-type", "", nil, "Show only this hash type (may be repeated).")
 	commandDefintion.Flags().BoolVarP(&noModTime, "no-modtime", "", false, "Don't read the modification time (can speed things up).")
 }
 
@@ -70,6 +73,13 @@ The output is an array of Items, where each Item looks like this
 
 If --hash is not specified the the Hashes property won't be emitted.
 
+If --hash-type is specified then only the hashes of that type will be
+shown.  It can be repeated to show several types, eg --hash-type MD5
+--hash-type BLAKE3.  The hash types rclone knows about are ` + "`" + `MD5` + "`" + `,
+` + "`" + `SHA-1` + "`" + `, ` + "`" + `DropboxHash` + "`" + `, ` + "`" + `CRC-32C` + "`" + `, ` + "`" + `SHA-256` + "`" + `, ` + "`" + `XXH64` + "`" + ` and
+` + "`" + `BLAKE3` + "`" + ` though each remote only supports some of them.  A
+warning is given for any hash type the remote doesn't support.
+
 If --no-modtime is specified then ModTime will be blank.
 
 The time is in RFC3339 format with nanosecond precision.
@@ -81,9 +91,25 @@ can be processed line by line as each item is written one to a line.
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(false, false, command, func() error {
+			hashes := fsrc.Hashes()
+			if len(hashTypes) > 0 {
+				showHash = true
+				var wanted fs.HashSet
+				for _, name := range hashTypes {
+					hashType, err := fs.HashTypeFromString(name)
+					if err != nil {
+						return err
+					}
+					if !hashes.Contains(hashType) {
+						fs.Logf(fsrc, "Hash type %v not supported by this remote so it won't be shown", hashType)
+					}
+					wanted.Add(hashType)
+				}
+				hashes = hashes.Overlap(wanted)
+			}
 			fmt.Println("[")
 			first := true
-			err := fs.Walk(fsrc, "", false, fs.ConfigMaxDepth(recurse), func(dirPath string, entries fs.DirEntries, err error) error {
+			err := fs.Walk(context.Background(), fsrc, "", false, fs.ConfigMaxDepth(recurse), func(dirPath string, entries fs.DirEntries, err error) error {
 				if err != nil {
 					fs.Stats.Error(err)
 					fs.Errorf(dirPath, "error listing: %v", err)
@@ -105,7 +131,7 @@ can be processed line by line as each item is written one to a line.
 						item.IsDir = false
 						if showHash {
 							item.Hashes = make(map[string]string)
-							for _, hashType := range x.Fs().Hashes().Array() {
+							for _, hashType := range hashes.Array() {
 								hash, err := x.Hash(hashType)
 								if err != nil {
 									fs.Errorf(x, "Failed to read hash: %v", err)
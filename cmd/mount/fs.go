@@ -61,6 +61,14 @@ func (f *FS) Statfs(ctx context.Context, req *fuse.StatfsRequest, resp *fuse.Sta
 	resp.Bsize = blockSize  // Block size
 	resp.Namelen = 255      // Maximum file name length?
 	resp.Frsize = blockSize // Fragment size, smallest addressable data size in the file system.
+	total, _, free := f.VFS.Statfs()
+	if total >= 0 {
+		resp.Blocks = uint64(total) / blockSize
+	}
+	if free >= 0 {
+		resp.Bfree = uint64(free) / blockSize
+		resp.Bavail = resp.Bfree
+	}
 	return nil
 }
 
@@ -4,10 +4,17 @@ import (
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// Globals
+var (
+	reportOpt cmd.ReportOpt
 )
 
 func init() {
 	cmd.Root.AddCommand(commandDefintion)
+	reportOpt.AddFlags(commandDefintion.Flags())
 }
 
 var commandDefintion = &cobra.Command{
@@ -30,12 +37,12 @@ into ` + "`dest:path`" + ` then delete the original (if no errors on copy) in
 
 **Important**: Since this can cause data loss, test first with the
 --dry-run flag.
-`,
+` + cmd.ReportHelp,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
-		cmd.Run(true, true, command, func() error {
-			return fs.MoveDir(fdst, fsrc)
+		reportOpt.Run(true, true, command, func(ctx context.Context) error {
+			return fs.MoveDir(ctx, fdst, fsrc)
 		})
 	},
 }
@@ -0,0 +1,131 @@
+package rc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/rc"
+	"github.com/ncw/rclone/rc/rcflags"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Globals
+var (
+	noOutput = false
+	url      = "http://localhost:5572/"
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	commandDefintion.Flags().BoolVarP(&noOutput, "no-output", "", noOutput, "If set don't output the JSON result.")
+	commandDefintion.Flags().StringVarP(&url, "url", "", url, "URL to connect to rclone remote control.")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "rc commands parameter",
+	Short: `Run a command against a running rclone.`,
+	Long: `
+This runs a command against a running rclone.  By default it will
+connect to http://localhost:5572/ - use --url to change this.
+
+Arguments should be passed in as parameter=value.
+
+The result will be returned as a JSON object by default.
+
+Use --rc-user and --rc-pass to authenticate if the server needs it.
+
+Use "rclone rc rc/list" to see a list of all possible commands.`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1e9, command, args)
+		cmd.Run(false, false, command, func() error {
+			return run(args)
+		})
+	},
+}
+
+// do a call from (path, in) to (out, err).
+//
+// if err is set, out may be a valid error return or it may be nil
+func doCall(path string, in rc.Params) (out rc.Params, err error) {
+	// Do HTTP request
+	client := fs.Config.Client()
+	callURL := url
+	if !strings.HasSuffix(callURL, "/") {
+		callURL += "/"
+	}
+	callURL += path
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode JSON")
+	}
+
+	req, err := http.NewRequest("POST", callURL, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if rcflags.Opt.User != "" {
+		req.SetBasicAuth(rcflags.Opt.User, rcflags.Opt.Pass)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "connection failed")
+	}
+	defer fs.CheckClose(resp.Body, &err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read rc response")
+	}
+
+	// Parse output
+	out = make(rc.Params)
+	err = json.Unmarshal(body, &out)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode JSON %q", body)
+	}
+
+	// Check HTTP status code
+	if resp.StatusCode != http.StatusOK {
+		return out, errors.Errorf("failed to read rc response: %s", resp.Status)
+	}
+
+	return out, nil
+}
+
+// Run the remote control command passed in
+func run(args []string) (err error) {
+	path := strings.Trim(args[0], "/")
+
+	// parse input
+	in := make(rc.Params)
+	for _, param := range args[1:] {
+		equals := strings.IndexRune(param, '=')
+		if equals < 0 {
+			return errors.Errorf("no '=' found in parameter %q", param)
+		}
+		key, value := param[:equals], param[equals+1:]
+		in[key] = value
+	}
+
+	// Do the call
+	out, callErr := doCall(path, in)
+
+	// Write the JSON blob to stdout if required
+	if out != nil && !noOutput {
+		data, err := json.MarshalIndent(out, "", "\t")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode output JSON")
+		}
+		fmt.Println(string(data))
+	}
+	return callErr
+}
@@ -0,0 +1,49 @@
+package rcd
+
+import (
+	"log"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/rc"
+	"github.com/ncw/rclone/rc/rcflags"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "rcd",
+	Short: `Run rclone listening to remote control commands only.`,
+	Long: `
+This runs rclone so that it only listens to remote control commands.
+
+This is useful if you are controlling rclone via the rc API.  Remotes
+created by the rc calls are kept between calls so directory caches,
+tokens and connections can be reused.
+
+Use --rc-addr to specify which IP address and port the server should
+listen on, eg --rc-addr 1.2.3.4:5572 or --rc-addr :5572 to listen to
+all IPs.  By default it only listens on localhost.
+
+Use --rc-user and --rc-pass to require HTTP basic authentication.
+
+See the [rc documentation](/rc/) for more info on the rc flags and
+the calls available.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(0, 0, command, args)
+		if rcflags.Opt.Enabled {
+			log.Fatalf("Don't supply --rc flag when using rcd")
+		}
+		opt := rcflags.Opt
+		opt.Enabled = true
+		s := rc.NewServer(&opt)
+		err := s.Listen()
+		if err != nil {
+			log.Fatalf("Failed to start remote control: %v", err)
+		}
+		log.Fatal(s.Serve())
+	},
+}
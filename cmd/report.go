@@ -0,0 +1,176 @@
+package cmd
+
+// Change report flags for sync, copy, move and check
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/net/context"
+)
+
+// ReportOpt holds the file names to write a change report to as set
+// by the flags added with AddFlags
+type ReportOpt struct {
+	Combined     string
+	MissingOnSrc string
+	MissingOnDst string
+	Match        string
+	Differ       string
+	Error        string
+	Format       string
+}
+
+// AddFlags adds the report flags to flagSet
+func (opt *ReportOpt) AddFlags(flagSet *pflag.FlagSet) {
+	fs.StringVarP(flagSet, &opt.Combined, "combined", "", "", "Make a combined report of changes to this file")
+	fs.StringVarP(flagSet, &opt.MissingOnSrc, "missing-on-src", "", "", "Report all files missing from the source to this file")
+	fs.StringVarP(flagSet, &opt.MissingOnDst, "missing-on-dst", "", "", "Report all files missing from the destination to this file")
+	fs.StringVarP(flagSet, &opt.Match, "match", "", "", "Report all matching files to this file")
+	fs.StringVarP(flagSet, &opt.Differ, "differ", "", "", "Report all non-matching files to this file")
+	fs.StringVarP(flagSet, &opt.Error, "error", "", "", "Report all files with errors to this file")
+	fs.StringVarP(flagSet, &opt.Format, "report-format", "", "text", "Format of the reports: text or json")
+}
+
+// report is the files the report is written to
+type report struct {
+	r     *fs.ReportWriter
+	files []*os.File
+}
+
+// Close all the files returning the first error writing or closing
+// them
+func (rep *report) Close() (err error) {
+	err = rep.r.Err()
+	for _, f := range rep.files {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Open opens the report files set in the flags and returns a copy
+// of ctx which reports to them and an io.Closer to close them.
+//
+// If no reports were asked for ctx is returned unchanged.
+//
+// A file name of "-" writes the report to stdout.
+func (opt *ReportOpt) Open(ctx context.Context) (context.Context, io.Closer, error) {
+	var useJSON bool
+	switch opt.Format {
+	case "text":
+	case "json":
+		useJSON = true
+	default:
+		return ctx, nil, errors.Errorf("unknown --report-format %q - must be text or json", opt.Format)
+	}
+	r := fs.NewReportWriter(useJSON)
+	rep := &report{r: r}
+	reporting := false
+	open := func(name string, set func(io.Writer)) error {
+		if name == "" {
+			return nil
+		}
+		reporting = true
+		if name == "-" {
+			set(os.Stdout)
+			return nil
+		}
+		f, err := os.Create(name)
+		if err != nil {
+			return errors.Wrap(err, "failed to open report file")
+		}
+		rep.files = append(rep.files, f)
+		set(f)
+		return nil
+	}
+	output := func(sigil fs.Sigil) func(io.Writer) {
+		return func(w io.Writer) {
+			r.SetOutput(sigil, w)
+		}
+	}
+	for _, item := range []struct {
+		name string
+		set  func(io.Writer)
+	}{
+		{opt.Combined, r.SetCombined},
+		{opt.MissingOnSrc, output(fs.SigilMissingOnSrc)},
+		{opt.MissingOnDst, output(fs.SigilMissingOnDst)},
+		{opt.Match, output(fs.SigilMatch)},
+		{opt.Differ, output(fs.SigilDiffer)},
+		{opt.Error, output(fs.SigilError)},
+	} {
+		err := open(item.name, item.set)
+		if err != nil {
+			_ = rep.Close()
+			return ctx, nil, err
+		}
+	}
+	if !reporting {
+		return ctx, rep, nil
+	}
+	return fs.WithReporter(ctx, r), rep, nil
+}
+
+// Run calls Run with f passing it a context which reports to the
+// report files.
+//
+// The report files are opened again for each retry of f so they only
+// contain the results of the last try.
+func (opt *ReportOpt) Run(Retry bool, showStats bool, cmd *cobra.Command, f func(ctx context.Context) error) {
+	var rep io.Closer
+	closeReport := func() {
+		if rep == nil {
+			return
+		}
+		err := rep.Close()
+		rep = nil
+		if err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+	}
+	Run(Retry, showStats, cmd, func() error {
+		closeReport()
+		ctx, newRep, err := opt.Open(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to make report: %v", err)
+		}
+		rep = newRep
+		return f(ctx)
+	})
+	closeReport()
+}
+
+// ReportHelp describes the report flags - add it to the Long help of
+// commands which use ReportOpt
+var ReportHelp = `
+If you supply the --combined flag, it will write a file (or stdout
+if "-") with a line for each file looked at.  Each path is prefixed
+by a sigil and a space to show what happened to it:
+
+    = path means path was identical in source and destination
+    * path means path differed (and was updated by sync, copy or move)
+    + path means path was missing on the destination (and was added)
+    - path means path was missing on the source (and was deleted by sync)
+    ! path means there was an error reading or writing path
+
+The --match, --differ, --missing-on-dst, --missing-on-src and
+--error flags write just the paths with that sigil to the file given.
+
+Use --report-format json to write each line as a JSON object with
+"sigil", "path" and "error" if there was one instead.
+
+If the command is retried the report files are started again so
+they only show the last try.  Reports written to stdout will contain
+the output of each try.
+
+These work with --dry-run to show what would have happened.  Note
+that directory moves aren't done server side when making a report so
+that each file can be listed.
+`
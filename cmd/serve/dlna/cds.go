@@ -0,0 +1,562 @@
+package dlna
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/pkg/errors"
+)
+
+const (
+	soapEnvelopeNS      = "http://schemas.xmlsoap.org/soap/envelope/"
+	soapEncodingStyle   = "http://schemas.xmlsoap.org/soap/encoding/"
+	rootObjectID        = "0"
+	dlnaContentFeatures = "DLNA.ORG_OP=01;DLNA.ORG_CI=0;DLNA.ORG_FLAGS=01700000000000000000000000000000"
+)
+
+// Media types which DLNA clients need to know about to play the
+// files.  These are used in preference to the system mime type table
+// which may not have them or may map them to something else.
+var mediaMimeTypes = map[string]string{
+	".3gp":  "video/3gpp",
+	".aac":  "audio/aac",
+	".avi":  "video/x-msvideo",
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+	".m4v":  "video/x-m4v",
+	".mkv":  "video/x-matroska",
+	".mov":  "video/quicktime",
+	".mp3":  "audio/mpeg",
+	".mp4":  "video/mp4",
+	".mpeg": "video/mpeg",
+	".mpg":  "video/mpeg",
+	".oga":  "audio/ogg",
+	".ogg":  "audio/ogg",
+	".ogv":  "video/ogg",
+	".ass":  "text/x-ssa",
+	".smi":  "application/smil",
+	".srt":  "text/srt",
+	".ssa":  "text/x-ssa",
+	".ts":   "video/mp2t",
+	".vtt":  "text/vtt",
+	".wav":  "audio/wav",
+	".webm": "video/webm",
+	".wmv":  "video/x-ms-wmv",
+}
+
+// subtitleExtensions are the file extensions of subtitles which are
+// offered with the video of the same name
+var subtitleExtensions = map[string]bool{
+	".ass": true,
+	".smi": true,
+	".srt": true,
+	".ssa": true,
+	".sub": true,
+	".vtt": true,
+}
+
+// mimeTypeFromName returns the mime type of remote from
+// mediaMimeTypes if it is there, otherwise from the system table
+func mimeTypeFromName(remote string) string {
+	if mimeType, ok := mediaMimeTypes[strings.ToLower(path.Ext(remote))]; ok {
+		return mimeType
+	}
+	return fs.MimeTypeFromName(remote)
+}
+
+// upnpError is an error returned to the client as a SOAP fault
+type upnpError struct {
+	Code        int
+	Description string
+}
+
+// Error satisfies the error interface
+func (e *upnpError) Error() string {
+	return fmt.Sprintf("UPnP error %d: %s", e.Code, e.Description)
+}
+
+// Errors from the UPnP specifications
+var (
+	errInvalidAction = &upnpError{401, "Invalid Action"}
+	errInvalidArgs   = &upnpError{402, "Invalid Args"}
+	errNoSuchObject  = &upnpError{701, "No such object"}
+)
+
+// soapArg is an argument of a SOAP action
+type soapArg struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// soapEnvelope is used to decode SOAP action requests
+type soapEnvelope struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+	Body    struct {
+		Action struct {
+			XMLName xml.Name
+			Args    []soapArg `xml:",any"`
+		} `xml:",any"`
+	} `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
+}
+
+// arg is a name and value returned from an action
+type arg struct {
+	name  string
+	value string
+}
+
+// action is the signature of the functions implementing the SOAP
+// actions
+type action func(s *server, args map[string]string, r *http.Request) ([]arg, error)
+
+// actions maps service type and action name to the function
+// implementing it
+var actions = map[string]map[string]action{
+	contentDirectoryService.ServiceType: {
+		"Browse":                (*server).browse,
+		"GetSearchCapabilities": constantAction(arg{"SearchCaps", ""}),
+		"GetSortCapabilities":   constantAction(arg{"SortCaps", "dc:title"}),
+		"GetSystemUpdateID":     constantAction(arg{"Id", "0"}),
+		"X_GetFeatureList":      constantAction(arg{"FeatureList", featureList}),
+	},
+	connectionManagerService.ServiceType: {
+		"GetProtocolInfo":          constantAction(arg{"Source", "http-get:*:*:*"}, arg{"Sink", ""}),
+		"GetCurrentConnectionIDs":  constantAction(arg{"ConnectionIDs", "0"}),
+		"GetCurrentConnectionInfo": (*server).getCurrentConnectionInfo,
+	},
+}
+
+// featureList is returned for the Samsung feature list action
+const featureList = `<Features xmlns="urn:schemas-upnp-org:av:avs" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="urn:schemas-upnp-org:av:avs http://www.upnp.org/schemas/av/avs.xsd">
+	<Feature name="samsung.com_BASICVIEW" version="1">
+		<container id="0" type="object.item.imageItem"/>
+		<container id="0" type="object.item.audioItem"/>
+		<container id="0" type="object.item.videoItem"/>
+	</Feature>
+</Features>`
+
+// constantAction makes an action which always returns out
+func constantAction(out ...arg) action {
+	return func(s *server, args map[string]string, r *http.Request) ([]arg, error) {
+		return out, nil
+	}
+}
+
+// getCurrentConnectionInfo returns the info for the single
+// connection the server supports
+func (s *server) getCurrentConnectionInfo(args map[string]string, r *http.Request) ([]arg, error) {
+	if args["ConnectionID"] != "0" {
+		return nil, &upnpError{706, "Invalid connection reference"}
+	}
+	return []arg{
+		{"RcsID", "-1"},
+		{"AVTransportID", "-1"},
+		{"ProtocolInfo", ""},
+		{"PeerConnectionManager", ""},
+		{"PeerConnectionID", "-1"},
+		{"Direction", "Output"},
+		{"Status", "OK"},
+	}, nil
+}
+
+// serviceControlHandler decodes SOAP action requests and dispatches
+// them to the action implementing them
+func (s *server) serviceControlHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	// The SOAPACTION header looks like
+	// "urn:schemas-upnp-org:service:ContentDirectory:1#Browse"
+	soapAction := strings.Trim(r.Header.Get("SOAPACTION"), `"`)
+	i := strings.LastIndex(soapAction, "#")
+	if i < 0 {
+		http.Error(w, "Bad SOAPACTION header", http.StatusBadRequest)
+		return
+	}
+	serviceType, actionName := soapAction[:i], soapAction[i+1:]
+
+	var env soapEnvelope
+	err := xml.NewDecoder(r.Body).Decode(&env)
+	if err != nil {
+		fs.Errorf(s.f, "%s: failed to decode SOAP request: %v", r.RemoteAddr, err)
+		writeSOAPFault(w, errInvalidArgs)
+		return
+	}
+	args := make(map[string]string, len(env.Body.Action.Args))
+	for _, a := range env.Body.Action.Args {
+		args[a.XMLName.Local] = a.Value
+	}
+
+	fn := actions[serviceType][actionName]
+	if fn == nil {
+		fs.Debugf(s.f, "%s: unknown action %q", r.RemoteAddr, soapAction)
+		writeSOAPFault(w, errInvalidAction)
+		return
+	}
+	fs.Debugf(s.f, "%s: %s %v", r.RemoteAddr, actionName, args)
+	out, err := fn(s, args, r)
+	if err != nil {
+		fs.Debugf(s.f, "%s: %s failed: %v", r.RemoteAddr, actionName, err)
+		writeSOAPFault(w, err)
+		return
+	}
+	writeSOAPResponse(w, serviceType, actionName, out)
+}
+
+// writeSOAPResponse writes the output arguments of an action as a
+// SOAP response
+func writeSOAPResponse(w http.ResponseWriter, serviceType, actionName string, out []arg) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="utf-8"?>`+"\n"+
+		`<s:Envelope xmlns:s="%s" s:encodingStyle="%s"><s:Body><u:%sResponse xmlns:u="%s">`,
+		soapEnvelopeNS, soapEncodingStyle, actionName, serviceType)
+	for _, a := range out {
+		fmt.Fprintf(&buf, "<%s>%s</%s>", a.name, xmlEscape(a.value), a.name)
+	}
+	fmt.Fprintf(&buf, `</u:%sResponse></s:Body></s:Envelope>`, actionName)
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	w.Header().Set("Ext", "")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeSOAPFault writes err as a SOAP fault
+func writeSOAPFault(w http.ResponseWriter, err error) {
+	upnpErr, ok := err.(*upnpError)
+	if !ok {
+		upnpErr = &upnpError{501, "Action Failed: " + err.Error()}
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="utf-8"?>`+"\n"+
+		`<s:Envelope xmlns:s="%s" s:encodingStyle="%s"><s:Body><s:Fault>`+
+		`<faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail>`+
+		`<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>%d</errorCode><errorDescription>%s</errorDescription></UPnPError>`+
+		`</detail></s:Fault></s:Body></s:Envelope>`,
+		soapEnvelopeNS, soapEncodingStyle, upnpErr.Code, xmlEscape(upnpErr.Description))
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// didlResource is a resource of a DIDL-Lite item - a URL the content
+// can be fetched from
+type didlResource struct {
+	XMLName      xml.Name `xml:"res"`
+	ProtocolInfo string   `xml:"protocolInfo,attr"`
+	Size         int64    `xml:"size,attr,omitempty"`
+	URL          string   `xml:",chardata"`
+}
+
+// didlObject has the fields common to DIDL-Lite items and containers
+type didlObject struct {
+	ID         string `xml:"id,attr"`
+	ParentID   string `xml:"parentID,attr"`
+	Restricted int    `xml:"restricted,attr"`
+	Title      string `xml:"dc:title"`
+	Class      string `xml:"upnp:class"`
+	Date       string `xml:"dc:date,omitempty"`
+}
+
+// didlContainer is a DIDL-Lite container - a directory
+type didlContainer struct {
+	XMLName xml.Name `xml:"container"`
+	didlObject
+	ChildCount *int `xml:"childCount,attr,omitempty"`
+}
+
+// didlCaption is the Samsung extension for subtitles
+type didlCaption struct {
+	Type string `xml:"sec:type,attr"`
+	URL  string `xml:",chardata"`
+}
+
+// didlItem is a DIDL-Lite item - a file
+type didlItem struct {
+	XMLName xml.Name `xml:"item"`
+	didlObject
+	Captions []didlCaption  `xml:"sec:CaptionInfoEx"`
+	Res      []didlResource `xml:"res"`
+}
+
+const (
+	didlHeader = `<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/" xmlns:dlna="urn:schemas-dlna-org:metadata-1-0/" xmlns:sec="http://www.sec.co.kr/">`
+	didlFooter = `</DIDL-Lite>`
+)
+
+// objectID returns the ID of the object at remote in the VFS
+func objectID(remote string) string {
+	if remote == "" {
+		return rootObjectID
+	}
+	return "/" + remote
+}
+
+// objectRemote returns the path in the VFS of the object with id
+func objectRemote(id string) (string, error) {
+	if id == rootObjectID {
+		return "", nil
+	}
+	if !strings.HasPrefix(id, "/") {
+		return "", errNoSuchObject
+	}
+	return strings.Trim(path.Clean(id), "/"), nil
+}
+
+// parentID returns the ID of the parent of the object at remote
+func parentID(remote string) string {
+	if remote == "" {
+		return "-1"
+	}
+	parent := path.Dir(remote)
+	if parent == "." {
+		parent = ""
+	}
+	return objectID(parent)
+}
+
+// resourceURL returns the URL to fetch remote from as seen by the
+// client making request r
+func resourceURL(r *http.Request, remote string) string {
+	u := url.URL{
+		Scheme: "http",
+		Host:   r.Host,
+		Path:   resPath + remote,
+	}
+	return u.String()
+}
+
+// upnpClass returns the UPnP class of a file with mimeType
+func upnpClass(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "video/"):
+		return "object.item.videoItem"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "object.item.audioItem.musicTrack"
+	case strings.HasPrefix(mimeType, "image/"):
+		return "object.item.imageItem.photo"
+	}
+	return "object.item"
+}
+
+// isSubtitle returns true if remote is a subtitle file
+func isSubtitle(remote string) bool {
+	return subtitleExtensions[strings.ToLower(path.Ext(remote))]
+}
+
+// subtitlesFor returns the subtitle files in nodes which go with the
+// video at remote.  These are named like the video with a subtitle
+// extension, optionally with a language, eg "film.srt" or
+// "film.en.srt" for "film.mkv".
+func subtitlesFor(remote string, nodes vfs.Nodes) (subtitles []vfs.Node) {
+	base := strings.TrimSuffix(path.Base(remote), path.Ext(remote))
+	for _, node := range nodes {
+		name := node.Name()
+		if !node.IsFile() || !isSubtitle(name) {
+			continue
+		}
+		subBase := strings.TrimSuffix(name, path.Ext(name))
+		if subBase == base || strings.HasPrefix(subBase, base+".") {
+			subtitles = append(subtitles, node)
+		}
+	}
+	return subtitles
+}
+
+// makeItem makes the DIDL-Lite item for a file node.  siblings are
+// the contents of the directory the node is in and are used to find
+// subtitles for videos.
+func makeItem(r *http.Request, node vfs.Node, siblings vfs.Nodes) didlItem {
+	remote := node.Path()
+	mimeType := mimeTypeFromName(remote)
+	item := didlItem{
+		didlObject: didlObject{
+			ID:         objectID(remote),
+			ParentID:   parentID(remote),
+			Restricted: 1,
+			Title:      node.Name(),
+			Class:      upnpClass(mimeType),
+			Date:       node.ModTime().UTC().Format("2006-01-02T15:04:05"),
+		},
+		Res: []didlResource{{
+			ProtocolInfo: fmt.Sprintf("http-get:*:%s:%s", mimeType, dlnaContentFeatures),
+			Size:         node.Size(),
+			URL:          resourceURL(r, remote),
+		}},
+	}
+	if strings.HasPrefix(mimeType, "video/") {
+		for _, sub := range subtitlesFor(remote, siblings) {
+			subURL := resourceURL(r, sub.Path())
+			subType := strings.TrimPrefix(strings.ToLower(path.Ext(sub.Name())), ".")
+			item.Res = append(item.Res, didlResource{
+				ProtocolInfo: fmt.Sprintf("http-get:*:%s:*", mimeTypeFromName(sub.Name())),
+				Size:         sub.Size(),
+				URL:          subURL,
+			})
+			item.Captions = append(item.Captions, didlCaption{Type: subType, URL: subURL})
+		}
+	}
+	return item
+}
+
+// makeContainer makes the DIDL-Lite container for a directory node
+func makeContainer(dir *vfs.Dir, childCount *int) didlContainer {
+	remote := dir.Path()
+	title := dir.Name()
+	if remote == "" {
+		title = "/"
+	}
+	return didlContainer{
+		didlObject: didlObject{
+			ID:         objectID(remote),
+			ParentID:   parentID(remote),
+			Restricted: 1,
+			Title:      title,
+			Class:      "object.container.storageFolder",
+		},
+		ChildCount: childCount,
+	}
+}
+
+// visibleChildren returns the nodes in dir which should be shown to
+// the client.  Subtitles which go with a video are offered with the
+// video so aren't shown on their own.
+func visibleChildren(nodes vfs.Nodes) (visible vfs.Nodes) {
+	attached := map[string]bool{}
+	for _, node := range nodes {
+		if node.IsFile() && strings.HasPrefix(mimeTypeFromName(node.Name()), "video/") {
+			for _, sub := range subtitlesFor(node.Name(), nodes) {
+				attached[sub.Name()] = true
+			}
+		}
+	}
+	for _, node := range nodes {
+		if !attached[node.Name()] {
+			visible = append(visible, node)
+		}
+	}
+	return visible
+}
+
+// readDir reads the directory at remote returning the directory and
+// its visible children
+func (s *server) readDir(remote string) (dir *vfs.Dir, all vfs.Nodes, visible vfs.Nodes, err error) {
+	node, err := s.vfs.Stat(remote)
+	if err == vfs.ENOENT {
+		return nil, nil, nil, errNoSuchObject
+	} else if err != nil {
+		return nil, nil, nil, err
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		return nil, nil, nil, errors.Errorf("%q is not a directory", remote)
+	}
+	all, err = dir.ReadDirAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return dir, all, visibleChildren(all), nil
+}
+
+// marshalDIDL marshals objects into a DIDL-Lite document
+func marshalDIDL(objects []interface{}) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(didlHeader)
+	for _, o := range objects {
+		out, err := xml.Marshal(o)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal DIDL-Lite")
+		}
+		buf.Write(out)
+	}
+	buf.WriteString(didlFooter)
+	return buf.String(), nil
+}
+
+// browse implements the ContentDirectory Browse action
+func (s *server) browse(args map[string]string, r *http.Request) ([]arg, error) {
+	remote, err := objectRemote(args["ObjectID"])
+	if err != nil {
+		return nil, err
+	}
+	var objects []interface{}
+	var totalMatches int
+	switch args["BrowseFlag"] {
+	case "BrowseDirectChildren":
+		_, all, visible, err := s.readDir(remote)
+		if err != nil {
+			return nil, err
+		}
+		totalMatches = len(visible)
+		start, count := parseIndex(args["StartingIndex"]), parseIndex(args["RequestedCount"])
+		if start > len(visible) {
+			start = len(visible)
+		}
+		visible = visible[start:]
+		if count > 0 && count < len(visible) {
+			visible = visible[:count]
+		}
+		for _, node := range visible {
+			if dir, ok := node.(*vfs.Dir); ok {
+				objects = append(objects, makeContainer(dir, nil))
+			} else {
+				objects = append(objects, makeItem(r, node, all))
+			}
+		}
+	case "BrowseMetadata":
+		node, err := s.vfs.Stat(remote)
+		if err == vfs.ENOENT {
+			return nil, errNoSuchObject
+		} else if err != nil {
+			return nil, err
+		}
+		if dir, ok := node.(*vfs.Dir); ok {
+			_, _, visible, err := s.readDir(remote)
+			if err != nil {
+				return nil, err
+			}
+			childCount := len(visible)
+			objects = append(objects, makeContainer(dir, &childCount))
+		} else {
+			parent := path.Dir(remote)
+			if parent == "." {
+				parent = ""
+			}
+			_, all, _, err := s.readDir(parent)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, makeItem(r, node, all))
+		}
+		totalMatches = 1
+	default:
+		return nil, errInvalidArgs
+	}
+	result, err := marshalDIDL(objects)
+	if err != nil {
+		return nil, err
+	}
+	return []arg{
+		{"Result", result},
+		{"NumberReturned", strconv.Itoa(len(objects))},
+		{"TotalMatches", strconv.Itoa(totalMatches)},
+		{"UpdateID", "0"},
+	}, nil
+}
+
+// parseIndex parses a non negative integer argument returning 0 if
+// it is invalid
+func parseIndex(s string) int {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 {
+		return 0
+	}
+	return i
+}
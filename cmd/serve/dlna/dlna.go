@@ -0,0 +1,79 @@
+// Package dlna implements a DLNA/UPnP media server to serve an
+// rclone VFS
+package dlna
+
+import (
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/ncw/rclone/vfs/vfsflags"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Options contains options for the DLNA server
+type Options struct {
+	ListenAddr   string // Port to listen on
+	FriendlyName string // Name the server advertises itself as
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	ListenAddr:   ":7879",
+	FriendlyName: "",
+}
+
+// Opt is options set by command line flags
+var Opt = DefaultOpt
+
+// AddFlags adds flags for dlna
+func AddFlags(flagSet *pflag.FlagSet) {
+	fs.StringVarP(flagSet, &Opt.ListenAddr, "addr", "", Opt.ListenAddr, "ip:port or :port to bind the DLNA http server to.")
+	fs.StringVarP(flagSet, &Opt.FriendlyName, "name", "", Opt.FriendlyName, "name of DLNA server")
+}
+
+func init() {
+	vfsflags.AddFlags(Command.Flags())
+	AddFlags(Command.Flags())
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "dlna remote:path",
+	Short: `Serve remote:path over DLNA`,
+	Long: `rclone serve dlna is a DLNA media server for media stored in a rclone remote. Many
+devices, such as the Xbox and PlayStation, can automatically discover this server in the LAN
+and play audio/video from it. VLC is also supported. Service discovery uses UDP multicast
+packets (SSDP) and will thus only work on LANs.
+
+Rclone will list all files present in the remote, without filtering based on media formats or
+file extensions.  Additionally, there is no media transcoding support.  This means that some
+players might show files that they are not able to play back correctly.
+
+Subtitle files (eg .srt) are not listed on their own but are offered
+to players along with the video file which has the same name.
+
+### Server options
+
+Use --addr to specify which IP address and port the server should
+listen on, eg --addr 1.2.3.4:8000 or --addr :8080 to listen to all
+IPs.  By default it listens on all IPs on port 7879 as DLNA clients
+need to be able to reach it over the LAN.
+
+Use --name to choose the friendly server name, which is by default
+"rclone (hostname)".
+` + vfs.Help,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		f := cmd.NewFsSrc(args)
+		cmd.Run(false, false, command, func() error {
+			s := newServer(f, &Opt)
+			err := s.Serve()
+			if err != nil {
+				return err
+			}
+			s.Wait()
+			return nil
+		})
+	},
+}
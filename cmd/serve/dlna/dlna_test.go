@@ -0,0 +1,430 @@
+package dlna
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/ncw/rclone/local"
+)
+
+const (
+	testBindAddress  = "localhost:0"
+	testFriendlyName = "rclone test"
+)
+
+// The server and the address it does SSDP on for the tests
+var (
+	dlnaServer   *server
+	testSSDPAddr string
+)
+
+// startServer starts a DLNA server serving f with SSDP only on a
+// loopback connection
+func startServer(t *testing.T, f fs.Fs) {
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.FriendlyName = testFriendlyName
+	dlnaServer = newServer(f, &opt)
+	require.NoError(t, dlnaServer.serveHTTP())
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	dlnaServer.startSSDPConn(conn, &net.IPNet{IP: net.IPv4(127, 0, 0, 1), Mask: net.CIDRMask(8, 32)}, nil)
+	testSSDPAddr = conn.LocalAddr().String()
+}
+
+func TestInit(t *testing.T) {
+	// Configure the remote
+	fs.LoadConfig()
+
+	// Create a test Fs
+	f, err := fs.NewFs("testdata/files")
+	require.NoError(t, err)
+
+	startServer(t, f)
+}
+
+// search sends an SSDP search for st to addr returning the responses
+// received
+func search(t *testing.T, addr, st string) (responses []*http.Response) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	udpAddr, err := net.ResolveUDPAddr("udp4", addr)
+	require.NoError(t, err)
+	msg := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: 239.255.255.250:1900\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 1\r\n" +
+		"ST: " + st + "\r\n\r\n"
+	_, err = conn.WriteTo([]byte(msg), udpAddr)
+	require.NoError(t, err)
+
+	buf := make([]byte, 2048)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return responses
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		require.NoError(t, err)
+		responses = append(responses, resp)
+	}
+}
+
+// rootDesc is the part of the root device description the tests use
+type rootDesc struct {
+	Device struct {
+		DeviceType   string `xml:"deviceType"`
+		FriendlyName string `xml:"friendlyName"`
+		UDN          string `xml:"UDN"`
+		Services     []struct {
+			ServiceType string `xml:"serviceType"`
+			SCPDURL     string `xml:"SCPDURL"`
+			ControlURL  string `xml:"controlURL"`
+		} `xml:"serviceList>service"`
+	} `xml:"device"`
+}
+
+// getXML fetches url and decodes the XML in it into out
+func getXML(t *testing.T, url string, out interface{}) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode, url)
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(out), url)
+}
+
+// soapFault is used to decode the error code from SOAP faults
+type soapFault struct {
+	Code int `xml:"Body>Fault>detail>UPnPError>errorCode"`
+}
+
+// call calls the SOAP action on the service of type serviceType at
+// controlURL with args returning the output arguments.  If the call
+// fails then it returns the UPnP error code.
+func call(t *testing.T, controlURL, serviceType, action string, args ...string) (map[string]string, int) {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, `<?xml version="1.0"?><s:Envelope xmlns:s="%s" s:encodingStyle="%s"><s:Body><u:%s xmlns:u="%s">`, soapEnvelopeNS, soapEncodingStyle, action, serviceType)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&body, "<%s>%s</%s>", args[i], xmlEscape(args[i+1]), args[i])
+	}
+	fmt.Fprintf(&body, `</u:%s></s:Body></s:Envelope>`, action)
+	req, err := http.NewRequest("POST", controlURL, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPACTION", fmt.Sprintf(`"%s#%s"`, serviceType, action))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if resp.StatusCode != http.StatusOK {
+		var fault soapFault
+		require.NoError(t, xml.Unmarshal(data, &fault), string(data))
+		return nil, fault.Code
+	}
+	var env soapEnvelope
+	require.NoError(t, xml.Unmarshal(data, &env))
+	assert.Equal(t, action+"Response", env.Body.Action.XMLName.Local)
+	out := map[string]string{}
+	for _, a := range env.Body.Action.Args {
+		out[a.XMLName.Local] = a.Value
+	}
+	return out, 0
+}
+
+// testDIDL is used to decode DIDL-Lite results
+type testDIDL struct {
+	Containers []struct {
+		ID         string `xml:"id,attr"`
+		ParentID   string `xml:"parentID,attr"`
+		ChildCount string `xml:"childCount,attr"`
+		Title      string `xml:"title"`
+		Class      string `xml:"class"`
+	} `xml:"container"`
+	Items []struct {
+		ID       string `xml:"id,attr"`
+		ParentID string `xml:"parentID,attr"`
+		Title    string `xml:"title"`
+		Class    string `xml:"class"`
+		Res      []struct {
+			ProtocolInfo string `xml:"protocolInfo,attr"`
+			Size         int64  `xml:"size,attr"`
+			URL          string `xml:",chardata"`
+		} `xml:"res"`
+		Captions []string `xml:"CaptionInfoEx"`
+	} `xml:"item"`
+}
+
+// browse calls Browse returning the decoded result and output args
+func browse(t *testing.T, controlURL, objectID, flag string, start, count int) (testDIDL, map[string]string) {
+	out, code := call(t, controlURL, contentDirectoryService.ServiceType, "Browse",
+		"ObjectID", objectID,
+		"BrowseFlag", flag,
+		"Filter", "*",
+		"StartingIndex", fmt.Sprint(start),
+		"RequestedCount", fmt.Sprint(count),
+		"SortCriteria", "")
+	require.Equal(t, 0, code)
+	var didl testDIDL
+	require.NoError(t, xml.Unmarshal([]byte(out["Result"]), &didl), out["Result"])
+	return didl, out
+}
+
+// discover finds the server with SSDP and returns the URL of the
+// ContentDirectory control
+func discover(t *testing.T, ssdpAddr string) (desc rootDesc, controlURL string) {
+	responses := search(t, ssdpAddr, deviceType)
+	require.Equal(t, 1, len(responses))
+	location := responses[0].Header.Get("LOCATION")
+	getXML(t, location, &desc)
+	for _, service := range desc.Device.Services {
+		if service.ServiceType == contentDirectoryService.ServiceType {
+			controlURL = strings.TrimSuffix(location, rootDescPath) + service.ControlURL
+		}
+	}
+	require.NotEqual(t, "", controlURL)
+	return desc, controlURL
+}
+
+func TestDLNADiscovery(t *testing.T) {
+	s := dlnaServer
+	desc, _ := discover(t, testSSDPAddr)
+	assert.Equal(t, deviceType, desc.Device.DeviceType)
+	assert.Equal(t, testFriendlyName, desc.Device.FriendlyName)
+	assert.Equal(t, s.rootDeviceUUID, desc.Device.UDN)
+	assert.Equal(t, 2, len(desc.Device.Services))
+
+	responses := search(t, testSSDPAddr, "ssdp:all")
+	assert.Equal(t, len(s.ssdpTargets()), len(responses))
+	for _, resp := range responses {
+		assert.Equal(t, s.usn(resp.Header.Get("ST")), resp.Header.Get("USN"))
+	}
+
+	responses = search(t, testSSDPAddr, "urn:schemas-upnp-org:device:MediaRenderer:1")
+	assert.Equal(t, 0, len(responses))
+}
+
+func TestDLNAServiceDescriptions(t *testing.T) {
+	for _, service := range services {
+		var scpd struct {
+			Actions []string `xml:"actionList>action>name"`
+		}
+		getXML(t, "http://"+dlnaServer.Addr()+service.SCPDURL, &scpd)
+		var want []string
+		for action := range actions[service.ServiceType] {
+			want = append(want, action)
+		}
+		assert.Equal(t, len(want), len(scpd.Actions), service.ServiceType)
+		for _, action := range want {
+			assert.Contains(t, scpd.Actions, action, service.ServiceType)
+		}
+	}
+}
+
+func TestDLNABrowse(t *testing.T) {
+	_, controlURL := discover(t, testSSDPAddr)
+
+	// root metadata
+	didl, out := browse(t, controlURL, "0", "BrowseMetadata", 0, 0)
+	assert.Equal(t, "1", out["NumberReturned"])
+	require.Equal(t, 1, len(didl.Containers))
+	assert.Equal(t, "0", didl.Containers[0].ID)
+	assert.Equal(t, "-1", didl.Containers[0].ParentID)
+	assert.Equal(t, "4", didl.Containers[0].ChildCount)
+
+	// root children - the subtitles for the video aren't listed
+	didl, out = browse(t, controlURL, "0", "BrowseDirectChildren", 0, 0)
+	assert.Equal(t, "4", out["NumberReturned"])
+	assert.Equal(t, "4", out["TotalMatches"])
+	require.Equal(t, 1, len(didl.Containers))
+	assert.Equal(t, "/music", didl.Containers[0].ID)
+	assert.Equal(t, "0", didl.Containers[0].ParentID)
+	assert.Equal(t, "object.container.storageFolder", didl.Containers[0].Class)
+	var titles []string
+	for _, item := range didl.Items {
+		titles = append(titles, item.Title)
+		assert.Equal(t, "0", item.ParentID)
+	}
+	assert.Equal(t, []string{"notes.txt", "orphan.srt", "video.mkv"}, titles)
+
+	// the video has the subtitles as extra resources
+	video := didl.Items[2]
+	assert.Equal(t, "/video.mkv", video.ID)
+	assert.Equal(t, "object.item.videoItem", video.Class)
+	require.Equal(t, 3, len(video.Res))
+	assert.Equal(t, int64(10), video.Res[0].Size)
+	assert.True(t, strings.HasSuffix(video.Res[0].URL, "/r/video.mkv"), video.Res[0].URL)
+	assert.True(t, strings.HasSuffix(video.Res[1].URL, "/r/video.en.srt"), video.Res[1].URL)
+	assert.True(t, strings.HasSuffix(video.Res[2].URL, "/r/video.srt"), video.Res[2].URL)
+	assert.Equal(t, []string{video.Res[1].URL, video.Res[2].URL}, video.Captions)
+
+	// paging
+	didl, out = browse(t, controlURL, "0", "BrowseDirectChildren", 1, 2)
+	assert.Equal(t, "2", out["NumberReturned"])
+	assert.Equal(t, "4", out["TotalMatches"])
+	require.Equal(t, 2, len(didl.Items))
+	assert.Equal(t, "notes.txt", didl.Items[0].Title)
+	assert.Equal(t, "orphan.srt", didl.Items[1].Title)
+
+	// item metadata
+	didl, _ = browse(t, controlURL, "/music/song.mp3", "BrowseMetadata", 0, 0)
+	require.Equal(t, 1, len(didl.Items))
+	assert.Equal(t, "/music", didl.Items[0].ParentID)
+	assert.Equal(t, "song.mp3", didl.Items[0].Title)
+	assert.Equal(t, "object.item.audioItem.musicTrack", didl.Items[0].Class)
+	require.Equal(t, 1, len(didl.Items[0].Res))
+	assert.True(t, strings.HasPrefix(didl.Items[0].Res[0].ProtocolInfo, "http-get:*:audio/mpeg:"), didl.Items[0].Res[0].ProtocolInfo)
+
+	didl, _ = browse(t, controlURL, "/music", "BrowseDirectChildren", 0, 0)
+	require.Equal(t, 2, len(didl.Items))
+	assert.Equal(t, "object.item.imageItem.photo", didl.Items[0].Class)
+
+	// errors
+	_, code := call(t, controlURL, contentDirectoryService.ServiceType, "Browse", "ObjectID", "/missing", "BrowseFlag", "BrowseDirectChildren")
+	assert.Equal(t, 701, code)
+	_, code = call(t, controlURL, contentDirectoryService.ServiceType, "Browse", "ObjectID", "potato", "BrowseFlag", "BrowseMetadata")
+	assert.Equal(t, 701, code)
+	_, code = call(t, controlURL, contentDirectoryService.ServiceType, "Browse", "ObjectID", "0", "BrowseFlag", "potato")
+	assert.Equal(t, 402, code)
+	_, code = call(t, controlURL, contentDirectoryService.ServiceType, "Potato")
+	assert.Equal(t, 401, code)
+
+	// other actions
+	out, code = call(t, controlURL, contentDirectoryService.ServiceType, "GetSystemUpdateID")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "0", out["Id"])
+	out, code = call(t, controlURL, connectionManagerService.ServiceType, "GetProtocolInfo")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "http-get:*:*:*", out["Source"])
+}
+
+func TestDLNAResource(t *testing.T) {
+	req, err := http.NewRequest("GET", "http://"+dlnaServer.Addr()+"/r/video.mkv", nil)
+	require.NoError(t, err)
+	req.Header.Set("Range", "bytes=2-5")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "2345", string(data))
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "video/"), resp.Header.Get("Content-Type"))
+	assert.Equal(t, "Streaming", resp.Header.Get("transferMode.dlna.org"))
+
+	for _, path := range []string{"/r/missing.mkv", "/r/music"} {
+		resp, err = http.Get("http://" + dlnaServer.Addr() + path)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
+
+// errorWriter is a response which fails when the body is written
+type errorWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w errorWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+// errorReader is a file which fails when it is read
+type errorReader struct {
+	*strings.Reader
+}
+
+func (r errorReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestTransferRecorder(t *testing.T) {
+	const contents = "0123456789"
+	for _, test := range []struct {
+		what    string
+		w       http.ResponseWriter
+		in      io.ReadSeeker
+		rang    string
+		wantErr bool
+	}{
+		{"ok", httptest.NewRecorder(), strings.NewReader(contents), "", false},
+		{"range ok", httptest.NewRecorder(), strings.NewReader(contents), "bytes=2-5", false},
+		{"write error", errorWriter{httptest.NewRecorder()}, strings.NewReader(contents), "", true},
+		{"read error", httptest.NewRecorder(), errorReader{strings.NewReader(contents)}, "", true},
+		{"bad range", httptest.NewRecorder(), strings.NewReader(contents), "bytes=100-", true},
+	} {
+		req, err := http.NewRequest("GET", "http://localhost/r/video.mkv", nil)
+		require.NoError(t, err)
+		if test.rang != "" {
+			req.Header.Set("Range", test.rang)
+		}
+		transfer := &transferRecorder{ResponseWriter: test.w, in: test.in}
+		transfer.Header().Set("Content-Type", "video/x-matroska")
+		http.ServeContent(transfer, req, "video.mkv", time.Time{}, transfer)
+		assert.Equal(t, test.wantErr, transfer.err != nil, "%s: %v", test.what, transfer.err)
+	}
+}
+
+func TestMimeTypeFromName(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"video.mkv", "video/x-matroska"},
+		{"VIDEO.MKV", "video/x-matroska"},
+		{"film.ts", "video/mp2t"},
+		{"video.srt", "text/srt"},
+		{"video.en.vtt", "text/vtt"},
+		{"cover.jpg", "image/jpeg"},
+		{"potato", "application/octet-stream"},
+	} {
+		assert.Equal(t, test.want, mimeTypeFromName(test.in), test.in)
+	}
+}
+
+func TestObjectIDs(t *testing.T) {
+	for _, test := range []struct {
+		remote   string
+		id       string
+		parentID string
+	}{
+		{"", "0", "-1"},
+		{"file.mkv", "/file.mkv", "0"},
+		{"dir/file.mkv", "/dir/file.mkv", "/dir"},
+	} {
+		assert.Equal(t, test.id, objectID(test.remote), test.remote)
+		assert.Equal(t, test.parentID, parentID(test.remote), test.remote)
+		remote, err := objectRemote(test.id)
+		require.NoError(t, err)
+		assert.Equal(t, test.remote, remote)
+	}
+	remote, err := objectRemote("/dir/../../file.mkv")
+	require.NoError(t, err)
+	assert.Equal(t, "file.mkv", remote)
+	_, err = objectRemote("file.mkv")
+	assert.Equal(t, errNoSuchObject, err)
+}
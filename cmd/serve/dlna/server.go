@@ -0,0 +1,389 @@
+package dlna
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/ncw/rclone/cmd/serve/listener"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/ncw/rclone/vfs/vfsflags"
+	"github.com/pkg/errors"
+)
+
+const (
+	rootDescPath      = "/rootDesc.xml"
+	resPath           = "/r/"
+	serviceControlURL = "/ctl"
+	serviceEventURL   = "/evt"
+)
+
+// server contains everything to run the server
+type server struct {
+	f              fs.Fs
+	opt            Options
+	vfs            *vfs.VFS
+	friendlyName   string
+	rootDeviceUUID string
+	httpServer     *http.Server
+	listener       *listener.Listener // nil until Serve succeeds
+	mu             sync.Mutex
+	ssdpConns      []*ssdpConn // SSDP connections, one per interface
+}
+
+// newServer makes a new DLNA server serving f with the options in opt
+func newServer(f fs.Fs, opt *Options) *server {
+	s := &server{
+		f:            f,
+		opt:          *opt,
+		vfs:          vfs.New(f, &vfsflags.Opt),
+		friendlyName: opt.FriendlyName,
+	}
+	if s.friendlyName == "" {
+		s.friendlyName = makeDefaultFriendlyName()
+	}
+	s.rootDeviceUUID = makeDeviceUUID(s.friendlyName)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(rootDescPath, s.rootDescHandler)
+	mux.HandleFunc(contentDirectoryService.SCPDURL, scpdHandler(contentDirectoryServiceDescription))
+	mux.HandleFunc(connectionManagerService.SCPDURL, scpdHandler(connectionManagerServiceDescription))
+	mux.HandleFunc(serviceControlURL, s.serviceControlHandler)
+	mux.HandleFunc(serviceEventURL, s.serviceEventHandler)
+	mux.HandleFunc(resPath, s.resourceHandler)
+	s.httpServer = &http.Server{
+		Handler: s.serverHeaderHandler(mux),
+	}
+	return s
+}
+
+// makeDefaultFriendlyName makes the name the server is advertised
+// as if none was supplied
+func makeDefaultFriendlyName() string {
+	hostName, err := os.Hostname()
+	if err != nil {
+		hostName = ""
+	} else {
+		hostName = " (" + hostName + ")"
+	}
+	return "rclone" + hostName
+}
+
+// makeDeviceUUID makes a UUID for the device from unique which
+// stays the same between runs so clients can recognise the server
+func makeDeviceUUID(unique string) string {
+	h := md5.New()
+	if _, err := fmt.Fprint(h, unique); err != nil {
+		fs.Errorf(nil, "Failed to make device UUID: %v", err)
+	}
+	buf := h.Sum(nil)
+	return fmt.Sprintf("uuid:%x-%x-%x-%x-%x", buf[:4], buf[4:6], buf[6:8], buf[8:10], buf[10:])
+}
+
+// serverHeaderHandler wraps handler setting the Server header on
+// all responses and logging the requests
+func (s *server) serverHeaderHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fs.Debugf(s.f, "%s: %s %s", r.RemoteAddr, r.Method, r.URL.Path)
+		w.Header().Set("Server", serverString())
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// service describes a UPnP service the server provides
+type service struct {
+	ServiceType string
+	ServiceID   string
+	SCPDURL     string
+}
+
+var (
+	contentDirectoryService = service{
+		ServiceType: "urn:schemas-upnp-org:service:ContentDirectory:1",
+		ServiceID:   "urn:upnp-org:serviceId:ContentDirectory",
+		SCPDURL:     "/static/ContentDirectory.xml",
+	}
+	connectionManagerService = service{
+		ServiceType: "urn:schemas-upnp-org:service:ConnectionManager:1",
+		ServiceID:   "urn:upnp-org:serviceId:ConnectionManager",
+		SCPDURL:     "/static/ConnectionManager.xml",
+	}
+	services = []service{contentDirectoryService, connectionManagerService}
+)
+
+const deviceType = "urn:schemas-upnp-org:device:MediaServer:1"
+
+// rootDescTmpl is the template for the root device description
+var rootDescTmpl = template.Must(template.New("rootDesc").Funcs(template.FuncMap{
+	"xml": xmlEscape,
+}).Parse(`<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0" xmlns:dlna="urn:schemas-dlna-org:device-1-0">
+  <specVersion>
+    <major>1</major>
+    <minor>0</minor>
+  </specVersion>
+  <device>
+    <deviceType>{{ .DeviceType }}</deviceType>
+    <friendlyName>{{ xml .FriendlyName }}</friendlyName>
+    <manufacturer>rclone (rclone.org)</manufacturer>
+    <manufacturerURL>https://rclone.org/</manufacturerURL>
+    <modelDescription>rclone</modelDescription>
+    <modelName>rclone</modelName>
+    <modelNumber>{{ xml .Version }}</modelNumber>
+    <modelURL>https://rclone.org/</modelURL>
+    <serialNumber>00000000</serialNumber>
+    <UDN>{{ .UDN }}</UDN>
+    <dlna:X_DLNADOC>DMS-1.50</dlna:X_DLNADOC>
+    <serviceList>{{ range .Services }}
+      <service>
+        <serviceType>{{ .ServiceType }}</serviceType>
+        <serviceId>{{ .ServiceID }}</serviceId>
+        <SCPDURL>{{ .SCPDURL }}</SCPDURL>
+        <controlURL>{{ $.ControlURL }}</controlURL>
+        <eventSubURL>{{ $.EventURL }}</eventSubURL>
+      </service>{{ end }}
+    </serviceList>
+    <presentationURL>/</presentationURL>
+  </device>
+</root>
+`))
+
+// xmlEscape returns s escaped for use in XML text
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// rootDescHandler serves the root device description
+func (s *server) rootDescHandler(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	err := rootDescTmpl.Execute(&buf, map[string]interface{}{
+		"DeviceType":   deviceType,
+		"FriendlyName": s.friendlyName,
+		"Version":      fs.Version,
+		"UDN":          s.rootDeviceUUID,
+		"Services":     services,
+		"ControlURL":   serviceControlURL,
+		"EventURL":     serviceEventURL,
+	})
+	if err != nil {
+		internalError(r.URL.Path, w, "Failed to render root description", err)
+		return
+	}
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// scpdHandler returns a handler which serves the service description
+// desc
+func scpdHandler(desc string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+		_, _ = w.Write([]byte(desc))
+	}
+}
+
+// serviceEventHandler accepts event subscriptions.  The server
+// doesn't send any events but some clients won't work unless they
+// can subscribe.
+func (s *server) serviceEventHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "SUBSCRIBE":
+		sid := r.Header.Get("SID")
+		if sid == "" {
+			sid = makeDeviceUUID(r.RemoteAddr + r.Header.Get("CALLBACK"))
+		}
+		w.Header().Set("SID", sid)
+		w.Header().Set("TIMEOUT", "Second-1800")
+	case "UNSUBSCRIBE":
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// internalError returns an http.StatusInternalServerError and logs the error
+func internalError(what interface{}, w http.ResponseWriter, text string, err error) {
+	fs.Stats.Error(err)
+	fs.Errorf(what, "%s: %v", text, err)
+	http.Error(w, text+".", http.StatusInternalServerError)
+}
+
+// resourceHandler serves the contents of a file
+func (s *server) resourceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	remote := strings.Trim(strings.TrimPrefix(r.URL.Path, resPath), "/")
+	node, err := s.vfs.Stat(remote)
+	if err == vfs.ENOENT {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		internalError(remote, w, "Failed to find file", err)
+		return
+	}
+	if !node.IsFile() {
+		http.Error(w, "Not a file", http.StatusNotFound)
+		return
+	}
+	file := node.(*vfs.File)
+
+	w.Header().Set("Content-Type", mimeTypeFromName(remote))
+	w.Header().Set("contentFeatures.dlna.org", dlnaContentFeatures)
+	w.Header().Set("transferMode.dlna.org", "Streaming")
+
+	in, err := file.OpenRead()
+	if err != nil {
+		internalError(remote, w, "Failed to open file", err)
+		return
+	}
+	defer func() {
+		err := in.Close()
+		if err != nil {
+			fs.Errorf(remote, "Failed to close file: %v", err)
+		}
+	}()
+
+	// Account the transfer
+	fs.Stats.Transferring(remote)
+	transfer := &transferRecorder{ResponseWriter: w, in: in}
+	defer func() {
+		fs.Stats.DoneTransferring(remote, transfer.err == nil)
+	}()
+
+	// Serve the file - this does range requests too
+	http.ServeContent(transfer, r, remote, node.ModTime(), transfer)
+	if transfer.err != nil {
+		fs.Debugf(remote, "%s: Failed to serve file: %v", r.RemoteAddr, transfer.err)
+	}
+}
+
+// transferRecorder wraps the response and the file being served
+// recording the first error from either, or an error status, so
+// http.ServeContent can be accounted properly.
+type transferRecorder struct {
+	http.ResponseWriter
+	in  io.ReadSeeker
+	err error
+}
+
+// setErr records err if it is the first error
+func (t *transferRecorder) setErr(err error) {
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+}
+
+// WriteHeader records error status codes
+func (t *transferRecorder) WriteHeader(code int) {
+	if code >= 400 {
+		t.setErr(errors.Errorf("HTTP error %d", code))
+	}
+	t.ResponseWriter.WriteHeader(code)
+}
+
+// Write records errors writing the response
+func (t *transferRecorder) Write(p []byte) (n int, err error) {
+	n, err = t.ResponseWriter.Write(p)
+	t.setErr(err)
+	return n, err
+}
+
+// Read records errors reading the file
+func (t *transferRecorder) Read(p []byte) (n int, err error) {
+	n, err = t.in.Read(p)
+	if err != io.EOF {
+		t.setErr(err)
+	}
+	return n, err
+}
+
+// Seek records errors seeking the file
+func (t *transferRecorder) Seek(offset int64, whence int) (int64, error) {
+	n, err := t.in.Seek(offset, whence)
+	t.setErr(err)
+	return n, err
+}
+
+// Serve starts the server listening.  It returns an error if the
+// server couldn't be started, otherwise it serves in the background.
+//
+// SSDP discovery is started on all the multicast capable interfaces
+// and failures to do that are logged but not fatal.
+//
+// Use Wait to block until the server has stopped.
+func (s *server) Serve() error {
+	err := s.serveHTTP()
+	if err != nil {
+		return err
+	}
+	s.startSSDP()
+	fs.Logf(s.f, "Serving DLNA as %q on %s", s.friendlyName, s.Addr())
+	return nil
+}
+
+// serveHTTP starts the HTTP server in the background
+func (s *server) serveHTTP() (err error) {
+	s.listener, err = listener.Listen(s.opt.ListenAddr)
+	if err != nil {
+		return err
+	}
+	s.listener.ServeHTTP(s.httpServer)
+	return nil
+}
+
+// Addr returns the address the HTTP server is listening on
+func (s *server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// location returns the URL of the root device description as seen
+// from an interface with address ip.  If the server is only listening
+// on one address then that is used instead.
+func (s *server) location(ip net.IP) string {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	if !addr.IP.IsUnspecified() {
+		ip = addr.IP
+	}
+	return "http://" + net.JoinHostPort(ip.String(), strconv.Itoa(addr.Port)) + rootDescPath
+}
+
+// Wait blocks while the server is running
+func (s *server) Wait() {
+	if s.listener == nil {
+		return
+	}
+	s.listener.Wait()
+}
+
+// Close shuts the running server down, sending SSDP byebye
+// notifications first
+func (s *server) Close() {
+	s.stopSSDP()
+	if s.listener == nil {
+		return
+	}
+	err := s.listener.Close()
+	if err != nil {
+		fs.Errorf(s.f, "Error on closing DLNA server: %v", err)
+	}
+}
+
+// serverString describes this server in the Server header of HTTP
+// responses and SSDP packets
+func serverString() string {
+	return fmt.Sprintf("%s/%s UPnP/1.0 DLNADOC/1.50 rclone/%s", runtime.GOOS, runtime.GOARCH, fs.Version)
+}
@@ -0,0 +1,285 @@
+package dlna
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	ssdpAddr             = "239.255.255.250:1900"
+	ssdpMaxAge           = 30 * time.Minute
+	ssdpNotifyInterval   = 5 * time.Minute
+	ssdpMaxResponseDelay = 5 * time.Second
+)
+
+// ssdpConn is a connection the server does SSDP discovery on
+type ssdpConn struct {
+	conn  net.PacketConn
+	ipNet *net.IPNet    // network of the interface - nil to answer everyone
+	ip    net.IP        // address of the interface to advertise
+	group *net.UDPAddr  // multicast group to notify - nil for no notifications
+	done  chan struct{} // closed to stop the connection
+	wg    sync.WaitGroup
+}
+
+// ssdpTargets returns the search targets the server responds to
+func (s *server) ssdpTargets() []string {
+	targets := []string{"upnp:rootdevice", s.rootDeviceUUID, deviceType}
+	for _, service := range services {
+		targets = append(targets, service.ServiceType)
+	}
+	return targets
+}
+
+// usn returns the unique service name for target
+func (s *server) usn(target string) string {
+	if target == s.rootDeviceUUID {
+		return target
+	}
+	return s.rootDeviceUUID + "::" + target
+}
+
+// interfaceIPv4 returns the first IPv4 network of iface or nil if
+// it doesn't have one
+func interfaceIPv4(iface *net.Interface) *net.IPNet {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+			return ipNet
+		}
+	}
+	return nil
+}
+
+// startSSDP starts SSDP discovery on all the multicast interfaces
+func (s *server) startSSDP() {
+	group, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		fs.Errorf(s.f, "Failed to resolve SSDP address: %v", err)
+		return
+	}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		fs.Errorf(s.f, "Failed to list network interfaces for SSDP: %v", err)
+		return
+	}
+	for i := range ifaces {
+		iface := &ifaces[i]
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		ipNet := interfaceIPv4(iface)
+		if ipNet == nil {
+			continue
+		}
+		conn, err := net.ListenMulticastUDP("udp4", iface, group)
+		if err != nil {
+			fs.Errorf(s.f, "Failed to start SSDP on interface %q: %v", iface.Name, err)
+			continue
+		}
+		p := ipv4.NewPacketConn(conn)
+		if err := p.SetMulticastInterface(iface); err != nil {
+			fs.Debugf(s.f, "Failed to set SSDP multicast interface %q: %v", iface.Name, err)
+		}
+		if err := p.SetMulticastTTL(2); err != nil {
+			fs.Debugf(s.f, "Failed to set SSDP multicast TTL on %q: %v", iface.Name, err)
+		}
+		fs.Debugf(s.f, "Started SSDP on interface %q (%v)", iface.Name, ipNet.IP)
+		s.startSSDPConn(conn, ipNet, group)
+	}
+	s.mu.Lock()
+	n := len(s.ssdpConns)
+	s.mu.Unlock()
+	if n == 0 {
+		fs.Logf(s.f, "No network interfaces available for SSDP - DLNA clients won't be able to discover the server")
+	}
+}
+
+// startSSDPConn starts answering searches on conn advertising ipNet.
+// If group is set then the server is announced to it periodically.
+func (s *server) startSSDPConn(conn net.PacketConn, ipNet *net.IPNet, group *net.UDPAddr) *ssdpConn {
+	c := &ssdpConn{
+		conn:  conn,
+		ipNet: ipNet,
+		ip:    ipNet.IP,
+		group: group,
+		done:  make(chan struct{}),
+	}
+	if group == nil {
+		// Multicast connections see the searches from all the
+		// interfaces so only those restrict who they answer
+		c.ipNet = nil
+	}
+	s.mu.Lock()
+	s.ssdpConns = append(s.ssdpConns, c)
+	s.mu.Unlock()
+	c.wg.Add(1)
+	go s.ssdpReadLoop(c)
+	if group != nil {
+		c.wg.Add(1)
+		go s.ssdpNotifyLoop(c)
+	}
+	return c
+}
+
+// stopSSDP sends byebye notifications and stops all the SSDP
+// connections
+func (s *server) stopSSDP() {
+	s.mu.Lock()
+	conns := s.ssdpConns
+	s.ssdpConns = nil
+	s.mu.Unlock()
+	for _, c := range conns {
+		close(c.done)
+		if c.group == nil {
+			// otherwise ssdpNotifyLoop closes the connection
+			_ = c.conn.Close()
+		}
+		c.wg.Wait()
+	}
+}
+
+// ssdpReadLoop reads SSDP packets from c until it is closed
+func (s *server) ssdpReadLoop(c *ssdpConn) {
+	defer c.wg.Done()
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := c.conn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-c.done:
+				return
+			default:
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			fs.Errorf(s.f, "SSDP read failed: %v", err)
+			return
+		}
+		s.handleSSDPPacket(c, buf[:n], addr)
+	}
+}
+
+// handleSSDPPacket answers packet from addr if it is a search for
+// the server
+func (s *server) handleSSDPPacket(c *ssdpConn, packet []byte, addr net.Addr) {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(packet)))
+	if err != nil {
+		fs.Debugf(s.f, "%v: failed to parse SSDP packet: %v", addr, err)
+		return
+	}
+	if req.Method != "M-SEARCH" || req.Header.Get("MAN") != `"ssdp:discover"` {
+		return
+	}
+	if udpAddr, ok := addr.(*net.UDPAddr); ok && c.ipNet != nil && !c.ipNet.Contains(udpAddr.IP) {
+		// another interface will answer this
+		return
+	}
+	st := req.Header.Get("ST")
+	var targets []string
+	for _, target := range s.ssdpTargets() {
+		if st == "ssdp:all" || st == target {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	// Delay the responses by a random amount up to MX seconds as
+	// required by the spec so clients aren't flooded
+	var delay time.Duration
+	if mx, err := strconv.Atoi(req.Header.Get("MX")); err == nil && mx > 0 {
+		maxDelay := time.Duration(mx) * time.Second
+		if maxDelay > ssdpMaxResponseDelay {
+			maxDelay = ssdpMaxResponseDelay
+		}
+		delay = time.Duration(rand.Int63n(int64(maxDelay)))
+	}
+	fs.Debugf(s.f, "%v: SSDP search for %q", addr, st)
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		select {
+		case <-time.After(delay):
+		case <-c.done:
+			return
+		}
+		for _, target := range targets {
+			_, err := c.conn.WriteTo(s.ssdpResponse(c, target), addr)
+			if err != nil {
+				fs.Debugf(s.f, "%v: failed to send SSDP response: %v", addr, err)
+				return
+			}
+		}
+	}()
+}
+
+// ssdpResponse makes the response to a search for target
+func (s *server) ssdpResponse(c *ssdpConn, target string) []byte {
+	return []byte(fmt.Sprintf("HTTP/1.1 200 OK\r\n"+
+		"CACHE-CONTROL: max-age=%d\r\n"+
+		"DATE: %s\r\n"+
+		"EXT:\r\n"+
+		"LOCATION: %s\r\n"+
+		"SERVER: %s\r\n"+
+		"ST: %s\r\n"+
+		"USN: %s\r\n"+
+		"\r\n",
+		int(ssdpMaxAge.Seconds()), time.Now().UTC().Format(http.TimeFormat), s.location(c.ip), serverString(), target, s.usn(target)))
+}
+
+// ssdpNotify sends notifications of type nts ("ssdp:alive" or
+// "ssdp:byebye") for all the targets to the multicast group
+func (s *server) ssdpNotify(c *ssdpConn, nts string) {
+	for _, target := range s.ssdpTargets() {
+		packet := fmt.Sprintf("NOTIFY * HTTP/1.1\r\n"+
+			"HOST: %s\r\n"+
+			"NT: %s\r\n"+
+			"NTS: %s\r\n"+
+			"USN: %s\r\n", ssdpAddr, target, nts, s.usn(target))
+		if nts == "ssdp:alive" {
+			packet += fmt.Sprintf("CACHE-CONTROL: max-age=%d\r\n"+
+				"LOCATION: %s\r\n"+
+				"SERVER: %s\r\n", int(ssdpMaxAge.Seconds()), s.location(c.ip), serverString())
+		}
+		packet += "\r\n"
+		_, err := c.conn.WriteTo([]byte(packet), c.group)
+		if err != nil {
+			fs.Debugf(s.f, "Failed to send SSDP %s: %v", nts, err)
+			return
+		}
+	}
+}
+
+// ssdpNotifyLoop announces the server periodically until c is
+// stopped when it says byebye and closes the connection
+func (s *server) ssdpNotifyLoop(c *ssdpConn) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(ssdpNotifyInterval)
+	defer ticker.Stop()
+	s.ssdpNotify(c, "ssdp:alive")
+	for {
+		select {
+		case <-ticker.C:
+			s.ssdpNotify(c, "ssdp:alive")
+		case <-c.done:
+			s.ssdpNotify(c, "ssdp:byebye")
+			_ = c.conn.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package ftp implements an FTP server to serve an rclone VFS
+package ftp
+
+import (
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/ncw/rclone/vfs/vfsflags"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Options contains options for the FTP server
+type Options struct {
+	ListenAddr   string // Port to listen on
+	PublicIP     string // Public IP address to advertise for passive connections
+	PassivePorts string // Passive ports range
+	BasicUser    string // single username for basic auth
+	BasicPass    string // password for BasicUser
+	TLSCert      string // TLS PEM key (concatenation of certificate and CA certificate)
+	TLSKey       string // TLS PEM Private key
+}
+
+// anonymousUser is the only user which may log in without a --pass
+const anonymousUser = "anonymous"
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	ListenAddr:   "localhost:2121",
+	PublicIP:     "",
+	PassivePorts: "30000-32000",
+	BasicUser:    anonymousUser,
+	BasicPass:    "",
+}
+
+// Opt is options set by command line flags
+var Opt = DefaultOpt
+
+// AddFlags adds flags for ftp
+func AddFlags(flagSet *pflag.FlagSet) {
+	fs.StringVarP(flagSet, &Opt.ListenAddr, "addr", "", Opt.ListenAddr, "IPaddress:Port or :Port to bind server to.")
+	fs.StringVarP(flagSet, &Opt.PublicIP, "public-ip", "", Opt.PublicIP, "Public IP address to advertise for passive connections.")
+	fs.StringVarP(flagSet, &Opt.PassivePorts, "passive-port", "", Opt.PassivePorts, "Passive port range to use.")
+	fs.StringVarP(flagSet, &Opt.BasicUser, "user", "", Opt.BasicUser, "User name for authentication.")
+	fs.StringVarP(flagSet, &Opt.BasicPass, "pass", "", Opt.BasicPass, "Password for authentication. (empty value allows every password for the anonymous user)")
+	fs.StringVarP(flagSet, &Opt.TLSCert, "cert", "", Opt.TLSCert, "TLS PEM key (concatenation of certificate and CA certificate)")
+	fs.StringVarP(flagSet, &Opt.TLSKey, "key", "", Opt.TLSKey, "TLS PEM Private key")
+}
+
+func init() {
+	vfsflags.AddFlags(Command.Flags())
+	AddFlags(Command.Flags())
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "ftp remote:path",
+	Short: `Serve remote:path over FTP.`,
+	Long: `
+rclone serve ftp implements a basic ftp server to serve the
+remote over FTP protocol. This can be viewed with a ftp client
+or you can make a remote of type ftp to read and write it.
+
+### Server options
+
+Use --addr to specify which IP address and port the server should
+listen on, eg --addr 1.2.3.4:8000 or --addr :8080 to listen to all
+IPs.  By default it only listens on localhost.  You can use port
+:0 to let the OS choose an available port.
+
+If you set --addr to listen on a public or LAN accessible IP address
+then using Authentication is advised - see the next section for info.
+
+Data connections are made in passive mode on a port chosen from the
+--passive-port range (default 30000-32000).  The server advertises
+the IP address the client connected to for passive connections
+unless --public-ip is set, which is useful if the server is behind
+NAT.  Active mode (PORT/EPRT) is also supported.  Data connections
+are only made to or accepted from the client's own IP address.
+
+#### Authentication
+
+By default this will serve files to the user "anonymous" with any
+password, as is usual for anonymous FTP.
+
+You can set a single username and password with the --user and --pass
+flags.  --pass is needed for any user other than "anonymous".  If you
+set --pass for "anonymous" then only that password will be accepted.
+
+If TLS is configured (see below) then clients must use AUTH TLS
+before they can log in so passwords are never sent in the clear.
+
+#### TLS
+
+If you supply --cert and --key then the server will support explicit
+TLS (FTPS) with AUTH TLS, and will refuse logins which haven't used
+it.  Clients can then use PROT P to encrypt the data connections too.
+
+#### Read only
+
+Use --read-only to serve the remote read only - any attempt to change
+it will be refused.
+` + vfs.Help,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		f := cmd.NewFsSrc(args)
+		cmd.Run(false, false, command, func() error {
+			s, err := newServer(f, &Opt)
+			if err != nil {
+				return err
+			}
+			err = s.Serve()
+			if err != nil {
+				return err
+			}
+			s.Wait()
+			return nil
+		})
+	},
+}
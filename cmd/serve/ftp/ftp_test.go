@@ -0,0 +1,79 @@
+// Serve ftp tests set up a server and run the integration tests
+// for the ftp remote against it.
+//
+// We skip tests on platforms with troublesome character mappings
+
+//+build !windows,!darwin
+
+package ftp
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	_ "github.com/ncw/rclone/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// TestFtp runs the ftp server then runs the unit tests for the
+// ftp remote against it.
+func TestFtp(t *testing.T) {
+	fstest.Initialise()
+
+	fremote, _, clean, err := fstest.RandomRemote(*fstest.RemoteName, *fstest.SubDir)
+	assert.NoError(t, err)
+	defer clean()
+
+	err = fremote.Mkdir(context.Background(), "")
+	assert.NoError(t, err)
+
+	// Start the server
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.PassivePorts = ""
+	opt.BasicUser = testUser
+	opt.BasicPass = testPass
+	w, err := newServer(fremote, &opt)
+	require.NoError(t, err)
+	require.NoError(t, w.Serve())
+	defer w.Close()
+	host, port, err := net.SplitHostPort(w.Addr())
+	require.NoError(t, err)
+
+	// Find the directory of the ftp remote to run the tests in
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+	testDir := filepath.Join(pwd, "../../../ftp")
+
+	// Run the ftp tests with an on the fly remote
+	args := []string{"test"}
+	if testing.Verbose() {
+		args = append(args, "-v")
+	}
+	if *fstest.Verbose {
+		args = append(args, "-verbose")
+	}
+	args = append(args, "-remote", "ftptest:")
+	cmd := exec.Command("go", args...)
+	cmd.Dir = testDir
+	cmd.Env = append(os.Environ(),
+		"PWD="+testDir,
+		"RCLONE_CONFIG_FTPTEST_TYPE=ftp",
+		"RCLONE_CONFIG_FTPTEST_HOST="+host,
+		"RCLONE_CONFIG_FTPTEST_PORT="+port,
+		"RCLONE_CONFIG_FTPTEST_USER="+testUser,
+		"RCLONE_CONFIG_FTPTEST_PASS="+fs.MustObscure(testPass),
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) != 0 {
+		t.Logf("\n----------\n%s----------\n", string(out))
+	}
+	assert.NoError(t, err, "Running ftp integration tests")
+}
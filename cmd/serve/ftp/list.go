@@ -0,0 +1,49 @@
+package ftp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/vfs"
+)
+
+// timeFormatMLSD is the time format used in MLSD, MLST and MDTM
+const timeFormatMLSD = "20060102150405"
+
+// listArg removes any ls style options (eg "-la") from the argument
+// to LIST or NLST which some clients send
+func listArg(arg string) string {
+	arg = strings.TrimSpace(arg)
+	for strings.HasPrefix(arg, "-") {
+		i := strings.IndexByte(arg, ' ')
+		if i < 0 {
+			return ""
+		}
+		arg = strings.TrimSpace(arg[i:])
+	}
+	return arg
+}
+
+// lsLine formats node like "ls -l" does for LIST
+//
+// Times more than 6 months old (or in the future) are shown with the
+// year rather than the time as ls does.
+func lsLine(node vfs.Node, now time.Time) string {
+	modTime := node.ModTime().UTC()
+	timeFormat := "Jan _2 15:04"
+	if modTime.Before(now.AddDate(0, -6, 0)) || modTime.After(now.Add(24*time.Hour)) {
+		timeFormat = "Jan _2  2006"
+	}
+	return fmt.Sprintf("%s 1 ftp ftp %12d %s %s", node.Mode(), node.Size(), modTime.Format(timeFormat), node.Name())
+}
+
+// mlsdLine formats the facts for node as used by MLSD and MLST as
+// defined in RFC 3659
+func mlsdLine(node vfs.Node) string {
+	kind := "file"
+	if node.IsDir() {
+		kind = "dir"
+	}
+	return fmt.Sprintf("type=%s;size=%d;modify=%s;", kind, node.Size(), node.ModTime().UTC().Format(timeFormatMLSD))
+}
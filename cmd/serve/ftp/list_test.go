@@ -0,0 +1,21 @@
+package ftp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListArg(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"/dir", "/dir"},
+		{"-la", ""},
+		{"-a -l dir/sub", "dir/sub"},
+	} {
+		assert.Equal(t, test.want, listArg(test.in), test.in)
+	}
+}
@@ -0,0 +1,164 @@
+package ftp
+
+import (
+	"crypto/tls"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ncw/rclone/cmd/serve/listener"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/ncw/rclone/vfs/vfsflags"
+	"github.com/pkg/errors"
+)
+
+// server contains everything to run the server
+type server struct {
+	f         fs.Fs
+	opt       Options
+	vfs       *vfs.VFS
+	tlsConfig *tls.Config           // set if TLS is configured
+	portMin   int                   // first passive port - 0 for any
+	portMax   int                   // last passive port
+	listener  *listener.Listener    // nil until Serve succeeds
+	mu        sync.Mutex            // protects sessions
+	sessions  map[*session]struct{} // active sessions
+}
+
+// newServer makes a new FTP server serving f with the options in opt
+func newServer(f fs.Fs, opt *Options) (*server, error) {
+	s := &server{
+		f:        f,
+		opt:      *opt,
+		sessions: make(map[*session]struct{}),
+	}
+	if s.opt.BasicPass == "" && s.opt.BasicUser != anonymousUser {
+		return nil, errors.Errorf("need --pass to use --user %q - only %q can log in without one", s.opt.BasicUser, anonymousUser)
+	}
+	var err error
+	s.portMin, s.portMax, err = parsePortRange(s.opt.PassivePorts)
+	if err != nil {
+		return nil, err
+	}
+	if s.opt.PublicIP != "" && net.ParseIP(s.opt.PublicIP) == nil {
+		return nil, errors.Errorf("invalid --public-ip %q", s.opt.PublicIP)
+	}
+	if s.opt.TLSCert != "" || s.opt.TLSKey != "" {
+		if s.opt.TLSCert == "" || s.opt.TLSKey == "" {
+			return nil, errors.New("need both --cert and --key to use TLS")
+		}
+		cert, err := tls.LoadX509KeyPair(s.opt.TLSCert, s.opt.TLSKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load --cert and --key")
+		}
+		s.tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+		}
+	}
+	s.vfs = vfs.New(f, &vfsflags.Opt)
+	return s, nil
+}
+
+// parsePortRange parses a port range like "30000-32000" or a single
+// port like "30000".  An empty string means any port and returns 0, 0.
+func parsePortRange(portRange string) (portMin, portMax int, err error) {
+	portRange = strings.TrimSpace(portRange)
+	if portRange == "" {
+		return 0, 0, nil
+	}
+	parts := strings.SplitN(portRange, "-", 2)
+	portMin, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid passive port range %q", portRange)
+	}
+	portMax = portMin
+	if len(parts) == 2 {
+		portMax, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return 0, 0, errors.Errorf("invalid passive port range %q", portRange)
+		}
+	}
+	if portMin <= 0 || portMax > 65535 || portMin > portMax {
+		return 0, 0, errors.Errorf("invalid passive port range %q", portRange)
+	}
+	return portMin, portMax, nil
+}
+
+// listenPassive opens a listener for a passive data connection on
+// host using a port from the passive port range
+func (s *server) listenPassive(host string) (net.Listener, error) {
+	if s.portMin == 0 {
+		return net.Listen("tcp", net.JoinHostPort(host, "0"))
+	}
+	// Start at a random port in the range so concurrent sessions
+	// don't all fight over the first port
+	n := s.portMax - s.portMin + 1
+	start := rand.Intn(n)
+	for i := 0; i < n; i++ {
+		port := s.portMin + (start+i)%n
+		listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err == nil {
+			return listener, nil
+		}
+	}
+	return nil, errors.Errorf("no free passive ports in range %d-%d", s.portMin, s.portMax)
+}
+
+// Serve starts the server listening.  It returns an error if the
+// server couldn't be started, otherwise it serves in the background.
+//
+// Use Wait to block until the server has stopped.
+func (s *server) Serve() (err error) {
+	s.listener, err = listener.Listen(s.opt.ListenAddr)
+	if err != nil {
+		return err
+	}
+	fs.Logf(s.f, "Serving FTP on %s", s.Addr())
+	s.listener.Serve(s.handleConnection)
+	return nil
+}
+
+// handleConnection runs an FTP session on conn until it finishes
+func (s *server) handleConnection(conn net.Conn) {
+	c := newSession(s, conn)
+	s.mu.Lock()
+	s.sessions[c] = struct{}{}
+	s.mu.Unlock()
+	c.serve()
+	s.mu.Lock()
+	delete(s.sessions, c)
+	s.mu.Unlock()
+}
+
+// Addr returns the address the server is listening on
+func (s *server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Wait blocks while the listener is open.
+func (s *server) Wait() {
+	if s.listener == nil {
+		return
+	}
+	s.listener.Wait()
+}
+
+// Close shuts the running server down closing any open sessions
+func (s *server) Close() {
+	if s.listener == nil {
+		return
+	}
+	err := s.listener.Close()
+	if err != nil {
+		fs.Errorf(nil, "Error on closing FTP server: %v", err)
+		return
+	}
+	s.mu.Lock()
+	for c := range s.sessions {
+		_ = c.conn.Close()
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,78 @@
+package ftp
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	_ "github.com/ncw/rclone/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBindAddress = "localhost:0"
+	testUser        = "user"
+	testPass        = "pass"
+)
+
+func TestNewServerOptions(t *testing.T) {
+	fstest.Initialise()
+	dir, err := ioutil.TempDir("", "rclone-serve-ftp")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		user    string
+		pass    string
+		cert    string
+		key     string
+		wantErr bool
+	}{
+		{anonymousUser, "", "", "", false},
+		{anonymousUser, testPass, "", "", false},
+		{testUser, testPass, "", "", false},
+		{testUser, "", "", "", true},
+		{"", "", "", "", true},
+		{anonymousUser, "", "cert.pem", "", true},
+		{anonymousUser, "", "", "key.pem", true},
+	} {
+		opt := DefaultOpt
+		opt.PassivePorts = ""
+		opt.BasicUser = test.user
+		opt.BasicPass = test.pass
+		opt.TLSCert = test.cert
+		opt.TLSKey = test.key
+		_, err := newServer(f, &opt)
+		assert.Equal(t, test.wantErr, err != nil, "%+v: %v", test, err)
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		min     int
+		max     int
+		wantErr bool
+	}{
+		{"", 0, 0, false},
+		{"30000-32000", 30000, 32000, false},
+		{" 2000 - 2001 ", 2000, 2001, false},
+		{"2121", 2121, 2121, false},
+		{"32000-30000", 0, 0, true},
+		{"0-10", 0, 0, true},
+		{"1-65536", 0, 0, true},
+		{"potato", 0, 0, true},
+	} {
+		min, max, err := parsePortRange(test.in)
+		assert.Equal(t, test.wantErr, err != nil, test.in)
+		assert.Equal(t, test.min, min, test.in)
+		assert.Equal(t, test.max, max, test.in)
+	}
+}
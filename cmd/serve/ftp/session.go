@@ -0,0 +1,805 @@
+package ftp
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/pkg/errors"
+)
+
+// dataTimeout is how long to wait for a data connection to be made
+const dataTimeout = 30 * time.Second
+
+// errQuit is returned by a command to end the session
+var errQuit = errors.New("quit")
+
+// session is a single FTP control connection
+type session struct {
+	s          *server
+	conn       net.Conn        // the underlying control connection
+	ctrl       *textproto.Conn // control connection, possibly over TLS
+	what       string          // description for logging
+	user       string          // user name from USER
+	loggedIn   bool            // set if PASS succeeded
+	cwd        string          // current working directory
+	renameFrom string          // path from RNFR
+	restOffset int64           // offset from REST
+	tls        bool            // set if the control connection is using TLS
+	protect    bool            // set if data connections should use TLS
+	pasv       net.Listener    // listener for a passive data connection
+	activeAddr string          // address for an active data connection
+}
+
+// newSession makes a new session for the control connection conn
+func newSession(s *server, conn net.Conn) *session {
+	return &session{
+		s:    s,
+		conn: conn,
+		ctrl: textproto.NewConn(conn),
+		what: fmt.Sprintf("serve ftp %s->%s", conn.RemoteAddr(), conn.LocalAddr()),
+		cwd:  "/",
+	}
+}
+
+// commandFunc is a handler for an FTP command
+type commandFunc func(c *session, arg string) error
+
+// command describes an FTP command
+type command struct {
+	fn        commandFunc
+	needLogin bool // set if the user must be logged in to use it
+}
+
+// commands is all the FTP commands the server implements
+var commands = map[string]command{
+	"ABOR": {(*session).cmdABOR, true},
+	"ALLO": {(*session).cmdALLO, true},
+	"AUTH": {(*session).cmdAUTH, false},
+	"CDUP": {(*session).cmdCDUP, true},
+	"CWD":  {(*session).cmdCWD, true},
+	"DELE": {(*session).cmdDELE, true},
+	"EPRT": {(*session).cmdEPRT, true},
+	"EPSV": {(*session).cmdEPSV, true},
+	"FEAT": {(*session).cmdFEAT, false},
+	"LIST": {(*session).cmdLIST, true},
+	"MDTM": {(*session).cmdMDTM, true},
+	"MKD":  {(*session).cmdMKD, true},
+	"MLSD": {(*session).cmdMLSD, true},
+	"MLST": {(*session).cmdMLST, true},
+	"MODE": {(*session).cmdMODE, true},
+	"NLST": {(*session).cmdNLST, true},
+	"NOOP": {(*session).cmdNOOP, false},
+	"OPTS": {(*session).cmdOPTS, false},
+	"PASS": {(*session).cmdPASS, false},
+	"PASV": {(*session).cmdPASV, true},
+	"PBSZ": {(*session).cmdPBSZ, false},
+	"PORT": {(*session).cmdPORT, true},
+	"PROT": {(*session).cmdPROT, false},
+	"PWD":  {(*session).cmdPWD, true},
+	"QUIT": {(*session).cmdQUIT, false},
+	"REIN": {(*session).cmdREIN, false},
+	"REST": {(*session).cmdREST, true},
+	"RETR": {(*session).cmdRETR, true},
+	"RMD":  {(*session).cmdRMD, true},
+	"RNFR": {(*session).cmdRNFR, true},
+	"RNTO": {(*session).cmdRNTO, true},
+	"SIZE": {(*session).cmdSIZE, true},
+	"STOR": {(*session).cmdSTOR, true},
+	"STRU": {(*session).cmdSTRU, true},
+	"SYST": {(*session).cmdSYST, false},
+	"TYPE": {(*session).cmdTYPE, true},
+	"USER": {(*session).cmdUSER, false},
+	"XCUP": {(*session).cmdCDUP, true},
+	"XCWD": {(*session).cmdCWD, true},
+	"XMKD": {(*session).cmdMKD, true},
+	"XPWD": {(*session).cmdPWD, true},
+	"XRMD": {(*session).cmdRMD, true},
+}
+
+// serve reads and runs commands until the connection is closed
+func (c *session) serve() {
+	defer c.close()
+	fs.Infof(c.what, "Connected")
+	if c.reply(220, "rclone %s FTP server ready", fs.Version) != nil {
+		return
+	}
+	for {
+		line, err := c.ctrl.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				fs.Debugf(c.what, "Failed to read command: %v", err)
+			}
+			return
+		}
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			verb, arg = line[:i], line[i+1:]
+		}
+		verb = strings.ToUpper(verb)
+		if verb == "PASS" {
+			fs.Debugf(c.what, "> PASS ****")
+		} else {
+			fs.Debugf(c.what, "> %s", line)
+		}
+		cmd, ok := commands[verb]
+		switch {
+		case !ok:
+			err = c.reply(502, "Command %q not implemented", verb)
+		case cmd.needLogin && !c.loggedIn:
+			err = c.reply(530, "Please login with USER and PASS")
+		default:
+			err = cmd.fn(c, arg)
+		}
+		if err == errQuit {
+			return
+		} else if err != nil {
+			fs.Debugf(c.what, "Failed to send reply: %v", err)
+			return
+		}
+	}
+}
+
+// close finishes the session
+func (c *session) close() {
+	c.closeData()
+	err := c.ctrl.Close()
+	if err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
+		fs.Debugf(c.what, "Failed to close connection: %v", err)
+	}
+	fs.Infof(c.what, "Disconnected")
+}
+
+// reply sends a single line reply to the client
+func (c *session) reply(code int, format string, a ...interface{}) error {
+	message := fmt.Sprintf(format, a...)
+	fs.Debugf(c.what, "< %d %s", code, message)
+	return c.ctrl.PrintfLine("%d %s", code, message)
+}
+
+// replyLines sends a multi line reply to the client
+func (c *session) replyLines(code int, first string, lines []string, last string) error {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "%d-%s\r\n", code, first)
+	for _, line := range lines {
+		_, _ = fmt.Fprintf(&buf, " %s\r\n", line)
+	}
+	_, _ = fmt.Fprintf(&buf, "%d %s\r\n", code, last)
+	fs.Debugf(c.what, "< %d %s", code, first)
+	_, err := c.ctrl.W.Write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return c.ctrl.W.Flush()
+}
+
+// replyError sends an error from the VFS to the client
+func (c *session) replyError(err error) error {
+	return c.reply(550, "%v", err)
+}
+
+// absPath returns the absolute path of p relative to the current
+// working directory
+func (c *session) absPath(p string) string {
+	if !path.IsAbs(p) {
+		p = path.Join(c.cwd, p)
+	}
+	return path.Clean("/" + p)
+}
+
+// quote quotes a path for use in a 257 reply
+func quote(p string) string {
+	return `"` + strings.Replace(p, `"`, `""`, -1) + `"`
+}
+
+// stat finds the node for the path p
+func (c *session) stat(p string) (vfs.Node, error) {
+	return c.s.vfs.Stat(c.absPath(p))
+}
+
+// takeRest returns the offset from REST and resets it
+func (c *session) takeRest() int64 {
+	offset := c.restOffset
+	c.restOffset = 0
+	return offset
+}
+
+// closeData closes any pending passive listener and forgets any
+// active address
+func (c *session) closeData() {
+	if c.pasv != nil {
+		_ = c.pasv.Close()
+		c.pasv = nil
+	}
+	c.activeAddr = ""
+}
+
+// openData opens the data connection set up by the last PASV, EPSV,
+// PORT or EPRT command
+func (c *session) openData() (conn net.Conn, err error) {
+	switch {
+	case c.pasv != nil:
+		listener := c.pasv
+		c.pasv = nil
+		defer func() {
+			_ = listener.Close()
+		}()
+		if tcpListener, ok := listener.(*net.TCPListener); ok {
+			_ = tcpListener.SetDeadline(time.Now().Add(dataTimeout))
+		}
+		conn, err = c.acceptPassive(listener)
+	case c.activeAddr != "":
+		conn, err = net.DialTimeout("tcp", c.activeAddr, dataTimeout)
+		c.activeAddr = ""
+	default:
+		return nil, errors.New("use PASV, EPSV, PORT or EPRT first")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.protect {
+		conn = tls.Server(conn, c.s.tlsConfig)
+	}
+	return conn, nil
+}
+
+// acceptPassive accepts a data connection on listener, rejecting any
+// which don't come from the client's address
+func (c *session) acceptPassive(listener net.Listener) (net.Conn, error) {
+	remote, ok := c.conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return nil, errors.New("can't find client address")
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+		if ok && addr.IP.Equal(remote.IP) {
+			return conn, nil
+		}
+		fs.Infof(c.what, "Rejected data connection from foreign address %v", conn.RemoteAddr())
+		_ = conn.Close()
+	}
+}
+
+// transfer opens the data connection and calls fn with it, sending
+// the replies to the client
+func (c *session) transfer(fn func(conn net.Conn) error) error {
+	err := c.reply(150, "Opening data connection")
+	if err != nil {
+		return err
+	}
+	conn, err := c.openData()
+	if err != nil {
+		return c.reply(425, "Can't open data connection: %v", err)
+	}
+	err = fn(conn)
+	closeErr := conn.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fs.Errorf(c.what, "Transfer failed: %v", err)
+		return c.reply(451, "Transfer failed: %v", err)
+	}
+	return c.reply(226, "Transfer complete")
+}
+
+// passiveIP returns the IP address to advertise for passive
+// connections
+func (c *session) passiveIP() net.IP {
+	if c.s.opt.PublicIP != "" {
+		return net.ParseIP(c.s.opt.PublicIP)
+	}
+	if addr, ok := c.conn.LocalAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
+
+// listenPassive opens a passive listener returning the port number
+func (c *session) listenPassive() (int, error) {
+	c.closeData()
+	host, _, err := net.SplitHostPort(c.conn.LocalAddr().String())
+	if err != nil {
+		return 0, err
+	}
+	listener, err := c.s.listenPassive(host)
+	if err != nil {
+		return 0, err
+	}
+	c.pasv = listener
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// setActive checks the address for an active data connection is the
+// client's address and remembers it
+func (c *session) setActive(ip net.IP, port int) error {
+	remote, ok := c.conn.RemoteAddr().(*net.TCPAddr)
+	if !ok || !remote.IP.Equal(ip) {
+		return c.reply(500, "Rejected data connection to foreign address")
+	}
+	if port <= 0 || port > 65535 {
+		return c.reply(501, "Bad port number")
+	}
+	c.closeData()
+	c.activeAddr = net.JoinHostPort(ip.String(), strconv.Itoa(port))
+	return c.reply(200, "Active mode data connection to %s", c.activeAddr)
+}
+
+// checkLoginTLS replies with an error and returns false if TLS is
+// configured but the control connection isn't using it yet, so
+// credentials are never sent in the clear.
+func (c *session) checkLoginTLS() (ok bool, err error) {
+	if c.s.tlsConfig != nil && !c.tls {
+		return false, c.reply(534, "Login must be over TLS - use AUTH TLS first")
+	}
+	return true, nil
+}
+
+func (c *session) cmdUSER(arg string) error {
+	if ok, err := c.checkLoginTLS(); !ok {
+		return err
+	}
+	c.user = arg
+	c.loggedIn = false
+	return c.reply(331, "Password required for %s", arg)
+}
+
+func (c *session) cmdPASS(arg string) error {
+	if ok, err := c.checkLoginTLS(); !ok {
+		return err
+	}
+	if c.user == "" {
+		return c.reply(503, "Login with USER first")
+	}
+	opt := &c.s.opt
+	userOK := subtle.ConstantTimeCompare([]byte(c.user), []byte(opt.BasicUser)) == 1
+	// Only the anonymous user may log in with any password -
+	// newServer refuses an empty --pass for anyone else
+	passOK := (opt.BasicPass == "" && opt.BasicUser == anonymousUser) || subtle.ConstantTimeCompare([]byte(arg), []byte(opt.BasicPass)) == 1
+	if !userOK || !passOK {
+		fs.Infof(c.what, "Login failed for user %q", c.user)
+		return c.reply(530, "Login incorrect")
+	}
+	c.loggedIn = true
+	fs.Infof(c.what, "Logged in as %q", c.user)
+	return c.reply(230, "Logged in")
+}
+
+func (c *session) cmdREIN(arg string) error {
+	c.closeData()
+	c.user = ""
+	c.loggedIn = false
+	c.cwd = "/"
+	c.renameFrom = ""
+	c.restOffset = 0
+	return c.reply(220, "Ready for new user")
+}
+
+func (c *session) cmdQUIT(arg string) error {
+	err := c.reply(221, "Goodbye")
+	if err != nil {
+		return err
+	}
+	return errQuit
+}
+
+func (c *session) cmdAUTH(arg string) error {
+	switch strings.ToUpper(arg) {
+	case "TLS", "TLS-C", "SSL":
+	default:
+		return c.reply(504, "Unsupported AUTH type %q", arg)
+	}
+	if c.s.tlsConfig == nil {
+		return c.reply(534, "TLS not configured, use --cert and --key")
+	}
+	if c.tls {
+		return c.reply(503, "Already using TLS")
+	}
+	err := c.reply(234, "AUTH %s successful", arg)
+	if err != nil {
+		return err
+	}
+	tlsConn := tls.Server(c.conn, c.s.tlsConfig)
+	err = tlsConn.Handshake()
+	if err != nil {
+		return errors.Wrap(err, "TLS handshake failed")
+	}
+	c.ctrl = textproto.NewConn(tlsConn)
+	c.tls = true
+	return nil
+}
+
+func (c *session) cmdPBSZ(arg string) error {
+	if !c.tls {
+		return c.reply(503, "PBSZ needs AUTH TLS first")
+	}
+	return c.reply(200, "PBSZ=0")
+}
+
+func (c *session) cmdPROT(arg string) error {
+	switch strings.ToUpper(arg) {
+	case "C":
+		c.protect = false
+	case "P":
+		if !c.tls {
+			return c.reply(503, "PROT P needs AUTH TLS first")
+		}
+		c.protect = true
+	default:
+		return c.reply(504, "Unsupported protection level %q", arg)
+	}
+	return c.reply(200, "Protection level set to %s", strings.ToUpper(arg))
+}
+
+func (c *session) cmdFEAT(arg string) error {
+	features := []string{
+		"EPRT",
+		"EPSV",
+		"MDTM",
+		"MLST type*;size*;modify*;",
+		"PASV",
+		"REST STREAM",
+		"SIZE",
+		"UTF8",
+	}
+	if c.s.tlsConfig != nil {
+		features = append(features, "AUTH TLS", "PBSZ", "PROT")
+	}
+	return c.replyLines(211, "Features:", features, "End")
+}
+
+func (c *session) cmdOPTS(arg string) error {
+	if strings.ToUpper(arg) == "UTF8 ON" {
+		return c.reply(200, "UTF8 mode enabled")
+	}
+	return c.reply(501, "Unsupported option %q", arg)
+}
+
+func (c *session) cmdSYST(arg string) error {
+	return c.reply(215, "UNIX Type: L8")
+}
+
+func (c *session) cmdNOOP(arg string) error {
+	return c.reply(200, "OK")
+}
+
+func (c *session) cmdALLO(arg string) error {
+	return c.reply(202, "No storage allocation necessary")
+}
+
+func (c *session) cmdABOR(arg string) error {
+	c.closeData()
+	return c.reply(226, "No transfer in progress")
+}
+
+func (c *session) cmdTYPE(arg string) error {
+	switch strings.ToUpper(arg) {
+	case "I", "L 8", "A", "A N":
+		return c.reply(200, "Type set to %s", arg)
+	}
+	return c.reply(504, "Unsupported type %q", arg)
+}
+
+func (c *session) cmdMODE(arg string) error {
+	if strings.ToUpper(arg) != "S" {
+		return c.reply(504, "Only stream mode is supported")
+	}
+	return c.reply(200, "Mode set to S")
+}
+
+func (c *session) cmdSTRU(arg string) error {
+	if strings.ToUpper(arg) != "F" {
+		return c.reply(504, "Only file structure is supported")
+	}
+	return c.reply(200, "Structure set to F")
+}
+
+func (c *session) cmdPWD(arg string) error {
+	return c.reply(257, "%s is the current directory", quote(c.cwd))
+}
+
+func (c *session) cmdCWD(arg string) error {
+	p := c.absPath(arg)
+	node, err := c.s.vfs.Stat(p)
+	if err != nil {
+		return c.replyError(err)
+	}
+	if !node.IsDir() {
+		return c.reply(550, "%s is not a directory", p)
+	}
+	c.cwd = p
+	return c.reply(250, "Directory changed to %s", p)
+}
+
+func (c *session) cmdCDUP(arg string) error {
+	return c.cmdCWD("..")
+}
+
+func (c *session) cmdMKD(arg string) error {
+	p := c.absPath(arg)
+	dir, leaf, err := c.s.vfs.StatParent(p)
+	if err != nil {
+		return c.replyError(err)
+	}
+	_, err = dir.Mkdir(leaf)
+	if err != nil {
+		return c.replyError(err)
+	}
+	return c.reply(257, "%s created", quote(p))
+}
+
+func (c *session) cmdRMD(arg string) error {
+	node, err := c.stat(arg)
+	if err != nil {
+		return c.replyError(err)
+	}
+	if !node.IsDir() {
+		return c.reply(550, "%s is not a directory", arg)
+	}
+	err = node.Remove()
+	if err != nil {
+		return c.replyError(err)
+	}
+	return c.reply(250, "Directory removed")
+}
+
+func (c *session) cmdDELE(arg string) error {
+	node, err := c.stat(arg)
+	if err != nil {
+		return c.replyError(err)
+	}
+	if node.IsDir() {
+		return c.reply(550, "%s is a directory", arg)
+	}
+	err = node.Remove()
+	if err != nil {
+		return c.replyError(err)
+	}
+	return c.reply(250, "File deleted")
+}
+
+func (c *session) cmdRNFR(arg string) error {
+	p := c.absPath(arg)
+	_, err := c.s.vfs.Stat(p)
+	if err != nil {
+		return c.replyError(err)
+	}
+	c.renameFrom = p
+	return c.reply(350, "Ready for RNTO")
+}
+
+func (c *session) cmdRNTO(arg string) error {
+	if c.renameFrom == "" {
+		return c.reply(503, "RNFR required first")
+	}
+	from := c.renameFrom
+	c.renameFrom = ""
+	err := c.s.vfs.Rename(from, c.absPath(arg))
+	if err != nil {
+		return c.replyError(err)
+	}
+	return c.reply(250, "File renamed")
+}
+
+func (c *session) cmdSIZE(arg string) error {
+	node, err := c.stat(arg)
+	if err != nil {
+		return c.replyError(err)
+	}
+	if node.IsDir() {
+		return c.reply(550, "%s is a directory", arg)
+	}
+	return c.reply(213, "%d", node.Size())
+}
+
+func (c *session) cmdMDTM(arg string) error {
+	node, err := c.stat(arg)
+	if err != nil {
+		return c.replyError(err)
+	}
+	return c.reply(213, "%s", node.ModTime().UTC().Format(timeFormatMLSD))
+}
+
+func (c *session) cmdREST(arg string) error {
+	offset, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || offset < 0 {
+		return c.reply(501, "Bad offset %q", arg)
+	}
+	c.restOffset = offset
+	return c.reply(350, "Restarting at %d", offset)
+}
+
+func (c *session) cmdPASV(arg string) error {
+	ip := c.passiveIP().To4()
+	if ip == nil {
+		return c.reply(425, "PASV needs an IPv4 address, use EPSV")
+	}
+	port, err := c.listenPassive()
+	if err != nil {
+		return c.reply(425, "Can't open passive connection: %v", err)
+	}
+	return c.reply(227, "Entering Passive Mode (%d,%d,%d,%d,%d,%d)", ip[0], ip[1], ip[2], ip[3], port>>8, port&0xFF)
+}
+
+func (c *session) cmdEPSV(arg string) error {
+	if strings.ToUpper(arg) == "ALL" {
+		return c.reply(200, "EPSV ALL accepted")
+	}
+	port, err := c.listenPassive()
+	if err != nil {
+		return c.reply(425, "Can't open passive connection: %v", err)
+	}
+	return c.reply(229, "Entering Extended Passive Mode (|||%d|)", port)
+}
+
+func (c *session) cmdPORT(arg string) error {
+	parts := strings.Split(arg, ",")
+	if len(parts) != 6 {
+		return c.reply(501, "Bad PORT argument %q", arg)
+	}
+	var b [6]byte
+	for i, part := range parts {
+		n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 8)
+		if err != nil {
+			return c.reply(501, "Bad PORT argument %q", arg)
+		}
+		b[i] = byte(n)
+	}
+	ip := net.IPv4(b[0], b[1], b[2], b[3])
+	return c.setActive(ip, int(b[4])<<8|int(b[5]))
+}
+
+func (c *session) cmdEPRT(arg string) error {
+	// format is <d><net-prt><d><net-addr><d><tcp-port><d>
+	if len(arg) < 2 {
+		return c.reply(501, "Bad EPRT argument %q", arg)
+	}
+	parts := strings.Split(arg[1:len(arg)-1], arg[:1])
+	if len(parts) != 3 {
+		return c.reply(501, "Bad EPRT argument %q", arg)
+	}
+	ip := net.ParseIP(parts[1])
+	port, err := strconv.Atoi(parts[2])
+	if ip == nil || err != nil {
+		return c.reply(501, "Bad EPRT argument %q", arg)
+	}
+	return c.setActive(ip, port)
+}
+
+func (c *session) cmdRETR(arg string) error {
+	offset := c.takeRest()
+	node, err := c.stat(arg)
+	if err != nil {
+		return c.replyError(err)
+	}
+	if node.IsDir() {
+		return c.reply(550, "%s is a directory", arg)
+	}
+	fd, err := node.Open(os.O_RDONLY)
+	if err != nil {
+		return c.replyError(err)
+	}
+	defer func() {
+		err := fd.Close()
+		if err != nil {
+			fs.Errorf(c.what, "Failed to close %q: %v", arg, err)
+		}
+	}()
+	if offset != 0 {
+		_, err = fd.Seek(offset, os.SEEK_SET)
+		if err != nil {
+			return c.replyError(err)
+		}
+	}
+	return c.transfer(func(conn net.Conn) error {
+		_, err := io.Copy(conn, fd)
+		return err
+	})
+}
+
+func (c *session) cmdSTOR(arg string) error {
+	if c.takeRest() != 0 {
+		return c.reply(550, "Resuming uploads is not supported")
+	}
+	fd, err := c.s.vfs.OpenFile(c.absPath(arg), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	if err != nil {
+		return c.replyError(err)
+	}
+	closed := false
+	defer func() {
+		if !closed {
+			_ = fd.Close()
+		}
+	}()
+	return c.transfer(func(conn net.Conn) error {
+		_, err := io.Copy(fd, conn)
+		closed = true
+		closeErr := fd.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	})
+}
+
+// list returns the nodes to list for p.  If p is a directory this
+// will be its contents otherwise it will be the node itself.
+func (c *session) list(p string) (vfs.Nodes, error) {
+	node, err := c.s.vfs.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		return vfs.Nodes{node}, nil
+	}
+	return dir.ReadDirAll()
+}
+
+// sendList sends the listing for arg formatted with format over the
+// data connection
+func (c *session) sendList(arg string, format func(node vfs.Node) string) error {
+	nodes, err := c.list(c.absPath(listArg(arg)))
+	if err != nil {
+		return c.replyError(err)
+	}
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		buf.WriteString(format(node))
+		buf.WriteString("\r\n")
+	}
+	return c.transfer(func(conn net.Conn) error {
+		_, err := conn.Write(buf.Bytes())
+		return err
+	})
+}
+
+func (c *session) cmdLIST(arg string) error {
+	now := time.Now()
+	return c.sendList(arg, func(node vfs.Node) string {
+		return lsLine(node, now)
+	})
+}
+
+func (c *session) cmdNLST(arg string) error {
+	return c.sendList(arg, func(node vfs.Node) string {
+		return node.Name()
+	})
+}
+
+func (c *session) cmdMLSD(arg string) error {
+	node, err := c.stat(arg)
+	if err != nil {
+		return c.replyError(err)
+	}
+	if !node.IsDir() {
+		return c.reply(501, "%s is not a directory", arg)
+	}
+	return c.sendList(arg, func(node vfs.Node) string {
+		return mlsdLine(node) + " " + node.Name()
+	})
+}
+
+func (c *session) cmdMLST(arg string) error {
+	p := c.absPath(arg)
+	node, err := c.s.vfs.Stat(p)
+	if err != nil {
+		return c.replyError(err)
+	}
+	return c.replyLines(250, "Listing "+p, []string{mlsdLine(node) + " " + p}, "End")
+}
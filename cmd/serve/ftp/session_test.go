@@ -0,0 +1,188 @@
+package ftp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession runs a session for s over a pipe returning the client
+// end of the control connection after reading the greeting
+func testSession(t *testing.T, s *server) *textproto.Conn {
+	client, conn := net.Pipe()
+	go newSession(s, conn).serve()
+	ctrl := textproto.NewConn(client)
+	_, _, err := ctrl.ReadResponse(220)
+	require.NoError(t, err)
+	return ctrl
+}
+
+// checkReply sends command and checks the reply has code
+func checkReply(t *testing.T, ctrl *textproto.Conn, command string, code int) {
+	require.NoError(t, ctrl.PrintfLine("%s", command))
+	_, _, err := ctrl.ReadResponse(code)
+	assert.NoError(t, err, command)
+}
+
+func TestFtpLogin(t *testing.T) {
+	opt := DefaultOpt
+	opt.BasicUser = testUser
+	opt.BasicPass = testPass
+	ctrl := testSession(t, &server{opt: opt})
+	defer func() {
+		_ = ctrl.Close()
+	}()
+
+	checkReply(t, ctrl, "PWD", 530)
+	checkReply(t, ctrl, "PASS "+testPass, 503)
+	checkReply(t, ctrl, "USER "+testUser, 331)
+	checkReply(t, ctrl, "PASS wrong", 530)
+	checkReply(t, ctrl, "PWD", 530)
+	checkReply(t, ctrl, "USER wrong", 331)
+	checkReply(t, ctrl, "PASS "+testPass, 530)
+	checkReply(t, ctrl, "USER "+testUser, 331)
+	checkReply(t, ctrl, "PASS ", 530)
+	checkReply(t, ctrl, "USER "+testUser, 331)
+	checkReply(t, ctrl, "PASS "+testPass, 230)
+	checkReply(t, ctrl, "PWD", 257)
+	checkReply(t, ctrl, "REIN", 220)
+	checkReply(t, ctrl, "PWD", 530)
+}
+
+func TestFtpLoginAnonymous(t *testing.T) {
+	// any password is accepted for anonymous with no --pass
+	ctrl := testSession(t, &server{opt: DefaultOpt})
+	defer func() {
+		_ = ctrl.Close()
+	}()
+	checkReply(t, ctrl, "USER "+anonymousUser, 331)
+	checkReply(t, ctrl, "PASS anything", 230)
+	checkReply(t, ctrl, "USER "+testUser, 331)
+	checkReply(t, ctrl, "PASS anything", 530)
+
+	// but only --pass if it is set
+	opt := DefaultOpt
+	opt.BasicPass = testPass
+	ctrl = testSession(t, &server{opt: opt})
+	defer func() {
+		_ = ctrl.Close()
+	}()
+	checkReply(t, ctrl, "USER "+anonymousUser, 331)
+	checkReply(t, ctrl, "PASS anything", 530)
+	checkReply(t, ctrl, "USER "+anonymousUser, 331)
+	checkReply(t, ctrl, "PASS "+testPass, 230)
+}
+
+// makeCert makes a self signed certificate for localhost
+func makeCert(t *testing.T) tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func TestFtpTLS(t *testing.T) {
+	client, conn := net.Pipe()
+	s := &server{
+		opt: DefaultOpt,
+		tlsConfig: &tls.Config{
+			Certificates: []tls.Certificate{makeCert(t)},
+		},
+	}
+	go newSession(s, conn).serve()
+	ctrl := textproto.NewConn(client)
+	_, _, err := ctrl.ReadResponse(220)
+	require.NoError(t, err)
+
+	// check we can't use PROT P or log in before AUTH TLS
+	checkReply(t, ctrl, "PROT P", 503)
+	checkReply(t, ctrl, "USER "+anonymousUser, 534)
+	checkReply(t, ctrl, "PASS anything", 534)
+	checkReply(t, ctrl, "PWD", 530)
+
+	checkReply(t, ctrl, "AUTH TLS", 234)
+	tlsConn := tls.Client(client, &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, tlsConn.Handshake())
+	ctrl = textproto.NewConn(tlsConn)
+	defer func() {
+		_ = ctrl.Close()
+	}()
+
+	checkReply(t, ctrl, "USER "+anonymousUser, 331)
+	checkReply(t, ctrl, "PASS anything", 230)
+	checkReply(t, ctrl, "PBSZ 0", 200)
+	checkReply(t, ctrl, "PROT P", 200)
+	checkReply(t, ctrl, "PWD", 257)
+}
+
+func TestFtpPassiveForeignAddress(t *testing.T) {
+	control, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = control.Close()
+	}()
+	client, err := net.Dial("tcp", control.Addr().String())
+	require.NoError(t, err)
+	defer func() {
+		_ = client.Close()
+	}()
+	serverConn, err := control.Accept()
+	require.NoError(t, err)
+	defer func() {
+		_ = serverConn.Close()
+	}()
+	c := &session{conn: serverConn, what: "test"}
+
+	data, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = data.Close()
+	}()
+
+	// connect from a different loopback address first
+	dialer := net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP("127.0.0.2")}}
+	foreign, err := dialer.Dial("tcp", data.Addr().String())
+	if err != nil {
+		t.Skipf("can't connect from 127.0.0.2: %v", err)
+	}
+	defer func() {
+		_ = foreign.Close()
+	}()
+	local, err := net.Dial("tcp", data.Addr().String())
+	require.NoError(t, err)
+	defer func() {
+		_ = local.Close()
+	}()
+
+	conn, err := c.acceptPassive(data)
+	require.NoError(t, err)
+	assert.Equal(t, local.LocalAddr().String(), conn.RemoteAddr().String())
+	require.NoError(t, conn.Close())
+
+	// the foreign connection was closed
+	_ = foreign.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, err = foreign.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
@@ -3,13 +3,13 @@ package http
 import (
 	"fmt"
 	"html/template"
-	"log"
 	"net/http"
 	"path"
 	"strconv"
 	"strings"
 
 	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/cmd/serve/httplib"
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/vfs"
 	"github.com/ncw/rclone/vfs/vfsflags"
@@ -18,11 +18,11 @@ import (
 
 // Globals
 var (
-	bindAddress = "localhost:8080"
+	httpOpt = httplib.DefaultOpt
 )
 
 func init() {
-	Command.Flags().StringVarP(&bindAddress, "addr", "", bindAddress, "IPaddress:Port to bind server to.")
+	httplib.AddFlags(Command.Flags(), &httpOpt)
 	vfsflags.AddFlags(Command.Flags())
 }
 
@@ -34,10 +34,6 @@ var Command = &cobra.Command{
 over HTTP.  This can be viewed in a web browser or you can make a
 remote of type http read from it.
 
-Use --addr to specify which IP address and port the server should
-listen on, eg --addr 1.2.3.4:8000 or --addr :8080 to listen to all
-IPs.  By default it only listens on localhost.
-
 You can use the filter flags (eg --include, --exclude) to control what
 is served.
 
@@ -45,13 +41,18 @@ The server will log errors.  Use -v to see access logs.
 
 --bwlimit will be respected for file transfers.  Use --stats to
 control the stats printing.
-` + vfs.Help,
+` + httplib.Help + vfs.Help,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
 		f := cmd.NewFsSrc(args)
 		cmd.Run(false, true, command, func() error {
-			s := newServer(f, bindAddress)
-			s.serve()
+			s := newServer(f, &httpOpt)
+			err := s.Serve()
+			if err != nil {
+				return err
+			}
+			fs.Logf(s.f, "Serving on %s", s.URL())
+			s.Wait()
 			return nil
 		})
 	},
@@ -59,33 +60,20 @@ control the stats printing.
 
 // server contains everything to run the server
 type server struct {
-	f           fs.Fs
-	bindAddress string
-	vfs         *vfs.VFS
+	*httplib.Server
+	f   fs.Fs
+	vfs *vfs.VFS
 }
 
-func newServer(f fs.Fs, bindAddress string) *server {
+func newServer(f fs.Fs, opt *httplib.Options) *server {
+	mux := http.NewServeMux()
 	s := &server{
-		f:           f,
-		bindAddress: bindAddress,
-		vfs:         vfs.New(f, &vfsflags.Opt),
+		Server: httplib.NewServer(mux, opt),
+		f:      f,
+		vfs:    vfs.New(f, &vfsflags.Opt),
 	}
-	return s
-}
-
-// serve creates the http server
-func (s *server) serve() {
-	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.handler)
-	// FIXME make a transport?
-	httpServer := &http.Server{
-		Addr:           s.bindAddress,
-		Handler:        mux,
-		MaxHeaderBytes: 1 << 20,
-	}
-	initServer(httpServer)
-	fs.Logf(s.f, "Serving on http://%s/", bindAddress)
-	log.Fatal(httpServer.ListenAndServe())
+	return s
 }
 
 // handler reads incoming requests and dispatches them
@@ -97,7 +85,10 @@ func (s *server) handler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Accept-Ranges", "bytes")
 	w.Header().Set("Server", "rclone/"+fs.Version)
 
-	urlPath := r.URL.Path
+	urlPath, ok := s.Path(w, r)
+	if !ok {
+		return
+	}
 	isDir := strings.HasSuffix(urlPath, "/")
 	remote := strings.Trim(urlPath, "/")
 	if isDir {
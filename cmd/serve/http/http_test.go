@@ -3,12 +3,11 @@ package http
 import (
 	"flag"
 	"io/ioutil"
-	"net"
 	"net/http"
 	"strings"
 	"testing"
-	"time"
 
+	"github.com/ncw/rclone/cmd/serve/httplib"
 	"github.com/ncw/rclone/fs"
 	_ "github.com/ncw/rclone/local"
 	"github.com/stretchr/testify/assert"
@@ -23,23 +22,10 @@ const (
 )
 
 func startServer(t *testing.T, f fs.Fs) {
-	s := newServer(f, testBindAddress)
-	go s.serve()
-
-	// try to connect to the test server
-	pause := time.Millisecond
-	for i := 0; i < 10; i++ {
-		conn, err := net.Dial("tcp", testBindAddress)
-		if err == nil {
-			_ = conn.Close()
-			return
-		}
-		// t.Logf("couldn't connect, sleeping for %v: %v", pause, err)
-		time.Sleep(pause)
-		pause *= 2
-	}
-	t.Fatal("couldn't connect to server")
-
+	opt := httplib.DefaultOpt
+	opt.ListenAddr = testBindAddress
+	s := newServer(f, &opt)
+	require.NoError(t, s.Serve())
 }
 
 func TestInit(t *testing.T) {
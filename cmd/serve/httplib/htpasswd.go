@@ -0,0 +1,156 @@
+package httplib
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// htpasswd is the user and password hashes read from an htpasswd file
+type htpasswd map[string]string
+
+// readHtpasswd reads the htpasswd file in path
+//
+// It understands the bcrypt, SHA1, MD5 (apr1) and crypt MD5 formats
+// that the Apache htpasswd tool produces.
+func readHtpasswd(path string) (htpasswd, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open htpasswd file")
+	}
+	defer func() {
+		_ = fd.Close()
+	}()
+	users := htpasswd{}
+	scanner := bufio.NewScanner(fd)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.IndexRune(line, ':')
+		if i <= 0 {
+			return nil, errors.Errorf("%s:%d: malformed htpasswd line", path, lineNumber)
+		}
+		users[line[:i]] = line[i+1:]
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read htpasswd file")
+	}
+	return users, nil
+}
+
+// check returns true if the password matches the hash for user
+func (h htpasswd) check(user, password string) bool {
+	hash, ok := h[user]
+	if !ok {
+		return false
+	}
+	return checkPassword(password, hash)
+}
+
+// checkPassword returns true if password matches the htpasswd hash
+func checkPassword(password, hash string) bool {
+	var got string
+	switch {
+	case strings.HasPrefix(hash, "$2y$") || strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$"):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		got = "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+	case strings.HasPrefix(hash, "$apr1$"):
+		got = md5Crypt(password, hash, "$apr1$")
+	case strings.HasPrefix(hash, "$1$"):
+		got = md5Crypt(password, hash, "$1$")
+	default:
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(hash)) == 1
+}
+
+// itoa64 is the alphabet used to encode md5Crypt output
+const itoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// md5Crypt works out the MD5 based crypt of password using the salt
+// found in hash and the magic string passed in ("$1$" or "$apr1$")
+func md5Crypt(password, hash, magic string) string {
+	salt := strings.TrimPrefix(hash, magic)
+	if i := strings.IndexRune(salt, '$'); i >= 0 {
+		salt = salt[:i]
+	}
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+	pw := []byte(password)
+
+	alt := md5.New()
+	_, _ = alt.Write(pw)
+	_, _ = alt.Write([]byte(salt))
+	_, _ = alt.Write(pw)
+	altSum := alt.Sum(nil)
+
+	ctx := md5.New()
+	_, _ = ctx.Write(pw)
+	_, _ = ctx.Write([]byte(magic + salt))
+	for i := len(pw); i > 0; i -= 16 {
+		n := i
+		if n > 16 {
+			n = 16
+		}
+		_, _ = ctx.Write(altSum[:n])
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			_, _ = ctx.Write([]byte{0})
+		} else {
+			_, _ = ctx.Write(pw[:1])
+		}
+	}
+	final := ctx.Sum(nil)
+
+	// Slow things down
+	for i := 0; i < 1000; i++ {
+		round := md5.New()
+		if i&1 != 0 {
+			_, _ = round.Write(pw)
+		} else {
+			_, _ = round.Write(final)
+		}
+		if i%3 != 0 {
+			_, _ = round.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			_, _ = round.Write(pw)
+		}
+		if i&1 != 0 {
+			_, _ = round.Write(final)
+		} else {
+			_, _ = round.Write(pw)
+		}
+		final = round.Sum(nil)
+	}
+
+	out := make([]byte, 0, 22)
+	to64 := func(v uint32, n int) {
+		for ; n > 0; n-- {
+			out = append(out, itoa64[v&0x3f])
+			v >>= 6
+		}
+	}
+	to64(uint32(final[0])<<16|uint32(final[6])<<8|uint32(final[12]), 4)
+	to64(uint32(final[1])<<16|uint32(final[7])<<8|uint32(final[13]), 4)
+	to64(uint32(final[2])<<16|uint32(final[8])<<8|uint32(final[14]), 4)
+	to64(uint32(final[3])<<16|uint32(final[9])<<8|uint32(final[15]), 4)
+	to64(uint32(final[4])<<16|uint32(final[10])<<8|uint32(final[5]), 4)
+	to64(uint32(final[11]), 2)
+	return magic + salt + "$" + string(out)
+}
@@ -0,0 +1,312 @@
+// Package httplib provides common functionality for the http based
+// serve commands: listening, TLS, authentication and base URLs
+package httplib
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/cmd/serve/listener"
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// Help contains text describing the http server to add to the command
+// help.
+var Help = `
+### Server options
+
+Use --addr to specify which IP address and port the server should
+listen on, eg --addr 1.2.3.4:8000 or --addr :8080 to listen to all
+IPs.  By default it only listens on localhost.
+
+If you set --addr to listen on a public or LAN accessible IP address
+then using Authentication is advised - see the next section for info.
+
+--server-read-timeout and --server-write-timeout can be used to
+control the timeouts on the server.  Note that this is the total time
+for a transfer.
+
+--max-header-bytes controls the maximum number of bytes the server will
+accept in the HTTP header.
+
+--baseurl controls the URL prefix that rclone serves from.  By default
+rclone will serve from the root.  If you used --baseurl "/rclone" then
+rclone would serve from a URL starting with "/rclone/".  This is
+useful if you wish to proxy rclone serve.  Rclone automatically
+inserts leading and trailing "/" on --baseurl, so --baseurl "rclone",
+--baseurl "/rclone" and --baseurl "/rclone/" are all treated
+identically.
+
+#### Authentication
+
+By default this will serve files without needing a login.
+
+You can either use an htpasswd file which can take lots of users, or
+set a single username and password with the --user and --pass flags.
+
+Use --htpasswd /path/to/htpasswd to provide an htpasswd file.  This is
+in standard apache format and supports MD5, SHA1 and BCrypt for basic
+authentication.  Bcrypt is recommended.
+
+To create an htpasswd file:
+
+    touch htpasswd
+    htpasswd -B htpasswd user
+    htpasswd -B htpasswd anotherUser
+
+Use --realm to set the authentication realm.
+
+#### SSL/TLS
+
+By default this will serve over http.  If you want you can serve over
+https.  You will need to supply the --cert and --key flags.  If you
+wish to do client side certificate validation then you will need to
+supply --client-ca also.
+
+--cert should be either a PEM encoded certificate or a concatenation
+of that with the CA certificate.  --key should be the PEM encoded
+private key and --client-ca should be the PEM encoded client
+certificate authority certificate.
+`
+
+// Options contains options for the http Server
+type Options struct {
+	ListenAddr         string        // Port to listen on
+	BaseURL            string        // prefix to strip from URLs
+	ServerReadTimeout  time.Duration // Timeout for server reading data
+	ServerWriteTimeout time.Duration // Timeout for server writing data
+	MaxHeaderBytes     int           // Maximum size of request header
+	SslCert            string        // SSL PEM key (concatenation of certificate and CA certificate)
+	SslKey             string        // SSL PEM Private key
+	ClientCA           string        // Client certificate authority to verify clients with
+	HtPasswd           string        // htpasswd file - if not provided no authentication is done
+	Realm              string        // realm for authentication
+	BasicUser          string        // single username for basic auth if not using Htpasswd
+	BasicPass          string        // password for BasicUser
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	ListenAddr:         "localhost:8080",
+	Realm:              "rclone",
+	ServerReadTimeout:  1 * time.Hour,
+	ServerWriteTimeout: 1 * time.Hour,
+	MaxHeaderBytes:     4096,
+}
+
+// AddFlags adds the flags for the http server to flags, setting the
+// values in opt
+func AddFlags(flags *pflag.FlagSet, opt *Options) {
+	fs.StringVarP(flags, &opt.ListenAddr, "addr", "", opt.ListenAddr, "IPaddress:Port or :Port to bind server to.")
+	fs.DurationVarP(flags, &opt.ServerReadTimeout, "server-read-timeout", "", opt.ServerReadTimeout, "Timeout for server reading data")
+	fs.DurationVarP(flags, &opt.ServerWriteTimeout, "server-write-timeout", "", opt.ServerWriteTimeout, "Timeout for server writing data")
+	fs.IntVarP(flags, &opt.MaxHeaderBytes, "max-header-bytes", "", opt.MaxHeaderBytes, "Maximum size of request header")
+	fs.StringVarP(flags, &opt.SslCert, "cert", "", opt.SslCert, "SSL PEM key (concatenation of certificate and CA certificate)")
+	fs.StringVarP(flags, &opt.SslKey, "key", "", opt.SslKey, "SSL PEM Private key")
+	fs.StringVarP(flags, &opt.ClientCA, "client-ca", "", opt.ClientCA, "Client certificate authority to verify clients with")
+	fs.StringVarP(flags, &opt.HtPasswd, "htpasswd", "", opt.HtPasswd, "htpasswd file - if not provided no authentication is done")
+	fs.StringVarP(flags, &opt.Realm, "realm", "", opt.Realm, "realm for authentication")
+	fs.StringVarP(flags, &opt.BasicUser, "user", "", opt.BasicUser, "User name for authentication.")
+	fs.StringVarP(flags, &opt.BasicPass, "pass", "", opt.BasicPass, "Password for authentication.")
+	fs.StringVarP(flags, &opt.BaseURL, "baseurl", "", opt.BaseURL, "Prefix for URLs - leave blank for root.")
+}
+
+// Server contains info about the running http server
+type Server struct {
+	Opt        Options
+	handler    http.Handler       // original handler
+	listener   *listener.Listener // nil until Serve succeeds
+	httpServer *http.Server
+	users      htpasswd // users from the htpasswd file if set
+	useSSL     bool     // if server is configured for SSL/TLS
+}
+
+// NewServer creates an http server serving handler.  The opt can be
+// nil in which case the default options will be used.
+//
+// The handler should call Path to find the path of the request with
+// the base URL removed.
+func NewServer(handler http.Handler, opt *Options) *Server {
+	s := &Server{
+		handler: handler,
+	}
+	if opt != nil {
+		s.Opt = *opt
+	} else {
+		s.Opt = DefaultOpt
+	}
+
+	// Make sure BaseURL starts with a / and doesn't end with one
+	s.Opt.BaseURL = strings.Trim(s.Opt.BaseURL, "/")
+	if s.Opt.BaseURL != "" {
+		s.Opt.BaseURL = "/" + s.Opt.BaseURL
+	}
+	s.useSSL = s.Opt.SslKey != "" || s.Opt.SslCert != ""
+	return s
+}
+
+// checkUser returns true if user and pass are allowed in
+func (s *Server) checkUser(user, pass string) bool {
+	if s.users != nil {
+		return s.users.check(user, pass)
+	}
+	userOK := subtle.ConstantTimeCompare([]byte(user), []byte(s.Opt.BasicUser)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(s.Opt.BasicPass)) == 1
+	return userOK && passOK
+}
+
+// authHandler wraps handler so it needs basic authentication
+func (s *Server) authHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || !s.checkUser(user, pass) {
+			if ok {
+				fs.Infof(r.URL.Path, "%s: Unauthorized request from user %q", r.RemoteAddr, user)
+			}
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", s.Opt.Realm))
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// tlsConfig makes the TLS config from the options
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(s.Opt.SslCert, s.Opt.SslKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load --cert and --key")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+	if s.Opt.ClientCA != "" {
+		caCert, err := ioutil.ReadFile(s.Opt.ClientCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read --client-ca")
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("can't parse client certificate authority")
+		}
+		config.ClientCAs = certPool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// Serve starts the server listening.  It returns an error if the
+// server couldn't be started, otherwise it serves in the background.
+//
+// Use Wait to block until the server has stopped.
+func (s *Server) Serve() error {
+	if (s.Opt.SslCert == "") != (s.Opt.SslKey == "") {
+		return errors.New("need both --cert and --key to use SSL")
+	}
+	if s.Opt.ClientCA != "" && !s.useSSL {
+		return errors.New("can't use --client-ca without --cert and --key")
+	}
+	if s.Opt.BasicPass != "" && s.Opt.BasicUser == "" {
+		return errors.New("need --user to use --pass")
+	}
+	handler := s.handler
+	if s.Opt.HtPasswd != "" {
+		users, err := readHtpasswd(s.Opt.HtPasswd)
+		if err != nil {
+			return err
+		}
+		s.users = users
+		fs.Infof(nil, "Using %q as htpasswd storage", s.Opt.HtPasswd)
+		handler = s.authHandler(handler)
+	} else if s.Opt.BasicUser != "" {
+		fs.Infof(nil, "Using --user %s --pass XXXX as authenticated user", s.Opt.BasicUser)
+		handler = s.authHandler(handler)
+	}
+	s.httpServer = &http.Server{
+		Handler:        handler,
+		MaxHeaderBytes: s.Opt.MaxHeaderBytes,
+		ReadTimeout:    s.Opt.ServerReadTimeout,
+		WriteTimeout:   s.Opt.ServerWriteTimeout,
+	}
+	initServer(s.httpServer)
+	ln, err := net.Listen("tcp", s.Opt.ListenAddr)
+	if err != nil {
+		return errors.Wrap(err, "failed to start server")
+	}
+	if s.useSSL {
+		config, err := s.tlsConfig()
+		if err != nil {
+			_ = ln.Close()
+			return err
+		}
+		s.httpServer.TLSConfig = config
+		ln = tls.NewListener(ln, config)
+	}
+	s.listener = listener.New(ln)
+	s.listener.ServeHTTP(s.httpServer)
+	return nil
+}
+
+// Wait blocks while the server is serving requests.  It returns
+// straight away if the server isn't running.
+func (s *Server) Wait() {
+	if s.listener == nil {
+		return
+	}
+	s.listener.Wait()
+}
+
+// Close shuts the running server down if it is running
+func (s *Server) Close() {
+	if s.listener == nil {
+		return
+	}
+	err := s.listener.Close()
+	if err != nil {
+		fs.Errorf(nil, "Error on closing HTTP server: %v", err)
+	}
+}
+
+// URL returns the serving address of this server
+func (s *Server) URL() string {
+	proto := "http"
+	if s.useSSL {
+		proto = "https"
+	}
+	addr := s.Opt.ListenAddr
+	if s.listener != nil && (strings.HasPrefix(addr, ":") || strings.HasSuffix(addr, ":0")) {
+		// use the actual address if the host or port wasn't set
+		addr = s.listener.Addr().String()
+	}
+	return fmt.Sprintf("%s://%s%s/", proto, addr, s.Opt.BaseURL)
+}
+
+// Path returns the path of the request with the BaseURL removed
+//
+// If it returns false then the path wasn't under the BaseURL and the
+// handler should return as the response has already been sent.
+func (s *Server) Path(w http.ResponseWriter, r *http.Request) (string, bool) {
+	urlPath := r.URL.Path
+	if s.Opt.BaseURL == "" {
+		return urlPath, true
+	}
+	if !strings.HasPrefix(urlPath, s.Opt.BaseURL+"/") {
+		// Redirect if the BaseURL was requested without the /
+		if urlPath == s.Opt.BaseURL {
+			http.Redirect(w, r, s.Opt.BaseURL+"/", http.StatusMovedPermanently)
+			return "", false
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return "", false
+	}
+	return urlPath[len(s.Opt.BaseURL):], true
+}
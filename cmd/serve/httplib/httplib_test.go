@@ -0,0 +1,165 @@
+package httplib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// startServer starts a server with opt which returns the path it was
+// called with
+func startServer(t *testing.T, opt Options) *Server {
+	opt.ListenAddr = "localhost:0"
+	var s *Server
+	s = NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		urlPath, ok := s.Path(w, r)
+		if !ok {
+			return
+		}
+		_, _ = fmt.Fprint(w, urlPath)
+	}), &opt)
+	require.NoError(t, s.Serve())
+	return s
+}
+
+// get fetches path from the server returning the status and body
+func get(t *testing.T, s *Server, path, user, pass string) (int, string) {
+	req, err := http.NewRequest("GET", s.URL()+path, nil)
+	require.NoError(t, err)
+	if user != "" {
+		req.SetBasicAuth(user, pass)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp.StatusCode, string(body)
+}
+
+func TestServeNoAuth(t *testing.T) {
+	s := startServer(t, DefaultOpt)
+	defer s.Close()
+
+	status, body := get(t, s, "file.txt", "", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/file.txt", body)
+}
+
+func TestServeBasicAuth(t *testing.T) {
+	opt := DefaultOpt
+	opt.BasicUser = "user"
+	opt.BasicPass = "pass"
+	s := startServer(t, opt)
+	defer s.Close()
+
+	status, _ := get(t, s, "file.txt", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = get(t, s, "file.txt", "user", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, body := get(t, s, "file.txt", "user", "pass")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/file.txt", body)
+}
+
+func TestServeHtpasswd(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("bcryptpass"), bcrypt.MinCost)
+	require.NoError(t, err)
+	fd, err := ioutil.TempFile("", "rclone-htpasswd")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Remove(fd.Name()))
+	}()
+	_, err = fmt.Fprintf(fd, "# comment\nbcrypt:%s\nsha:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n", hash)
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	opt := DefaultOpt
+	opt.HtPasswd = fd.Name()
+	s := startServer(t, opt)
+	defer s.Close()
+
+	status, _ := get(t, s, "", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = get(t, s, "", "bcrypt", "password")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = get(t, s, "", "bcrypt", "bcryptpass")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = get(t, s, "", "sha", "password")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestServeBaseURL(t *testing.T) {
+	opt := DefaultOpt
+	opt.BaseURL = "/rclone/"
+	s := startServer(t, opt)
+	defer s.Close()
+
+	status, body := get(t, s, "dir/file.txt", "", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/dir/file.txt", body)
+
+	// outside the base URL
+	req, err := http.NewRequest("GET", "http://"+s.listener.Addr().String()+"/file.txt", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// the base URL without a trailing / is redirected
+	req.URL.Path = "/rclone"
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/rclone/", resp.Request.URL.Path)
+}
+
+func TestServeNeedsCertAndKey(t *testing.T) {
+	for _, opt := range []Options{
+		{ListenAddr: "localhost:0", SslCert: "cert.pem"},
+		{ListenAddr: "localhost:0", SslKey: "key.pem"},
+		{ListenAddr: "localhost:0", ClientCA: "ca.pem"},
+		{ListenAddr: "localhost:0", BasicPass: "pass"},
+	} {
+		s := NewServer(http.NotFoundHandler(), &opt)
+		assert.Error(t, s.Serve(), fmt.Sprintf("%+v", opt))
+	}
+}
+
+func TestWaitCloseNotServing(t *testing.T) {
+	s := NewServer(http.NotFoundHandler(), &Options{ListenAddr: "localhost:0", SslKey: "key.pem"})
+	require.Error(t, s.Serve())
+	s.Wait()
+	s.Close()
+}
+
+func TestCheckPassword(t *testing.T) {
+	for _, test := range []struct {
+		password string
+		hash     string
+		want     bool
+	}{
+		{"password", "$apr1$Zx3bNq7L$oO.a3wUhN/lFd0xpe6ZX30", true},
+		{"Password", "$apr1$Zx3bNq7L$oO.a3wUhN/lFd0xpe6ZX30", false},
+		{"secret", "$1$abcdefgh$cHJi5PXp/ki/ktXzqlk6I1", true},
+		{"secrets", "$1$abcdefgh$cHJi5PXp/ki/ktXzqlk6I1", false},
+		{"password", "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=", true},
+		{"password", "plaintext", false},
+	} {
+		got := checkPassword(test.password, test.hash)
+		assert.Equal(t, test.want, got, fmt.Sprintf("%q %q", test.password, test.hash))
+	}
+}
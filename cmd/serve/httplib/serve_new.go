@@ -2,14 +2,14 @@
 
 //+build go1.8
 
-package http
+package httplib
 
 import (
 	"net/http"
 	"time"
 )
 
-// Initialise the http.Server for pre go1.8
+// Initialise the http.Server for go1.8+
 func initServer(s *http.Server) {
 	s.ReadHeaderTimeout = 10 * time.Second // time to send the headers
 	s.IdleTimeout = 60 * time.Second       // time to keep idle connections open
@@ -0,0 +1,122 @@
+// Package listener accepts connections in the background for the
+// serve commands
+package listener
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// Delays used to back off when Accept returns errors, eg when the
+// process has run out of file descriptors
+var (
+	minAcceptDelay = 5 * time.Millisecond
+	maxAcceptDelay = 1 * time.Second
+)
+
+// Listener accepts connections for a serve command in the background
+// until it is closed.
+type Listener struct {
+	listener net.Listener
+	waitChan chan struct{} // closed when the listener has stopped
+	mu       sync.Mutex    // protects closed
+	closed   bool          // set when Close has been called
+}
+
+// Listen starts listening on the TCP address addr.  Use Serve or
+// ServeHTTP to start accepting connections.
+func Listen(addr string) (*Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen for connection")
+	}
+	return New(listener), nil
+}
+
+// New makes a Listener from listener, eg one wrapped with
+// tls.NewListener.
+func New(listener net.Listener) *Listener {
+	return &Listener{
+		listener: listener,
+		waitChan: make(chan struct{}),
+	}
+}
+
+// isClosed returns whether Close has been called
+func (l *Listener) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// Serve calls handle in a new go routine for each connection
+// accepted until the listener is closed.  It returns immediately.
+//
+// If accepting a connection fails it waits before trying again,
+// doubling the wait each time up to a maximum, so errors like running
+// out of file descriptors don't make it spin.
+func (l *Listener) Serve(handle func(conn net.Conn)) {
+	go func() {
+		defer close(l.waitChan)
+		var delay time.Duration
+		for {
+			conn, err := l.listener.Accept()
+			if err != nil {
+				if l.isClosed() {
+					return
+				}
+				if delay == 0 {
+					delay = minAcceptDelay
+				} else {
+					delay *= 2
+				}
+				if delay > maxAcceptDelay {
+					delay = maxAcceptDelay
+				}
+				fs.Errorf(nil, "Failed to accept incoming connection: %v - retrying in %v", err, delay)
+				time.Sleep(delay)
+				continue
+			}
+			delay = 0
+			go handle(conn)
+		}
+	}()
+}
+
+// ServeHTTP serves httpServer on the listener until it is closed.  It
+// returns immediately.
+func (l *Listener) ServeHTTP(httpServer *http.Server) {
+	go func() {
+		err := httpServer.Serve(l.listener)
+		fs.Debugf(nil, "HTTP server stopped: %v", err)
+		close(l.waitChan)
+	}()
+}
+
+// Addr returns the address being listened on
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Wait blocks until the listener has stopped serving
+func (l *Listener) Wait() {
+	<-l.waitChan
+}
+
+// Close closes the listener and waits for it to stop serving
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+	err := l.listener.Close()
+	if err != nil {
+		return err
+	}
+	l.Wait()
+	return nil
+}
@@ -0,0 +1,94 @@
+package listener
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe(t *testing.T) {
+	l, err := Listen("localhost:0")
+	require.NoError(t, err)
+	l.Serve(func(conn net.Conn) {
+		_, _ = conn.Write([]byte("hello"))
+		_ = conn.Close()
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+	require.NoError(t, conn.Close())
+
+	require.NoError(t, l.Close())
+	_, err = net.Dial("tcp", l.Addr().String())
+	assert.Error(t, err)
+}
+
+// errorListener is a net.Listener whose Accept fails errors times
+// before accepting conn
+type errorListener struct {
+	net.Listener
+	mu      sync.Mutex
+	errors  int
+	accepts []time.Time
+	conn    net.Conn
+}
+
+func (l *errorListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.accepts = append(l.accepts, time.Now())
+	if l.errors > 0 {
+		l.errors--
+		return nil, errors.New("too many open files")
+	}
+	if l.conn != nil {
+		conn := l.conn
+		l.conn = nil
+		return conn, nil
+	}
+	return nil, errors.New("no more connections")
+}
+
+func (l *errorListener) Close() error { return nil }
+
+func TestServeBackoff(t *testing.T) {
+	oldMin, oldMax := minAcceptDelay, maxAcceptDelay
+	minAcceptDelay, maxAcceptDelay = 10*time.Millisecond, 40*time.Millisecond
+	defer func() { minAcceptDelay, maxAcceptDelay = oldMin, oldMax }()
+
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	el := &errorListener{errors: 4, conn: server}
+	l := New(el)
+	handled := make(chan struct{})
+	l.Serve(func(conn net.Conn) {
+		close(handled)
+		_ = conn.Close()
+	})
+
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection not handled")
+	}
+	require.NoError(t, l.Close())
+
+	// Check the delays between the failed accepts doubled up to
+	// the maximum: 10, 20, 40, 40ms
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	require.True(t, len(el.accepts) >= 5)
+	for i, want := range []time.Duration{10, 20, 40, 40} {
+		got := el.accepts[i+1].Sub(el.accepts[i])
+		assert.True(t, got >= want*time.Millisecond, "delay %d: want >= %vms got %v", i, want, got)
+	}
+}
@@ -0,0 +1,17 @@
+// HTTP request context go1.7+
+
+//+build go1.7
+
+package restic
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+// requestContext returns the context of r so work is cancelled if the
+// client goes away
+func requestContext(r *http.Request) context.Context {
+	return r.Context()
+}
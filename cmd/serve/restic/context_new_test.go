@@ -0,0 +1,42 @@
+// Tests for the HTTP request context go1.7+
+
+//+build go1.7
+
+package restic
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResticStatsFromContext(t *testing.T) {
+	s, _, cleanup := newTestServer(t, DefaultOpt)
+	defer cleanup()
+
+	w := do(t, s, "POST", "/repo/?create=true", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// transfers are counted in the stats in the request context
+	// and not in the global stats
+	stats := fs.NewStats()
+	withStats := func(r *http.Request) {
+		*r = *r.WithContext(fs.WithStats(r.Context(), stats))
+	}
+	before := fs.Stats.GetBytes()
+	w = do(t, s, "POST", "/repo/data/abcdef", strings.NewReader("0123456789"), withStats)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(10), stats.GetBytes())
+	w = do(t, s, "GET", "/repo/data/abcdef", nil, func(r *http.Request) {
+		withStats(r)
+		r.Header.Set("Range", "bytes=2-5")
+	})
+	require.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "2345", w.Body.String())
+	assert.Equal(t, int64(14), stats.GetBytes())
+	assert.Equal(t, before, fs.Stats.GetBytes())
+}
@@ -0,0 +1,17 @@
+// HTTP request context pre go1.7
+
+//+build !go1.7
+
+package restic
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+// requestContext returns a background context as requests don't have
+// a context before go1.7
+func requestContext(r *http.Request) context.Context {
+	return context.Background()
+}
@@ -0,0 +1,485 @@
+// Package restic serves a remote suitable for use with restic
+package restic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/cmd/serve/httplib"
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/net/context"
+)
+
+// Options contains the options for restic serving
+type Options struct {
+	AppendOnly   bool // if set only objects can be added and locks deleted
+	PrivateRepos bool // if set users can only access their own repo
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{}
+
+// Globals
+var (
+	httpOpt = httplib.DefaultOpt
+	Opt     = DefaultOpt
+)
+
+// AddFlags adds flags for restic
+func AddFlags(flagSet *pflag.FlagSet, opt *Options) {
+	fs.BoolVarP(flagSet, &opt.AppendOnly, "append-only", "", opt.AppendOnly, "disallow deletion of repository data")
+	fs.BoolVarP(flagSet, &opt.PrivateRepos, "private-repos", "", opt.PrivateRepos, "users can only access their private repo")
+}
+
+func init() {
+	httpOpt.ListenAddr = "localhost:8080"
+	httplib.AddFlags(Command.Flags(), &httpOpt)
+	AddFlags(Command.Flags(), &Opt)
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "restic remote:path",
+	Short: `Serve the remote for restic's REST API.`,
+	Long: `rclone serve restic implements restic's REST backend API
+over HTTP.  This allows restic to use rclone as a data storage
+mechanism for cloud providers that restic does not support directly.
+
+[Restic](https://restic.net/) is a command line program for doing
+backups.
+
+The server will log errors.  Use -v to see access logs.
+
+--bwlimit will be respected for file transfers.  Use --stats to
+control the stats printing.
+
+### Setting up rclone for use by restic ###
+
+First [set up a remote for your chosen cloud provider](/docs/#configure).
+
+Once you have set up the remote, check it is working with, for example
+"rclone lsd remote:".  You may have called the remote something other
+than "remote:" - just substitute whatever you called it in the
+following instructions.
+
+Now start the rclone restic server
+
+    rclone serve restic -v remote:backup
+
+Where you can replace "backup" in the above by whatever path in the
+remote you wish to use.
+
+By default this will serve on "localhost:8080" you can change this
+with use of the "--addr" flag.
+
+You might wish to start this server on boot.
+
+### Setting up restic to use rclone ###
+
+Now you can [follow the restic
+instructions](http://restic.readthedocs.io/en/latest/030_preparing_a_new_repo.html#rest-server)
+on setting up restic.
+
+Note that you will need restic 0.8.2 or later to interoperate with
+rclone.
+
+For the example above you will want to use "http://localhost:8080/" as
+the URL for the REST server.
+
+For example:
+
+    $ export RESTIC_REPOSITORY=rest:http://localhost:8080/
+    $ export RESTIC_PASSWORD=yourpassword
+    $ restic init
+    created restic backend 8b1a4b56ae at rest:http://localhost:8080/
+
+    Please note that knowledge of your password is required to access
+    the repository. Losing your password means that your data is
+    irrecoverably lost.
+    $ restic backup /path/to/files/to/backup
+    scan [/path/to/files/to/backup]
+    scanned 189 directories, 312 files in 0:00
+    [0:00] 100.00%  38.128 MiB / 38.128 MiB  501 / 501 items  0 errors  ETA 0:00
+    duration: 0:00
+    snapshot 45c8fdd8 saved
+
+#### Multiple repositories ####
+
+Note that you can use the endpoint to host multiple repositories.  Do
+this by adding a directory name or path after the URL.  Note that
+these **must** end with /.  Eg
+
+    $ export RESTIC_REPOSITORY=rest:http://localhost:8080/user1repo/
+    # backup user1 stuff
+    $ export RESTIC_REPOSITORY=rest:http://localhost:8080/user2repo/
+    # backup user2 stuff
+
+#### Private repositories ####
+
+The "--private-repos" flag can be used to limit users to repositories
+starting with a path of "/<username>/".  It requires authentication to
+be set up with --user/--pass or --htpasswd.
+
+#### Append only ####
+
+Use "--append-only" to stop restic (or anyone else) deleting or
+overwriting data in the repository.  Only the lock files may be
+deleted.  This is useful to protect backups from a compromised client.
+
+#### Streaming uploads ####
+
+If the remote supports streaming uploads then objects are streamed
+straight to the remote without being buffered locally, otherwise
+uploads of unknown length will be spooled to a temporary file first.
+` + httplib.Help,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		f := cmd.NewFsSrc(args)
+		cmd.Run(false, true, command, func() error {
+			if Opt.PrivateRepos && httpOpt.HtPasswd == "" && httpOpt.BasicUser == "" {
+				return errors.New("--private-repos needs authentication set up with --user/--pass or --htpasswd")
+			}
+			s := newServer(f, &httpOpt, &Opt)
+			err := s.Serve()
+			if err != nil {
+				return err
+			}
+			fs.Logf(s.f, "Serving restic REST API on %s", s.URL())
+			s.Wait()
+			return nil
+		})
+	},
+}
+
+const (
+	resticAPIV1 = "application/vnd.x.restic.rest.v1"
+	resticAPIV2 = "application/vnd.x.restic.rest.v2"
+)
+
+// server contains everything to run the server
+type server struct {
+	*httplib.Server
+	f   fs.Fs
+	opt Options
+}
+
+func newServer(f fs.Fs, httpOpt *httplib.Options, opt *Options) *server {
+	mux := http.NewServeMux()
+	s := &server{
+		Server: httplib.NewServer(mux, httpOpt),
+		f:      f,
+		opt:    *opt,
+	}
+	mux.HandleFunc("/", s.handler)
+	return s
+}
+
+// matchData matches the data directory in a restic repository so
+// the first two characters of the object name can be inserted as a
+// sub directory
+var matchData = regexp.MustCompile("(?:^|/)data/([^/]{2,})$")
+
+// makeRemote converts the path of the request into the path of the
+// object in the remote
+func makeRemote(path string) string {
+	path = strings.TrimLeft(path, "/")
+	parts := matchData.FindStringSubmatchIndex(path)
+	// if no data directory, layout is like this
+	// /<repo>/<type>/<name>
+	if parts == nil {
+		return strings.TrimRight(path, "/")
+	}
+	// otherwise it is like this
+	// /<repo>/data/<2 byte hash prefix>/<name>
+	nameStart := parts[2]
+	return path[:nameStart] + path[nameStart:nameStart+2] + "/" + path[nameStart:]
+}
+
+// handler reads incoming requests and dispatches them
+func (s *server) handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("Server", "rclone/"+fs.Version)
+
+	urlPath, ok := s.Path(w, r)
+	if !ok {
+		return
+	}
+	if s.opt.PrivateRepos {
+		user, _, _ := r.BasicAuth()
+		if user == "" || (urlPath != "/"+user && !strings.HasPrefix(urlPath, "/"+user+"/")) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+	}
+	remote := makeRemote(urlPath)
+	fs.Debugf(s.f, "%s %s", r.Method, urlPath)
+
+	// Dispatch on path then method
+	if strings.HasSuffix(urlPath, "/") {
+		switch r.Method {
+		case "GET":
+			s.listObjects(w, r, remote)
+		case "POST":
+			s.createRepo(w, r, remote)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	} else {
+		switch r.Method {
+		case "GET", "HEAD":
+			s.serveObject(w, r, remote)
+		case "POST":
+			s.postObject(w, r, remote)
+		case "DELETE":
+			s.deleteObject(w, r, remote)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// internalError returns an http.StatusInternalServerError and logs the error
+func internalError(ctx context.Context, what interface{}, w http.ResponseWriter, text string, err error) {
+	fs.StatsFromContext(ctx).Error(err)
+	fs.Errorf(what, "%s: %v", text, err)
+	http.Error(w, text+".", http.StatusInternalServerError)
+}
+
+// parseRange parses a single HTTP byte range of the form
+// "bytes=start-end" or "bytes=start-" for an object of size bytes
+// returning the offset and length to read.
+//
+// It returns ok as false if the range is missing or can't be
+// satisfied, in which case the whole object should be returned.
+func parseRange(header string, size int64) (offset, length int64, ok bool) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) || strings.Contains(header, ",") {
+		return 0, 0, false
+	}
+	parts := strings.SplitN(header[len(prefix):], "-", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false
+	}
+	end := size - 1
+	if parts[1] != "" {
+		end, err = strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end - start + 1, true
+}
+
+// serveObject serves the object at remote
+func (s *server) serveObject(w http.ResponseWriter, r *http.Request, remote string) {
+	ctx := requestContext(r)
+	o, err := s.f.NewObject(ctx, remote)
+	if err != nil {
+		fs.Debugf(remote, "%s: File not found", r.RemoteAddr)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	size := o.Size()
+	offset, length, partial := parseRange(r.Header.Get("Range"), size)
+	if !partial {
+		offset, length = 0, size
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if partial {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+	}
+
+	// If HEAD no need to read the object since we have set the headers
+	if r.Method == "HEAD" {
+		return
+	}
+
+	// Ask for the range, but seek too for remotes which only
+	// understand seeking, and never send more than asked for
+	in0, err := o.Open(ctx, &fs.RangeOption{Start: offset, End: offset + length - 1}, &fs.SeekOption{Offset: offset})
+	if err != nil {
+		internalError(ctx, remote, w, "Failed to open object", err)
+		return
+	}
+
+	// Account the transfer
+	stats := fs.StatsFromContext(ctx)
+	stats.Transferring(remote)
+	in := fs.NewAccountSizeName(ctx, in0, length, remote)
+	defer func() {
+		fs.CheckClose(in, &err)
+		stats.DoneTransferring(remote, err == nil)
+	}()
+
+	if partial {
+		w.WriteHeader(http.StatusPartialContent)
+	}
+	_, err = io.Copy(w, io.LimitReader(in, length))
+	if err != nil {
+		fs.Errorf(remote, "Didn't finish writing GET request: %v", err)
+	}
+}
+
+// postObject posts an object to the repository
+func (s *server) postObject(w http.ResponseWriter, r *http.Request, remote string) {
+	ctx := requestContext(r)
+	if s.opt.AppendOnly {
+		// make sure the file does not exist yet
+		_, err := s.f.NewObject(ctx, remote)
+		if err == nil {
+			fs.Errorf(remote, "Post request: file already exists, refusing to overwrite in append-only mode")
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+	}
+
+	var err error
+	modTime := time.Now()
+	if r.ContentLength >= 0 {
+		// Account the transfer
+		stats := fs.StatsFromContext(ctx)
+		stats.Transferring(remote)
+		in := fs.NewAccountSizeName(ctx, r.Body, r.ContentLength, remote)
+		src := fs.NewStaticObjectInfo(remote, modTime, r.ContentLength, true, nil, nil)
+		_, err = s.f.Put(ctx, in, src)
+		fs.CheckClose(in, &err)
+		stats.DoneTransferring(remote, err == nil)
+	} else {
+		// Unknown length so stream it with PutStream if possible -
+		// Rcat does the accounting
+		_, err = fs.Rcat(ctx, s.f, remote, r.Body, modTime)
+	}
+	if err != nil {
+		internalError(ctx, remote, w, "Post request rcat error", err)
+		return
+	}
+}
+
+// deleteObject deletes an object in the repository
+func (s *server) deleteObject(w http.ResponseWriter, r *http.Request, remote string) {
+	ctx := requestContext(r)
+	if s.opt.AppendOnly {
+		parts := strings.Split(remote, "/")
+		// if path doesn't end in "locks/:name", disallow the operation
+		if len(parts) < 2 || parts[len(parts)-2] != "locks" {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+	}
+
+	o, err := s.f.NewObject(ctx, remote)
+	if err != nil {
+		fs.Debugf(remote, "Delete request: %v", err)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err := o.Remove(ctx); err != nil {
+		internalError(ctx, remote, w, "Delete request remove error", err)
+		return
+	}
+}
+
+// listItem is an element returned for the restic v2 list response
+type listItem struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// lastPathElement returns the part of path after the last "/"
+func lastPathElement(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// listObjects lists all Objects of a given type in an arbitrary order.
+func (s *server) listObjects(w http.ResponseWriter, r *http.Request, remote string) {
+	ctx := requestContext(r)
+	fs.Debugf(remote, "list request")
+
+	// make sure an empty list is returned, and not a 'nil' value
+	items := []listItem{}
+
+	// if remote supports ListR use that directly, otherwise use recursive Walk
+	err := fs.Walk(ctx, s.f, remote, true, -1, func(path string, entries fs.DirEntries, err error) error {
+		if err == nil {
+			for _, entry := range entries {
+				if o, ok := entry.(fs.Object); ok {
+					items = append(items, listItem{
+						Name: lastPathElement(o.Remote()),
+						Size: o.Size(),
+					})
+				}
+			}
+		}
+		return err
+	})
+	if err != nil && err != fs.ErrorDirNotFound {
+		internalError(ctx, remote, w, "Failed to list directory", err)
+		return
+	}
+
+	// The v1 API returns just the names, v2 the names and sizes
+	var out interface{} = items
+	contentType := resticAPIV2
+	if r.Header.Get("Accept") != resticAPIV2 {
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		out = names
+		contentType = resticAPIV1
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	err = json.NewEncoder(w).Encode(out)
+	if err != nil {
+		internalError(ctx, remote, w, "Failed to write list", err)
+		return
+	}
+}
+
+// createRepo creates repository directories.
+//
+// We don't bother creating the data dirs as rclone will create them on the fly
+func (s *server) createRepo(w http.ResponseWriter, r *http.Request, remote string) {
+	ctx := requestContext(r)
+	fs.Infof(remote, "Creating repository")
+
+	if r.URL.Query().Get("create") != "true" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	err := s.f.Mkdir(ctx, remote)
+	if err != nil {
+		internalError(ctx, remote, w, "Failed to create repository", err)
+		return
+	}
+	for _, name := range []string{"data", "index", "keys", "locks", "snapshots"} {
+		dirRemote := strings.TrimPrefix(remote+"/"+name, "/")
+		err := s.f.Mkdir(ctx, dirRemote)
+		if err != nil {
+			internalError(ctx, dirRemote, w, "Failed to create repository", err)
+			return
+		}
+	}
+}
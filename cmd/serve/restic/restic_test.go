@@ -0,0 +1,268 @@
+package restic
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ncw/rclone/cmd/serve/httplib"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/ncw/rclone/local"
+)
+
+func TestMain(m *testing.M) {
+	fstest.Initialise()
+	os.Exit(m.Run())
+}
+
+// newTestServer makes a server serving a temporary directory
+// returning the server, the directory and a cleanup function
+func newTestServer(t *testing.T, opt Options) (*server, string, func()) {
+	dir, err := ioutil.TempDir("", "rclone-serve-restic")
+	require.NoError(t, err)
+
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+
+	s := newServer(f, &httplib.DefaultOpt, &opt)
+	return s, dir, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// do makes a request to the server returning the response
+func do(t *testing.T, s *server, method, path string, body io.Reader, setup func(r *http.Request)) *httptest.ResponseRecorder {
+	r, err := http.NewRequest(method, "http://localhost"+path, body)
+	require.NoError(t, err)
+	if setup != nil {
+		setup(r)
+	}
+	w := httptest.NewRecorder()
+	s.handler(w, r)
+	return w
+}
+
+func TestMakeRemote(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"/", ""},
+		{"/data", "data"},
+		{"/data/", "data"},
+		{"/data/1", "data/1"},
+		{"/data/12", "data/12/12"},
+		{"/data/123", "data/12/123"},
+		{"/data/123/", "data/123"},
+		{"/keys/123", "keys/123"},
+		{"/repo/data/123456", "repo/data/12/123456"},
+		{"/repo/config", "repo/config"},
+	} {
+		assert.Equal(t, test.want, makeRemote(test.in), test.in)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		offset int64
+		length int64
+		ok     bool
+	}{
+		{"", 0, 0, false},
+		{"bytes=0-9", 0, 10, true},
+		{"bytes=5-", 5, 5, true},
+		{"bytes=5-100", 5, 5, true},
+		{"bytes=10-", 0, 0, false},
+		{"bytes=-5", 0, 0, false},
+		{"bytes=5-4", 0, 0, false},
+		{"bytes=0-1,3-4", 0, 0, false},
+		{"potato", 0, 0, false},
+	} {
+		offset, length, ok := parseRange(test.in, 10)
+		assert.Equal(t, test.offset, offset, test.in)
+		assert.Equal(t, test.length, length, test.in)
+		assert.Equal(t, test.ok, ok, test.in)
+	}
+}
+
+func TestResticRepository(t *testing.T) {
+	s, dir, cleanup := newTestServer(t, DefaultOpt)
+	defer cleanup()
+
+	// create the repository
+	w := do(t, s, "POST", "/repo/", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(t, s, "POST", "/repo/?create=true", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	for _, name := range []string{"data", "index", "keys", "locks", "snapshots"} {
+		fi, err := os.Stat(filepath.Join(dir, "repo", name))
+		require.NoError(t, err, name)
+		assert.True(t, fi.IsDir(), name)
+	}
+
+	// config
+	w = do(t, s, "GET", "/repo/config", nil, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = do(t, s, "POST", "/repo/config", strings.NewReader("config data"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = do(t, s, "HEAD", "/repo/config", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "11", w.Header().Get("Content-Length"))
+	w = do(t, s, "GET", "/repo/config", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "config data", w.Body.String())
+
+	// data objects are stored in a sub directory of their prefix
+	w = do(t, s, "POST", "/repo/data/abcdef", strings.NewReader("0123456789"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	_, err := os.Stat(filepath.Join(dir, "repo", "data", "ab", "abcdef"))
+	require.NoError(t, err)
+	w = do(t, s, "GET", "/repo/data/abcdef", nil, func(r *http.Request) {
+		r.Header.Set("Range", "bytes=2-5")
+	})
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes 2-5/10", w.Header().Get("Content-Range"))
+	assert.Equal(t, "2345", w.Body.String())
+
+	// upload of unknown length is streamed
+	r := ioutil.NopCloser(strings.NewReader("streamed"))
+	w = do(t, s, "POST", "/repo/data/bcdefg", r, func(r *http.Request) {
+		r.ContentLength = -1
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "repo", "data", "bc", "bcdefg"))
+	require.NoError(t, err)
+	assert.Equal(t, "streamed", string(data))
+
+	// list v1
+	w = do(t, s, "GET", "/repo/data/", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, resticAPIV1, w.Header().Get("Content-Type"))
+	var names []string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &names))
+	sort.Strings(names)
+	assert.Equal(t, []string{"abcdef", "bcdefg"}, names)
+
+	// list v2
+	w = do(t, s, "GET", "/repo/data/", nil, func(r *http.Request) {
+		r.Header.Set("Accept", resticAPIV2)
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, resticAPIV2, w.Header().Get("Content-Type"))
+	var items []listItem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	sizes := map[string]int64{}
+	for _, item := range items {
+		sizes[item.Name] = item.Size
+	}
+	assert.Equal(t, map[string]int64{"abcdef": 10, "bcdefg": 8}, sizes)
+
+	// list of missing directory is empty
+	w = do(t, s, "GET", "/repo/missing/", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[]\n", w.Body.String())
+
+	// delete
+	w = do(t, s, "DELETE", "/repo/data/abcdef", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = do(t, s, "DELETE", "/repo/data/abcdef", nil, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	_, err = os.Stat(filepath.Join(dir, "repo", "data", "ab", "abcdef"))
+	assert.True(t, os.IsNotExist(err))
+
+	w = do(t, s, "PUT", "/repo/config", nil, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestResticAccounting(t *testing.T) {
+	s, _, cleanup := newTestServer(t, DefaultOpt)
+	defer cleanup()
+
+	w := do(t, s, "POST", "/repo/?create=true", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// each transfer is counted in the stats
+	for _, test := range []struct {
+		method string
+		path   string
+		body   io.Reader
+		setup  func(r *http.Request)
+		want   int64
+	}{
+		{"POST", "/repo/data/abcdef", strings.NewReader("0123456789"), nil, 10},
+		{"POST", "/repo/data/bcdefg", ioutil.NopCloser(strings.NewReader("streamed")), func(r *http.Request) {
+			r.ContentLength = -1
+		}, 8},
+		{"GET", "/repo/data/abcdef", nil, nil, 10},
+		{"GET", "/repo/data/abcdef", nil, func(r *http.Request) {
+			r.Header.Set("Range", "bytes=2-5")
+		}, 4},
+	} {
+		what := test.method + " " + test.path
+		before := fs.Stats.GetBytes()
+		w = do(t, s, test.method, test.path, test.body, test.setup)
+		require.True(t, w.Code == http.StatusOK || w.Code == http.StatusPartialContent, what)
+		assert.Equal(t, test.want, fs.Stats.GetBytes()-before, what)
+	}
+}
+
+func TestResticAppendOnly(t *testing.T) {
+	opt := DefaultOpt
+	opt.AppendOnly = true
+	s, _, cleanup := newTestServer(t, opt)
+	defer cleanup()
+
+	w := do(t, s, "POST", "/config", strings.NewReader("config data"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = do(t, s, "POST", "/config", strings.NewReader("overwrite"), nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do(t, s, "DELETE", "/config", nil, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = do(t, s, "GET", "/config", nil, nil)
+	assert.Equal(t, "config data", w.Body.String())
+
+	// locks can be deleted
+	w = do(t, s, "POST", "/locks/1234", strings.NewReader("lock"), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = do(t, s, "DELETE", "/locks/1234", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestResticPrivateRepos(t *testing.T) {
+	opt := DefaultOpt
+	opt.PrivateRepos = true
+	s, _, cleanup := newTestServer(t, opt)
+	defer cleanup()
+
+	for _, test := range []struct {
+		user string
+		path string
+		code int
+	}{
+		{"", "/user/config", http.StatusForbidden},
+		{"user", "/user/config", http.StatusNotFound},
+		{"user", "/user", http.StatusNotFound},
+		{"user", "/user2/config", http.StatusForbidden},
+		{"user", "/config", http.StatusForbidden},
+		{"user2", "/user/config", http.StatusForbidden},
+	} {
+		w := do(t, s, "GET", test.path, nil, func(r *http.Request) {
+			if test.user != "" {
+				r.SetBasicAuth(test.user, "pass")
+			}
+		})
+		assert.Equal(t, test.code, w.Code, test.user+" "+test.path)
+	}
+}
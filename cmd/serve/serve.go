@@ -4,7 +4,11 @@ import (
 	"errors"
 
 	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/cmd/serve/dlna"
+	"github.com/ncw/rclone/cmd/serve/ftp"
 	"github.com/ncw/rclone/cmd/serve/http"
+	"github.com/ncw/rclone/cmd/serve/restic"
+	"github.com/ncw/rclone/cmd/serve/sftp"
 	"github.com/ncw/rclone/cmd/serve/webdav"
 	"github.com/spf13/cobra"
 )
@@ -12,6 +16,10 @@ import (
 func init() {
 	Command.AddCommand(http.Command)
 	Command.AddCommand(webdav.Command)
+	Command.AddCommand(sftp.Command)
+	Command.AddCommand(ftp.Command)
+	Command.AddCommand(restic.Command)
+	Command.AddCommand(dlna.Command)
 	cmd.Root.AddCommand(Command)
 }
 
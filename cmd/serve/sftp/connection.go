@@ -0,0 +1,221 @@
+package sftp
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// describeConn returns a string description of the connection
+func describeConn(c net.Conn) string {
+	return fmt.Sprintf("serve sftp %s->%s", c.RemoteAddr(), c.LocalAddr())
+}
+
+// conn encapsulates a single SSH connection
+type conn struct {
+	what string
+	vfs  *vfs.VFS
+}
+
+// execCommand implements an extremely limited number of commands to
+// interoperate with the rclone sftp backend
+func (c *conn) execCommand(out io.Writer, in io.Reader, command string) (err error) {
+	binary, args := command, ""
+	space := strings.Index(command, " ")
+	if space >= 0 {
+		binary = command[:space]
+		args = strings.TrimSpace(command[space+1:])
+	}
+	fs.Debugf(c.what, "exec command: binary = %q, args = %q", binary, args)
+	switch binary {
+	case "md5sum", "sha1sum":
+		ht := fs.HashMD5
+		if binary == "sha1sum" {
+			ht = fs.HashSHA1
+		}
+		var hashSum string
+		if args == "" {
+			// hash the input like md5sum/sha1sum would
+			hasher, err := fs.NewMultiHasherTypes(fs.NewHashSet(ht))
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(hasher, in)
+			if err != nil {
+				return errors.Wrap(err, "failed to hash input")
+			}
+			hashSum = hasher.Sums()[ht]
+			args = "-"
+		} else {
+			args = shellUnescape(args)
+			node, err := c.vfs.Stat(args)
+			if err != nil {
+				return errors.Wrapf(err, "hash failed finding file %q", args)
+			}
+			if node.IsDir() {
+				return errors.New("can't hash directory")
+			}
+			o, ok := node.DirEntry().(fs.Object)
+			if !ok {
+				return errors.New("unexpected non file")
+			}
+			hashSum, err = o.Hash(ht)
+			if err != nil {
+				return errors.Wrap(err, "hash failed")
+			}
+		}
+		_, err = fmt.Fprintf(out, "%s  %s\n", hashSum, args)
+		if err != nil {
+			return errors.Wrap(err, "send output failed")
+		}
+	case "echo":
+		// special cases for rclone command detection
+		switch args {
+		case "'abc' | md5sum":
+			_, err = fmt.Fprintf(out, "0bee89b07a248e27c83fc3d5951213c1  -\n")
+		case "'abc' | sha1sum":
+			_, err = fmt.Fprintf(out, "03cfd743661f07975fa2f1220c5194cbaff48451  -\n")
+		default:
+			_, err = fmt.Fprintf(out, "%s\n", args)
+		}
+		if err != nil {
+			return errors.Wrap(err, "send output failed")
+		}
+	default:
+		return errors.Errorf("%q not implemented", command)
+	}
+	return nil
+}
+
+// shellUnescapeRegex matches the escapes made by the rclone sftp
+// backend's shellEscape
+var shellUnescapeRegex = regexp.MustCompile(`\\(.)`)
+
+// shellUnescape reverses the escaping the rclone sftp backend does
+// on paths it passes to commands
+func shellUnescape(str string) string {
+	str = strings.Replace(str, "'\n'", "\n", -1)
+	return shellUnescapeRegex.ReplaceAllString(str, `$1`)
+}
+
+// handleChannel is called for each channel opened on the connection
+func (c *conn) handleChannel(newChannel ssh.NewChannel) {
+	fs.Debugf(c.what, "Incoming channel: %s", newChannel.ChannelType())
+	if newChannel.ChannelType() != "session" {
+		err := newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+		fs.Debugf(c.what, "Unknown channel type: %s", newChannel.ChannelType())
+		if err != nil {
+			fs.Errorf(c.what, "Failed to reject unknown channel: %v", err)
+		}
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		fs.Errorf(c.what, "could not accept channel: %v", err)
+		return
+	}
+	defer func() {
+		err := channel.Close()
+		if err != nil && err != io.EOF {
+			fs.Debugf(c.what, "Failed to close channel: %v", err)
+		}
+	}()
+	fs.Debugf(c.what, "Channel accepted")
+
+	isSFTP := make(chan bool, 1)
+	var command execCommandRequest
+
+	// Handle out-of-band requests
+	go func(in <-chan *ssh.Request) {
+		for req := range in {
+			fs.Debugf(c.what, "Request: %v", req.Type)
+			ok := false
+			var subSystemIsSFTP bool
+			var reply []byte
+			switch req.Type {
+			case "subsystem":
+				if len(req.Payload) >= 4 && string(req.Payload[4:]) == "sftp" {
+					ok = true
+					subSystemIsSFTP = true
+				}
+			case "exec":
+				err := ssh.Unmarshal(req.Payload, &command)
+				if err != nil {
+					fs.Errorf(c.what, "ignoring bad exec command: %v", err)
+				} else {
+					ok = true
+					subSystemIsSFTP = false
+				}
+			}
+			fs.Debugf(c.what, " - accepted: %v", ok)
+			err := req.Reply(ok, reply)
+			if err != nil {
+				fs.Errorf(c.what, "Failed to Reply to request: %v", err)
+				return
+			}
+			if ok {
+				// Wake up main routine after we have responded
+				isSFTP <- subSystemIsSFTP
+			}
+		}
+	}(requests)
+
+	// Wait for either subsystem "sftp" or "exec" request
+	if <-isSFTP {
+		fs.Debugf(c.what, "Starting SFTP server")
+		server := sftp.NewRequestServer(channel, newVFSHandler(c.vfs))
+		defer func() {
+			err := server.Close()
+			if err != nil && err != io.EOF {
+				fs.Debugf(c.what, "Failed to close server: %v", err)
+			}
+		}()
+		err = server.Serve()
+		if err == io.EOF || err == nil {
+			fs.Debugf(c.what, "exited session")
+		} else {
+			fs.Errorf(c.what, "completed with error: %v", err)
+		}
+	} else {
+		var rc = uint32(0)
+		err := c.execCommand(channel, channel, command.Command)
+		if err != nil {
+			rc = 1
+			_, errPrint := fmt.Fprintf(channel.Stderr(), "%v\n", err)
+			if errPrint != nil {
+				fs.Errorf(c.what, "Failed to write to stderr: %v", errPrint)
+			}
+			fs.Debugf(c.what, "command %q failed with error: %v", command.Command, err)
+		}
+		_, err = channel.SendRequest("exit-status", false, ssh.Marshal(exitStatus{RC: rc}))
+		if err != nil {
+			fs.Errorf(c.what, "Failed to send exit status: %v", err)
+		}
+	}
+}
+
+// handleChannels services the channels on the connection
+func (c *conn) handleChannels(chans <-chan ssh.NewChannel) {
+	// Service the incoming Channel channel.
+	for newChannel := range chans {
+		go c.handleChannel(newChannel)
+	}
+}
+
+// execCommandRequest is the payload of an "exec" request
+type execCommandRequest struct {
+	Command string
+}
+
+// exitStatus is the payload of an "exit-status" request
+type exitStatus struct {
+	RC uint32
+}
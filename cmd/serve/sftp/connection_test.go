@@ -0,0 +1,72 @@
+package sftp
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	_ "github.com/ncw/rclone/local"
+	"github.com/ncw/rclone/vfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSftpExec(t *testing.T) {
+	fstest.Initialise()
+	dir, err := ioutil.TempDir("", "rclone-serve-sftp")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a file.txt"), []byte("hello"), 0666))
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+	c := &conn{what: "test", vfs: vfs.New(f, nil)}
+
+	run := func(command string, stdin string) (string, error) {
+		var out bytes.Buffer
+		err := c.execCommand(&out, strings.NewReader(stdin), command)
+		return out.String(), err
+	}
+
+	out, err := run(`md5sum a\ file.txt`, "")
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592  a file.txt\n", out)
+
+	out, err = run(`sha1sum /a\ file.txt`, "")
+	require.NoError(t, err)
+	assert.Equal(t, "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d  /a file.txt\n", out)
+
+	out, err = run("md5sum", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592  -\n", out)
+
+	out, err = run("echo 'abc' | md5sum", "")
+	require.NoError(t, err)
+	assert.Equal(t, "0bee89b07a248e27c83fc3d5951213c1  -\n", out)
+
+	_, err = run("md5sum potato", "")
+	assert.Error(t, err)
+
+	_, err = run("rm -rf /", "")
+	assert.Error(t, err)
+}
+
+func TestShellUnescape(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{`file.txt`, `file.txt`},
+		{`dir/a\ file\!.txt`, `dir/a file!.txt`},
+		{`back\\slash`, `back\slash`},
+		{"new'\n'line", "new\nline"},
+	} {
+		assert.Equal(t, test.want, shellUnescape(test.in), test.in)
+	}
+}
@@ -0,0 +1,179 @@
+package sftp
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/ncw/rclone/vfs"
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+)
+
+// vfsHandler converts the VFS to be served by SFTP
+type vfsHandler struct {
+	*vfs.VFS
+}
+
+// newVFSHandler returns a Handlers object serving VFS
+func newVFSHandler(VFS *vfs.VFS) sftp.Handlers {
+	v := vfsHandler{VFS: VFS}
+	return sftp.Handlers{
+		FileGet:  v,
+		FilePut:  v,
+		FileCmd:  v,
+		FileList: v,
+	}
+}
+
+// cleanPath cleans the path p from an SFTP request.  The SFTP server
+// passes on paths like "/." which the VFS can't look up.
+func cleanPath(p string) string {
+	return path.Clean(p)
+}
+
+// Fileread opens the file for reading
+func (v vfsHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	file, err := v.OpenFile(cleanPath(r.Filepath), os.O_RDONLY, 0777)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Filewrite opens the file for writing, creating or truncating it
+func (v vfsHandler) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	file, err := v.OpenFile(cleanPath(r.Filepath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Filecmd implements the commands which don't return anything
+func (v vfsHandler) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Setstat":
+		node, err := v.Stat(cleanPath(r.Filepath))
+		if err != nil {
+			return err
+		}
+		return setStat(node, r.Flags, r.Attrs)
+	case "Rename":
+		return v.Rename(cleanPath(r.Filepath), cleanPath(r.Target))
+	case "Rmdir", "Remove":
+		node, err := v.Stat(cleanPath(r.Filepath))
+		if err != nil {
+			return err
+		}
+		return node.Remove()
+	case "Mkdir":
+		dir, leaf, err := v.StatParent(cleanPath(r.Filepath))
+		if err != nil {
+			return err
+		}
+		_, err = dir.Mkdir(leaf)
+		return err
+	}
+	return errors.Errorf("%s is not supported", r.Method)
+}
+
+// Flags for the attributes in an SFTP setstat request
+const (
+	attrFlagSize        = 0x00000001
+	attrFlagUIDGID      = 0x00000002
+	attrFlagPermissions = 0x00000004
+	attrFlagAcModTime   = 0x00000008
+)
+
+// setStat applies the size and modification time from the SFTP
+// attributes in attrs to node.  The other attributes are ignored.
+func setStat(node vfs.Node, flags uint32, attrs []byte) error {
+	// next returns the next n bytes of attrs
+	next := func(n int) ([]byte, error) {
+		if len(attrs) < n {
+			return nil, errors.New("short setstat attributes")
+		}
+		b := attrs[:n]
+		attrs = attrs[n:]
+		return b, nil
+	}
+	if flags&attrFlagSize != 0 {
+		b, err := next(8)
+		if err != nil {
+			return err
+		}
+		err = node.Truncate(int64(binary.BigEndian.Uint64(b)))
+		if err != nil {
+			return err
+		}
+	}
+	if flags&attrFlagUIDGID != 0 {
+		if _, err := next(8); err != nil {
+			return err
+		}
+	}
+	if flags&attrFlagPermissions != 0 {
+		if _, err := next(4); err != nil {
+			return err
+		}
+	}
+	if flags&attrFlagAcModTime != 0 {
+		b, err := next(8)
+		if err != nil {
+			return err
+		}
+		mtime := time.Unix(int64(binary.BigEndian.Uint32(b[4:])), 0)
+		err = node.SetModTime(mtime)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listerat implements sftp.ListerAt for a slice of os.FileInfo
+type listerat []os.FileInfo
+
+// ListAt copies the entries from offset into ls returning io.EOF
+// when there are no more
+func (f listerat) ListAt(ls []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(f)) {
+		return 0, io.EOF
+	}
+	n := copy(ls, f[offset:])
+	if n < len(ls) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Filelist lists a directory or stats a single node
+func (v vfsHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	node, err := v.Stat(cleanPath(r.Filepath))
+	if err != nil {
+		return nil, err
+	}
+	switch r.Method {
+	case "List":
+		dir, ok := node.(*vfs.Dir)
+		if !ok {
+			return nil, syscall.ENOTDIR
+		}
+		items, err := dir.ReadDirAll()
+		if err != nil {
+			return nil, err
+		}
+		fis := make([]os.FileInfo, 0, len(items))
+		for _, item := range items {
+			fis = append(fis, item)
+		}
+		return listerat(fis), nil
+	case "Stat":
+		return listerat([]os.FileInfo{node}), nil
+	}
+	return nil, errors.Errorf("%s is not supported", r.Method)
+}
@@ -0,0 +1,275 @@
+package sftp
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/ncw/rclone/cmd/serve/listener"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/ncw/rclone/vfs/vfsflags"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// server contains everything to run the server
+type server struct {
+	f        fs.Fs
+	opt      Options
+	vfs      *vfs.VFS
+	config   *ssh.ServerConfig
+	listener *listener.Listener // nil until Serve succeeds
+}
+
+func newServer(f fs.Fs, opt *Options) *server {
+	s := &server{
+		f:   f,
+		vfs: vfs.New(f, &vfsflags.Opt),
+		opt: *opt,
+	}
+	return s
+}
+
+// expandHome replaces a leading ~ in path with the user's home
+// directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home := os.Getenv("HOME")
+	if usr, err := user.Current(); err == nil {
+		home = usr.HomeDir
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// loadAuthorizedKeys reads the authorized keys file returning a map
+// of the marshalled public keys.
+//
+// If the file doesn't exist and it is the default it isn't an error.
+func (s *server) loadAuthorizedKeys() (map[string]struct{}, error) {
+	authorizedKeysMap := map[string]struct{}{}
+	if s.opt.AuthorizedKeys == "" || s.opt.NoAuth {
+		return authorizedKeysMap, nil
+	}
+	authorizedKeysPath := expandHome(s.opt.AuthorizedKeys)
+	authorizedKeysBytes, err := ioutil.ReadFile(authorizedKeysPath)
+	if err != nil {
+		if os.IsNotExist(err) && s.opt.AuthorizedKeys == DefaultOpt.AuthorizedKeys {
+			return authorizedKeysMap, nil
+		}
+		return nil, errors.Wrap(err, "failed to load authorized keys")
+	}
+	fs.Logf(nil, "Loading authorized keys from %q", authorizedKeysPath)
+	for len(authorizedKeysBytes) > 0 {
+		pubKey, _, _, rest, err := ssh.ParseAuthorizedKey(authorizedKeysBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse authorized keys")
+		}
+		authorizedKeysMap[string(pubKey.Marshal())] = struct{}{}
+		authorizedKeysBytes = bytes.TrimSpace(rest)
+	}
+	return authorizedKeysMap, nil
+}
+
+// makeConfig makes the ssh server config with the authentication
+// methods and host keys set up
+func (s *server) makeConfig() error {
+	authorizedKeysMap, err := s.loadAuthorizedKeys()
+	if err != nil {
+		return err
+	}
+	if (s.opt.User == "") != (s.opt.Pass == "") {
+		return errors.New("need both --user and --pass to use password authentication")
+	}
+	if !s.opt.NoAuth && len(authorizedKeysMap) == 0 && s.opt.User == "" {
+		return errors.New("no authorization found, use --user/--pass or --authorized-keys or --no-auth")
+	}
+
+	s.config = &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-rclone-" + fs.Version,
+		NoClientAuth:  s.opt.NoAuth,
+	}
+	if s.opt.User != "" {
+		s.config.PasswordCallback = func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			fs.Debugf(nil, "Password login attempt for %s", c.User())
+			userOK := subtle.ConstantTimeCompare([]byte(c.User()), []byte(s.opt.User)) == 1
+			passOK := subtle.ConstantTimeCompare(pass, []byte(s.opt.Pass)) == 1
+			if userOK && passOK {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("password rejected for %q", c.User())
+		}
+	}
+	if len(authorizedKeysMap) > 0 {
+		s.config.PublicKeyCallback = func(c ssh.ConnMetadata, pubKey ssh.PublicKey) (*ssh.Permissions, error) {
+			fs.Debugf(nil, "Public key login attempt for %s", c.User())
+			if _, ok := authorizedKeysMap[string(pubKey.Marshal())]; ok {
+				return &ssh.Permissions{
+					// Record the public key used for authentication.
+					Extensions: map[string]string{
+						"pubkey-fp": ssh.FingerprintSHA256(pubKey),
+					},
+				}, nil
+			}
+			return nil, fmt.Errorf("unknown public key for %q", c.User())
+		}
+	}
+
+	// Load the private keys, generating them if not supplied
+	keyPaths := s.opt.HostKeys
+	if len(keyPaths) == 0 {
+		cachePath := filepath.Join(fs.CacheDir, "serve-sftp")
+		keyPaths = []string{
+			filepath.Join(cachePath, "id_rsa"),
+			filepath.Join(cachePath, "id_ecdsa"),
+		}
+		for _, keyPath := range keyPaths {
+			err = makeHostKey(keyPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyPath := range keyPaths {
+		private, err := loadPrivateKey(keyPath)
+		if err != nil {
+			return err
+		}
+		s.config.AddHostKey(private)
+	}
+	return nil
+}
+
+// loadPrivateKey reads the private host key in keyPath
+func loadPrivateKey(keyPath string) (ssh.Signer, error) {
+	privateBytes, err := ioutil.ReadFile(expandHome(keyPath))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load private key")
+	}
+	private, err := ssh.ParsePrivateKey(privateBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+	return private, nil
+}
+
+// makeHostKey makes a private host key in keyPath if it doesn't
+// exist already.  The type of key is chosen by the name of the file.
+func makeHostKey(keyPath string) error {
+	if _, err := os.Stat(keyPath); err == nil {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(keyPath), 0700)
+	if err != nil {
+		return errors.Wrap(err, "failed to create host key directory")
+	}
+	var block *pem.Block
+	if strings.HasSuffix(keyPath, "_ecdsa") {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate ECDSA key")
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal ECDSA key")
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	} else {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate RSA key")
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	}
+	err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(block), 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to save host key")
+	}
+	fs.Logf(nil, "Generated new host key %q", keyPath)
+	return nil
+}
+
+// Serve starts the server listening.  It returns an error if the
+// server couldn't be started, otherwise it serves in the background.
+//
+// Use Wait to block until the server has stopped.
+func (s *server) Serve() error {
+	err := s.makeConfig()
+	if err != nil {
+		return err
+	}
+	// Once a ServerConfig has been configured, connections can be
+	// accepted.
+	s.listener, err = listener.Listen(s.opt.ListenAddr)
+	if err != nil {
+		return err
+	}
+	fs.Logf(nil, "SFTP server listening on %v", s.listener.Addr())
+
+	s.listener.Serve(s.acceptConnection)
+	return nil
+}
+
+// acceptConnection does the SSH handshake on nConn then serves its
+// channels
+func (s *server) acceptConnection(nConn net.Conn) {
+	what := describeConn(nConn)
+
+	// Before use, a handshake must be performed on the incoming net.Conn.
+	sshConn, chans, reqs, err := ssh.NewServerConn(nConn, s.config)
+	if err != nil {
+		fs.Errorf(what, "SSH login failed: %v", err)
+		return
+	}
+
+	fs.Infof(what, "SSH login from %s using %s", sshConn.User(), sshConn.ClientVersion())
+
+	// Discard all global out-of-band Requests
+	go ssh.DiscardRequests(reqs)
+
+	c := &conn{
+		what: what,
+		vfs:  s.vfs,
+	}
+
+	// Accept all channels
+	c.handleChannels(chans)
+}
+
+// Addr returns the address the server is listening on
+func (s *server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Wait blocks while the listener is open.
+func (s *server) Wait() {
+	if s.listener == nil {
+		return
+	}
+	s.listener.Wait()
+}
+
+// Close shuts the running server down
+func (s *server) Close() {
+	if s.listener == nil {
+		return
+	}
+	err := s.listener.Close()
+	if err != nil {
+		fs.Errorf(nil, "Error on closing SFTP server: %v", err)
+	}
+}
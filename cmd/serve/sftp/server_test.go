@@ -0,0 +1,75 @@
+package sftp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	testBindAddress = "localhost:0"
+	testUser        = "user"
+	testPass        = "pass"
+)
+
+// connMetadata is the ssh.ConnMetadata for a login attempt
+type connMetadata struct {
+	ssh.ConnMetadata
+	user string
+}
+
+func (c connMetadata) User() string { return c.user }
+
+func TestSftpPasswordAuth(t *testing.T) {
+	opt := DefaultOpt
+	opt.AuthorizedKeys = ""
+	opt.User = testUser
+	opt.Pass = testPass
+	s := &server{opt: opt}
+	require.NoError(t, s.makeConfig())
+	require.NotNil(t, s.config.PasswordCallback)
+
+	for _, test := range []struct {
+		user string
+		pass string
+		ok   bool
+	}{
+		{testUser, testPass, true},
+		{testUser, "wrong", false},
+		{"wrong", testPass, false},
+		{"", "", false},
+	} {
+		_, err := s.config.PasswordCallback(connMetadata{user: test.user}, []byte(test.pass))
+		assert.Equal(t, test.ok, err == nil, "user %q pass %q", test.user, test.pass)
+	}
+}
+
+func TestSftpNoAuthConfigured(t *testing.T) {
+	opt := DefaultOpt
+	opt.AuthorizedKeys = ""
+	s := &server{opt: opt}
+	assert.Error(t, s.makeConfig())
+}
+
+func TestSftpUserWithoutPass(t *testing.T) {
+	opt := DefaultOpt
+	opt.AuthorizedKeys = ""
+	opt.User = testUser
+	s := &server{opt: opt}
+	assert.Error(t, s.makeConfig())
+}
+
+func TestSftpPassWithoutUser(t *testing.T) {
+	opt := DefaultOpt
+	opt.AuthorizedKeys = ""
+	opt.Pass = testPass
+	s := &server{opt: opt}
+	assert.Error(t, s.makeConfig())
+
+	// Even when there is another way of logging in
+	opt.NoAuth = true
+	s = &server{opt: opt}
+	assert.Error(t, s.makeConfig())
+}
@@ -0,0 +1,89 @@
+// Serve sftp tests set up a server and run the integration tests
+// for the sftp remote against it.
+//
+// We skip tests on platforms with troublesome character mappings
+
+//+build !windows,!darwin
+
+package sftp
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	_ "github.com/ncw/rclone/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// TestSftp runs the sftp server then runs the unit tests for the
+// sftp remote against it.
+func TestSftp(t *testing.T) {
+	fstest.Initialise()
+
+	fremote, _, clean, err := fstest.RandomRemote(*fstest.RemoteName, *fstest.SubDir)
+	assert.NoError(t, err)
+	defer clean()
+
+	err = fremote.Mkdir(context.Background(), "")
+	assert.NoError(t, err)
+
+	// Make a host key for the server
+	keyDir, err := ioutil.TempDir("", "rclone-serve-sftp")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(keyDir)
+	}()
+	keyPath := filepath.Join(keyDir, "id_rsa")
+	require.NoError(t, makeHostKey(keyPath))
+
+	// Start the server
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.HostKeys = []string{keyPath}
+	opt.AuthorizedKeys = ""
+	opt.User = testUser
+	opt.Pass = testPass
+	w := newServer(fremote, &opt)
+	require.NoError(t, w.Serve())
+	defer w.Close()
+	host, port, err := net.SplitHostPort(w.Addr())
+	require.NoError(t, err)
+
+	// Find the directory of the sftp remote to run the tests in
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+	testDir := filepath.Join(pwd, "../../../sftp")
+
+	// Run the sftp tests with an on the fly remote
+	args := []string{"test"}
+	if testing.Verbose() {
+		args = append(args, "-v")
+	}
+	if *fstest.Verbose {
+		args = append(args, "-verbose")
+	}
+	args = append(args, "-remote", "sftptest:")
+	cmd := exec.Command("go", args...)
+	cmd.Dir = testDir
+	cmd.Env = append(os.Environ(),
+		"PWD="+testDir,
+		"RCLONE_CONFIG_SFTPTEST_TYPE=sftp",
+		"RCLONE_CONFIG_SFTPTEST_HOST="+host,
+		"RCLONE_CONFIG_SFTPTEST_PORT="+port,
+		"RCLONE_CONFIG_SFTPTEST_USER="+testUser,
+		"RCLONE_CONFIG_SFTPTEST_PASS="+fs.MustObscure(testPass),
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) != 0 {
+		t.Logf("\n----------\n%s----------\n", string(out))
+	}
+	assert.NoError(t, err, "Running sftp integration tests")
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/cmd/serve/httplib"
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/vfs"
 	"github.com/ncw/rclone/vfs/vfsflags"
@@ -18,11 +19,12 @@ import (
 
 // Globals
 var (
-	bindAddress = "localhost:8081"
+	httpOpt = httplib.DefaultOpt
 )
 
 func init() {
-	Command.Flags().StringVarP(&bindAddress, "addr", "", bindAddress, "IPaddress:Port to bind server to.")
+	httpOpt.ListenAddr = "localhost:8081"
+	httplib.AddFlags(Command.Flags(), &httpOpt)
 	vfsflags.AddFlags(Command.Flags())
 }
 
@@ -39,34 +41,26 @@ write it.
 NB at the moment each directory listing reads the start of each file
 which is undesirable: see https://github.com/golang/go/issues/22577
 
-` + vfs.Help,
+` + httplib.Help + vfs.Help,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(false, false, command, func() error {
-			return serveWebDav(fsrc)
+			return serveWebDav(fsrc, &httpOpt)
 		})
 	},
 }
 
 // serve the remote
-func serveWebDav(f fs.Fs) error {
-	fs.Logf(f, "WebDav Server started on %v", bindAddress)
-
-	webdavFS := &WebDAV{
-		f:   f,
-		vfs: vfs.New(f, &vfsflags.Opt),
-	}
-
-	handler := &webdav.Handler{
-		FileSystem: webdavFS,
-		LockSystem: webdav.NewMemLS(),
-		Logger:     webdavFS.logRequest, // FIXME
+func serveWebDav(f fs.Fs, opt *httplib.Options) error {
+	w := newWebDAV(f, opt)
+	err := w.Serve()
+	if err != nil {
+		return err
 	}
-
-	// FIXME use our HTTP transport
-	http.Handle("/", handler)
-	return http.ListenAndServe(bindAddress, nil)
+	fs.Logf(f, "WebDav Server started on %s", w.URL())
+	w.Wait()
+	return nil
 }
 
 // WebDAV is a webdav.FileSystem interface
@@ -82,13 +76,40 @@ func serveWebDav(f fs.Fs) error {
 // might apply". In particular, whether or not renaming a file or directory
 // overwriting another existing file or directory is an error is OS-dependent.
 type WebDAV struct {
-	f   fs.Fs
-	vfs *vfs.VFS
+	*httplib.Server
+	f             fs.Fs
+	vfs           *vfs.VFS
+	webdavhandler *webdav.Handler
 }
 
 // check interface
 var _ webdav.FileSystem = (*WebDAV)(nil)
 
+// newWebDAV makes a WebDAV server serving f
+func newWebDAV(f fs.Fs, opt *httplib.Options) *WebDAV {
+	w := &WebDAV{
+		f:   f,
+		vfs: vfs.New(f, &vfsflags.Opt),
+	}
+	w.Server = httplib.NewServer(http.HandlerFunc(w.handler), opt)
+	w.webdavhandler = &webdav.Handler{
+		Prefix:     w.Opt.BaseURL,
+		FileSystem: w,
+		LockSystem: webdav.NewMemLS(),
+		Logger:     w.logRequest, // FIXME
+	}
+	return w
+}
+
+// handler checks the request is under the base URL and passes it to
+// the webdav handler
+func (w *WebDAV) handler(rw http.ResponseWriter, r *http.Request) {
+	if _, ok := w.Path(rw, r); !ok {
+		return
+	}
+	w.webdavhandler.ServeHTTP(rw, r)
+}
+
 // logRequest is called by the webdav module on every request
 func (w *WebDAV) logRequest(r *http.Request, err error) {
 	fs.Infof(r.URL.Path, "%s from %s", r.Method, r.RemoteAddr)
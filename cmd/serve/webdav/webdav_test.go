@@ -15,6 +15,7 @@ import (
 	"github.com/ncw/rclone/fstest"
 	_ "github.com/ncw/rclone/local"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
 )
 
 // TestWebDav runs the webdav server then runs the unit tests for the
@@ -26,12 +27,12 @@ func TestWebDav(t *testing.T) {
 	assert.NoError(t, err)
 	defer clean()
 
-	err = fremote.Mkdir("")
+	err = fremote.Mkdir(context.Background(), "")
 	assert.NoError(t, err)
 
 	// Start the server
 	go func() {
-		err := serveWebDav(fremote)
+		err := serveWebDav(fremote, &httpOpt)
 		assert.NoError(t, err)
 	}()
 	// FIXME shut it down somehow?
@@ -4,10 +4,17 @@ import (
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// Globals
+var (
+	reportOpt cmd.ReportOpt
 )
 
 func init() {
 	cmd.Root.AddCommand(commandDefintion)
+	reportOpt.AddFlags(commandDefintion.Flags())
 }
 
 var commandDefintion = &cobra.Command{
@@ -32,12 +39,12 @@ extended explanation in the ` + "`" + `copy` + "`" + ` command above if unsure.
 
 If dest:path doesn't exist, it is created and the source:path contents
 go there.
-`,
+` + cmd.ReportHelp,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
-		cmd.Run(true, true, command, func() error {
-			return fs.Sync(fdst, fsrc)
+		reportOpt.Run(true, true, command, func(ctx context.Context) error {
+			return fs.Sync(ctx, fdst, fsrc)
 		})
 	},
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -51,19 +52,19 @@ func Touch(fsrc fs.Fs, srcFileName string) error {
 		}
 		timeAtr = timeAtrFromFlags
 	}
-	file, err := fsrc.NewObject(srcFileName)
+	file, err := fsrc.NewObject(context.Background(), srcFileName)
 	if err != nil {
 		if !notCreateNewFile {
 			var buffer []byte
 			src := fs.NewStaticObjectInfo(srcFileName, timeAtr, int64(len(buffer)), true, nil, fsrc)
-			_, err = fsrc.Put(bytes.NewBuffer(buffer), src)
+			_, err = fsrc.Put(context.Background(), bytes.NewBuffer(buffer), src)
 			if err != nil {
 				return err
 			}
 		}
 		return nil
 	}
-	err = file.SetModTime(timeAtr)
+	err = file.SetModTime(context.Background(), timeAtr)
 	if err != nil {
 		return errors.Wrap(err, "touch: couldn't set mod time")
 	}
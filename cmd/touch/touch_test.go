@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	_ "github.com/ncw/rclone/local"
+	"golang.org/x/net/context"
 )
 
 func TestTouch(t *testing.T) {
@@ -17,45 +18,45 @@ func TestTouch(t *testing.T) {
 	f, err := fs.NewFs("testfiles")
 	err = Touch(f, "newFile")
 	require.NoError(t, err)
-	file, errFile := f.NewObject("newFile")
+	file, errFile := f.NewObject(context.Background(), "newFile")
 	require.NoError(t, errFile)
-	err = file.Remove()
+	err = file.Remove(context.Background())
 	require.NoError(t, err)
 
 	notCreateNewFile = true
 	err = Touch(f, "fileWithCflag")
 	require.NoError(t, err)
-	file, errFile = f.NewObject("fileWithCflag")
+	file, errFile = f.NewObject(context.Background(), "fileWithCflag")
 	require.Error(t, errFile)
 	notCreateNewFile = false
 
 	timeAsArgument = "060102"
 	err = Touch(f, "oldFile")
 	require.NoError(t, err)
-	file, err = f.NewObject("oldFile")
+	file, err = f.NewObject(context.Background(), "oldFile")
 	require.NoError(t, err)
 	curretTime := time.Now()
 	require.NoError(t, err)
 	print(file.ModTime().Year() < curretTime.Year())
 	assert.Equal(t, true, file.ModTime().Year() < curretTime.Year())
-	err = file.Remove()
+	err = file.Remove(context.Background())
 	require.NoError(t, err)
 
 	timeAsArgument = "2006-01-02T15:04:05"
 	err = Touch(f, "oldFile")
 	require.NoError(t, err)
-	file, err = f.NewObject("oldFile")
+	file, err = f.NewObject(context.Background(), "oldFile")
 	require.NoError(t, err)
 	assert.Equal(t, true, file.ModTime().Year() < curretTime.Year())
 
 	timeAsArgument = ""
 	err = Touch(f, "oldFile")
 	require.NoError(t, err)
-	file, err = f.NewObject("oldFile")
+	file, err = f.NewObject(context.Background(), "oldFile")
 	require.NoError(t, err)
 	timeBetween2007YearAndCurrent, errTime := time.Parse("060102", "121212")
 	require.NoError(t, errTime)
 	assert.Equal(t, true, file.ModTime().Year() > timeBetween2007YearAndCurrent.Year())
-	err = file.Remove()
+	err = file.Remove(context.Background())
 	require.NoError(t, err)
 }
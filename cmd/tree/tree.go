@@ -15,6 +15,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -115,7 +116,7 @@ short options as they conflict with rclone's short options.
 
 // Tree lists fsrc to outFile using the Options passed in
 func Tree(fsrc fs.Fs, outFile io.Writer, opts *tree.Options) error {
-	dirs, err := fs.NewDirTree(fsrc, "", false, opts.DeepLevel)
+	dirs, err := fs.NewDirTree(context.Background(), fsrc, "", false, opts.DeepLevel)
 	if err != nil {
 		return err
 	}
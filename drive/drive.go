@@ -24,6 +24,7 @@ import (
 	"github.com/ncw/rclone/pacer"
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
+	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v2"
@@ -86,7 +87,7 @@ var (
 		"text/tab-separated-values":                                                 "tsv",
 	}
 	extensionToMimeType map[string]string
-	partialFields       = "id,downloadUrl,exportLinks,fileExtension,fullFileExtension,fileSize,labels,md5Checksum,modifiedDate,mimeType,title"
+	partialFields       = "id,downloadUrl,exportLinks,fileExtension,fullFileExtension,fileSize,labels,md5Checksum,modifiedDate,mimeType,properties,title"
 )
 
 // Register with Fs
@@ -100,7 +101,7 @@ func init() {
 			if err != nil {
 				log.Fatalf("Failed to configure token: %v", err)
 			}
-			err = configTeamDrive(name)
+			err = configTeamDrive(context.Background(), name)
 			if err != nil {
 				log.Fatalf("Failed to configure team drive: %v", err)
 			}
@@ -149,6 +150,7 @@ type Object struct {
 	modifiedDate string // RFC3339 time it was last modified
 	isDocument   bool   // if set this is a Google doc
 	mimeType     string
+	properties   fs.Metadata // custom properties of the object - may be nil
 }
 
 // ------------------------------------------------------------
@@ -213,7 +215,7 @@ type listFn func(*drive.File) bool
 // If the user fn ever returns true then it early exits with found = true
 //
 // Search params: https://developers.google.com/drive/search-parameters
-func (f *Fs) list(dirID string, title string, directoriesOnly bool, filesOnly bool, includeAll bool, fn listFn) (found bool, err error) {
+func (f *Fs) list(ctx context.Context, dirID string, title string, directoriesOnly bool, filesOnly bool, includeAll bool, fn listFn) (found bool, err error) {
 	var query []string
 	if !includeAll {
 		q := "trashed=" + strconv.FormatBool(*driveTrashedOnly)
@@ -271,8 +273,8 @@ func (f *Fs) list(dirID string, title string, directoriesOnly bool, filesOnly bo
 OUTER:
 	for {
 		var files *drive.FileList
-		err = f.pacer.Call(func() (bool, error) {
-			files, err = list.Fields(googleapi.Field(fields)).Do()
+		err = f.pacer.Call(ctx, func() (bool, error) {
+			files, err = list.Fields(googleapi.Field(fields)).Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -328,7 +330,7 @@ func (f *Fs) parseExtensions(extensions string) error {
 }
 
 // Figure out if the user wants to use a team drive
-func configTeamDrive(name string) error {
+func configTeamDrive(ctx context.Context, name string) error {
 	teamDrive := fs.ConfigFileGet(name, "team_drive")
 	if teamDrive == "" {
 		fmt.Printf("Configure this as a team drive?\n")
@@ -351,8 +353,8 @@ func configTeamDrive(name string) error {
 	listTeamDrives := svc.Teamdrives.List().MaxResults(100)
 	for {
 		var teamDrives *drive.TeamDriveList
-		err = newPacer().Call(func() (bool, error) {
-			teamDrives, err = listTeamDrives.Do()
+		err = newPacer().Call(ctx, func() (bool, error) {
+			teamDrives, err = listTeamDrives.Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -384,6 +386,7 @@ func newPacer() *pacer.Pacer {
 
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, path string) (fs.Fs, error) {
+	ctx := context.Background()
 	if !isPowerOfTwo(int64(chunkSize)) {
 		return nil, errors.Errorf("drive: chunk size %v isn't a power of two", chunkSize)
 	}
@@ -412,7 +415,11 @@ func NewFs(name, path string) (fs.Fs, error) {
 		DuplicateFiles:          true,
 		ReadMimeType:            true,
 		WriteMimeType:           true,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
 		CanHaveEmptyDirectories: true,
+		ServerSideAcrossConfigs: fs.ConfigFileGetBool(name, "server_side_across_configs", false),
+		RangeRead:               true,
 	}).Fill(f)
 
 	// Create a new authorized Drive client.
@@ -423,8 +430,8 @@ func NewFs(name, path string) (fs.Fs, error) {
 	}
 
 	// Read About so we know the root path
-	err = f.pacer.Call(func() (bool, error) {
-		f.about, err = f.svc.About.Get().Do()
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		f.about, err = f.svc.About.Get().Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -448,7 +455,7 @@ func NewFs(name, path string) (fs.Fs, error) {
 	}
 
 	// Find the current root
-	err = f.dirCache.FindRoot(false)
+	err = f.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		// Assume it is a file
 		newRoot, remote := dircache.SplitPath(root)
@@ -456,12 +463,12 @@ func NewFs(name, path string) (fs.Fs, error) {
 		newF.dirCache = dircache.New(newRoot, f.about.RootFolderId, &newF)
 		newF.root = newRoot
 		// Make new Fs which is the parent
-		err = newF.dirCache.FindRoot(false)
+		err = newF.dirCache.FindRoot(ctx, false)
 		if err != nil {
 			// No root so return old f
 			return f, nil
 		}
-		_, err := newF.newObjectWithInfo(remote, nil)
+		_, err := newF.newObjectWithInfo(ctx, remote, nil)
 		if err != nil {
 			// File doesn't exist so return old f
 			return f, nil
@@ -476,7 +483,7 @@ func NewFs(name, path string) (fs.Fs, error) {
 // Return an Object from a path
 //
 // If it can't be found it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *drive.File) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *drive.File) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -484,7 +491,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *drive.File) (fs.Object, erro
 	if info != nil {
 		o.setMetaData(info)
 	} else {
-		err := o.readMetaData() // reads info and meta, returning an error
+		err := o.readMetaData(ctx) // reads info and meta, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -494,14 +501,14 @@ func (f *Fs) newObjectWithInfo(remote string, info *drive.File) (fs.Object, erro
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // FindLeaf finds a directory of name leaf in the folder with ID pathID
-func (f *Fs) FindLeaf(pathID, leaf string) (pathIDOut string, found bool, err error) {
+func (f *Fs) FindLeaf(ctx context.Context, pathID, leaf string) (pathIDOut string, found bool, err error) {
 	// Find the leaf in pathID
-	found, err = f.list(pathID, leaf, true, false, false, func(item *drive.File) bool {
+	found, err = f.list(ctx, pathID, leaf, true, false, false, func(item *drive.File) bool {
 		if item.Title == leaf {
 			pathIDOut = item.Id
 			return true
@@ -512,7 +519,7 @@ func (f *Fs) FindLeaf(pathID, leaf string) (pathIDOut string, found bool, err er
 }
 
 // CreateDir makes a directory with pathID as parent and name leaf
-func (f *Fs) CreateDir(pathID, leaf string) (newID string, err error) {
+func (f *Fs) CreateDir(ctx context.Context, pathID, leaf string) (newID string, err error) {
 	// fmt.Println("Making", path)
 	// Define the metadata for the directory we are going to create.
 	createInfo := &drive.File{
@@ -522,8 +529,8 @@ func (f *Fs) CreateDir(pathID, leaf string) (newID string, err error) {
 		Parents:     []*drive.ParentReference{{Id: pathID}},
 	}
 	var info *drive.File
-	err = f.pacer.Call(func() (bool, error) {
-		info, err = f.svc.Files.Insert(createInfo).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		info, err = f.svc.Files.Insert(createInfo).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -576,18 +583,18 @@ func (f *Fs) findExportFormat(filepath string, item *drive.File) (extension, lin
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
-	err = f.dirCache.FindRoot(false)
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	err = f.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		return nil, err
 	}
-	directoryID, err := f.dirCache.FindDir(dir, false)
+	directoryID, err := f.dirCache.FindDir(ctx, dir, false)
 	if err != nil {
 		return nil, err
 	}
 
 	var iErr error
-	_, err = f.list(directoryID, "", false, false, false, func(item *drive.File) bool {
+	_, err = f.list(ctx, directoryID, "", false, false, false, func(item *drive.File) bool {
 		remote := path.Join(dir, item.Title)
 		switch {
 		case *driveAuthOwnerOnly && !isAuthOwned(item):
@@ -600,7 +607,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 			entries = append(entries, d)
 		case item.Md5Checksum != "" || item.FileSize > 0:
 			// If item has MD5 sum or a length it is a file stored on drive
-			o, err := f.newObjectWithInfo(remote, item)
+			o, err := f.newObjectWithInfo(ctx, remote, item)
 			if err != nil {
 				iErr = err
 				return true
@@ -612,7 +619,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 			if extension == "" {
 				fs.Debugf(remote, "No export formats found")
 			} else {
-				o, err := f.newObjectWithInfo(remote+"."+extension, item)
+				o, err := f.newObjectWithInfo(ctx, remote+"."+extension, item)
 				if err != nil {
 					iErr = err
 					return true
@@ -645,7 +652,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 // finished Object which must have setMetaData called on it
 //
 // Used to create new objects
-func (f *Fs) createFileInfo(remote string, modTime time.Time, size int64) (*Object, *drive.File, error) {
+func (f *Fs) createFileInfo(ctx context.Context, remote string, modTime time.Time, size int64) (*Object, *drive.File, error) {
 	// Temporary Object under construction
 	o := &Object{
 		fs:     f,
@@ -653,7 +660,7 @@ func (f *Fs) createFileInfo(remote string, modTime time.Time, size int64) (*Obje
 		bytes:  size,
 	}
 
-	leaf, directoryID, err := f.dirCache.FindRootAndPath(remote, true)
+	leaf, directoryID, err := f.dirCache.FindRootAndPath(ctx, remote, true)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -669,49 +676,74 @@ func (f *Fs) createFileInfo(remote string, modTime time.Time, size int64) (*Obje
 	return o, createInfo, nil
 }
 
+// applyMetadata sets the MimeType and Properties of info from any
+// metadata passed in with the options
+//
+// Drive can't store the HTTP headers other than the Content-Type so
+// these are ignored.
+func applyMetadata(info *drive.File, options []fs.OpenOption) {
+	metadata := fs.GetMetadataOptions(options)
+	for _, k := range metadata.Keys() {
+		v := metadata[k]
+		switch {
+		case k == "content-type":
+			info.MimeType = v
+		case fs.IsMetadataHeaderKey(k):
+			// ignore
+		default:
+			info.Properties = append(info.Properties, &drive.Property{
+				Key:        k,
+				Value:      v,
+				Visibility: "PRIVATE",
+			})
+		}
+	}
+}
+
 // Put the object
 //
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	exisitingObj, err := f.newObjectWithInfo(src.Remote(), nil)
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	exisitingObj, err := f.newObjectWithInfo(ctx, src.Remote(), nil)
 	switch err {
 	case nil:
-		return exisitingObj, exisitingObj.Update(in, src, options...)
+		return exisitingObj, exisitingObj.Update(ctx, in, src, options...)
 	case fs.ErrorObjectNotFound:
 		// Not found so create it
-		return f.PutUnchecked(in, src, options...)
+		return f.PutUnchecked(ctx, in, src, options...)
 	default:
 		return nil, err
 	}
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // PutUnchecked uploads the object
 //
 // This will create a duplicate if we upload a new file without
 // checking to see if there is one already - use Put() for that.
-func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) PutUnchecked(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	remote := src.Remote()
 	size := src.Size()
 	modTime := src.ModTime()
 
-	o, createInfo, err := f.createFileInfo(remote, modTime, size)
+	o, createInfo, err := f.createFileInfo(ctx, remote, modTime, size)
 	if err != nil {
 		return nil, err
 	}
+	applyMetadata(createInfo, options)
 
 	var info *drive.File
 	if size == 0 || size < int64(driveUploadCutoff) {
 		// Make the API request to upload metadata and file data.
 		// Don't retry, return a retry error instead
-		err = f.pacer.CallNoRetry(func() (bool, error) {
-			info, err = f.svc.Files.Insert(createInfo).Media(in, googleapi.ContentType("")).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+		err = f.pacer.CallNoRetry(ctx, func() (bool, error) {
+			info, err = f.svc.Files.Insert(createInfo).Media(in, googleapi.ContentType("")).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -719,7 +751,7 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 		}
 	} else {
 		// Upload the file in chunks
-		info, err = f.Upload(in, size, createInfo.MimeType, createInfo, remote)
+		info, err = f.Upload(ctx, in, size, createInfo.MimeType, createInfo, remote)
 		if err != nil {
 			return o, err
 		}
@@ -730,7 +762,7 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 
 // MergeDirs merges the contents of all the directories passed
 // in into the first one and rmdirs the other directories.
-func (f *Fs) MergeDirs(dirs []fs.Directory) error {
+func (f *Fs) MergeDirs(ctx context.Context, dirs []fs.Directory) error {
 	if len(dirs) < 2 {
 		return nil
 	}
@@ -738,7 +770,7 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 	for _, srcDir := range dirs[1:] {
 		// list the the objects
 		infos := []*drive.File{}
-		_, err := f.list(srcDir.ID(), "", false, false, true, func(info *drive.File) bool {
+		_, err := f.list(ctx, srcDir.ID(), "", false, false, true, func(info *drive.File) bool {
 			infos = append(infos, info)
 			return false
 		})
@@ -750,8 +782,8 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 			fs.Infof(srcDir, "merging %q", info.Title)
 			// Move the file into the destination
 			info.Parents = []*drive.ParentReference{{Id: dstDir.ID()}}
-			err = f.pacer.Call(func() (bool, error) {
-				_, err = f.svc.Files.Patch(info.Id, info).SetModifiedDate(true).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+			err = f.pacer.Call(ctx, func() (bool, error) {
+				_, err = f.svc.Files.Patch(info.Id, info).SetModifiedDate(true).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 				return shouldRetry(err)
 			})
 			if err != nil {
@@ -759,7 +791,7 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 			}
 		}
 		// rmdir (into trash) the now empty source directory
-		err = f.rmdir(srcDir.ID(), true)
+		err = f.rmdir(ctx, srcDir.ID(), true)
 		if err != nil {
 			fs.Infof(srcDir, "removing empty directory")
 			return errors.Wrapf(err, "MergDirs move failed to rmdir %q", srcDir)
@@ -769,25 +801,25 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
-	err := f.dirCache.FindRoot(true)
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	err := f.dirCache.FindRoot(ctx, true)
 	if err != nil {
 		return err
 	}
 	if dir != "" {
-		_, err = f.dirCache.FindDir(dir, true)
+		_, err = f.dirCache.FindDir(ctx, dir, true)
 	}
 	return err
 }
 
 // Rmdir deletes a directory unconditionally by ID
-func (f *Fs) rmdir(directoryID string, useTrash bool) error {
-	return f.pacer.Call(func() (bool, error) {
+func (f *Fs) rmdir(ctx context.Context, directoryID string, useTrash bool) error {
+	return f.pacer.Call(ctx, func() (bool, error) {
 		var err error
 		if useTrash {
-			_, err = f.svc.Files.Trash(directoryID).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+			_, err = f.svc.Files.Trash(directoryID).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		} else {
-			err = f.svc.Files.Delete(directoryID).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+			err = f.svc.Files.Delete(directoryID).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		}
 		return shouldRetry(err)
 	})
@@ -796,15 +828,15 @@ func (f *Fs) rmdir(directoryID string, useTrash bool) error {
 // Rmdir deletes a directory
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	root := path.Join(f.root, dir)
 	dc := f.dirCache
-	directoryID, err := dc.FindDir(dir, false)
+	directoryID, err := dc.FindDir(ctx, dir, false)
 	if err != nil {
 		return err
 	}
 	var trashedFiles = false
-	found, err := f.list(directoryID, "", false, false, true, func(item *drive.File) bool {
+	found, err := f.list(ctx, directoryID, "", false, false, true, func(item *drive.File) bool {
 		if item.Labels == nil || !item.Labels.Trashed {
 			fs.Debugf(dir, "Rmdir: contains file: %q", item.Title)
 			return true
@@ -823,7 +855,7 @@ func (f *Fs) Rmdir(dir string) error {
 		// trash the directory if it had trashed files
 		// in or the user wants to trash, otherwise
 		// delete it.
-		err = f.rmdir(directoryID, trashedFiles || *driveUseTrash)
+		err = f.rmdir(ctx, directoryID, trashedFiles || *driveUseTrash)
 		if err != nil {
 			return err
 		}
@@ -846,10 +878,11 @@ func (f *Fs) Precision() time.Duration {
 //
 // It returns the destination Object and a possible error
 //
-// Will only be called if src.Fs().Name() == f.Name()
+// Will only be called if src.Fs().Name() == f.Name() or if
+// server_side_across_configs is set and src is another drive remote
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't copy - not same remote type")
@@ -859,14 +892,14 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 		return nil, errors.New("can't copy a Google document")
 	}
 
-	o, createInfo, err := f.createFileInfo(remote, srcObj.ModTime(), srcObj.bytes)
+	o, createInfo, err := f.createFileInfo(ctx, remote, srcObj.ModTime(), srcObj.bytes)
 	if err != nil {
 		return nil, err
 	}
 
 	var info *drive.File
-	err = o.fs.pacer.Call(func() (bool, error) {
-		info, err = o.fs.svc.Files.Copy(srcObj.id, createInfo).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
+		info, err = o.fs.svc.Files.Copy(srcObj.id, createInfo).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -882,19 +915,19 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 // Optional interface: Only implement this if you have a way of
 // deleting all the files quicker than just running Remove() on the
 // result of List()
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	if f.root == "" {
 		return errors.New("can't purge root directory")
 	}
-	err := f.dirCache.FindRoot(false)
+	err := f.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		return err
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		if *driveUseTrash {
-			_, err = f.svc.Files.Trash(f.dirCache.RootID()).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+			_, err = f.svc.Files.Trash(f.dirCache.RootID()).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		} else {
-			err = f.svc.Files.Delete(f.dirCache.RootID()).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+			err = f.svc.Files.Delete(f.dirCache.RootID()).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		}
 		return shouldRetry(err)
 	})
@@ -906,9 +939,9 @@ func (f *Fs) Purge() error {
 }
 
 // CleanUp empties the trash
-func (f *Fs) CleanUp() error {
-	err := f.pacer.Call(func() (bool, error) {
-		err := f.svc.Files.EmptyTrash().Do()
+func (f *Fs) CleanUp(ctx context.Context) error {
+	err := f.pacer.Call(ctx, func() (bool, error) {
+		err := f.svc.Files.EmptyTrash().Context(ctx).Do()
 		return shouldRetry(err)
 	})
 
@@ -918,16 +951,80 @@ func (f *Fs) CleanUp() error {
 	return nil
 }
 
+// About gets quota information
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	if f.isTeamDrive {
+		// Team drives don't have a quota so just return the empty usage
+		return &fs.Usage{}, nil
+	}
+	var about *drive.About
+	var err error
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		about, err = f.svc.About.Get().Context(ctx).Do()
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Drive storageQuota")
+	}
+	usage := &fs.Usage{
+		Used:    fs.NewUsageValue(about.QuotaBytesUsed),                                 // bytes in use
+		Trashed: fs.NewUsageValue(about.QuotaBytesUsedInTrash),                          // bytes in trash
+		Other:   fs.NewUsageValue(about.QuotaBytesUsedAggregate - about.QuotaBytesUsed), // other usage eg gmail in drive
+	}
+	if total := about.QuotaBytesTotal; total > 0 {
+		usage.Total = fs.NewUsageValue(total)                                // quota of bytes that can be used
+		usage.Free = fs.NewUsageValue(total - about.QuotaBytesUsedAggregate) // bytes which can be uploaded before reaching the quota
+	}
+	return usage, nil
+}
+
+// PublicLink adds an "anyone with the link can read" permission to
+// the file or directory at remote and returns a link to it.
+//
+// Drive doesn't support expiry of these permissions so expire is
+// ignored.
+func (f *Fs) PublicLink(ctx context.Context, remote string, expire time.Duration) (link string, err error) {
+	if expire > 0 {
+		fs.Debugf(f, "Ignoring expiry as drive doesn't support it for public links")
+	}
+	var id string
+	o, err := f.NewObject(ctx, remote)
+	if err == nil {
+		id = o.(*Object).id
+	} else if err == fs.ErrorObjectNotFound || err == fs.ErrorNotAFile {
+		id, err = f.dirCache.FindDir(ctx, remote, false)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		return "", err
+	}
+	permission := &drive.Permission{
+		Role:     "reader",
+		Type:     "anyone",
+		WithLink: true,
+	}
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		_, err = f.svc.Permissions.Insert(id, permission).SendNotificationEmails(false).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to add public permission")
+	}
+	return fmt.Sprintf("https://drive.google.com/open?id=%s", id), nil
+}
+
 // Move src to this remote using server side move operations.
 //
 // This is stored with the remote path given
 //
 // It returns the destination Object and a possible error
 //
-// Will only be called if src.Fs().Name() == f.Name()
+// Will only be called if src.Fs().Name() == f.Name() or if
+// server_side_across_configs is set and src is another drive remote
 //
 // If it isn't possible then return fs.ErrorCantMove
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
@@ -938,15 +1035,15 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 	}
 
 	// Temporary Object under construction
-	dstObj, dstInfo, err := f.createFileInfo(remote, srcObj.ModTime(), srcObj.bytes)
+	dstObj, dstInfo, err := f.createFileInfo(ctx, remote, srcObj.ModTime(), srcObj.bytes)
 	if err != nil {
 		return nil, err
 	}
 
 	// Do the move
 	var info *drive.File
-	err = f.pacer.Call(func() (bool, error) {
-		info, err = f.svc.Files.Patch(srcObj.id, dstInfo).SetModifiedDate(true).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		info, err = f.svc.Files.Patch(srcObj.id, dstInfo).SetModifiedDate(true).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -965,7 +1062,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
@@ -981,14 +1078,14 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	}
 
 	// find the root src directory
-	err := srcFs.dirCache.FindRoot(false)
+	err := srcFs.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		return err
 	}
 
 	// find the root dst directory
 	if dstRemote != "" {
-		err = f.dirCache.FindRoot(true)
+		err = f.dirCache.FindRoot(ctx, true)
 		if err != nil {
 			return err
 		}
@@ -1004,14 +1101,14 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	if dstRemote == "" {
 		findPath = f.root
 	}
-	leaf, directoryID, err = f.dirCache.FindPath(findPath, true)
+	leaf, directoryID, err = f.dirCache.FindPath(ctx, findPath, true)
 	if err != nil {
 		return err
 	}
 
 	// Check destination does not exist
 	if dstRemote != "" {
-		_, err = f.dirCache.FindDir(dstRemote, false)
+		_, err = f.dirCache.FindDir(ctx, dstRemote, false)
 		if err == fs.ErrorDirNotFound {
 			// OK
 		} else if err != nil {
@@ -1022,7 +1119,7 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	}
 
 	// Find ID of src
-	srcID, err := srcFs.dirCache.FindDir(srcRemote, false)
+	srcID, err := srcFs.dirCache.FindDir(ctx, srcRemote, false)
 	if err != nil {
 		return err
 	}
@@ -1032,8 +1129,8 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 		Title:   leaf,
 		Parents: []*drive.ParentReference{{Id: directoryID}},
 	}
-	err = f.pacer.Call(func() (bool, error) {
-		_, err = f.svc.Files.Patch(srcID, &patch).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Do()
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		_, err = f.svc.Files.Patch(srcID, &patch).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1058,7 +1155,7 @@ func (f *Fs) DirChangeNotify(notifyFunc func(string), pollInterval time.Duration
 			return
 		default:
 			for {
-				f.dirchangeNotifyRunner(notifyFunc, pollInterval)
+				f.dirchangeNotifyRunner(context.Background(), notifyFunc, pollInterval)
 				fs.Debugf(f, "Notify listener service ran into issues, restarting shortly.")
 				time.Sleep(pollInterval)
 			}
@@ -1067,15 +1164,15 @@ func (f *Fs) DirChangeNotify(notifyFunc func(string), pollInterval time.Duration
 	return quit
 }
 
-func (f *Fs) dirchangeNotifyRunner(notifyFunc func(string), pollInterval time.Duration) {
+func (f *Fs) dirchangeNotifyRunner(ctx context.Context, notifyFunc func(string), pollInterval time.Duration) {
 	var err error
 	var changeList *drive.ChangeList
 	var pageToken string
 	var largestChangeID int64
 
 	var startPageToken *drive.StartPageToken
-	err = f.pacer.Call(func() (bool, error) {
-		startPageToken, err = f.svc.Changes.GetStartPageToken().SupportsTeamDrives(f.isTeamDrive).Do()
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		startPageToken, err = f.svc.Changes.GetStartPageToken().SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1086,7 +1183,7 @@ func (f *Fs) dirchangeNotifyRunner(notifyFunc func(string), pollInterval time.Du
 
 	for {
 		fs.Debugf(f, "Checking for changes on remote")
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.Call(ctx, func() (bool, error) {
 			changesCall := f.svc.Changes.List().PageToken(pageToken).Fields(googleapi.Field("nextPageToken,largestChangeId,newStartPageToken,items(fileId,file/parents(id))"))
 			if largestChangeID != 0 {
 				changesCall = changesCall.StartChangeId(largestChangeID)
@@ -1094,7 +1191,7 @@ func (f *Fs) dirchangeNotifyRunner(notifyFunc func(string), pollInterval time.Du
 			if *driveListChunk > 0 {
 				changesCall = changesCall.MaxResults(*driveListChunk)
 			}
-			changeList, err = changesCall.SupportsTeamDrives(f.isTeamDrive).Do()
+			changeList, err = changesCall.SupportsTeamDrives(f.isTeamDrive).Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -1187,7 +1284,7 @@ func (o *Object) Size() int64 {
 	if o.isDocument && o.bytes < 0 {
 		// If it is a google doc then we must HEAD it to see
 		// how big it is
-		_, res, err := o.httpResponse("HEAD", nil)
+		_, res, err := o.httpResponse(context.TODO(), "HEAD", nil)
 		if err != nil {
 			fs.Errorf(o, "Error reading size: %v", err)
 			return 0
@@ -1207,15 +1304,19 @@ func (o *Object) setMetaData(info *drive.File) {
 	o.bytes = info.FileSize
 	o.modifiedDate = info.ModifiedDate
 	o.mimeType = info.MimeType
+	o.properties = nil
+	for _, property := range info.Properties {
+		o.properties.Set(property.Key, property.Value)
+	}
 }
 
 // readMetaData gets the info if it hasn't already been fetched
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if o.id != "" {
 		return nil
 	}
 
-	leaf, directoryID, err := o.fs.dirCache.FindRootAndPath(o.remote, false)
+	leaf, directoryID, err := o.fs.dirCache.FindRootAndPath(ctx, o.remote, false)
 	if err != nil {
 		if err == fs.ErrorDirNotFound {
 			return fs.ErrorObjectNotFound
@@ -1223,7 +1324,7 @@ func (o *Object) readMetaData() (err error) {
 		return err
 	}
 
-	found, err := o.fs.list(directoryID, leaf, false, true, false, func(item *drive.File) bool {
+	found, err := o.fs.list(ctx, directoryID, leaf, false, true, false, func(item *drive.File) bool {
 		if item.Title == leaf {
 			o.setMetaData(item)
 			return true
@@ -1245,7 +1346,7 @@ func (o *Object) readMetaData() (err error) {
 // It attempts to read the objects mtime and if that isn't present the
 // LastModified returned in the http headers
 func (o *Object) ModTime() time.Time {
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		fs.Debugf(o, "Failed to read metadata: %v", err)
 		return time.Now()
@@ -1259,8 +1360,8 @@ func (o *Object) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the drive fs object
-func (o *Object) SetModTime(modTime time.Time) error {
-	err := o.readMetaData()
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
+	err := o.readMetaData(ctx)
 	if err != nil {
 		return err
 	}
@@ -1270,8 +1371,8 @@ func (o *Object) SetModTime(modTime time.Time) error {
 	}
 	// Set modified date
 	var info *drive.File
-	err = o.fs.pacer.Call(func() (bool, error) {
-		info, err = o.fs.svc.Files.Update(o.id, updateInfo).SetModifiedDate(true).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Do()
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
+		info, err = o.fs.svc.Files.Update(o.id, updateInfo).SetModifiedDate(true).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1289,7 +1390,7 @@ func (o *Object) Storable() bool {
 
 // httpResponse gets an http.Response object for the object o.url
 // using the method passed in
-func (o *Object) httpResponse(method string, options []fs.OpenOption) (req *http.Request, res *http.Response, err error) {
+func (o *Object) httpResponse(ctx context.Context, method string, options []fs.OpenOption) (req *http.Request, res *http.Response, err error) {
 	if o.url == "" {
 		return nil, nil, errors.New("forbidden to download - check sharing permission")
 	}
@@ -1298,7 +1399,7 @@ func (o *Object) httpResponse(method string, options []fs.OpenOption) (req *http
 		return req, nil, err
 	}
 	fs.OpenOptionAddHTTPHeaders(req.Header, options)
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		res, err = o.fs.client.Do(req)
 		return shouldRetry(err)
 	})
@@ -1340,8 +1441,8 @@ func (file *openFile) Close() (err error) {
 var _ io.ReadCloser = &openFile{}
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
-	req, res, err := o.httpResponse("GET", options)
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
+	req, res, err := o.httpResponse(ctx, "GET", options)
 	if err != nil {
 		return nil, err
 	}
@@ -1365,7 +1466,7 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 // Copy the reader into the object updating modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	size := src.Size()
 	modTime := src.ModTime()
 	if o.isDocument {
@@ -1376,14 +1477,15 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		MimeType:     fs.MimeType(src),
 		ModifiedDate: modTime.Format(timeFormatOut),
 	}
+	applyMetadata(updateInfo, options)
 
 	// Make the API request to upload metadata and file data.
 	var err error
 	var info *drive.File
 	if size == 0 || size < int64(driveUploadCutoff) {
 		// Don't retry, return a retry error instead
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
-			info, err = o.fs.svc.Files.Update(updateInfo.Id, updateInfo).SetModifiedDate(true).Media(in, googleapi.ContentType("")).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Do()
+		err = o.fs.pacer.CallNoRetry(ctx, func() (bool, error) {
+			info, err = o.fs.svc.Files.Update(updateInfo.Id, updateInfo).SetModifiedDate(true).Media(in, googleapi.ContentType("")).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -1391,7 +1493,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		}
 	} else {
 		// Upload the file in chunks
-		info, err = o.fs.Upload(in, size, updateInfo.MimeType, updateInfo, o.remote)
+		info, err = o.fs.Upload(ctx, in, size, updateInfo.MimeType, updateInfo, o.remote)
 		if err != nil {
 			return err
 		}
@@ -1401,16 +1503,16 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	if o.isDocument {
 		return errors.New("can't delete a google document")
 	}
 	var err error
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		if *driveUseTrash {
-			_, err = o.fs.svc.Files.Trash(o.id).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Do()
+			_, err = o.fs.svc.Files.Trash(o.id).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Context(ctx).Do()
 		} else {
-			err = o.fs.svc.Files.Delete(o.id).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Do()
+			err = o.fs.svc.Files.Delete(o.id).Fields(googleapi.Field(partialFields)).SupportsTeamDrives(o.fs.isTeamDrive).Context(ctx).Do()
 		}
 		return shouldRetry(err)
 	})
@@ -1419,7 +1521,7 @@ func (o *Object) Remove() error {
 
 // MimeType of an Object if known, "" otherwise
 func (o *Object) MimeType() string {
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		fs.Debugf(o, "Failed to read metadata: %v", err)
 		return ""
@@ -1427,6 +1529,26 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the custom properties of the file along with its MIME type.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Merge(o.properties)
+	if o.mimeType != "" {
+		metadata.Set("content-type", o.mimeType)
+	}
+	return metadata, nil
+}
+
+// ID returns the ID of the Object if known, or "" if not
+func (o *Object) ID() string {
+	return o.id
+}
+
 // Check the interfaces are satisfied
 var (
 	_ fs.Fs                = (*Fs)(nil)
@@ -1440,6 +1562,10 @@ var (
 	_ fs.DirChangeNotifier = (*Fs)(nil)
 	_ fs.PutUncheckeder    = (*Fs)(nil)
 	_ fs.MergeDirser       = (*Fs)(nil)
+	_ fs.Abouter           = (*Fs)(nil)
+	_ fs.PublicLinker      = (*Fs)(nil)
 	_ fs.Object            = (*Object)(nil)
 	_ fs.MimeTyper         = &Object{}
+	_ fs.IDer              = &Object{}
+	_ fs.Metadataer        = &Object{}
 )
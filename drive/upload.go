@@ -21,6 +21,7 @@ import (
 
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 	"google.golang.org/api/drive/v2"
 	"google.golang.org/api/googleapi"
 )
@@ -51,7 +52,7 @@ type resumableUpload struct {
 }
 
 // Upload the io.Reader in of size bytes with contentType and info
-func (f *Fs) Upload(in io.Reader, size int64, contentType string, info *drive.File, remote string) (*drive.File, error) {
+func (f *Fs) Upload(ctx context.Context, in io.Reader, size int64, contentType string, info *drive.File, remote string) (*drive.File, error) {
 	fileID := info.Id
 	params := make(url.Values)
 	params.Set("alt", "json")
@@ -69,7 +70,7 @@ func (f *Fs) Upload(in io.Reader, size int64, contentType string, info *drive.Fi
 	urls += "?" + params.Encode()
 	var res *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		var body io.Reader
 		body, err = googleapi.WithoutDataWrapper.JSONReader(info)
 		if err != nil {
@@ -105,7 +106,7 @@ func (f *Fs) Upload(in io.Reader, size int64, contentType string, info *drive.Fi
 		MediaType:     contentType,
 		ContentLength: size,
 	}
-	return rx.Upload()
+	return rx.Upload(ctx)
 }
 
 // Make an http.Request for the range passed in
@@ -191,7 +192,7 @@ func (rx *resumableUpload) transferChunk(start int64, chunk io.ReadSeeker, chunk
 
 // Upload uploads the chunks from the input
 // It retries each chunk maxTries times (with a pause of uploadPause between attempts).
-func (rx *resumableUpload) Upload() (*drive.File, error) {
+func (rx *resumableUpload) Upload(ctx context.Context) (*drive.File, error) {
 	start := int64(0)
 	var StatusCode int
 	var err error
@@ -204,7 +205,7 @@ func (rx *resumableUpload) Upload() (*drive.File, error) {
 		chunk := fs.NewRepeatableLimitReaderBuffer(rx.Media, buf, reqSize)
 
 		// Transfer the chunk
-		err = rx.f.pacer.Call(func() (bool, error) {
+		err = rx.f.pacer.Call(ctx, func() (bool, error) {
 			fs.Debugf(rx.remote, "Sending chunk %d length %d", start, reqSize)
 			StatusCode, err = rx.transferChunk(start, chunk, reqSize)
 			again, err := shouldRetry(err)
@@ -33,10 +33,13 @@ import (
 
 	"github.com/dropbox/dropbox-sdk-go-unofficial/dropbox"
 	"github.com/dropbox/dropbox-sdk-go-unofficial/dropbox/files"
+	"github.com/dropbox/dropbox-sdk-go-unofficial/dropbox/sharing"
+	"github.com/dropbox/dropbox-sdk-go-unofficial/dropbox/users"
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/oauthutil"
 	"github.com/ncw/rclone/pacer"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 )
 
@@ -117,13 +120,15 @@ func init() {
 
 // Fs represents a remote dropbox server
 type Fs struct {
-	name           string       // name of this remote
-	root           string       // the path we are working on
-	features       *fs.Features // optional features
-	srv            files.Client // the connection to the dropbox server
-	slashRoot      string       // root with "/" prefix, lowercase
-	slashRootSlash string       // root with "/" prefix and postfix, lowercase
-	pacer          *pacer.Pacer // To pace the API calls
+	name           string         // name of this remote
+	root           string         // the path we are working on
+	features       *fs.Features   // optional features
+	srv            files.Client   // the connection to the dropbox server
+	users          users.Client   // the connection to the dropbox users API
+	sharing        sharing.Client // the connection to the dropbox sharing API
+	slashRoot      string         // root with "/" prefix, lowercase
+	slashRootSlash string         // root with "/" prefix and postfix, lowercase
+	pacer          *pacer.Pacer   // To pace the API calls
 }
 
 // Object describes a dropbox object
@@ -175,6 +180,7 @@ func shouldRetry(err error) (bool, error) {
 
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, root string) (fs.Fs, error) {
+	ctx := context.Background()
 	if uploadChunkSize > maxUploadChunkSize {
 		return nil, errors.Errorf("chunk size too big, must be < %v", maxUploadChunkSize)
 	}
@@ -203,19 +209,22 @@ func NewFs(name, root string) (fs.Fs, error) {
 	srv := files.New(config)
 
 	f := &Fs{
-		name:  name,
-		srv:   srv,
-		pacer: pacer.New().SetMinSleep(minSleep).SetMaxSleep(maxSleep).SetDecayConstant(decayConstant),
+		name:    name,
+		srv:     srv,
+		users:   users.New(config),
+		sharing: sharing.New(config),
+		pacer:   pacer.New().SetMinSleep(minSleep).SetMaxSleep(maxSleep).SetDecayConstant(decayConstant),
 	}
 	f.features = (&fs.Features{
 		CaseInsensitive:         true,
 		ReadMimeType:            true,
 		CanHaveEmptyDirectories: true,
+		RangeRead:               true,
 	}).Fill(f)
 	f.setRoot(root)
 
 	// See if the root is actually an object
-	_, err = f.getFileMetadata(f.slashRoot)
+	_, err = f.getFileMetadata(ctx, f.slashRoot)
 	if err == nil {
 		newRoot := path.Dir(f.root)
 		if newRoot == "." {
@@ -241,8 +250,8 @@ func (f *Fs) setRoot(root string) {
 }
 
 // getMetadata gets the metadata for a file or directory
-func (f *Fs) getMetadata(objPath string) (entry files.IsMetadata, notFound bool, err error) {
-	err = f.pacer.Call(func() (bool, error) {
+func (f *Fs) getMetadata(ctx context.Context, objPath string) (entry files.IsMetadata, notFound bool, err error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		entry, err = f.srv.GetMetadata(&files.GetMetadataArg{Path: objPath})
 		return shouldRetry(err)
 	})
@@ -260,8 +269,8 @@ func (f *Fs) getMetadata(objPath string) (entry files.IsMetadata, notFound bool,
 }
 
 // getFileMetadata gets the metadata for a file
-func (f *Fs) getFileMetadata(filePath string) (fileInfo *files.FileMetadata, err error) {
-	entry, notFound, err := f.getMetadata(filePath)
+func (f *Fs) getFileMetadata(ctx context.Context, filePath string) (fileInfo *files.FileMetadata, err error) {
+	entry, notFound, err := f.getMetadata(ctx, filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -276,8 +285,8 @@ func (f *Fs) getFileMetadata(filePath string) (fileInfo *files.FileMetadata, err
 }
 
 // getDirMetadata gets the metadata for a directory
-func (f *Fs) getDirMetadata(dirPath string) (dirInfo *files.FolderMetadata, err error) {
-	entry, notFound, err := f.getMetadata(dirPath)
+func (f *Fs) getDirMetadata(ctx context.Context, dirPath string) (dirInfo *files.FolderMetadata, err error) {
+	entry, notFound, err := f.getMetadata(ctx, dirPath)
 	if err != nil {
 		return nil, err
 	}
@@ -294,7 +303,7 @@ func (f *Fs) getDirMetadata(dirPath string) (dirInfo *files.FolderMetadata, err
 // Return an Object from a path
 //
 // If it can't be found it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *files.FileMetadata) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *files.FileMetadata) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -303,7 +312,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *files.FileMetadata) (fs.Obje
 	if info != nil {
 		err = o.setMetadataFromEntry(info)
 	} else {
-		err = o.readEntryAndSetMetadata()
+		err = o.readEntryAndSetMetadata(ctx)
 	}
 	if err != nil {
 		return nil, err
@@ -313,8 +322,8 @@ func (f *Fs) newObjectWithInfo(remote string, info *files.FileMetadata) (fs.Obje
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // List the objects and directories in dir into entries.  The
@@ -326,7 +335,7 @@ func (f *Fs) NewObject(remote string) (fs.Object, error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	root := f.slashRoot
 	if dir != "" {
 		root += "/" + dir
@@ -343,7 +352,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 			if root == "/" {
 				arg.Path = "" // Specify root folder as empty string
 			}
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.Call(ctx, func() (bool, error) {
 				res, err = f.srv.ListFolder(&arg)
 				return shouldRetry(err)
 			})
@@ -362,7 +371,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 			arg := files.ListFolderContinueArg{
 				Cursor: res.Cursor,
 			}
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.Call(ctx, func() (bool, error) {
 				res, err = f.srv.ListFolderContinue(&arg)
 				return shouldRetry(err)
 			})
@@ -394,7 +403,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 				d := fs.NewDir(remote, time.Now())
 				entries = append(entries, d)
 			} else if fileInfo != nil {
-				o, err := f.newObjectWithInfo(remote, fileInfo)
+				o, err := f.newObjectWithInfo(ctx, remote, fileInfo)
 				if err != nil {
 					return nil, err
 				}
@@ -428,22 +437,22 @@ func (rc *readCloser) Close() error {
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	o := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return o, o.Update(in, src, options...)
+	return o, o.Update(ctx, in, src, options...)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	root := path.Join(f.slashRoot, dir)
 
 	// can't create or run metadata on root
@@ -452,7 +461,7 @@ func (f *Fs) Mkdir(dir string) error {
 	}
 
 	// check directory doesn't exist
-	_, err := f.getDirMetadata(root)
+	_, err := f.getDirMetadata(ctx, root)
 	if err == nil {
 		return nil // directory exists already
 	} else if err != fs.ErrorDirNotFound {
@@ -463,7 +472,7 @@ func (f *Fs) Mkdir(dir string) error {
 	arg2 := files.CreateFolderArg{
 		Path: root,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		_, err = f.srv.CreateFolderV2(&arg2)
 		return shouldRetry(err)
 	})
@@ -473,7 +482,7 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir deletes the container
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	root := path.Join(f.slashRoot, dir)
 
 	// can't remove root
@@ -482,7 +491,7 @@ func (f *Fs) Rmdir(dir string) error {
 	}
 
 	// check directory exists
-	_, err := f.getDirMetadata(root)
+	_, err := f.getDirMetadata(ctx, root)
 	if err != nil {
 		return errors.Wrap(err, "Rmdir")
 	}
@@ -496,7 +505,7 @@ func (f *Fs) Rmdir(dir string) error {
 		arg.Path = "" // Specify root folder as empty string
 	}
 	var res *files.ListFolderResult
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		res, err = f.srv.ListFolder(&arg)
 		return shouldRetry(err)
 	})
@@ -508,7 +517,7 @@ func (f *Fs) Rmdir(dir string) error {
 	}
 
 	// remove it
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		_, err = f.srv.DeleteV2(&files.DeleteArg{Path: root})
 		return shouldRetry(err)
 	})
@@ -529,7 +538,7 @@ func (f *Fs) Precision() time.Duration {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't copy - not same remote type")
@@ -548,7 +557,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	arg.ToPath = dstObj.remotePath()
 	var err error
 	var result *files.RelocationResult
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		result, err = f.srv.CopyV2(&arg)
 		return shouldRetry(err)
 	})
@@ -574,9 +583,9 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 // Optional interface: Only implement this if you have a way of
 // deleting all the files quicker than just running Remove() on the
 // result of List()
-func (f *Fs) Purge() (err error) {
+func (f *Fs) Purge(ctx context.Context) (err error) {
 	// Let dropbox delete the filesystem tree
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		_, err = f.srv.DeleteV2(&files.DeleteArg{Path: f.slashRoot})
 		return shouldRetry(err)
 	})
@@ -592,7 +601,7 @@ func (f *Fs) Purge() (err error) {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantMove
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
@@ -611,7 +620,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 	arg.ToPath = dstObj.remotePath()
 	var err error
 	var result *files.RelocationResult
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		result, err = f.srv.MoveV2(&arg)
 		return shouldRetry(err)
 	})
@@ -639,7 +648,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
@@ -649,7 +658,7 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	dstPath := path.Join(f.slashRoot, dstRemote)
 
 	// Check if destination exists
-	_, err := f.getDirMetadata(dstPath)
+	_, err := f.getDirMetadata(ctx, dstPath)
 	if err == nil {
 		return fs.ErrorDirExists
 	} else if err != fs.ErrorDirNotFound {
@@ -663,7 +672,7 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	arg := files.RelocationArg{}
 	arg.FromPath = srcPath
 	arg.ToPath = dstPath
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.Call(ctx, func() (bool, error) {
 		_, err = f.srv.MoveV2(&arg)
 		return shouldRetry(err)
 	})
@@ -674,6 +683,96 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	return nil
 }
 
+// About gets quota information
+func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
+	var q *users.SpaceUsage
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		q, err = f.users.GetSpaceUsage()
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "about failed")
+	}
+	// Team accounts share the space between the members of the team
+	total, used := uint64(0), q.Used
+	if q.Allocation != nil {
+		switch {
+		case q.Allocation.Individual != nil:
+			total = q.Allocation.Individual.Allocated
+		case q.Allocation.Team != nil:
+			total, used = q.Allocation.Team.Allocated, q.Allocation.Team.Used
+		}
+	}
+	usage = &fs.Usage{
+		Used: fs.NewUsageValue(int64(q.Used)), // bytes in use
+	}
+	if total != 0 {
+		free := int64(0)
+		if total > used {
+			free = int64(total - used)
+		}
+		usage.Total = fs.NewUsageValue(int64(total)) // quota of bytes that can be used
+		usage.Free = fs.NewUsageValue(free)          // bytes which can be uploaded before reaching the quota
+	}
+	return usage, nil
+}
+
+// sharedLinkURL returns the URL from the shared link metadata
+func sharedLinkURL(link sharing.IsSharedLinkMetadata) (string, error) {
+	switch x := link.(type) {
+	case *sharing.FileLinkMetadata:
+		return x.Url, nil
+	case *sharing.FolderLinkMetadata:
+		return x.Url, nil
+	case *sharing.SharedLinkMetadata:
+		return x.Url, nil
+	}
+	return "", errors.Errorf("unknown shared link type %T", link)
+}
+
+// PublicLink creates a shared link to the remote path, or returns the
+// existing one if there is one already.
+//
+// Setting an expiry only works with Dropbox Pro accounts and isn't
+// applied to an existing link.
+func (f *Fs) PublicLink(ctx context.Context, remote string, expire time.Duration) (link string, err error) {
+	absPath := path.Join(f.slashRoot, remote)
+	createArg := sharing.NewCreateSharedLinkWithSettingsArg(absPath)
+	if expire > 0 {
+		createArg.Settings = sharing.NewSharedLinkSettings()
+		createArg.Settings.Expires = time.Now().Add(expire).UTC().Round(time.Second)
+	}
+	var linkRes sharing.IsSharedLinkMetadata
+	err = f.pacer.Call(ctx, func() (bool, error) {
+		linkRes, err = f.sharing.CreateSharedLinkWithSettings(createArg)
+		return shouldRetry(err)
+	})
+	if e, ok := err.(sharing.CreateSharedLinkWithSettingsAPIError); ok && e.EndpointError != nil && e.EndpointError.Tag == sharing.CreateSharedLinkWithSettingsErrorSharedLinkAlreadyExists {
+		fs.Debugf(absPath, "has a public link already - reusing it")
+		listArg := sharing.NewListSharedLinksArg()
+		listArg.Path = absPath
+		listArg.DirectOnly = true
+		var listRes *sharing.ListSharedLinksResult
+		err = f.pacer.Call(ctx, func() (bool, error) {
+			listRes, err = f.sharing.ListSharedLinks(listArg)
+			return shouldRetry(err)
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list shared links")
+		}
+		if len(listRes.Links) == 0 {
+			return "", errors.New("no shared links found")
+		}
+		linkRes = listRes.Links[0]
+		if expire > 0 {
+			fs.Logf(absPath, "Can't set an expiry on an existing public link - returning it unchanged")
+		}
+	} else if err != nil {
+		return "", errors.Wrap(err, "failed to create shared link")
+	}
+	return sharedLinkURL(linkRes)
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() fs.HashSet {
 	return fs.HashSet(fs.HashDropbox)
@@ -704,7 +803,7 @@ func (o *Object) Hash(t fs.HashType) (string, error) {
 	if t != fs.HashDropbox {
 		return "", fs.ErrHashUnsupported
 	}
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		return "", errors.Wrap(err, "failed to read hash from metadata")
 	}
@@ -727,17 +826,17 @@ func (o *Object) setMetadataFromEntry(info *files.FileMetadata) error {
 }
 
 // Reads the entry for a file from dropbox
-func (o *Object) readEntry() (*files.FileMetadata, error) {
-	return o.fs.getFileMetadata(o.remotePath())
+func (o *Object) readEntry(ctx context.Context) (*files.FileMetadata, error) {
+	return o.fs.getFileMetadata(ctx, o.remotePath())
 }
 
 // Read entry if not set and set metadata from it
-func (o *Object) readEntryAndSetMetadata() error {
+func (o *Object) readEntryAndSetMetadata(ctx context.Context) error {
 	// Last resort set time from client
 	if !o.modTime.IsZero() {
 		return nil
 	}
-	entry, err := o.readEntry()
+	entry, err := o.readEntry(ctx)
 	if err != nil {
 		return err
 	}
@@ -764,12 +863,12 @@ func (o *Object) metadataKey() string {
 }
 
 // readMetaData gets the info if it hasn't already been fetched
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if !o.modTime.IsZero() {
 		return nil
 	}
 	// Last resort
-	return o.readEntryAndSetMetadata()
+	return o.readEntryAndSetMetadata(ctx)
 }
 
 // ModTime returns the modification time of the object
@@ -777,7 +876,7 @@ func (o *Object) readMetaData() (err error) {
 // It attempts to read the objects mtime and if that isn't present the
 // LastModified returned in the http headers
 func (o *Object) ModTime() time.Time {
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		fs.Debugf(o, "Failed to read metadata: %v", err)
 		return time.Now()
@@ -788,7 +887,7 @@ func (o *Object) ModTime() time.Time {
 // SetModTime sets the modification time of the local fs object
 //
 // Commits the datastore
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	// Dropbox doesn't have a way of doing this so returning this
 	// error will cause the file to be deleted first then
 	// re-uploaded to set the time.
@@ -801,10 +900,10 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	headers := fs.OpenOptionHeaders(options)
 	arg := files.DownloadArg{Path: o.remotePath(), ExtraHeaders: headers}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		_, in, err = o.fs.srv.Download(&arg)
 		return shouldRetry(err)
 	})
@@ -825,7 +924,7 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 // Will work optimally if size is >= uploadChunkSize. If the size is either
 // unknown (i.e. -1) or smaller than uploadChunkSize, the method incurs an
 // avoidable request to the Dropbox API that does not carry payload.
-func (o *Object) uploadChunked(in0 io.Reader, commitInfo *files.CommitInfo, size int64) (entry *files.FileMetadata, err error) {
+func (o *Object) uploadChunked(ctx context.Context, in0 io.Reader, commitInfo *files.CommitInfo, size int64) (entry *files.FileMetadata, err error) {
 	chunkSize := int64(uploadChunkSize)
 	chunks := 0
 	if size != -1 {
@@ -848,7 +947,7 @@ func (o *Object) uploadChunked(in0 io.Reader, commitInfo *files.CommitInfo, size
 	fmtChunk(1, false)
 	var res *files.UploadSessionStartResult
 	chunk := fs.NewRepeatableLimitReaderBuffer(in, buf, chunkSize)
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		// seek to the start in case this is a retry
 		if _, err = chunk.Seek(0, 0); err != nil {
 			return false, nil
@@ -884,7 +983,7 @@ func (o *Object) uploadChunked(in0 io.Reader, commitInfo *files.CommitInfo, size
 		cursor.Offset = in.BytesRead()
 		fmtChunk(currentChunk, false)
 		chunk = fs.NewRepeatableLimitReaderBuffer(in, buf, chunkSize)
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.Call(ctx, func() (bool, error) {
 			// seek to the start in case this is a retry
 			if _, err = chunk.Seek(0, 0); err != nil {
 				return false, nil
@@ -907,7 +1006,7 @@ func (o *Object) uploadChunked(in0 io.Reader, commitInfo *files.CommitInfo, size
 	}
 	fmtChunk(currentChunk, true)
 	chunk = fs.NewRepeatableReaderBuffer(in, buf)
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		// seek to the start in case this is a retry
 		if _, err = chunk.Seek(0, 0); err != nil {
 			return false, nil
@@ -927,7 +1026,7 @@ func (o *Object) uploadChunked(in0 io.Reader, commitInfo *files.CommitInfo, size
 // Copy the reader into the object updating modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	remote := o.remotePath()
 	if ignoredFiles.MatchString(remote) {
 		fs.Logf(o, "File name disallowed - not uploading")
@@ -942,9 +1041,9 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	var err error
 	var entry *files.FileMetadata
 	if size > int64(uploadChunkSize) || size == -1 {
-		entry, err = o.uploadChunked(in, commitInfo, size)
+		entry, err = o.uploadChunked(ctx, in, commitInfo, size)
 	} else {
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetry(ctx, func() (bool, error) {
 			entry, err = o.fs.srv.Upload(commitInfo, in)
 			return shouldRetry(err)
 		})
@@ -956,8 +1055,8 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove an object
-func (o *Object) Remove() (err error) {
-	err = o.fs.pacer.Call(func() (bool, error) {
+func (o *Object) Remove(ctx context.Context) (err error) {
+	err = o.fs.pacer.Call(ctx, func() (bool, error) {
 		_, err = o.fs.srv.DeleteV2(&files.DeleteArg{Path: o.remotePath()})
 		return shouldRetry(err)
 	})
@@ -966,11 +1065,13 @@ func (o *Object) Remove() (err error) {
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = (*Fs)(nil)
-	_ fs.Copier      = (*Fs)(nil)
-	_ fs.Purger      = (*Fs)(nil)
-	_ fs.PutStreamer = (*Fs)(nil)
-	_ fs.Mover       = (*Fs)(nil)
-	_ fs.DirMover    = (*Fs)(nil)
-	_ fs.Object      = (*Object)(nil)
+	_ fs.Fs           = (*Fs)(nil)
+	_ fs.Copier       = (*Fs)(nil)
+	_ fs.Purger       = (*Fs)(nil)
+	_ fs.PutStreamer  = (*Fs)(nil)
+	_ fs.Mover        = (*Fs)(nil)
+	_ fs.DirMover     = (*Fs)(nil)
+	_ fs.Abouter      = (*Fs)(nil)
+	_ fs.PublicLinker = (*Fs)(nil)
+	_ fs.Object       = (*Object)(nil)
 )
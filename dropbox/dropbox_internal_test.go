@@ -0,0 +1,87 @@
+package dropbox
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dropbox/dropbox-sdk-go-unofficial/dropbox"
+	"github.com/dropbox/dropbox-sdk-go-unofficial/dropbox/sharing"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/pacer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// fakeSharing implements the parts of sharing.Client used by
+// PublicLink
+type fakeSharing struct {
+	sharing.Client
+	links     map[string]string // existing links by path
+	createArg *sharing.CreateSharedLinkWithSettingsArg
+}
+
+func (s *fakeSharing) CreateSharedLinkWithSettings(arg *sharing.CreateSharedLinkWithSettingsArg) (sharing.IsSharedLinkMetadata, error) {
+	s.createArg = arg
+	if _, found := s.links[arg.Path]; found {
+		return nil, sharing.CreateSharedLinkWithSettingsAPIError{
+			APIError: dropbox.APIError{ErrorSummary: "shared_link_already_exists/"},
+			EndpointError: &sharing.CreateSharedLinkWithSettingsError{
+				Tagged: dropbox.Tagged{Tag: sharing.CreateSharedLinkWithSettingsErrorSharedLinkAlreadyExists},
+			},
+		}
+	}
+	link := "https://example.com/new" + arg.Path
+	s.links[arg.Path] = link
+	return &sharing.FileLinkMetadata{SharedLinkMetadata: sharing.SharedLinkMetadata{Url: link}}, nil
+}
+
+func (s *fakeSharing) ListSharedLinks(arg *sharing.ListSharedLinksArg) (*sharing.ListSharedLinksResult, error) {
+	var links []sharing.IsSharedLinkMetadata
+	if link, found := s.links[arg.Path]; found {
+		links = append(links, &sharing.FileLinkMetadata{SharedLinkMetadata: sharing.SharedLinkMetadata{Url: link}})
+	}
+	return sharing.NewListSharedLinksResult(links, false), nil
+}
+
+func TestPublicLink(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeSharing{links: map[string]string{"/root/old": "https://example.com/old"}}
+	f := &Fs{
+		sharing:   fake,
+		slashRoot: "/root",
+		pacer:     pacer.New().SetMinSleep(time.Millisecond).SetRetries(1),
+	}
+
+	// A new link gets the expiry
+	link, err := f.PublicLink(ctx, "new", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/new/root/new", link)
+	require.NotNil(t, fake.createArg.Settings)
+	assert.False(t, fake.createArg.Settings.Expires.IsZero())
+
+	// An existing link is returned and the expiry can't be set
+	oldLogLevel := fs.Config.LogLevel
+	fs.Config.LogLevel = fs.LogLevelNotice
+	defer func() { fs.Config.LogLevel = oldLogLevel }()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	link, err = f.PublicLink(ctx, "old", time.Hour)
+	log.SetOutput(os.Stderr)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/old", link)
+	assert.Contains(t, buf.String(), "Can't set an expiry on an existing public link")
+
+	// No log if no expiry was asked for
+	buf.Reset()
+	log.SetOutput(&buf)
+	link, err = f.PublicLink(ctx, "old", 0)
+	log.SetOutput(os.Stderr)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/old", link)
+	assert.Nil(t, fake.createArg.Settings)
+	assert.Equal(t, "", buf.String())
+}
@@ -105,6 +105,22 @@ func startTokenTicker() {
 	}()
 }
 
+// SetBwLimit sets the current bandwidth limit overriding any
+// --bwlimit timetable until the next scheduled change.
+//
+// A bandwidth of 0 or less disables the limit.
+func SetBwLimit(bandwidth SizeSuffix) {
+	tokenBucketMu.Lock()
+	defer tokenBucketMu.Unlock()
+	if bandwidth > 0 {
+		tokenBucket = newTokenBucket(bandwidth)
+		Logf(nil, "Bandwidth limit set to %vBytes/s", &bandwidth)
+	} else {
+		tokenBucket = nil
+		Logf(nil, "Bandwidth limit reset to unlimited")
+	}
+}
+
 // stringSet holds a set of strings
 type stringSet map[string]struct{}
 
@@ -142,12 +158,13 @@ func (ip *inProgress) get(name string) *Account {
 	return ip.m[name]
 }
 
-// Strings returns all the strings in the stringSet
-func (ss stringSet) Strings() []string {
+// Strings returns all the strings in the stringSet using the
+// accounts in ip to describe those in progress
+func (ss stringSet) Strings(ip *inProgress) []string {
 	strings := make([]string, 0, len(ss))
 	for name := range ss {
 		var out string
-		if acc := Stats.inProgress.get(name); acc != nil {
+		if acc := ip.get(name); acc != nil {
 			out = acc.String()
 		} else {
 			out = name
@@ -160,8 +177,18 @@ func (ss stringSet) Strings() []string {
 }
 
 // String returns all the file names in the stringSet joined by newline
-func (ss stringSet) String() string {
-	return strings.Join(ss.Strings(), "\n")
+func (ss stringSet) String(ip *inProgress) string {
+	return strings.Join(ss.Strings(ip), "\n")
+}
+
+// Names returns all the file names in the stringSet sorted
+func (ss stringSet) Names() []string {
+	names := make([]string, 0, len(ss))
+	for name := range ss {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // StatsInfo limits and accounts all transfers
@@ -174,10 +201,34 @@ type StatsInfo struct {
 	checking     stringSet
 	transfers    int64
 	transferring stringSet
+	deletes      int64
 	start        time.Time
 	inProgress   *inProgress
 }
 
+// statsKeyType is the type of the key used to store a StatsInfo in a
+// context.Context
+type statsKeyType struct{}
+
+// statsKey is the key used to store a StatsInfo in a context.Context
+var statsKey = statsKeyType{}
+
+// WithStats returns a copy of ctx which accounts any operations using
+// it into stats rather than the global Stats
+func WithStats(ctx context.Context, stats *StatsInfo) context.Context {
+	return context.WithValue(ctx, statsKey, stats)
+}
+
+// StatsFromContext returns the StatsInfo operations using ctx should
+// be accounted into.  This is the global Stats unless one was set
+// with WithStats.
+func StatsFromContext(ctx context.Context) *StatsInfo {
+	if stats, ok := ctx.Value(statsKey).(*StatsInfo); ok && stats != nil {
+		return stats
+	}
+	return Stats
+}
+
 // NewStats cretates an initialised StatsInfo
 func NewStats() *StatsInfo {
 	return &StatsInfo{
@@ -218,17 +269,60 @@ Elapsed time:  %10v
 		s.transfers,
 		dtRounded)
 	if len(s.checking) > 0 {
-		fmt.Fprintf(buf, "Checking:\n%s\n", s.checking)
+		fmt.Fprintf(buf, "Checking:\n%s\n", s.checking.String(s.inProgress))
 	}
 	if len(s.transferring) > 0 {
-		fmt.Fprintf(buf, "Transferring:\n%s\n", s.transferring)
+		fmt.Fprintf(buf, "Transferring:\n%s\n", s.transferring.String(s.inProgress))
 	}
 	return buf.String()
 }
 
+// RemoteStats returns the StatsInfo as a map suitable for encoding
+// as JSON for the remote control
+func (s *StatsInfo) RemoteStats() map[string]interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	dt := time.Now().Sub(s.start)
+	speed := 0.0
+	if dt > 0 {
+		speed = float64(s.bytes) / dt.Seconds()
+	}
+	out := map[string]interface{}{
+		"bytes":       s.bytes,
+		"speed":       speed,
+		"errors":      s.errors,
+		"checks":      s.checks,
+		"transfers":   s.transfers,
+		"deletes":     s.deletes,
+		"elapsedTime": dt.Seconds(),
+	}
+	if s.lastError != nil {
+		out["lastError"] = s.lastError.Error()
+	}
+	if len(s.checking) > 0 {
+		out["checking"] = s.checking.Names()
+	}
+	if len(s.transferring) > 0 {
+		var transferring []map[string]interface{}
+		for _, name := range s.transferring.Names() {
+			transfer := map[string]interface{}{
+				"name": name,
+			}
+			if acc := s.inProgress.get(name); acc != nil {
+				for k, v := range acc.RemoteStats() {
+					transfer[k] = v
+				}
+			}
+			transferring = append(transferring, transfer)
+		}
+		out["transferring"] = transferring
+	}
+	return out
+}
+
 // Log outputs the StatsInfo to the log
 func (s *StatsInfo) Log() {
-	LogLevelPrintf(Config.StatsLogLevel, nil, "%v\n", s)
+	LogLevelPrintf(Config.StatsLogLevel, nil, "%v%v\n", s, LogValueHide("stats", s.RemoteStats()))
 }
 
 // Bytes updates the stats for bytes bytes
@@ -236,6 +330,33 @@ func (s *StatsInfo) Bytes(bytes int64) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.bytes += bytes
+	if bytes > 0 {
+		bytesMetric.Add(float64(bytes))
+	}
+}
+
+// GetBytes returns the number of bytes transferred so far
+func (s *StatsInfo) GetBytes() int64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.bytes
+}
+
+// GetBytesWithPending returns the number of bytes transferred so far
+// plus the bytes still to be transferred by the transfers in progress
+func (s *StatsInfo) GetBytesWithPending() int64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	pending := int64(0)
+	for name := range s.transferring {
+		if acc := s.inProgress.get(name); acc != nil {
+			bytes, size := acc.Progress()
+			if size >= 0 && bytes < size {
+				pending += size - bytes
+			}
+		}
+	}
+	return s.bytes + pending
 }
 
 // Errors updates the stats for errors
@@ -243,6 +364,9 @@ func (s *StatsInfo) Errors(errors int64) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.errors += errors
+	if errors > 0 {
+		errorsMetric.Add(float64(errors))
+	}
 }
 
 // GetErrors reads the number of errors
@@ -259,7 +383,8 @@ func (s *StatsInfo) GetLastError() error {
 	return s.lastError
 }
 
-// ResetCounters sets the counters (bytes, checks, errors, transfers) to 0
+// ResetCounters sets the counters (bytes, checks, errors, transfers,
+// deletes) to 0
 func (s *StatsInfo) ResetCounters() {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -267,6 +392,7 @@ func (s *StatsInfo) ResetCounters() {
 	s.errors = 0
 	s.checks = 0
 	s.transfers = 0
+	s.deletes = 0
 }
 
 // ResetErrors sets the errors count to 0
@@ -289,12 +415,16 @@ func (s *StatsInfo) Error(err error) {
 	defer s.lock.Unlock()
 	s.errors++
 	s.lastError = err
+	errorsMetric.Inc()
 }
 
 // Checking adds a check into the stats
 func (s *StatsInfo) Checking(remote string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	if _, found := s.checking[remote]; !found {
+		checkingMetric.Inc()
+	}
 	s.checking[remote] = struct{}{}
 }
 
@@ -302,8 +432,12 @@ func (s *StatsInfo) Checking(remote string) {
 func (s *StatsInfo) DoneChecking(remote string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	if _, found := s.checking[remote]; found {
+		checkingMetric.Dec()
+	}
 	delete(s.checking, remote)
 	s.checks++
+	checksMetric.Inc()
 }
 
 // GetTransfers reads the number of transfers
@@ -317,6 +451,9 @@ func (s *StatsInfo) GetTransfers() int64 {
 func (s *StatsInfo) Transferring(remote string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	if _, found := s.transferring[remote]; !found {
+		transferringMetric.Inc()
+	}
 	s.transferring[remote] = struct{}{}
 }
 
@@ -326,12 +463,33 @@ func (s *StatsInfo) Transferring(remote string) {
 func (s *StatsInfo) DoneTransferring(remote string, ok bool) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	if _, found := s.transferring[remote]; found {
+		transferringMetric.Dec()
+	}
 	delete(s.transferring, remote)
 	if ok {
 		s.transfers++
+		transfersMetric.Inc()
+	}
+}
+
+// Deletes updates the stats for deletes
+func (s *StatsInfo) Deletes(deletes int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.deletes += deletes
+	if deletes > 0 {
+		deletesMetric.Add(float64(deletes))
 	}
 }
 
+// GetDeletes reads the number of deletes
+func (s *StatsInfo) GetDeletes() int64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.deletes
+}
+
 // Account limits and accounts for one transfer
 type Account struct {
 	// The mutex is to make sure Read() and Close() aren't called
@@ -354,12 +512,17 @@ type Account struct {
 	exit    chan struct{}      // channel that will be closed when transfer is finished
 	withBuf bool               // is using a buffered in
 
-	wholeFileDisabled bool // disables the whole file when doing parts
+	wholeFileDisabled bool       // disables the whole file when doing parts
+	stats             *StatsInfo // the stats this transfer is accounted into
 }
 
 // NewAccountSizeName makes a Account reader for an io.ReadCloser of
 // the given size and name
-func NewAccountSizeName(in io.ReadCloser, size int64, name string) *Account {
+//
+// The transfer is accounted into the StatsInfo for ctx.
+//
+// in may be nil if the transfer is only read through accountPart.
+func NewAccountSizeName(ctx context.Context, in io.ReadCloser, size int64, name string) *Account {
 	acc := &Account{
 		in:     in,
 		origIn: in,
@@ -368,15 +531,18 @@ func NewAccountSizeName(in io.ReadCloser, size int64, name string) *Account {
 		exit:   make(chan struct{}),
 		avg:    ewma.NewMovingAverage(),
 		lpTime: time.Now(),
+		stats:  StatsFromContext(ctx),
 	}
 	go acc.averageLoop()
-	Stats.inProgress.set(acc.name, acc)
+	acc.stats.inProgress.set(acc.name, acc)
 	return acc
 }
 
 // NewAccount makes a Account reader for an object
-func NewAccount(in io.ReadCloser, obj Object) *Account {
-	return NewAccountSizeName(in, obj.Size(), obj.Remote())
+//
+// The transfer is accounted into the StatsInfo for ctx.
+func NewAccount(ctx context.Context, in io.ReadCloser, obj Object) *Account {
+	return NewAccountSizeName(ctx, in, obj.Size(), obj.Remote())
 }
 
 // WithBuffer - If the file is above a certain size it adds an Async reader
@@ -467,6 +633,11 @@ func (acc *Account) read(in io.Reader, p []byte) (n int, err error) {
 	}
 	acc.statmu.Unlock()
 
+	// Stop the transfer if --max-transfer has been reached
+	if maxTransferReachedHard(acc.stats) {
+		return 0, ErrorMaxTransferLimitReached
+	}
+
 	n, err = in.Read(p)
 
 	// Update Stats
@@ -475,7 +646,7 @@ func (acc *Account) read(in io.Reader, p []byte) (n int, err error) {
 	acc.bytes += int64(n)
 	acc.statmu.Unlock()
 
-	Stats.Bytes(int64(n))
+	acc.stats.Bytes(int64(n))
 
 	// Get the token bucket in use
 	tokenBucketMu.Lock()
@@ -596,6 +767,26 @@ func (acc *Account) String() string {
 	)
 }
 
+// RemoteStats produces stats for this file as a map suitable for
+// encoding as JSON for the remote control
+func (acc *Account) RemoteStats() map[string]interface{} {
+	bytes, size := acc.Progress()
+	speed, speedAvg := acc.Speed()
+	out := map[string]interface{}{
+		"bytes":    bytes,
+		"size":     size,
+		"speed":    speed,
+		"speedAvg": speedAvg,
+	}
+	if size > 0 {
+		out["percentage"] = int(100 * float64(bytes) / float64(size))
+	}
+	if eta, ok := acc.ETA(); ok {
+		out["eta"] = eta.Seconds()
+	}
+	return out
+}
+
 // Close the object
 func (acc *Account) Close() error {
 	acc.mu.Lock()
@@ -605,7 +796,10 @@ func (acc *Account) Close() error {
 	}
 	acc.closed = true
 	close(acc.exit)
-	Stats.inProgress.clear(acc.name)
+	acc.stats.inProgress.clear(acc.name)
+	if acc.in == nil {
+		return nil
+	}
 	return acc.in.Close()
 }
 
@@ -630,9 +824,10 @@ func (a *accountStream) Read(p []byte) (n int, err error) {
 
 // AccountByPart turns off whole file accounting
 //
-// Returns the current account or nil if not found
-func AccountByPart(obj Object) *Account {
-	acc := Stats.inProgress.get(obj.Remote())
+// Returns the current account in the StatsInfo for ctx or nil if not
+// found
+func AccountByPart(ctx context.Context, obj Object) *Account {
+	acc := StatsFromContext(ctx).inProgress.get(obj.Remote())
 	if acc == nil {
 		Debugf(obj, "Didn't find object to account part transfer")
 		return nil
@@ -645,8 +840,8 @@ func AccountByPart(obj Object) *Account {
 //
 // It disables the whole file counter and returns an io.Reader to wrap
 // a segment of the transfer.
-func AccountPart(obj Object, in io.Reader) io.Reader {
-	acc := AccountByPart(obj)
+func AccountPart(ctx context.Context, obj Object, in io.Reader) io.Reader {
+	acc := AccountByPart(ctx, obj)
 	if acc == nil {
 		return in
 	}
@@ -0,0 +1,502 @@
+// Implementation of bidirectional sync
+
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// BisyncOpt contains the options for Bisync
+type BisyncOpt struct {
+	Resync           bool   // ignore the saved state and make both sides contain all the files
+	MaxDeletePercent int    // refuse to run if more than this percentage of files would be deleted on either side
+	Force            bool   // run even if MaxDeletePercent is exceeded
+	StateDir         string // directory to keep the listings in - blank for the default
+}
+
+// DefaultBisyncOpt is the default values used for BisyncOpt
+var DefaultBisyncOpt = BisyncOpt{
+	MaxDeletePercent: 50,
+}
+
+// ErrorBisyncNoState is returned by Bisync when there are no saved
+// listings from a previous run
+var ErrorBisyncNoState = errors.New("no listings from a previous run found - run with --resync first")
+
+// bisyncFile is the saved state of a single file
+type bisyncFile struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Hash    string    `json:"hash,omitempty"`
+}
+
+// bisyncListing is the saved state of one side keyed by remote
+type bisyncListing map[string]bisyncFile
+
+// bisyncState is the state saved between runs
+type bisyncState struct {
+	Path1 bisyncListing `json:"path1"`
+	Path2 bisyncListing `json:"path2"`
+}
+
+// bisyncChange describes how a file has changed since the last run
+type bisyncChange int
+
+// Types of bisyncChange
+const (
+	bisyncUnchanged bisyncChange = iota
+	bisyncNew
+	bisyncChanged
+	bisyncDeleted
+)
+
+// bisync holds the state for a bidirectional sync
+type bisync struct {
+	ctx      context.Context
+	opt      BisyncOpt
+	path1    Fs
+	path2    Fs
+	hashType HashType // hash type to record or HashNone
+	mu       sync.Mutex
+	objects1 map[string]Object // current objects on path1
+	objects2 map[string]Object // current objects on path2
+}
+
+// SrcOnly is called for a DirEntry found only in path1
+func (b *bisync) SrcOnly(src DirEntry) (recurse bool) {
+	return b.add(b.objects1, src)
+}
+
+// DstOnly is called for a DirEntry found only in path2
+func (b *bisync) DstOnly(dst DirEntry) (recurse bool) {
+	return b.add(b.objects2, dst)
+}
+
+// Match is called for a DirEntry found on both path1 and path2
+func (b *bisync) Match(dst, src DirEntry) (recurse bool) {
+	recurse1 := b.add(b.objects1, src)
+	recurse2 := b.add(b.objects2, dst)
+	return recurse1 || recurse2
+}
+
+// add records an object found in the march in objects returning
+// whether to recurse into it if it is a directory
+func (b *bisync) add(objects map[string]Object, entry DirEntry) (recurse bool) {
+	switch x := entry.(type) {
+	case Object:
+		b.mu.Lock()
+		objects[x.Remote()] = x
+		b.mu.Unlock()
+	case Directory:
+		return true
+	default:
+		panic("Bad object in DirEntries")
+	}
+	return false
+}
+
+// list reads the current objects on both sides using march
+func (b *bisync) list() error {
+	b.objects1 = make(map[string]Object)
+	b.objects2 = make(map[string]Object)
+	errorsBefore := StatsFromContext(b.ctx).GetErrors()
+	newMarch(b.ctx, b.path2, b.path1, "", b).run()
+	if b.ctx.Err() != nil {
+		return b.ctx.Err()
+	}
+	if StatsFromContext(b.ctx).GetErrors() != errorsBefore {
+		return errors.New("failed to list directories")
+	}
+	return nil
+}
+
+// listing makes a bisyncListing from the objects passed in
+func (b *bisync) listing(objects map[string]Object) (listing bisyncListing) {
+	listing = make(bisyncListing, len(objects))
+	for remote, o := range objects {
+		file := bisyncFile{
+			Size:    o.Size(),
+			ModTime: o.ModTime(),
+		}
+		if b.hashType != HashNone {
+			hash, err := o.Hash(b.hashType)
+			if err != nil {
+				Debugf(o, "Failed to read hash: %v", err)
+			}
+			file.Hash = hash
+		}
+		listing[remote] = file
+	}
+	return listing
+}
+
+// changed returns how the object o has changed since it was saved
+// in the listing
+func (b *bisync) changed(listing bisyncListing, remote string, o Object) bisyncChange {
+	old, found := listing[remote]
+	switch {
+	case !found && o == nil:
+		return bisyncUnchanged
+	case !found:
+		return bisyncNew
+	case o == nil:
+		return bisyncDeleted
+	}
+	if !Config.IgnoreSize && old.Size != o.Size() {
+		return bisyncChanged
+	}
+	if b.hashType != HashNone && old.Hash != "" {
+		hash, err := o.Hash(b.hashType)
+		if err == nil && hash != "" && !HashEquals(old.Hash, hash) {
+			return bisyncChanged
+		}
+		return bisyncUnchanged
+	}
+	if !Config.SizeOnly && Config.ModifyWindow != ModTimeNotSupported {
+		dt := o.ModTime().Sub(old.ModTime)
+		if dt >= Config.ModifyWindow || dt <= -Config.ModifyWindow {
+			return bisyncChanged
+		}
+	}
+	return bisyncUnchanged
+}
+
+var bisyncStateNameRe = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// bisyncStateNameMax is the longest the readable part of each half
+// of the state file name can be
+const bisyncStateNameMax = 64
+
+// stateFile returns the name of the file to store the listings for
+// path1 and path2 in
+//
+// Each remote is named with a readable version of "name:root"
+// followed by a hash of it, as the readable part on its own can be
+// the same for different remotes (eg "a/b" and "a_b").
+func (b *bisync) stateFile() string {
+	dir := b.opt.StateDir
+	if dir == "" {
+		dir = filepath.Join(CacheDir, "bisync")
+	}
+	name := func(f Fs) string {
+		fsString := f.Name() + ":" + f.Root()
+		readable := bisyncStateNameRe.ReplaceAllString(fsString, "_")
+		if len(readable) > bisyncStateNameMax {
+			readable = readable[:bisyncStateNameMax]
+		}
+		hash := sha256.Sum256([]byte(fsString))
+		return fmt.Sprintf("%s-%x", readable, hash[:16])
+	}
+	return filepath.Join(dir, name(b.path1)+".."+name(b.path2)+".json")
+}
+
+// loadState reads the listings saved by the last run
+func (b *bisync) loadState() (state *bisyncState, err error) {
+	data, err := ioutil.ReadFile(b.stateFile())
+	if os.IsNotExist(err) {
+		return nil, ErrorBisyncNoState
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read bisync listings")
+	}
+	state = new(bisyncState)
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode bisync listings")
+	}
+	return state, nil
+}
+
+// saveState writes the listings for the next run
+func (b *bisync) saveState(state *bisyncState) error {
+	stateFile := b.stateFile()
+	err := os.MkdirAll(filepath.Dir(stateFile), 0700)
+	if err != nil {
+		return errors.Wrap(err, "failed to make bisync listings directory")
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode bisync listings")
+	}
+	tmpFile := stateFile + ".tmp"
+	err = ioutil.WriteFile(tmpFile, data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write bisync listings")
+	}
+	err = os.Rename(tmpFile, stateFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to save bisync listings")
+	}
+	return nil
+}
+
+// bisyncAction is something to do to bring the two sides into line
+type bisyncAction func() error
+
+// copyTo copies src to the remote in f, replacing dst if set
+func (b *bisync) copyTo(f Fs, dst Object, remote string, src Object) bisyncAction {
+	return func() error {
+		StatsFromContext(b.ctx).Transferring(remote)
+		err := Copy(b.ctx, f, dst, remote, src)
+		StatsFromContext(b.ctx).DoneTransferring(remote, err == nil)
+		return err
+	}
+}
+
+// deleteFile deletes o
+func (b *bisync) deleteFile(o Object) bisyncAction {
+	return func() error {
+		return DeleteFile(b.ctx, o)
+	}
+}
+
+// conflict renames the conflicting files on both sides and copies
+// each of them to the other side so no data is lost
+func (b *bisync) conflict(remote string, o1, o2 Object) bisyncAction {
+	return func() error {
+		remote1, remote2 := remote+"..path1", remote+"..path2"
+		Logf(remote, "Both sides changed - renaming to %q and %q", remote1, remote2)
+		if Config.DryRun {
+			return nil
+		}
+		err := Move(b.ctx, b.path1, nil, remote1, o1)
+		if err != nil {
+			return err
+		}
+		err = Move(b.ctx, b.path2, nil, remote2, o2)
+		if err != nil {
+			return err
+		}
+		new1, err := b.path1.NewObject(b.ctx, remote1)
+		if err != nil {
+			return errors.Wrap(err, "failed to find renamed file")
+		}
+		new2, err := b.path2.NewObject(b.ctx, remote2)
+		if err != nil {
+			return errors.Wrap(err, "failed to find renamed file")
+		}
+		err = b.copyTo(b.path2, nil, remote1, new1)()
+		if err != nil {
+			return err
+		}
+		return b.copyTo(b.path1, nil, remote2, new2)()
+	}
+}
+
+// plan works out what needs doing to bring path1 and path2 into
+// line given the state from the last run
+//
+// It returns the actions and the number of files which will be
+// deleted from path1 and path2
+func (b *bisync) plan(state *bisyncState) (actions []bisyncAction, deletes1, deletes2 int) {
+	remotes := map[string]struct{}{}
+	for _, listing := range []map[string]Object{b.objects1, b.objects2} {
+		for remote := range listing {
+			remotes[remote] = struct{}{}
+		}
+	}
+	for _, listing := range []bisyncListing{state.Path1, state.Path2} {
+		for remote := range listing {
+			remotes[remote] = struct{}{}
+		}
+	}
+	sorted := make([]string, 0, len(remotes))
+	for remote := range remotes {
+		sorted = append(sorted, remote)
+	}
+	sort.Strings(sorted)
+
+	for _, remote := range sorted {
+		o1, o2 := b.objects1[remote], b.objects2[remote]
+		change1 := b.changed(state.Path1, remote, o1)
+		change2 := b.changed(state.Path2, remote, o2)
+		switch {
+		case change1 == bisyncUnchanged && change2 == bisyncUnchanged:
+			// nothing to do
+		case change1 == bisyncDeleted && change2 == bisyncDeleted:
+			// deleted on both sides
+		case change1 == bisyncDeleted:
+			if change2 == bisyncUnchanged {
+				Debugf(remote, "Deleted on %v - deleting on %v", b.path1, b.path2)
+				actions = append(actions, b.deleteFile(o2))
+				deletes2++
+			} else {
+				Debugf(remote, "Deleted on %v but changed on %v - copying", b.path1, b.path2)
+				actions = append(actions, b.copyTo(b.path1, nil, remote, o2))
+			}
+		case change2 == bisyncDeleted:
+			if change1 == bisyncUnchanged {
+				Debugf(remote, "Deleted on %v - deleting on %v", b.path2, b.path1)
+				actions = append(actions, b.deleteFile(o1))
+				deletes1++
+			} else {
+				Debugf(remote, "Deleted on %v but changed on %v - copying", b.path2, b.path1)
+				actions = append(actions, b.copyTo(b.path2, nil, remote, o1))
+			}
+		case change2 == bisyncUnchanged:
+			Debugf(remote, "Changed on %v - copying to %v", b.path1, b.path2)
+			actions = append(actions, b.copyTo(b.path2, o2, remote, o1))
+		case change1 == bisyncUnchanged:
+			Debugf(remote, "Changed on %v - copying to %v", b.path2, b.path1)
+			actions = append(actions, b.copyTo(b.path1, o1, remote, o2))
+		case o1 != nil && o2 != nil && Equal(b.ctx, o1, o2):
+			Debugf(remote, "Changed identically on both sides")
+		default:
+			actions = append(actions, b.conflict(remote, o1, o2))
+		}
+	}
+	return actions, deletes1, deletes2
+}
+
+// planResync works out what needs doing to make both sides contain
+// all the files, ignoring any saved state.  Where a file differs
+// the version on path1 wins.
+func (b *bisync) planResync() (actions []bisyncAction) {
+	for remote, o1 := range b.objects1 {
+		o2 := b.objects2[remote]
+		if o2 == nil || !Equal(b.ctx, o1, o2) {
+			actions = append(actions, b.copyTo(b.path2, o2, remote, o1))
+		}
+	}
+	for remote, o2 := range b.objects2 {
+		if b.objects1[remote] == nil {
+			actions = append(actions, b.copyTo(b.path1, nil, remote, o2))
+		}
+	}
+	return actions
+}
+
+// checkDeletes returns an error if more than MaxDeletePercent of
+// the files in listing would be deleted from f
+func (b *bisync) checkDeletes(f Fs, listing bisyncListing, deletes int) error {
+	if b.opt.Force || deletes == 0 || len(listing) == 0 {
+		return nil
+	}
+	percent := 100 * deletes / len(listing)
+	if percent > b.opt.MaxDeletePercent {
+		return FatalError(errors.Errorf("refusing to delete %d of %d files (%d%%) on %v - more than --max-delete-percent %d%% - use --force to override", deletes, len(listing), percent, f, b.opt.MaxDeletePercent))
+	}
+	return nil
+}
+
+// run the actions with --transfers in parallel
+func (b *bisync) runActions(actions []bisyncAction) (err error) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		actionC = make(chan bisyncAction, Config.Transfers)
+	)
+	for i := 0; i < Config.Transfers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for action := range actionC {
+				if b.ctx.Err() != nil {
+					continue
+				}
+				actionErr := action()
+				if actionErr != nil {
+					mu.Lock()
+					err = actionErr
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, action := range actions {
+		actionC <- action
+	}
+	close(actionC)
+	wg.Wait()
+	if err == nil {
+		err = b.ctx.Err()
+	}
+	return err
+}
+
+// Bisync makes path1 and path2 contain the same files, propagating
+// new, changed and deleted files in both directions since the last
+// time it was run.
+//
+// The listings of both sides are saved after a successful run and
+// used to detect the changes next time.  If there are no saved
+// listings then opt.Resync must be set to make the first ones.
+//
+// Files changed on both sides are renamed with ..path1 and ..path2
+// suffixes and both copies kept on both sides.
+func Bisync(ctx context.Context, path1, path2 Fs, opt BisyncOpt) error {
+	if Overlapping(path1, path2) {
+		return FatalError(errors.New("can't bisync overlapping remotes"))
+	}
+	b := &bisync{
+		ctx:   ctx,
+		opt:   opt,
+		path1: path1,
+		path2: path2,
+	}
+	if Config.CheckSum {
+		b.hashType = path1.Hashes().Overlap(path2.Hashes()).GetOne()
+	}
+
+	var state *bisyncState
+	if !opt.Resync {
+		var err error
+		state, err = b.loadState()
+		if err != nil {
+			return FatalError(err)
+		}
+	}
+
+	Infof(path1, "Listing %v and %v", path1, path2)
+	err := b.list()
+	if err != nil {
+		return err
+	}
+
+	var actions []bisyncAction
+	if opt.Resync {
+		actions = b.planResync()
+	} else {
+		var deletes1, deletes2 int
+		actions, deletes1, deletes2 = b.plan(state)
+		err = b.checkDeletes(path1, state.Path1, deletes1)
+		if err != nil {
+			return err
+		}
+		err = b.checkDeletes(path2, state.Path2, deletes2)
+		if err != nil {
+			return err
+		}
+	}
+
+	Infof(path1, "Making %d changes", len(actions))
+	err = b.runActions(actions)
+	if err != nil {
+		return errors.Wrap(err, "bisync failed - listings not updated so the next run will retry")
+	}
+	if Config.DryRun {
+		return nil
+	}
+
+	// Read the listings again to save for the next run
+	err = b.list()
+	if err != nil {
+		return errors.Wrap(err, "failed to read listings to save")
+	}
+	return b.saveState(&bisyncState{
+		Path1: b.listing(b.objects1),
+		Path2: b.listing(b.objects2),
+	})
+}
@@ -0,0 +1,147 @@
+// Test bisync
+
+package fs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// bisync options with the listings in a temporary directory
+func bisyncOpt(t *testing.T) (opt fs.BisyncOpt, cleanup func()) {
+	stateDir, err := ioutil.TempDir("", "rclone-bisync-test")
+	require.NoError(t, err)
+	opt = fs.DefaultBisyncOpt
+	opt.StateDir = stateDir
+	return opt, func() {
+		_ = os.RemoveAll(stateDir)
+	}
+}
+
+// remove the object remote from f
+func bisyncRemove(t *testing.T, f fs.Fs, remote string) {
+	o, err := f.NewObject(context.Background(), remote)
+	require.NoError(t, err)
+	require.NoError(t, o.Remove(context.Background()))
+}
+
+func TestBisyncNoState(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := bisyncOpt(t)
+	defer cleanup()
+	r.Mkdir(r.Fremote)
+
+	err := fs.Bisync(context.Background(), r.Flocal, r.Fremote, opt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--resync")
+}
+
+// Check remotes whose names only differ in characters which aren't
+// allowed in the state file name don't share state
+func TestBisyncStateNames(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := bisyncOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "rclone-bisync-names")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	fSlash, err := fs.NewFs(filepath.Join(dir, "a", "b"))
+	require.NoError(t, err)
+	fUnderscore, err := fs.NewFs(filepath.Join(dir, "a_b"))
+	require.NoError(t, err)
+	r.Mkdir(r.Fremote)
+	r.Mkdir(fSlash)
+	r.Mkdir(fUnderscore)
+
+	opt.Resync = true
+	require.NoError(t, fs.Bisync(ctx, r.Fremote, fSlash, opt))
+	opt.Resync = false
+	require.NoError(t, fs.Bisync(ctx, r.Fremote, fSlash, opt))
+
+	err = fs.Bisync(ctx, r.Fremote, fUnderscore, opt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--resync")
+}
+
+func TestBisync(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := bisyncOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Resync makes both sides the same
+	file1 := r.WriteFile("one", "one", t1)
+	file2 := r.WriteObject("sub dir/two", "two", t2)
+	file3 := r.WriteBoth("three", "three", t3)
+	opt.Resync = true
+	require.NoError(t, fs.Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	// New and changed files propagate both ways and deletes too
+	file4 := r.WriteFile("four", "four", t1)
+	file2 = r.WriteFile("sub dir/two", "two changed", t3)
+	bisyncRemove(t, r.Fremote, "one")
+	require.NoError(t, fs.Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file2, file3, file4)
+	fstest.CheckItems(t, r.Fremote, file2, file3, file4)
+
+	// Nothing to do the second time
+	require.NoError(t, fs.Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file2, file3, file4)
+	fstest.CheckItems(t, r.Fremote, file2, file3, file4)
+
+	// Changes on both sides are renamed and kept
+	r.WriteFile("three", "three path1", t2)
+	r.WriteObject("three", "three path2", t1)
+	require.NoError(t, fs.Bisync(ctx, r.Flocal, r.Fremote, opt))
+	conflict1 := fstest.NewItem("three..path1", "three path1", t2)
+	conflict2 := fstest.NewItem("three..path2", "three path2", t1)
+	fstest.CheckItems(t, r.Flocal, file2, file4, conflict1, conflict2)
+	fstest.CheckItems(t, r.Fremote, file2, file4, conflict1, conflict2)
+}
+
+func TestBisyncMaxDelete(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := bisyncOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	file1 := r.WriteBoth("one", "one", t1)
+	file2 := r.WriteBoth("two", "two", t1)
+	file3 := r.WriteBoth("three", "three", t1)
+	opt.Resync = true
+	require.NoError(t, fs.Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	// Deleting 2 of 3 files is refused
+	bisyncRemove(t, r.Flocal, "one")
+	bisyncRemove(t, r.Flocal, "two")
+	err := fs.Bisync(ctx, r.Flocal, r.Fremote, opt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to delete")
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	// Unless forced
+	opt.Force = true
+	require.NoError(t, fs.Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file3)
+	fstest.CheckItems(t, r.Fremote, file3)
+}
@@ -105,6 +105,8 @@ var (
 	noTraverse            = BoolP("no-traverse", "", false, "Don't traverse destination file system on copy.")
 	noUpdateModTime       = BoolP("no-update-modtime", "", false, "Don't update destination mod-time if files identical.")
 	backupDir             = StringP("backup-dir", "", "", "Make backups into hierarchy based in DIR.")
+	compareDest           = StringArrayP("compare-dest", "", nil, "Include additional server-side path during comparison.")
+	copyDest              = StringArrayP("copy-dest", "", nil, "Implies --compare-dest but also copies files from path into destination.")
 	suffix                = StringP("suffix", "", "", "Suffix for use with --backup-dir.")
 	useListR              = BoolP("fast-list", "", false, "Use recursive list if available. Uses more memory but fewer transactions.")
 	tpsLimit              = Float64P("tpslimit", "", 0, "Limit HTTP transactions per second to this.")
@@ -114,12 +116,17 @@ var (
 	userAgent             = StringP("user-agent", "", "rclone/"+Version, "Set the user-agent to a specified string. The default is rclone/ version")
 	immutable             = BoolP("immutable", "", false, "Do not modify files. Fail if existing files have been modified.")
 	autoConfirm           = BoolP("auto-confirm", "", false, "If enabled, do not request console confirmation.")
+	multiThreadStreams    = IntP("multi-thread-streams", "", 4, "Max number of streams to use for multi-thread downloads.")
+	metadata              = BoolP("metadata", "M", false, "If set, preserve metadata when copying objects.")
 	streamingUploadCutoff = SizeSuffix(100 * 1024)
 	dump                  DumpFlags
 	logLevel              = LogLevelNotice
 	statsLogLevel         = LogLevelInfo
 	bwLimit               BwTimetable
 	bufferSize            SizeSuffix = 16 << 20
+	multiThreadCutoff     SizeSuffix = 250 << 20
+	maxTransfer           SizeSuffix = -1
+	cutoffMode            CutoffMode
 
 	// Key to use for password en/decryption.
 	// When nil, no encryption will be used for saving.
@@ -133,6 +140,9 @@ func init() {
 	VarP(&bufferSize, "buffer-size", "", "Buffer size when copying files.")
 	VarP(&streamingUploadCutoff, "streaming-upload-cutoff", "", "Cutoff for switching to chunked upload if file size is unknown. Upload starts after reaching cutoff or when file ends.")
 	VarP(&dump, "dump", "", "List of items to dump from: "+dumpFlagsList)
+	VarP(&multiThreadCutoff, "multi-thread-cutoff", "", "Use multi-thread downloads for files above this size.")
+	VarP(&maxTransfer, "max-transfer", "", "Maximum size of data to transfer.")
+	VarP(&cutoffMode, "cutoff-mode", "", "Mode to stop transfers when reaching the max transfer limit HARD|SOFT|CAUTIOUS")
 }
 
 // crypt internals
@@ -245,6 +255,8 @@ type ConfigInfo struct {
 	NoUpdateModTime       bool
 	DataRateUnit          string
 	BackupDir             string
+	CompareDest           []string
+	CopyDest              []string
 	Suffix                string
 	UseListR              bool
 	BufferSize            SizeSuffix
@@ -255,6 +267,11 @@ type ConfigInfo struct {
 	Immutable             bool
 	AutoConfirm           bool
 	StreamingUploadCutoff SizeSuffix
+	MultiThreadCutoff     SizeSuffix
+	MultiThreadStreams    int
+	Metadata              bool
+	MaxTransfer           SizeSuffix
+	CutoffMode            CutoffMode
 }
 
 // Return the path to the configuration file
@@ -386,6 +403,8 @@ func LoadConfig() {
 	Config.NoTraverse = *noTraverse
 	Config.NoUpdateModTime = *noUpdateModTime
 	Config.BackupDir = *backupDir
+	Config.CompareDest = *compareDest
+	Config.CopyDest = *copyDest
 	Config.Suffix = *suffix
 	Config.UseListR = *useListR
 	Config.TPSLimit = *tpsLimit
@@ -394,6 +413,11 @@ func LoadConfig() {
 	Config.AutoConfirm = *autoConfirm
 	Config.BufferSize = bufferSize
 	Config.StreamingUploadCutoff = streamingUploadCutoff
+	Config.MultiThreadCutoff = multiThreadCutoff
+	Config.MultiThreadStreams = *multiThreadStreams
+	Config.Metadata = *metadata
+	Config.MaxTransfer = maxTransfer
+	Config.CutoffMode = cutoffMode
 	Config.Dump = dump
 	if *dumpHeaders {
 		Config.Dump |= DumpHeaders
@@ -428,6 +452,10 @@ func LoadConfig() {
 		log.Fatalf(`Can only use --suffix with --backup-dir.`)
 	}
 
+	if len(Config.CompareDest) > 0 && len(Config.CopyDest) > 0 {
+		log.Fatalf(`Can't use --compare-dest with --copy-dest.`)
+	}
+
 	if *bindAddr != "" {
 		addrs, err := net.LookupIP(*bindAddr)
 		if err != nil {
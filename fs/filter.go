@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // Global
@@ -24,6 +25,7 @@ var (
 	excludeRule    = StringArrayP("exclude", "", nil, "Exclude files matching pattern")
 	excludeFrom    = StringArrayP("exclude-from", "", nil, "Read exclude patterns from file")
 	excludeFile    = StringP("exclude-if-present", "", "", "Exclude directories if filename is present")
+	ignoreFile     = StringP("ignore-file", "", "", "Read gitignore style exclude patterns from files with this name in each directory")
 	includeRule    = StringArrayP("include", "", nil, "Include files matching pattern")
 	includeFrom    = StringArrayP("include-from", "", nil, "Read include patterns from file")
 	filesFrom      = StringArrayP("files-from", "", nil, "Read list of source-file names from file")
@@ -106,6 +108,7 @@ type Filter struct {
 	fileRules      rules
 	dirRules       rules
 	ExcludeFile    string
+	IgnoreFile     string
 	files          FilesMap // files if filesFrom
 	dirs           FilesMap // dirs from filesFrom
 }
@@ -223,6 +226,7 @@ func NewFilter() (f *Filter, err error) {
 		}
 	}
 	f.ExcludeFile = *excludeFile
+	f.IgnoreFile = *ignoreFile
 	if addImplicitExclude {
 		err = f.Add(false, "/**")
 		if err != nil {
@@ -375,7 +379,8 @@ func (f *Filter) InActive() bool {
 		f.MaxSize < 0 &&
 		f.fileRules.len() == 0 &&
 		f.dirRules.len() == 0 &&
-		len(f.ExcludeFile) == 0)
+		len(f.ExcludeFile) == 0 &&
+		len(f.IgnoreFile) == 0)
 }
 
 // includeRemote returns whether this remote passes the filter rules.
@@ -407,12 +412,12 @@ func (f *Filter) ListContainsExcludeFile(entries DirEntries) bool {
 
 // IncludeDirectory returns a function which checks whether this
 // directory should be included in the sync or not.
-func (f *Filter) IncludeDirectory(fs Fs) func(string) (bool, error) {
+func (f *Filter) IncludeDirectory(ctx context.Context, fs Fs) func(string) (bool, error) {
 	return func(remote string) (bool, error) {
 		remote = strings.Trim(remote, "/")
 		// first check if we need to remove directory based on
 		// the exclude file
-		excl, err := f.DirContainsExcludeFile(fs, remote)
+		excl, err := f.DirContainsExcludeFile(ctx, fs, remote)
 		if err != nil {
 			return false, err
 		}
@@ -439,9 +444,9 @@ func (f *Filter) IncludeDirectory(fs Fs) func(string) (bool, error) {
 // DirContainsExcludeFile checks if exclude file is present in a
 // directroy. If fs is nil, it works properly if ExcludeFile is an
 // empty string (for testing).
-func (f *Filter) DirContainsExcludeFile(fs Fs, remote string) (bool, error) {
+func (f *Filter) DirContainsExcludeFile(ctx context.Context, fs Fs, remote string) (bool, error) {
 	if len(Config.Filter.ExcludeFile) > 0 {
-		exists, err := FileExists(fs, path.Join(remote, Config.Filter.ExcludeFile))
+		exists, err := FileExists(ctx, fs, path.Join(remote, Config.Filter.ExcludeFile))
 		if err != nil {
 			return false, err
 		}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // Constants
@@ -51,6 +52,7 @@ var (
 	ErrorCantMoveOverlapping         = errors.New("can't move files on overlapping remotes")
 	ErrorDirectoryNotEmpty           = errors.New("directory not empty")
 	ErrorImmutableModified           = errors.New("immutable file modified")
+	ErrorMaxTransferLimitReached     = FatalError(errors.New("max transfer limit reached as set by --max-transfer"))
 )
 
 // RegInfo provides information about a filesystem
@@ -119,28 +121,28 @@ type Fs interface {
 	//
 	// This should return ErrDirNotFound if the directory isn't
 	// found.
-	List(dir string) (entries DirEntries, err error)
+	List(ctx context.Context, dir string) (entries DirEntries, err error)
 
 	// NewObject finds the Object at remote.  If it can't be found
 	// it returns the error ErrorObjectNotFound.
-	NewObject(remote string) (Object, error)
+	NewObject(ctx context.Context, remote string) (Object, error)
 
 	// Put in to the remote path with the modTime given of the given size
 	//
 	// May create the object even if it returns an error - if so
 	// will return the object and the error, otherwise will return
 	// nil and the error
-	Put(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	Put(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 
 	// Mkdir makes the directory (container, bucket)
 	//
 	// Shouldn't return an error if it already exists
-	Mkdir(dir string) error
+	Mkdir(ctx context.Context, dir string) error
 
 	// Rmdir removes the directory (container, bucket) if empty
 	//
 	// Return an error if it doesn't exist or isn't empty
-	Rmdir(dir string) error
+	Rmdir(ctx context.Context, dir string) error
 }
 
 // Info provides a read only interface to information about a filesystem.
@@ -169,16 +171,16 @@ type Object interface {
 	ObjectInfo
 
 	// SetModTime sets the metadata on the object to set the modification date
-	SetModTime(time.Time) error
+	SetModTime(ctx context.Context, t time.Time) error
 
 	// Open opens the file for read.  Call Close() on the returned io.ReadCloser
-	Open(options ...OpenOption) (io.ReadCloser, error)
+	Open(ctx context.Context, options ...OpenOption) (io.ReadCloser, error)
 
 	// Update in to the object with the modTime given of the given size
-	Update(in io.Reader, src ObjectInfo, options ...OpenOption) error
+	Update(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) error
 
 	// Removes this object
-	Remove() error
+	Remove(ctx context.Context) error
 }
 
 // ObjectInfo provides read only information about an object.
@@ -234,6 +236,12 @@ type MimeTyper interface {
 	MimeType() string
 }
 
+// IDer is an optional interface for Object
+type IDer interface {
+	// ID returns the ID of the Object if known, or "" if not
+	ID() string
+}
+
 // ListRCallback defines a callback function for ListR to use
 //
 // It is called for each tranche of entries read from the listing and
@@ -241,7 +249,7 @@ type MimeTyper interface {
 type ListRCallback func(entries DirEntries) error
 
 // ListRFn is defines the call used to recursively list a directory
-type ListRFn func(dir string, callback ListRCallback) error
+type ListRFn func(ctx context.Context, dir string, callback ListRCallback) error
 
 // Features describe the optional features of the Fs
 type Features struct {
@@ -252,6 +260,10 @@ type Features struct {
 	WriteMimeType           bool // can set the mime type of objects
 	CanHaveEmptyDirectories bool // can have empty directories
 	BucketBased             bool // is bucket based (like s3, swift etc)
+	ReadMetadata            bool // can read metadata from objects
+	WriteMetadata           bool // can write metadata to objects
+	ServerSideAcrossConfigs bool // can server side copy/move between remotes of this type
+	RangeRead               bool // can read part of an object using a RangeOption
 
 	// Purge all files in the root and the root directory
 	//
@@ -259,7 +271,7 @@ type Features struct {
 	// quicker than just running Remove() on the result of List()
 	//
 	// Return an error if it doesn't exist
-	Purge func() error
+	Purge func(ctx context.Context) error
 
 	// Copy src to this remote using server side copy operations.
 	//
@@ -267,10 +279,11 @@ type Features struct {
 	//
 	// It returns the destination Object and a possible error
 	//
-	// Will only be called if src.Fs().Name() == f.Name()
+	// Will only be called if src.Fs().Name() == f.Name() or if
+	// ServerSideAcrossConfigs is set and src is the same type of Fs
 	//
 	// If it isn't possible then return fs.ErrorCantCopy
-	Copy func(src Object, remote string) (Object, error)
+	Copy func(ctx context.Context, src Object, remote string) (Object, error)
 
 	// Move src to this remote using server side move operations.
 	//
@@ -278,10 +291,11 @@ type Features struct {
 	//
 	// It returns the destination Object and a possible error
 	//
-	// Will only be called if src.Fs().Name() == f.Name()
+	// Will only be called if src.Fs().Name() == f.Name() or if
+	// ServerSideAcrossConfigs is set and src is the same type of Fs
 	//
 	// If it isn't possible then return fs.ErrorCantMove
-	Move func(src Object, remote string) (Object, error)
+	Move func(ctx context.Context, src Object, remote string) (Object, error)
 
 	// DirMove moves src, srcRemote to this remote at dstRemote
 	// using server side move operations.
@@ -291,7 +305,7 @@ type Features struct {
 	// If it isn't possible then return fs.ErrorCantDirMove
 	//
 	// If destination exists then return fs.ErrorDirExists
-	DirMove func(src Fs, srcRemote, dstRemote string) error
+	DirMove func(ctx context.Context, src Fs, srcRemote, dstRemote string) error
 
 	// DirChangeNotify calls the passed function with a path
 	// of a directory that has had changes. If the implementation
@@ -313,24 +327,24 @@ type Features struct {
 	//
 	// May create duplicates or return errors if src already
 	// exists.
-	PutUnchecked func(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutUnchecked func(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 
 	// PutStream uploads to the remote path with the modTime given of indeterminate size
 	//
 	// May create the object even if it returns an error - if so
 	// will return the object and the error, otherwise will return
 	// nil and the error
-	PutStream func(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutStream func(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 
 	// MergeDirs merges the contents of all the directories passed
 	// in into the first one and rmdirs the other directories.
-	MergeDirs func([]Directory) error
+	MergeDirs func(ctx context.Context, dirs []Directory) error
 
 	// CleanUp the trash in the Fs
 	//
 	// Implement this if you have a way of emptying the trash or
 	// otherwise cleaning up old versions of files.
-	CleanUp func() error
+	CleanUp func(ctx context.Context) error
 
 	// ListR lists the objects and directories of the Fs starting
 	// from dir recursively into out.
@@ -349,6 +363,22 @@ type Features struct {
 	// Don't implement this unless you have a more efficient way
 	// of listing recursively that doing a directory traversal.
 	ListR ListRFn
+
+	// OpenWriterAt opens with a handle for random access writes
+	//
+	// Pass in the remote desired and the size if known.
+	//
+	// It truncates any existing object
+	OpenWriterAt func(ctx context.Context, remote string, size int64) (WriterAtCloser, error)
+
+	// About gets quota information from the Fs
+	About func(ctx context.Context) (*Usage, error)
+
+	// PublicLink generates a public link to the remote path (usually readable by anyone)
+	//
+	// If expire is non zero the link should stop working after
+	// that time if the remote supports it.
+	PublicLink func(ctx context.Context, remote string, expire time.Duration) (string, error)
 }
 
 // Disable nil's out the named feature.  If it isn't found then it
@@ -431,6 +461,15 @@ func (ft *Features) Fill(f Fs) *Features {
 	if do, ok := f.(ListRer); ok {
 		ft.ListR = do.ListR
 	}
+	if do, ok := f.(OpenWriterAter); ok {
+		ft.OpenWriterAt = do.OpenWriterAt
+	}
+	if do, ok := f.(Abouter); ok {
+		ft.About = do.About
+	}
+	if do, ok := f.(PublicLinker); ok {
+		ft.PublicLink = do.PublicLink
+	}
 	return ft.DisableList(Config.DisableFeatures)
 }
 
@@ -448,6 +487,10 @@ func (ft *Features) Mask(f Fs) *Features {
 	ft.WriteMimeType = ft.WriteMimeType && mask.WriteMimeType
 	ft.CanHaveEmptyDirectories = ft.CanHaveEmptyDirectories && mask.CanHaveEmptyDirectories
 	ft.BucketBased = ft.BucketBased && mask.BucketBased
+	ft.ReadMetadata = ft.ReadMetadata && mask.ReadMetadata
+	ft.WriteMetadata = ft.WriteMetadata && mask.WriteMetadata
+	ft.ServerSideAcrossConfigs = ft.ServerSideAcrossConfigs && mask.ServerSideAcrossConfigs
+	ft.RangeRead = ft.RangeRead && mask.RangeRead
 	if mask.Purge == nil {
 		ft.Purge = nil
 	}
@@ -484,6 +527,15 @@ func (ft *Features) Mask(f Fs) *Features {
 	if mask.ListR == nil {
 		ft.ListR = nil
 	}
+	if mask.OpenWriterAt == nil {
+		ft.OpenWriterAt = nil
+	}
+	if mask.About == nil {
+		ft.About = nil
+	}
+	if mask.PublicLink == nil {
+		ft.PublicLink = nil
+	}
 	return ft.DisableList(Config.DisableFeatures)
 }
 
@@ -506,7 +558,7 @@ type Purger interface {
 	// quicker than just running Remove() on the result of List()
 	//
 	// Return an error if it doesn't exist
-	Purge() error
+	Purge(ctx context.Context) error
 }
 
 // Copier is an optional interface for Fs
@@ -517,10 +569,11 @@ type Copier interface {
 	//
 	// It returns the destination Object and a possible error
 	//
-	// Will only be called if src.Fs().Name() == f.Name()
+	// Will only be called if src.Fs().Name() == f.Name() or if
+	// ServerSideAcrossConfigs is set and src is the same type of Fs
 	//
 	// If it isn't possible then return fs.ErrorCantCopy
-	Copy(src Object, remote string) (Object, error)
+	Copy(ctx context.Context, src Object, remote string) (Object, error)
 }
 
 // Mover is an optional interface for Fs
@@ -531,10 +584,11 @@ type Mover interface {
 	//
 	// It returns the destination Object and a possible error
 	//
-	// Will only be called if src.Fs().Name() == f.Name()
+	// Will only be called if src.Fs().Name() == f.Name() or if
+	// ServerSideAcrossConfigs is set and src is the same type of Fs
 	//
 	// If it isn't possible then return fs.ErrorCantMove
-	Move(src Object, remote string) (Object, error)
+	Move(ctx context.Context, src Object, remote string) (Object, error)
 }
 
 // DirMover is an optional interface for Fs
@@ -547,7 +601,7 @@ type DirMover interface {
 	// If it isn't possible then return fs.ErrorCantDirMove
 	//
 	// If destination exists then return fs.ErrorDirExists
-	DirMove(src Fs, srcRemote, dstRemote string) error
+	DirMove(ctx context.Context, src Fs, srcRemote, dstRemote string) error
 }
 
 // DirChangeNotifier is an optional interface for Fs
@@ -581,7 +635,7 @@ type PutUncheckeder interface {
 	//
 	// May create duplicates or return errors if src already
 	// exists.
-	PutUnchecked(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutUnchecked(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 }
 
 // PutStreamer is an optional interface for Fs
@@ -591,14 +645,14 @@ type PutStreamer interface {
 	// May create the object even if it returns an error - if so
 	// will return the object and the error, otherwise will return
 	// nil and the error
-	PutStream(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutStream(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 }
 
 // MergeDirser is an option interface for Fs
 type MergeDirser interface {
 	// MergeDirs merges the contents of all the directories passed
 	// in into the first one and rmdirs the other directories.
-	MergeDirs([]Directory) error
+	MergeDirs(ctx context.Context, dirs []Directory) error
 }
 
 // CleanUpper is an optional interfaces for Fs
@@ -607,7 +661,7 @@ type CleanUpper interface {
 	//
 	// Implement this if you have a way of emptying the trash or
 	// otherwise cleaning up old versions of files.
-	CleanUp() error
+	CleanUp(ctx context.Context) error
 }
 
 // ListRer is an optional interfaces for Fs
@@ -628,7 +682,56 @@ type ListRer interface {
 	//
 	// Don't implement this unless you have a more efficient way
 	// of listing recursively that doing a directory traversal.
-	ListR(dir string, callback ListRCallback) error
+	ListR(ctx context.Context, dir string, callback ListRCallback) error
+}
+
+// OpenWriterAter is an optional interface for Fs
+type OpenWriterAter interface {
+	// OpenWriterAt opens with a handle for random access writes
+	//
+	// Pass in the remote desired and the size if known.
+	//
+	// It truncates any existing object
+	OpenWriterAt(ctx context.Context, remote string, size int64) (WriterAtCloser, error)
+}
+
+// Abouter is an optional interface for Fs
+type Abouter interface {
+	// About gets quota information from the Fs
+	About(ctx context.Context) (*Usage, error)
+}
+
+// PublicLinker is an optional interface for Fs
+type PublicLinker interface {
+	// PublicLink generates a public link to the remote path (usually readable by anyone)
+	//
+	// If expire is non zero the link should stop working after
+	// that time if the remote supports it.
+	PublicLink(ctx context.Context, remote string, expire time.Duration) (string, error)
+}
+
+// Usage is returned by the About call
+//
+// If a value is nil then it isn't supported by that backend
+type Usage struct {
+	Total   *int64 `json:"total,omitempty"`   // quota of bytes that can be used
+	Used    *int64 `json:"used,omitempty"`    // bytes in use
+	Trashed *int64 `json:"trashed,omitempty"` // bytes in trash
+	Other   *int64 `json:"other,omitempty"`   // other usage eg gmail in drive
+	Free    *int64 `json:"free,omitempty"`    // bytes which can be uploaded before reaching the quota
+}
+
+// NewUsageValue makes a valid value for the Usage fields
+func NewUsageValue(value int64) *int64 {
+	p := new(int64)
+	*p = value
+	return p
+}
+
+// WriterAtCloser wraps io.WriterAt and io.Closer
+type WriterAtCloser interface {
+	io.WriterAt
+	io.Closer
 }
 
 // ObjectsChan is a channel of Objects
@@ -736,8 +839,8 @@ func CheckClose(c io.Closer, err *error) {
 
 // FileExists returns true if a file remote exists.
 // If remote is a directory, FileExists returns false.
-func FileExists(fs Fs, remote string) (bool, error) {
-	_, err := fs.NewObject(remote)
+func FileExists(ctx context.Context, fs Fs, remote string) (bool, error) {
+	_, err := fs.NewObject(ctx, remote)
 	if err != nil {
 		if err == ErrorObjectNotFound || err == ErrorNotAFile {
 			return false, nil
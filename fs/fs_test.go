@@ -5,11 +5,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
 )
 
 func TestFeaturesDisable(t *testing.T) {
 	ft := new(Features)
-	ft.Copy = func(src Object, remote string) (Object, error) {
+	ft.Copy = func(ctx context.Context, src Object, remote string) (Object, error) {
 		return nil, nil
 	}
 	ft.CaseInsensitive = true
@@ -35,7 +36,7 @@ func TestFeaturesList(t *testing.T) {
 
 func TestFeaturesDisableList(t *testing.T) {
 	ft := new(Features)
-	ft.Copy = func(src Object, remote string) (Object, error) {
+	ft.Copy = func(ctx context.Context, src Object, remote string) (Object, error) {
 		return nil, nil
 	}
 	ft.CaseInsensitive = true
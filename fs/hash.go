@@ -3,14 +3,18 @@ package fs
 import (
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"hash/crc32"
 	"io"
 	"strings"
 
+	"github.com/cespare/xxhash"
 	"github.com/ncw/rclone/dropbox/dbhash"
 	"github.com/pkg/errors"
+	"lukechampine.com/blake3"
 )
 
 // HashType indicates a standard hashing algorithm
@@ -20,35 +24,144 @@ type HashType int
 // if it is requested to deliver an unsupported hash type.
 var ErrHashUnsupported = errors.New("hash type not supported")
 
-const (
+// hashDefinition describes a registered hash type
+type hashDefinition struct {
+	name     string
+	width    int
+	newFunc  func() hash.Hash
+	hashType HashType
+}
+
+// The registered hash types
+var (
+	hashes     []*hashDefinition
+	hashByType = map[HashType]*hashDefinition{}
+	hashByName = map[string]*hashDefinition{}
+)
+
+// HashNone indicates no hashes are supported
+const HashNone HashType = 0
+
+// SupportedHashes returns a set of all the supported hashes by
+// HashStream and MultiHasher.
+var SupportedHashes = NewHashSet()
+
+// HashWidth returns the width in characters for any HashType
+var HashWidth = map[HashType]int{}
+
+// RegisterHash adds a new hash type to the registry returning its
+// HashType.
+//
+// name is what the hash is called, eg "MD5", width is the width of
+// the hash as a hex string and newFunc makes a new hasher for it.
+//
+// The HashType depends on the order the hashes are registered in, so
+// new hashes should be registered after the existing ones.
+func RegisterHash(name string, width int, newFunc func() hash.Hash) HashType {
+	if _, found := hashByName[strings.ToLower(name)]; found {
+		panic(fmt.Sprintf("hash %q registered twice", name))
+	}
+	if len(hashes) >= 31 {
+		panic(fmt.Sprintf("too many hashes registering %q", name))
+	}
+	hashType := HashType(1 << uint(len(hashes)))
+	definition := &hashDefinition{
+		name:     name,
+		width:    width,
+		newFunc:  newFunc,
+		hashType: hashType,
+	}
+	hashes = append(hashes, definition)
+	hashByType[hashType] = definition
+	hashByName[strings.ToLower(name)] = definition
+	SupportedHashes.Add(hashType)
+	HashWidth[hashType] = width
+	return hashType
+}
+
+// newCRC32C returns a hasher for the CRC-32 with the Castagnoli
+// polynomial
+func newCRC32C() hash.Hash {
+	return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+}
+
+// The built in hash types - these must stay in this order as the
+// HashType values may be persisted
+var (
 	// HashMD5 indicates MD5 support
-	HashMD5 HashType = 1 << iota
+	HashMD5 = RegisterHash("MD5", 32, md5.New)
 
 	// HashSHA1 indicates SHA-1 support
-	HashSHA1
+	HashSHA1 = RegisterHash("SHA-1", 40, sha1.New)
 
 	// HashDropbox indicates Dropbox special hash
 	// https://www.dropbox.com/developers/reference/content-hash
-	HashDropbox
+	HashDropbox = RegisterHash("DropboxHash", 64, dbhash.New)
+
+	// HashCRC32C indicates CRC-32C support as used by Google Cloud Storage
+	HashCRC32C = RegisterHash("CRC-32C", 8, newCRC32C)
+
+	// HashSHA256 indicates SHA-256 support
+	HashSHA256 = RegisterHash("SHA-256", 64, sha256.New)
 
-	// HashNone indicates no hashes are supported
-	HashNone HashType = 0
+	// HashXXH64 indicates 64 bit xxHash support - a fast non
+	// cryptographic hash
+	HashXXH64 = RegisterHash("XXH64", 16, func() hash.Hash { return xxhash.New() })
+
+	// HashBLAKE3 indicates BLAKE3 support - a fast cryptographic hash
+	HashBLAKE3 = RegisterHash("BLAKE3", 64, func() hash.Hash { return blake3.New(32, nil) })
 )
 
-// SupportedHashes returns a set of all the supported hashes by
-// HashStream and MultiHasher.
-var SupportedHashes = NewHashSet(HashMD5, HashSHA1, HashDropbox)
+// DefaultHashes is the set of hashes calculated by HashStream and
+// NewMultiHasher.  The other supported hashes are only calculated
+// when asked for with HashStreamTypes or NewMultiHasherTypes.
+var DefaultHashes = NewHashSet(HashMD5, HashSHA1, HashDropbox)
 
-// HashWidth returns the width in characters for any HashType
-var HashWidth = map[HashType]int{
-	HashMD5:     32,
-	HashSHA1:    40,
-	HashDropbox: 64,
+// HashTypes returns all the registered hash types in the order they
+// were registered
+func HashTypes() (types []HashType) {
+	for _, definition := range hashes {
+		types = append(types, definition.hashType)
+	}
+	return types
+}
+
+// HashTypeFromString returns the HashType for the name passed in
+// which is matched case insensitively, eg "md5" or "SHA-1".  It
+// returns an error if the hash isn't known.
+func HashTypeFromString(name string) (HashType, error) {
+	if strings.ToLower(name) == "none" {
+		return HashNone, nil
+	}
+	definition, found := hashByName[strings.ToLower(name)]
+	if !found {
+		var names []string
+		for _, definition := range hashes {
+			names = append(names, definition.name)
+		}
+		return HashNone, errors.Errorf("unknown hash type %q - known types are %s", name, strings.Join(names, ", "))
+	}
+	return definition.hashType, nil
+}
+
+// Set a HashType from a flag - satisfies pflag.Value
+func (h *HashType) Set(s string) error {
+	hashType, err := HashTypeFromString(s)
+	if err != nil {
+		return err
+	}
+	*h = hashType
+	return nil
 }
 
-// HashStream will calculate hashes of all supported hash types.
+// Type of the value - satisfies pflag.Value
+func (h HashType) Type() string {
+	return "string"
+}
+
+// HashStream will calculate hashes of the DefaultHashes types.
 func HashStream(r io.Reader) (map[HashType]string, error) {
-	return HashStreamTypes(r, SupportedHashes)
+	return HashStreamTypes(r, DefaultHashes)
 }
 
 // HashStreamTypes will calculate hashes of the requested hash types.
@@ -72,42 +185,26 @@ func HashStreamTypes(r io.Reader, set HashSet) (map[HashType]string, error) {
 // String returns a string representation of the hash type.
 // The function will panic if the hash type is unknown.
 func (h HashType) String() string {
-	switch h {
-	case HashNone:
+	if h == HashNone {
 		return "None"
-	case HashMD5:
-		return "MD5"
-	case HashSHA1:
-		return "SHA-1"
-	case HashDropbox:
-		return "DropboxHash"
-	default:
+	}
+	definition, found := hashByType[h]
+	if !found {
 		err := fmt.Sprintf("internal error: unknown hash type: 0x%x", int(h))
 		panic(err)
 	}
+	return definition.name
 }
 
 // hashFromTypes will return hashers for all the requested types.
-// The types must be a subset of SupportedHashes,
-// and this function must support all types.
+// The types must be a subset of SupportedHashes.
 func hashFromTypes(set HashSet) (map[HashType]hash.Hash, error) {
 	if !set.SubsetOf(SupportedHashes) {
 		return nil, errors.Errorf("requested set %08x contains unknown hash types", int(set))
 	}
 	var hashers = make(map[HashType]hash.Hash)
-	types := set.Array()
-	for _, t := range types {
-		switch t {
-		case HashMD5:
-			hashers[t] = md5.New()
-		case HashSHA1:
-			hashers[t] = sha1.New()
-		case HashDropbox:
-			hashers[t] = dbhash.New()
-		default:
-			err := fmt.Sprintf("internal error: Unsupported hash type %v", t)
-			panic(err)
-		}
+	for _, t := range set.Array() {
+		hashers[t] = hashByType[t].newFunc()
 	}
 	return hashers, nil
 }
@@ -132,10 +229,10 @@ type MultiHasher struct {
 	h    map[HashType]hash.Hash // Hashes
 }
 
-// NewMultiHasher will return a hash writer that will write all
-// supported hash types.
+// NewMultiHasher will return a hash writer that will write the
+// DefaultHashes types.
 func NewMultiHasher() *MultiHasher {
-	h, err := NewMultiHasherTypes(SupportedHashes)
+	h, err := NewMultiHasherTypes(DefaultHashes)
 	if err != nil {
 		panic("internal error: could not create multihasher")
 	}
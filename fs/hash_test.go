@@ -68,6 +68,10 @@ var hashTestSet = []hashTest{
 			fs.HashMD5:     "bf13fc19e5151ac57d4252e0e0f87abe",
 			fs.HashSHA1:    "3ab6543c08a75f292a5ecedac87ec41642d12166",
 			fs.HashDropbox: "214d2fcf3566e94c99ad2f59bd993daca46d8521a0c447adf4b324f53fddc0c7",
+			fs.HashCRC32C:  "4d8ae017",
+			fs.HashSHA256:  "c839e57675862af5c21bd0a15413c3ec579e0d5522dab600bc6c3489b05b8f54",
+			fs.HashXXH64:   "13cd7ced0c4af679",
+			fs.HashBLAKE3:  "0a7276a407a3be1b4d31488318ee05a335aad5a3b82c4420e592a8178c9e86bb",
 		},
 	},
 	// Empty data set
@@ -77,6 +81,10 @@ var hashTestSet = []hashTest{
 			fs.HashMD5:     "d41d8cd98f00b204e9800998ecf8427e",
 			fs.HashSHA1:    "da39a3ee5e6b4b0d3255bfef95601890afd80709",
 			fs.HashDropbox: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			fs.HashCRC32C:  "00000000",
+			fs.HashSHA256:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			fs.HashXXH64:   "ef46db3751d8e999",
+			fs.HashBLAKE3:  "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262",
 		},
 	},
 }
@@ -93,8 +101,9 @@ func TestMultiHasher(t *testing.T) {
 			require.True(t, ok, "test output for hash not found")
 			assert.Equal(t, v, expect)
 		}
-		// Test that all are present
-		for k, v := range test.output {
+		// Test that all the default hashes are present
+		for _, k := range fs.DefaultHashes.Array() {
+			v := test.output[k]
 			expect, ok := sums[k]
 			require.True(t, ok, "test output for hash not found")
 			assert.Equal(t, v, expect)
@@ -127,8 +136,9 @@ func TestHashStream(t *testing.T) {
 			require.True(t, ok)
 			assert.Equal(t, v, expect)
 		}
-		// Test that all are present
-		for k, v := range test.output {
+		// Test that all the default hashes are present
+		for _, k := range fs.DefaultHashes.Array() {
+			v := test.output[k]
 			expect, ok := sums[k]
 			require.True(t, ok)
 			assert.Equal(t, v, expect)
@@ -143,9 +153,22 @@ func TestHashStreamTypes(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, sums, 1)
 		assert.Equal(t, sums[h], test.output[h])
+
+		// All the supported hashes when asked for
+		sums, err = fs.HashStreamTypes(bytes.NewBuffer(test.input), fs.SupportedHashes)
+		require.NoError(t, err)
+		assert.Equal(t, test.output, sums)
 	}
 }
 
+func TestDefaultHashes(t *testing.T) {
+	assert.Equal(t, fs.NewHashSet(fs.HashMD5, fs.HashSHA1, fs.HashDropbox), fs.DefaultHashes)
+	assert.True(t, fs.DefaultHashes.SubsetOf(fs.SupportedHashes))
+	sums, err := fs.HashStream(bytes.NewBuffer(nil))
+	require.NoError(t, err)
+	assert.Len(t, sums, fs.DefaultHashes.Count())
+}
+
 func TestHashSetStringer(t *testing.T) {
 	h := fs.NewHashSet(fs.HashSHA1, fs.HashMD5, fs.HashDropbox)
 	assert.Equal(t, h.String(), "[MD5, SHA-1, DropboxHash]")
@@ -161,3 +184,40 @@ func TestHashStringer(t *testing.T) {
 	h = fs.HashNone
 	assert.Equal(t, h.String(), "None")
 }
+
+func TestHashTypeFromString(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want fs.HashType
+		err  bool
+	}{
+		{"MD5", fs.HashMD5, false},
+		{"md5", fs.HashMD5, false},
+		{"sha-1", fs.HashSHA1, false},
+		{"DropboxHash", fs.HashDropbox, false},
+		{"crc-32c", fs.HashCRC32C, false},
+		{"SHA-256", fs.HashSHA256, false},
+		{"xxh64", fs.HashXXH64, false},
+		{"blake3", fs.HashBLAKE3, false},
+		{"none", fs.HashNone, false},
+		{"potato", fs.HashNone, true},
+	} {
+		got, err := fs.HashTypeFromString(test.in)
+		assert.Equal(t, test.want, got, test.in)
+		assert.Equal(t, test.err, err != nil, test.in)
+	}
+}
+
+func TestHashTypes(t *testing.T) {
+	types := fs.HashTypes()
+	require.True(t, len(types) >= 7)
+	// The built in types must keep their values
+	assert.Equal(t, fs.HashMD5, types[0])
+	assert.Equal(t, fs.HashType(1), fs.HashMD5)
+	assert.Equal(t, fs.HashType(2), fs.HashSHA1)
+	assert.Equal(t, fs.HashType(4), fs.HashDropbox)
+	for _, hashType := range types {
+		assert.True(t, fs.SupportedHashes.Contains(hashType), hashType.String())
+		assert.NotZero(t, fs.HashWidth[hashType], hashType.String())
+	}
+}
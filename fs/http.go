@@ -141,6 +141,34 @@ func (ci *ConfigInfo) Client() *http.Client {
 	}
 }
 
+// TransportRemote returns an http.RoundTripper like Transport whose
+// requests are recorded in the metrics against the remote name
+func (ci *ConfigInfo) TransportRemote(name string) http.RoundTripper {
+	return &remoteTransport{
+		Transport: ci.Transport().(*Transport),
+		name:      name,
+	}
+}
+
+// ClientRemote returns an http.Client like Client whose requests are
+// recorded in the metrics against the remote name
+func (ci *ConfigInfo) ClientRemote(name string) *http.Client {
+	return &http.Client{
+		Transport: ci.TransportRemote(name),
+	}
+}
+
+// remoteTransport is a Transport used for the remote name
+type remoteTransport struct {
+	*Transport
+	name string
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (t *remoteTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	return t.Transport.roundTrip(req, t.name)
+}
+
 // Transport is a our http Transport which wraps an http.Transport
 // * Sets the User Agent
 // * Does logging
@@ -246,6 +274,12 @@ func cleanAuths(buf []byte) []byte {
 
 // RoundTrip implements the RoundTripper interface.
 func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	return t.roundTrip(req, "")
+}
+
+// roundTrip does the round trip for req recording it in the metrics
+// against the remote name
+func (t *Transport) roundTrip(req *http.Request, name string) (resp *http.Response, err error) {
 	// Get transactions per second token first if limiting
 	if tpsBucket != nil {
 		tbErr := tpsBucket.Wait(context.Background()) // FIXME switch to req.Context() when we drop go1.6 support
@@ -271,7 +305,9 @@ func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error
 		Debugf(nil, "%s", separatorReq)
 	}
 	// Do round trip
+	start := time.Now()
 	resp, err = t.Transport.RoundTrip(req)
+	accountHTTP(name, req, resp, err, time.Since(start))
 	// Logf response
 	if t.dump&(DumpHeaders|DumpBodies|DumpAuth|DumpRequests|DumpResponses) != 0 {
 		Debugf(nil, "%s", separatorResp)
@@ -0,0 +1,224 @@
+// Per directory ignore files with gitignore semantics
+
+package fs
+
+import (
+	"bufio"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// ignoreRule is one rule read from an ignore file
+type ignoreRule struct {
+	include bool           // set if the rule was negated with !
+	dirOnly bool           // set if the rule only matches directories
+	re      *regexp.Regexp // matches paths relative to the ignore file
+}
+
+// newIgnoreRule parses a line of an ignore file into an ignoreRule
+//
+// It returns nil for blank lines and comments
+func newIgnoreRule(line string) (*ignoreRule, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || line[0] == '#' {
+		return nil, nil
+	}
+	r := &ignoreRule{}
+	if line[0] == '!' {
+		r.include = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil, errors.New("empty pattern")
+	}
+	// A leading "**/" matches in all directories so the rest of
+	// the pattern isn't anchored even if it has a slash in
+	anywhere := strings.HasPrefix(line, "**/")
+	if anywhere {
+		line = line[3:]
+	}
+	// Otherwise a pattern with a slash in is relative to the
+	// ignore file
+	if !anywhere && strings.Contains(line, "/") && !strings.HasPrefix(line, "/") {
+		line = "/" + line
+	}
+	re, err := globToRegexp(line)
+	if err != nil {
+		return nil, err
+	}
+	// "a/**/b" should match "a/b" too
+	if strings.Contains(re.String(), "/.*/") {
+		re, err = regexp.Compile(strings.Replace(re.String(), "/.*/", "/(.*/)?", -1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.re = re
+	return r, nil
+}
+
+// ignoreRules are the rules read from the ignore file in dir
+type ignoreRules struct {
+	parent *ignoreRules // rules from the parent directories or nil
+	dir    string       // directory the ignore file was found in
+	rules  []*ignoreRule
+}
+
+// parseIgnoreRules reads the ignore file from in returning the rules
+// for dir which inherit from parent
+func parseIgnoreRules(parent *ignoreRules, dir string, in *bufio.Scanner) (*ignoreRules, error) {
+	rs := &ignoreRules{
+		parent: parent,
+		dir:    dir,
+	}
+	for in.Scan() {
+		rule, err := newIgnoreRule(in.Text())
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad rule %q", in.Text())
+		}
+		if rule != nil {
+			rs.rules = append(rs.rules, rule)
+		}
+	}
+	if err := in.Err(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// excluded returns whether the ignore files exclude remote
+//
+// Like gitignore the last matching rule wins and the rules in
+// subdirectories take precedence over those in their parents.
+func (rs *ignoreRules) excluded(remote string, isDir bool) bool {
+	for ; rs != nil; rs = rs.parent {
+		relative := remote
+		if rs.dir != "" {
+			relative = strings.TrimPrefix(remote, rs.dir+"/")
+		}
+		for i := len(rs.rules) - 1; i >= 0; i-- {
+			rule := rs.rules[i]
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			if rule.re.MatchString(relative) {
+				return !rule.include
+			}
+		}
+	}
+	return false
+}
+
+// filter removes the entries excluded by the ignore files in place
+func (rs *ignoreRules) filter(entries DirEntries) DirEntries {
+	if rs == nil {
+		return entries
+	}
+	newEntries := entries[:0]
+	for _, entry := range entries {
+		_, isDir := entry.(Directory)
+		if rs.excluded(entry.Remote(), isDir) {
+			Debugf(entry, "Excluded from sync (and deletion) by ignore file")
+			continue
+		}
+		newEntries = append(newEntries, entry)
+	}
+	return newEntries
+}
+
+// ignoreLister lists the directories of an Fs removing the entries
+// excluded by the ignore files found in them and their parents
+type ignoreLister struct {
+	mu    sync.Mutex
+	rules map[string]*ignoreRules // rules in force in each directory listed
+}
+
+// newIgnoreLister makes a new ignoreLister
+func newIgnoreLister() *ignoreLister {
+	return &ignoreLister{
+		rules: make(map[string]*ignoreRules),
+	}
+}
+
+// rulesFor returns the ignore rules in force in dir - these are the
+// rules of the nearest directory listed
+func (l *ignoreLister) rulesFor(dir string) *ignoreRules {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for {
+		if rs, ok := l.rules[dir]; ok {
+			return rs
+		}
+		if dir == "" {
+			return nil
+		}
+		dir = parentDir(dir)
+	}
+}
+
+// load reads the ignore file o returning the rules for dir
+func (l *ignoreLister) load(ctx context.Context, parent *ignoreRules, dir string, o Object) (rs *ignoreRules, err error) {
+	in, err := o.Open(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open ignore file")
+	}
+	defer CheckClose(in, &err)
+	rs, err = parseIgnoreRules(parent, dir, bufio.NewScanner(in))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ignore file %q", o.Remote())
+	}
+	Debugf(o, "Read %d rules from ignore file", len(rs.rules))
+	return rs, nil
+}
+
+// listDir lists dir like ListDirSorted then removes the entries
+// excluded by the ignore files.  It remembers the rules for dir for
+// the listings of its subdirectories.
+//
+// It satisfies listDirFunc
+func (l *ignoreLister) listDir(ctx context.Context, f Fs, includeAll bool, dir string) (entries DirEntries, err error) {
+	entries, err = f.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	rules := l.rulesFor(dir)
+	for _, entry := range entries {
+		if o, ok := entry.(Object); ok && path.Base(o.Remote()) == Config.Filter.IgnoreFile {
+			rules, err = l.load(ctx, rules, dir, o)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	l.mu.Lock()
+	l.rules[dir] = rules
+	l.mu.Unlock()
+	if !includeAll && Config.Filter.ListContainsExcludeFile(entries) {
+		Debugf(dir, "Excluded from sync (and deletion)")
+		return nil, nil
+	}
+	entries, err = filterAndSortDir(entries, includeAll, dir, Config.Filter.IncludeObject, Config.Filter.IncludeDirectory(ctx, f))
+	if err != nil {
+		return nil, err
+	}
+	return rules.filter(entries), nil
+}
+
+// listDirFor returns the function to list the directories of an Fs
+// with - this reads the ignore files if they are in use
+func listDirFor(includeAll bool) listDirFunc {
+	if includeAll || Config.Filter.IgnoreFile == "" {
+		return ListDirSorted
+	}
+	return newIgnoreLister().listDir
+}
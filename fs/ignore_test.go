@@ -0,0 +1,111 @@
+package fs
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIgnoreRule(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		include bool
+		dirOnly bool
+		re      string
+		err     bool
+	}{
+		{in: ""},
+		{in: "   "},
+		{in: "# comment"},
+		{in: "*.o", re: `(^|/)[^/]*\.o$`},
+		{in: "*.o  ", re: `(^|/)[^/]*\.o$`},
+		{in: "!keep.o", include: true, re: `(^|/)keep\.o$`},
+		{in: `\!bang`, re: `(^|/)\!bang$`},
+		{in: `\#hash`, re: `(^|/)\#hash$`},
+		{in: "build/", dirOnly: true, re: `(^|/)build$`},
+		{in: "/top", re: `^top$`},
+		{in: "a/b", re: `^a/b$`},
+		{in: "**/b", re: `(^|/)b$`},
+		{in: "**/foo/bar", re: `(^|/)foo/bar$`},
+		{in: "a/**/b", re: `^a/(.*/)?b$`},
+		{in: "a/**", re: `^a/.*$`},
+		{in: "!", err: true},
+		{in: "[", err: true},
+	} {
+		rule, err := newIgnoreRule(test.in)
+		if test.err {
+			assert.Error(t, err, test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		if test.re == "" {
+			assert.Nil(t, rule, test.in)
+			continue
+		}
+		require.NotNil(t, rule, test.in)
+		assert.Equal(t, test.include, rule.include, test.in)
+		assert.Equal(t, test.dirOnly, rule.dirOnly, test.in)
+		assert.Equal(t, test.re, rule.re.String(), test.in)
+	}
+}
+
+func parseTestIgnoreRules(t *testing.T, parent *ignoreRules, dir, file string) *ignoreRules {
+	rs, err := parseIgnoreRules(parent, dir, bufio.NewScanner(strings.NewReader(file)))
+	require.NoError(t, err)
+	return rs
+}
+
+func TestIgnoreRulesExcluded(t *testing.T) {
+	root := parseTestIgnoreRules(t, nil, "", `
+# Objects everywhere
+*.o
+!keep.o
+/top.txt
+build/
+**/cache/tmp
+`)
+	sub := parseTestIgnoreRules(t, root, "sub", `
+!*.o
+nested/*.txt
+top.txt
+`)
+	for _, test := range []struct {
+		rs     *ignoreRules
+		remote string
+		isDir  bool
+		want   bool
+	}{
+		{root, "a.o", false, true},
+		{root, "dir/a.o", false, true},
+		{root, "keep.o", false, false},
+		{root, "dir/keep.o", false, false},
+		{root, "top.txt", false, true},
+		{root, "dir/top.txt", false, false},
+		{root, "build", true, true},
+		{root, "dir/build", true, true},
+		{root, "build", false, false},
+		{root, "a.c", false, false},
+		{root, "cache/tmp", false, true},
+		{root, "dir/cache/tmp", false, true},
+		{root, "dir/notcache/tmp", false, false},
+		{sub, "sub/a.o", false, false},
+		{sub, "sub/dir/a.o", false, false},
+		{sub, "sub/nested/a.txt", false, true},
+		{sub, "sub/nested/deeper/a.txt", false, false},
+		{sub, "sub/top.txt", false, true},
+		{sub, "sub/build", true, true},
+		{sub, "sub/a.c", false, false},
+		{nil, "a.o", false, false},
+	} {
+		got := test.rs.excluded(test.remote, test.isDir)
+		assert.Equal(t, test.want, got, test.remote)
+	}
+}
+
+func TestParseIgnoreRulesError(t *testing.T) {
+	_, err := parseIgnoreRules(nil, "", bufio.NewScanner(strings.NewReader("ok\n[bad\n")))
+	assert.EqualError(t, err, `bad rule "[bad": mismatched '[' and ']' in glob "[bad"`)
+}
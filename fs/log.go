@@ -3,12 +3,15 @@
 package fs
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
@@ -82,18 +85,104 @@ var (
 	logFile        = StringP("log-file", "", "", "Log everything to this file")
 	useSyslog      = BoolP("syslog", "", false, "Use Syslog for logging")
 	syslogFacility = StringP("syslog-facility", "", "DAEMON", "Facility for syslog, eg KERN,USER,...")
+	useJSONLog     = BoolP("use-json-log", "", false, "Use json log format.")
 )
 
+// jsonLogging is set by InitLogging if the logs should be in JSON
+var jsonLogging bool
+
 // logPrint sends the text to the logger of level
 var logPrint = func(level LogLevel, text string) {
 	text = fmt.Sprintf("%-6s: %s", level, text)
 	log.Print(text)
 }
 
+// LogValueItem is a key and value which is added to the JSON log
+// output as a structured field.  See LogValue and LogValueHide.
+type LogValueItem struct {
+	key    string
+	value  interface{}
+	render bool
+}
+
+// LogValue should be passed as an argument to any logging calls to
+// add a key and value to the JSON log output.  It will also be
+// rendered as its value in the text log.
+func LogValue(key string, value interface{}) LogValueItem {
+	return LogValueItem{key: key, value: value, render: true}
+}
+
+// LogValueHide should be passed as an argument to any logging calls
+// to add a key and value to the JSON log output.  It will be rendered
+// as an empty string in the text log.
+func LogValueHide(key string, value interface{}) LogValueItem {
+	return LogValueItem{key: key, value: value, render: false}
+}
+
+// String returns the representation of value in the text log
+func (j LogValueItem) String() string {
+	if !j.render {
+		return ""
+	}
+	if stringer, ok := j.value.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprint(j.value)
+}
+
+// logSource returns the file and line of the caller depth frames up
+// the stack in the form "dir/file.go:line"
+func logSource(depth int) string {
+	_, file, line, ok := runtime.Caller(depth + 1)
+	if !ok {
+		return "*Unknown*"
+	}
+	dir, file := filepath.Split(file)
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(dir), file, line)
+}
+
+// logJSON makes a log line in JSON from the arguments passed in
+//
+// The first error in args and any LogValueItems in args are added as
+// fields.
+func logJSON(level LogLevel, o interface{}, out string, args []interface{}) string {
+	fields := map[string]interface{}{
+		"time":   time.Now().Format(time.RFC3339Nano),
+		"level":  strings.ToLower(level.String()),
+		"msg":    out,
+		"source": logSource(3),
+	}
+	if o != nil {
+		fields["object"] = fmt.Sprintf("%v", o)
+		fields["objectType"] = fmt.Sprintf("%T", o)
+	}
+	for _, arg := range args {
+		switch x := arg.(type) {
+		case LogValueItem:
+			fields[x.key] = x.value
+		case error:
+			if _, found := fields["error"]; !found {
+				fields["error"] = x.Error()
+			}
+		}
+	}
+	buf, err := json.Marshal(fields)
+	if err != nil {
+		buf, _ = json.Marshal(map[string]interface{}{
+			"time":  fields["time"],
+			"level": fields["level"],
+			"msg":   fmt.Sprintf("Failed to make JSON log line %q: %v", out, err),
+		})
+	}
+	return string(buf)
+}
+
 // logPrintf produces a log string from the arguments passed in
 func logPrintf(level LogLevel, o interface{}, text string, args ...interface{}) {
 	out := fmt.Sprintf(text, args...)
-	if o != nil {
+	if jsonLogging {
+		out = logJSON(level, o, strings.TrimSpace(out), args)
+	} else if o != nil {
 		out = fmt.Sprintf("%v: %s", o, out)
 	}
 	logPrint(level, out)
@@ -202,6 +291,15 @@ func InitLogging() {
 		redirectStderr(f)
 	}
 
+	// JSON output - the level and time are in the JSON
+	if *useJSONLog {
+		jsonLogging = true
+		log.SetFlags(0)
+		logPrint = func(level LogLevel, text string) {
+			log.Print(text)
+		}
+	}
+
 	// Syslog output
 	if *useSyslog {
 		if *logFile != "" {
@@ -0,0 +1,85 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogValue(t *testing.T) {
+	assert.Equal(t, "potato", LogValue("key", "potato").String())
+	assert.Equal(t, "42", LogValue("key", 42).String())
+	assert.Equal(t, "", LogValueHide("key", "potato").String())
+	assert.Equal(t, "a=potato", fmt.Sprintf("a=%v%v", LogValue("key", "potato"), LogValueHide("key2", 1)))
+}
+
+// testLogObject is passed as the object to the log functions
+type testLogObject string
+
+// captureLog replaces logPrint while fn runs and returns what was
+// logged
+func captureLog(fn func()) (lines []string) {
+	oldLogPrint := logPrint
+	defer func() {
+		logPrint = oldLogPrint
+	}()
+	logPrint = func(level LogLevel, text string) {
+		lines = append(lines, text)
+	}
+	fn()
+	return lines
+}
+
+func TestLogJSON(t *testing.T) {
+	oldJSONLogging, oldLogLevel := jsonLogging, Config.LogLevel
+	defer func() {
+		jsonLogging, Config.LogLevel = oldJSONLogging, oldLogLevel
+	}()
+	jsonLogging = true
+	Config.LogLevel = LogLevelDebug
+
+	o := testLogObject("potato")
+	err := errors.New("boom")
+	lines := captureLog(func() {
+		Errorf(o, "Failed to copy: %v", err)
+		Debugf(nil, "Stats%v", LogValueHide("stats", map[string]int{"checks": 3}))
+	})
+	require.Len(t, lines, 2)
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, "error", got["level"])
+	assert.Equal(t, "Failed to copy: boom", got["msg"])
+	assert.Equal(t, "boom", got["error"])
+	assert.Equal(t, "potato", got["object"])
+	assert.Equal(t, "fs.testLogObject", got["objectType"])
+	assert.True(t, strings.HasPrefix(got["source"].(string), "fs/log_test.go:"), got["source"])
+	assert.NotEqual(t, "", got["time"])
+
+	got = nil
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, "debug", got["level"])
+	assert.Equal(t, "Stats", got["msg"])
+	assert.Equal(t, map[string]interface{}{"checks": float64(3)}, got["stats"])
+	_, found := got["object"]
+	assert.False(t, found)
+}
+
+func TestLogText(t *testing.T) {
+	oldJSONLogging, oldLogLevel := jsonLogging, Config.LogLevel
+	defer func() {
+		jsonLogging, Config.LogLevel = oldJSONLogging, oldLogLevel
+	}()
+	jsonLogging = false
+	Config.LogLevel = LogLevelDebug
+
+	lines := captureLog(func() {
+		Errorf("file.txt", "Failed to copy: %v%v", errors.New("boom"), LogValueHide("key", 1))
+	})
+	assert.Equal(t, []string{"file.txt: Failed to copy: boom"}, lines)
+}
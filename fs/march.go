@@ -22,6 +22,7 @@ type march struct {
 	srcListDir listDirFn // function to call to list a directory in the src
 	dstListDir listDirFn // function to call to list a directory in the dst
 	transforms []matchTransformFn
+	ignore     *ignoreLister // reads the ignore files in the src if set
 }
 
 // marcher is called on each match
@@ -43,8 +44,11 @@ func newMarch(ctx context.Context, fdst, fsrc Fs, dir string, callback marcher)
 		dir:      dir,
 		callback: callback,
 	}
-	m.srcListDir = m.makeListDir(fsrc, false)
-	m.dstListDir = m.makeListDir(fdst, Config.Filter.DeleteExcluded)
+	if Config.Filter.IgnoreFile != "" {
+		m.ignore = newIgnoreLister()
+	}
+	m.srcListDir = m.makeListDir(fsrc, false, m.ignore)
+	m.dstListDir = m.makeListDir(fdst, Config.Filter.DeleteExcluded, nil)
 	// Now create the matching transform
 	// ..normalise the UTF8 first
 	m.transforms = append(m.transforms, norm.NFC.String)
@@ -64,10 +68,17 @@ func newMarch(ctx context.Context, fdst, fsrc Fs, dir string, callback marcher)
 type listDirFn func(dir string) (entries DirEntries, err error)
 
 // makeListDir makes a listing function for the given fs and includeAll flags
-func (m *march) makeListDir(f Fs, includeAll bool) listDirFn {
+//
+// If ignore is set then it is used to read the ignore files
+func (m *march) makeListDir(f Fs, includeAll bool, ignore *ignoreLister) listDirFn {
+	if ignore != nil && !includeAll {
+		return func(dir string) (entries DirEntries, err error) {
+			return ignore.listDir(m.ctx, f, includeAll, dir)
+		}
+	}
 	if !Config.UseListR || f.Features().ListR == nil {
 		return func(dir string) (entries DirEntries, err error) {
-			return ListDirSorted(f, includeAll, dir)
+			return ListDirSorted(m.ctx, f, includeAll, dir)
 		}
 	}
 	var (
@@ -80,7 +91,7 @@ func (m *march) makeListDir(f Fs, includeAll bool) listDirFn {
 		mu.Lock()
 		defer mu.Unlock()
 		if !started {
-			dirs, dirsErr = NewDirTree(f, m.dir, includeAll, Config.MaxDepth)
+			dirs, dirsErr = NewDirTree(m.ctx, f, m.dir, includeAll, Config.MaxDepth)
 			started = true
 		}
 		if dirsErr != nil {
@@ -342,17 +353,26 @@ func (m *march) processJob(job listDirJob) (jobs []listDirJob) {
 	wg.Wait()
 	if srcListErr != nil {
 		Errorf(job.srcRemote, "error reading source directory: %v", srcListErr)
-		Stats.Error(srcListErr)
+		StatsFromContext(m.ctx).Error(srcListErr)
 		return nil
 	}
 	if dstListErr == ErrorDirNotFound {
 		// Copy the stuff anyway
 	} else if dstListErr != nil {
 		Errorf(job.dstRemote, "error reading destination directory: %v", dstListErr)
-		Stats.Error(dstListErr)
+		StatsFromContext(m.ctx).Error(dstListErr)
 		return nil
 	}
 
+	// Exclude from the dst what the ignore files in the src exclude
+	if m.ignore != nil && !Config.Filter.DeleteExcluded {
+		dir := job.srcRemote
+		if job.noSrc {
+			dir = job.dstRemote
+		}
+		dstList = m.ignore.rulesFor(dir).filter(dstList)
+	}
+
 	// Work out what to do and do it
 	srcOnly, dstOnly, matches := matchListings(srcList, dstList, m.transforms)
 	for _, src := range srcOnly {
@@ -0,0 +1,85 @@
+// Limit the amount of data transferred with --max-transfer
+
+package fs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// CutoffMode describes what happens when --max-transfer is reached
+type CutoffMode byte
+
+// Cutoff modes
+const (
+	CutoffModeHard     CutoffMode = iota // stop all transfers immediately
+	CutoffModeSoft                       // let the transfers in progress finish but start no new ones
+	CutoffModeCautious                   // don't start transfers which would go over the limit
+)
+
+var cutoffModeToString = []string{
+	CutoffModeHard:     "HARD",
+	CutoffModeSoft:     "SOFT",
+	CutoffModeCautious: "CAUTIOUS",
+}
+
+// String turns a CutoffMode into a string
+func (m CutoffMode) String() string {
+	if m >= CutoffMode(len(cutoffModeToString)) {
+		return fmt.Sprintf("CutoffMode(%d)", m)
+	}
+	return cutoffModeToString[m]
+}
+
+// Set a CutoffMode
+func (m *CutoffMode) Set(s string) error {
+	for n, name := range cutoffModeToString {
+		if s != "" && name == strings.ToUpper(s) {
+			*m = CutoffMode(n)
+			return nil
+		}
+	}
+	return errors.Errorf("Unknown cutoff mode %q", s)
+}
+
+// Type of the value
+func (m *CutoffMode) Type() string {
+	return "string"
+}
+
+// Check it satisfies the interface
+var _ pflag.Value = (*CutoffMode)(nil)
+
+// checkMaxTransfer returns ErrorMaxTransferLimitReached if a new
+// transfer of size bytes (or -1 if unknown) accounted into stats
+// shouldn't be started because of --max-transfer
+//
+// A --max-transfer of 0 or less is no limit.
+func checkMaxTransfer(stats *StatsInfo, size int64) error {
+	if Config.MaxTransfer <= 0 {
+		return nil
+	}
+	maxTransfer := int64(Config.MaxTransfer)
+	if Config.CutoffMode == CutoffModeCautious {
+		if size < 0 {
+			size = 0
+		}
+		if stats.GetBytesWithPending()+size > maxTransfer {
+			return ErrorMaxTransferLimitReached
+		}
+		return nil
+	}
+	if stats.GetBytes() >= maxTransfer {
+		return ErrorMaxTransferLimitReached
+	}
+	return nil
+}
+
+// maxTransferReachedHard returns whether --max-transfer has been
+// reached in HARD mode so the transfers in progress should stop
+func maxTransferReachedHard(stats *StatsInfo) bool {
+	return Config.MaxTransfer > 0 && Config.CutoffMode == CutoffModeHard && stats.GetBytes() >= int64(Config.MaxTransfer)
+}
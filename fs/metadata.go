@@ -0,0 +1,137 @@
+// Object metadata
+
+package fs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// Metadata represents Object metadata in a standardised form
+//
+// Keys are lower case.  These keys have a standard meaning across
+// backends, the rest are backend specific user metadata:
+//
+//     content-type        - the MIME type of the object
+//     cache-control       - the Cache-Control header
+//     content-disposition - the Content-Disposition header
+//     content-encoding    - the Content-Encoding header
+//     content-language    - the Content-Language header
+//     mode                - file mode in octal, eg "0644"
+//     uid                 - numeric user ID of the owner
+//     gid                 - numeric group ID of the owner
+type Metadata map[string]string
+
+// Set k to v on m
+//
+// If m is nil, then it will get made
+func (m *Metadata) Set(k, v string) {
+	if *m == nil {
+		*m = make(Metadata, 1)
+	}
+	(*m)[k] = v
+}
+
+// Merge other into m
+//
+// If m is nil, then it will get made
+func (m *Metadata) Merge(other Metadata) {
+	for k, v := range other {
+		m.Set(k, v)
+	}
+}
+
+// Keys returns the keys of m sorted
+func (m Metadata) Keys() (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Standard metadata keys which map onto HTTP headers
+var metadataHeaderKeys = []string{
+	"content-type",
+	"cache-control",
+	"content-disposition",
+	"content-encoding",
+	"content-language",
+}
+
+// IsMetadataHeaderKey returns whether key is one of the standard
+// metadata keys which map onto an HTTP header, eg "content-type"
+func IsMetadataHeaderKey(key string) bool {
+	for _, headerKey := range metadataHeaderKeys {
+		if key == headerKey {
+			return true
+		}
+	}
+	return false
+}
+
+// Metadataer is an optional interface for Object
+type Metadataer interface {
+	// Metadata returns metadata for an object
+	//
+	// It should return nil if there is no Metadata
+	Metadata(ctx context.Context) (Metadata, error)
+}
+
+// GetMetadata from an ObjectInfo
+//
+// If the object doesn't support metadata then it returns nil.  The
+// MIME type is included as content-type if the object supports
+// MimeTyper but not Metadataer.
+func GetMetadata(ctx context.Context, o ObjectInfo) (metadata Metadata, err error) {
+	if do, ok := o.(Metadataer); ok {
+		return do.Metadata(ctx)
+	}
+	if do, ok := o.(MimeTyper); ok {
+		if mimeType := do.MimeType(); mimeType != "" {
+			metadata.Set("content-type", mimeType)
+		}
+	}
+	return metadata, nil
+}
+
+// MetadataOption defines an Option which passes the metadata to be
+// set on the object into Put or Update.  Backends which don't
+// support metadata ignore it.
+type MetadataOption Metadata
+
+// Header formats the option as an http header
+func (o MetadataOption) Header() (key string, value string) {
+	return "", ""
+}
+
+// String formats the option into human readable form
+func (o MetadataOption) String() string {
+	var out []string
+	for _, k := range Metadata(o).Keys() {
+		out = append(out, fmt.Sprintf("%s=%q", k, o[k]))
+	}
+	return "MetadataOption(" + strings.Join(out, ",") + ")"
+}
+
+// Mandatory returns whether the option must be parsed or can be ignored
+func (o MetadataOption) Mandatory() bool {
+	return false
+}
+
+// GetMetadataOptions finds the metadata passed in as a
+// MetadataOption in options or returns nil if there isn't one
+func GetMetadataOptions(options []OpenOption) (metadata Metadata) {
+	for _, option := range options {
+		if x, ok := option.(MetadataOption); ok {
+			metadata.Merge(Metadata(x))
+		}
+	}
+	return metadata
+}
+
+// check interface
+var _ OpenOption = MetadataOption(nil)
@@ -0,0 +1,48 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestMetadataSet(t *testing.T) {
+	var m Metadata
+	assert.Nil(t, m)
+	m.Set("key", "value")
+	assert.Equal(t, Metadata{"key": "value"}, m)
+	m.Merge(Metadata{"key": "value2", "other": "potato"})
+	assert.Equal(t, Metadata{"key": "value2", "other": "potato"}, m)
+	assert.Equal(t, []string{"key", "other"}, m.Keys())
+}
+
+func TestGetMetadataOptions(t *testing.T) {
+	assert.Nil(t, GetMetadataOptions(nil))
+	options := []OpenOption{
+		&HashesOption{HashSet(HashMD5)},
+		MetadataOption{"mode": "0644"},
+		MetadataOption{"content-type": "text/plain"},
+	}
+	assert.Equal(t, Metadata{"mode": "0644", "content-type": "text/plain"}, GetMetadataOptions(options))
+	assert.Equal(t, `MetadataOption(content-type="text/plain",mode="0644")`, MetadataOption{"mode": "0644", "content-type": "text/plain"}.String())
+}
+
+type testMimeTyper struct {
+	ObjectInfo
+	mimeType string
+}
+
+func (o testMimeTyper) MimeType() string {
+	return o.mimeType
+}
+
+func TestGetMetadata(t *testing.T) {
+	metadata, err := GetMetadata(context.Background(), testMimeTyper{mimeType: "text/html"})
+	assert.NoError(t, err)
+	assert.Equal(t, Metadata{"content-type": "text/html"}, metadata)
+
+	metadata, err = GetMetadata(context.Background(), testMimeTyper{})
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+}
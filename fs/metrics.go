@@ -0,0 +1,63 @@
+// Prometheus metrics for the stats and the HTTP transport
+
+package fs
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ncw/rclone/metrics"
+)
+
+// The stats metrics are updated by every StatsInfo, so they include
+// the stats for the rc jobs as well as the global Stats.  The
+// counters aren't reset with the StatsInfo so they only ever go up.
+var (
+	bytesMetric        = metrics.NewCounterVec("rclone_bytes_transferred_total", "Total transferred bytes since the start of the process.")
+	transfersMetric    = metrics.NewCounterVec("rclone_transfers_total", "Total number of files transferred since the start of the process.")
+	checksMetric       = metrics.NewCounterVec("rclone_checks_total", "Total number of files checked since the start of the process.")
+	errorsMetric       = metrics.NewCounterVec("rclone_errors_total", "Total number of errors since the start of the process.")
+	deletesMetric      = metrics.NewCounterVec("rclone_deletes_total", "Total number of files deleted since the start of the process.")
+	transferringMetric = metrics.NewGaugeVec("rclone_transfers_in_progress", "Number of files being transferred now.")
+	checkingMetric     = metrics.NewGaugeVec("rclone_checks_in_progress", "Number of files being checked now.")
+)
+
+// The HTTP metrics are labelled with the name of the remote which
+// made the request, or "" if the client wasn't made for a remote.
+var (
+	httpRequests = metrics.NewCounterVec(
+		"rclone_http_requests_total",
+		"Number of HTTP requests made by the backends.",
+		"remote", "method", "code",
+	)
+	httpRequestDuration = metrics.NewHistogramVec(
+		"rclone_http_request_duration_seconds",
+		"Time taken for HTTP requests made by the backends to return their headers.",
+		nil,
+		"remote", "method",
+	)
+)
+
+func init() {
+	metrics.Register(bytesMetric)
+	metrics.Register(transfersMetric)
+	metrics.Register(checksMetric)
+	metrics.Register(errorsMetric)
+	metrics.Register(deletesMetric)
+	metrics.Register(transferringMetric)
+	metrics.Register(checkingMetric)
+	metrics.Register(httpRequests)
+	metrics.Register(httpRequestDuration)
+}
+
+// accountHTTP records the metrics for an HTTP request for the remote
+// name which took dt and returned resp and err
+func accountHTTP(name string, req *http.Request, resp *http.Response, err error, dt time.Duration) {
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	httpRequests.Inc(name, req.Method, code)
+	httpRequestDuration.Observe(dt.Seconds(), name, req.Method)
+}
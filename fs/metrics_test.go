@@ -0,0 +1,45 @@
+package fs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsMetrics(t *testing.T) {
+	s := NewStats()
+	bytes, transfers, errors := bytesMetric.Value(), transfersMetric.Value(), errorsMetric.Value()
+	transferring := transferringMetric.Value()
+
+	s.Bytes(42)
+	s.Error(nil)
+	s.Transferring("potato")
+	s.Transferring("potato")
+	assert.Equal(t, transferring+1, transferringMetric.Value())
+	s.DoneTransferring("potato", true)
+	assert.Equal(t, transferring, transferringMetric.Value())
+	s.DoneTransferring("potato", true)
+	assert.Equal(t, transferring, transferringMetric.Value())
+
+	// Resetting the stats doesn't reset the metrics
+	s.ResetCounters()
+	assert.Equal(t, bytes+42, bytesMetric.Value())
+	assert.Equal(t, transfers+2, transfersMetric.Value())
+	assert.Equal(t, errors+1, errorsMetric.Value())
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer ts.Close()
+
+	before := httpRequests.Value("metricstest", "GET", "418")
+	resp, err := Config.ClientRemote("metricstest").Get(ts.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, before+1, httpRequests.Value("metricstest", "GET", "418"))
+}
@@ -0,0 +1,216 @@
+// Multi-thread downloads of single large files
+
+package fs
+
+import (
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// multiThreadChunkAlign is the size the parts of a multi-thread
+// download are rounded up to
+const multiThreadChunkAlign = 64 * 1024
+
+// doMultiThreadCopy returns whether src should be copied to f using
+// concurrent ranged downloads
+func doMultiThreadCopy(f Fs, src Object) bool {
+	if Config.MultiThreadStreams <= 1 {
+		return false
+	}
+	if src.Size() < int64(Config.MultiThreadCutoff) {
+		return false
+	}
+	// OpenWriterAt can't set metadata
+	if Config.Metadata {
+		return false
+	}
+	// The source must be able to read each part on its own
+	if !src.Fs().Features().RangeRead {
+		return false
+	}
+	return f.Features().OpenWriterAt != nil
+}
+
+// multiThreadCopyState holds the state of a multi-thread copy
+type multiThreadCopyState struct {
+	ctx      context.Context
+	partSize int64
+	size     int64
+	wc       WriterAtCloser
+	src      Object
+	acc      *Account
+	streams  int
+}
+
+// offsetWriter writes to an io.WriterAt starting at off
+type offsetWriter struct {
+	w   io.WriterAt
+	off int64
+}
+
+// Write writes p at the current offset - see io.Writer
+func (o *offsetWriter) Write(p []byte) (n int, err error) {
+	n, err = o.w.WriteAt(p, o.off)
+	o.off += int64(n)
+	return n, err
+}
+
+// copyStream copies part stream of the file
+func (mc *multiThreadCopyState) copyStream(stream int) (err error) {
+	start := int64(stream) * mc.partSize
+	if start >= mc.size {
+		return nil
+	}
+	end := start + mc.partSize
+	if end > mc.size {
+		end = mc.size
+	}
+	Debugf(mc.src, "multi-thread copy: stream %d/%d (%d-%d) size %v starting", stream+1, mc.streams, start, end, SizeSuffix(end-start))
+
+	rc, err := mc.src.Open(mc.ctx, &RangeOption{Start: start, End: end - 1})
+	if err != nil {
+		return errors.Wrap(err, "multi-thread copy: failed to open source")
+	}
+	defer CheckClose(rc, &err)
+
+	in := mc.acc.accountPart(io.LimitReader(rc, end-start))
+	n, err := io.Copy(&offsetWriter{w: mc.wc, off: start}, in)
+	if err != nil {
+		return errors.Wrap(err, "multi-thread copy: failed to write chunk")
+	}
+	if n != end-start {
+		return errors.Errorf("multi-thread copy: stream %d/%d read %d bytes, expecting %d", stream+1, mc.streams, n, end-start)
+	}
+	// If the source sent more than the range asked for then it
+	// ignored the range and the part came from the wrong place
+	var extra [1]byte
+	if n, _ := io.ReadFull(rc, extra[:]); n != 0 {
+		return errors.Errorf("multi-thread copy: stream %d/%d read more than %d bytes - source doesn't support ranged reads", stream+1, mc.streams, end-start)
+	}
+
+	Debugf(mc.src, "multi-thread copy: stream %d/%d (%d-%d) size %v finished", stream+1, mc.streams, start, end, SizeSuffix(end-start))
+	return nil
+}
+
+// calculatePartSize calculates the part size to split size into
+// streams parts aligned to multiThreadChunkAlign
+func calculatePartSize(size int64, streams int) int64 {
+	partSize := size / int64(streams)
+	if size%int64(streams) != 0 {
+		partSize++
+	}
+	if rem := partSize % multiThreadChunkAlign; rem != 0 {
+		partSize += multiThreadChunkAlign - rem
+	}
+	return partSize
+}
+
+// multiThreadCopy copies src to remote in f using streams concurrent
+// ranged reads written at their offsets in the destination
+//
+// The transfer is accounted as a single file in the StatsInfo for
+// ctx.
+func multiThreadCopy(ctx context.Context, f Fs, remote string, src Object, streams int) (newDst Object, err error) {
+	openWriterAt := f.Features().OpenWriterAt
+	if openWriterAt == nil {
+		return nil, errors.New("multi-thread copy: OpenWriterAt not supported")
+	}
+	size := src.Size()
+	if size <= 0 {
+		return nil, errors.New("multi-thread copy: can't copy zero sized file")
+	}
+	partSize := calculatePartSize(size, streams)
+	// Don't start streams which would have nothing to do
+	if n := int((size + partSize - 1) / partSize); n < streams {
+		streams = n
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wc, err := openWriterAt(ctx, remote, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "multi-thread copy: failed to open destination")
+	}
+	acc := NewAccountSizeName(ctx, nil, size, remote)
+	mc := &multiThreadCopyState{
+		ctx:      ctx,
+		partSize: partSize,
+		size:     size,
+		wc:       wc,
+		src:      src,
+		acc:      acc,
+		streams:  streams,
+	}
+
+	Debugf(src, "Starting multi-thread copy with %d parts of size %v", streams, SizeSuffix(partSize))
+	var (
+		wg      sync.WaitGroup
+		errMu   sync.Mutex
+		copyErr error
+	)
+	for stream := 0; stream < streams; stream++ {
+		wg.Add(1)
+		go func(stream int) {
+			defer wg.Done()
+			streamErr := mc.copyStream(stream)
+			if streamErr != nil {
+				errMu.Lock()
+				if copyErr == nil {
+					copyErr = streamErr
+				}
+				errMu.Unlock()
+				// stop the other streams
+				cancel()
+			}
+		}(stream)
+	}
+	wg.Wait()
+
+	closeErr := wc.Close()
+	_ = acc.Close()
+	if copyErr != nil {
+		removeErr := removeMultiThreadCopy(f, remote)
+		if removeErr != nil {
+			Errorf(remote, "multi-thread copy: failed to remove partially written file: %v", removeErr)
+		}
+		return nil, copyErr
+	}
+	if closeErr != nil {
+		return nil, errors.Wrap(closeErr, "multi-thread copy: failed to close destination")
+	}
+
+	newDst, err = f.NewObject(ctx, remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "multi-thread copy: failed to find destination")
+	}
+	err = newDst.SetModTime(ctx, src.ModTime())
+	switch err {
+	case ErrorCantSetModTime, ErrorCantSetModTimeWithoutDelete:
+		// the modtime just won't match - the sync will fix it up
+	case nil:
+	default:
+		return nil, errors.Wrap(err, "multi-thread copy: failed to set modification time")
+	}
+
+	Debugf(src, "Finished multi-thread copy with %d parts of size %v", streams, SizeSuffix(partSize))
+	return newDst, nil
+}
+
+// removeMultiThreadCopy removes the partially written remote from f
+//
+// This uses a fresh context as the one for the copy may have been
+// cancelled.
+func removeMultiThreadCopy(f Fs, remote string) error {
+	o, err := f.NewObject(context.Background(), remote)
+	if err == ErrorObjectNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return o.Remove(context.Background())
+}
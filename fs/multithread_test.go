@@ -0,0 +1,25 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculatePartSize(t *testing.T) {
+	for _, test := range []struct {
+		size    int64
+		streams int
+		want    int64
+	}{
+		{1, 4, multiThreadChunkAlign},
+		{multiThreadChunkAlign * 4, 4, multiThreadChunkAlign},
+		{multiThreadChunkAlign*4 + 1, 4, 2 * multiThreadChunkAlign},
+		{10 * multiThreadChunkAlign, 3, 4 * multiThreadChunkAlign},
+		{1 << 30, 4, 1 << 28},
+	} {
+		got := calculatePartSize(test.size, test.streams)
+		assert.Equal(t, test.want, got, "size=%d, streams=%d", test.size, test.streams)
+		assert.True(t, got*int64(test.streams) >= test.size)
+	}
+}
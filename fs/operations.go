@@ -3,12 +3,14 @@
 package fs
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"mime"
+	"net/http"
 	"path"
 	"sort"
 	"strings"
@@ -19,6 +21,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
 )
 
 // CalculateModifyWindow works out modify window for Fses passed in -
@@ -64,7 +67,7 @@ func HashEquals(src, dst string) bool {
 // err - may return an error which will already have been logged
 //
 // If an error is returned it will return equal as false
-func CheckHashes(src ObjectInfo, dst Object) (equal bool, hash HashType, err error) {
+func CheckHashes(ctx context.Context, src ObjectInfo, dst Object) (equal bool, hash HashType, err error) {
 	common := src.Fs().Hashes().Overlap(dst.Fs().Hashes())
 	// Debugf(nil, "Shared hashes: %v", common)
 	if common.Count() == 0 {
@@ -73,7 +76,7 @@ func CheckHashes(src ObjectInfo, dst Object) (equal bool, hash HashType, err err
 	hash = common.GetOne()
 	srcHash, err := src.Hash(hash)
 	if err != nil {
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		Errorf(src, "Failed to calculate src hash: %v", err)
 		return false, hash, err
 	}
@@ -82,7 +85,7 @@ func CheckHashes(src ObjectInfo, dst Object) (equal bool, hash HashType, err err
 	}
 	dstHash, err := dst.Hash(hash)
 	if err != nil {
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		Errorf(dst, "Failed to calculate dst hash: %v", err)
 		return false, hash, err
 	}
@@ -114,11 +117,15 @@ func CheckHashes(src ObjectInfo, dst Object) (equal bool, hash HashType, err err
 //
 // Otherwise the file is considered to be not equal including if there
 // were errors reading info.
-func Equal(src ObjectInfo, dst Object) bool {
-	return equal(src, dst, Config.SizeOnly, Config.CheckSum)
+func Equal(ctx context.Context, src ObjectInfo, dst Object) bool {
+	return equal(ctx, src, dst, Config.SizeOnly, Config.CheckSum, true)
 }
 
-func equal(src ObjectInfo, dst Object, sizeOnly, checkSum bool) bool {
+// equal is Equal with the config passed in
+//
+// If updateModTime is false then the mtime on the dst is never
+// updated.
+func equal(ctx context.Context, src ObjectInfo, dst Object, sizeOnly, checkSum, updateModTime bool) bool {
 	if !Config.IgnoreSize {
 		if src.Size() != dst.Size() {
 			Debugf(src, "Sizes differ (src %d vs dst %d)", src.Size(), dst.Size())
@@ -135,7 +142,7 @@ func equal(src ObjectInfo, dst Object, sizeOnly, checkSum bool) bool {
 	// If checking checksum and not modtime
 	if checkSum {
 		// Check the hash
-		same, hash, _ := CheckHashes(src, dst)
+		same, hash, _ := CheckHashes(ctx, src, dst)
 		if !same {
 			Debugf(src, "%v differ", hash)
 			return false
@@ -165,7 +172,7 @@ func equal(src ObjectInfo, dst Object, sizeOnly, checkSum bool) bool {
 	Debugf(src, "Modification times differ by %s: %v, %v", dt, srcModTime, dstModTime)
 
 	// Check if the hashes are the same
-	same, hash, _ := CheckHashes(src, dst)
+	same, hash, _ := CheckHashes(ctx, src, dst)
 	if !same {
 		Debugf(src, "%v differ", hash)
 		return false
@@ -176,7 +183,7 @@ func equal(src ObjectInfo, dst Object, sizeOnly, checkSum bool) bool {
 	}
 
 	// mod time differs but hash is the same to reset mod time if required
-	if !Config.NoUpdateModTime {
+	if updateModTime && !Config.NoUpdateModTime {
 		if Config.DryRun {
 			Logf(src, "Not updating modification time as --dry-run")
 		} else {
@@ -187,19 +194,19 @@ func equal(src ObjectInfo, dst Object, sizeOnly, checkSum bool) bool {
 				return false
 			}
 			// Update the mtime of the dst object here
-			err := dst.SetModTime(srcModTime)
+			err := dst.SetModTime(ctx, srcModTime)
 			if err == ErrorCantSetModTime {
 				Debugf(dst, "src and dst identical but can't set mod time without re-uploading")
 				return false
 			} else if err == ErrorCantSetModTimeWithoutDelete {
 				Debugf(dst, "src and dst identical but can't set mod time without deleting and re-uploading")
-				err = dst.Remove()
+				err = dst.Remove(ctx)
 				if err != nil {
 					Errorf(dst, "failed to delete before re-upload: %v", err)
 				}
 				return false
 			} else if err != nil {
-				Stats.Error(err)
+				StatsFromContext(ctx).Error(err)
 				Errorf(dst, "Failed to set modification time: %v", err)
 			} else {
 				Infof(src, "Updated modification time in destination")
@@ -235,12 +242,12 @@ func MimeType(o ObjectInfo) (mimeType string) {
 // Used to remove a failed copy
 //
 // Returns whether the file was succesfully removed or not
-func removeFailedCopy(dst Object) bool {
+func removeFailedCopy(ctx context.Context, dst Object) bool {
 	if dst == nil {
 		return false
 	}
 	Infof(dst, "Removing failed copy")
-	removeErr := dst.Remove()
+	removeErr := dst.Remove(ctx)
 	if removeErr != nil {
 		Infof(dst, "Failed to remove failed copy: %s", removeErr)
 		return false
@@ -273,7 +280,7 @@ var _ MimeTyper = (*overrideRemoteObject)(nil)
 
 // Copy src object to dst or f if nil.  If dst is nil then it uses
 // remote as the name of the new object.
-func Copy(f Fs, dst Object, remote string, src Object) (err error) {
+func Copy(ctx context.Context, f Fs, dst Object, remote string, src Object) (err error) {
 	if Config.DryRun {
 		Logf(src, "Not copying as --dry-run")
 		return nil
@@ -292,28 +299,54 @@ func Copy(f Fs, dst Object, remote string, src Object) (err error) {
 		}
 	}
 	hashOption := &HashesOption{Hashes: common}
+	options := []OpenOption{hashOption}
+	// Pass the metadata on to the destination if required
+	if Config.Metadata {
+		metadata, err := GetMetadata(ctx, src)
+		if err != nil {
+			Errorf(src, "Failed to read metadata: %v", err)
+		} else if metadata != nil {
+			options = append(options, MetadataOption(metadata))
+		}
+	}
 	var actionTaken string
 	for {
 		// Try server side copy first - if has optional interface and
 		// is same underlying remote
 		actionTaken = "Copied (server side copy)"
-		if doCopy := f.Features().Copy; doCopy != nil && SameConfig(src.Fs(), f) {
+		if doCopy := f.Features().Copy; doCopy != nil && canServerSide(f, src.Fs()) {
 			var newDst Object
-			newDst, err = doCopy(src, remote)
+			newDst, err = doCopy(ctx, src, remote)
 			if err == nil {
 				dst = newDst
 			}
 		} else {
 			err = ErrorCantCopy
 		}
-		// If can't server side copy, do it manually
+		// Don't start a transfer if --max-transfer has been reached
+		// - this isn't an error for this file so don't count it
 		if err == ErrorCantCopy {
+			if limitErr := checkMaxTransfer(StatsFromContext(ctx), src.Size()); limitErr != nil {
+				Debugf(src, "Not copying: %v", limitErr)
+				return limitErr
+			}
+		}
+		// If can't server side copy, do it manually
+		if err == ErrorCantCopy && doMultiThreadCopy(f, src) {
+			// Split the download into concurrent ranged reads
+			if doUpdate {
+				actionTaken = "Multi-thread Copied (replaced existing)"
+			} else {
+				actionTaken = "Multi-thread Copied (new)"
+			}
+			dst, err = multiThreadCopy(ctx, f, remote, src, Config.MultiThreadStreams)
+		} else if err == ErrorCantCopy {
 			var in0 io.ReadCloser
-			in0, err = src.Open(hashOption)
+			in0, err = src.Open(ctx, hashOption)
 			if err != nil {
 				err = errors.Wrap(err, "failed to open source object")
 			} else {
-				in := NewAccount(in0, src).WithBuffer() // account and buffer the transfer
+				in := NewAccount(ctx, in0, src).WithBuffer() // account and buffer the transfer
 				var wrappedSrc ObjectInfo = src
 				// We try to pass the original object if possible
 				if src.Remote() != remote {
@@ -321,10 +354,10 @@ func Copy(f Fs, dst Object, remote string, src Object) (err error) {
 				}
 				if doUpdate {
 					actionTaken = "Copied (replaced existing)"
-					err = dst.Update(in, wrappedSrc, hashOption)
+					err = dst.Update(ctx, in, wrappedSrc, options...)
 				} else {
 					actionTaken = "Copied (new)"
-					dst, err = f.Put(in, wrappedSrc, hashOption)
+					dst, err = f.Put(ctx, in, wrappedSrc, options...)
 				}
 				closeErr := in.Close()
 				if err == nil {
@@ -332,10 +365,20 @@ func Copy(f Fs, dst Object, remote string, src Object) (err error) {
 				}
 			}
 		}
+		// The transfer was stopped by --max-transfer - the error
+		// from the backend may not say so
+		if err != nil && maxTransferReachedHard(StatsFromContext(ctx)) {
+			err = ErrorMaxTransferLimitReached
+			break
+		}
 		tries++
 		if tries >= maxTries {
 			break
 		}
+		// Don't retry if the context has been cancelled
+		if ctx.Err() != nil {
+			break
+		}
 		// Retry if err returned a retry error
 		if IsRetryError(err) || ShouldRetry(err) {
 			Debugf(src, "Received error: %v - low level retry %d/%d", err, tries, maxTries)
@@ -345,7 +388,7 @@ func Copy(f Fs, dst Object, remote string, src Object) (err error) {
 		break
 	}
 	if err != nil {
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		Errorf(src, "Failed to copy: %v", err)
 		return err
 	}
@@ -354,8 +397,8 @@ func Copy(f Fs, dst Object, remote string, src Object) (err error) {
 	if !Config.IgnoreSize && src.Size() != dst.Size() {
 		err = errors.Errorf("corrupted on transfer: sizes differ %d vs %d", src.Size(), dst.Size())
 		Errorf(dst, "%v", err)
-		Stats.Error(err)
-		removeFailedCopy(dst)
+		StatsFromContext(ctx).Error(err)
+		removeFailedCopy(ctx, dst)
 		return err
 	}
 
@@ -366,19 +409,19 @@ func Copy(f Fs, dst Object, remote string, src Object) (err error) {
 		var srcSum string
 		srcSum, err = src.Hash(hashType)
 		if err != nil {
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(src, "Failed to read src hash: %v", err)
 		} else if srcSum != "" {
 			var dstSum string
 			dstSum, err = dst.Hash(hashType)
 			if err != nil {
-				Stats.Error(err)
+				StatsFromContext(ctx).Error(err)
 				Errorf(dst, "Failed to read hash: %v", err)
 			} else if !Config.IgnoreChecksum && !HashEquals(srcSum, dstSum) {
 				err = errors.Errorf("corrupted on transfer: %v hash differ %q vs %q", hashType, srcSum, dstSum)
 				Errorf(dst, "%v", err)
-				Stats.Error(err)
-				removeFailedCopy(dst)
+				StatsFromContext(ctx).Error(err)
+				removeFailedCopy(ctx, dst)
 				return err
 			}
 		}
@@ -390,22 +433,22 @@ func Copy(f Fs, dst Object, remote string, src Object) (err error) {
 
 // Move src object to dst or fdst if nil.  If dst is nil then it uses
 // remote as the name of the new object.
-func Move(fdst Fs, dst Object, remote string, src Object) (err error) {
+func Move(ctx context.Context, fdst Fs, dst Object, remote string, src Object) (err error) {
 	if Config.DryRun {
 		Logf(src, "Not moving as --dry-run")
 		return nil
 	}
 	// See if we have Move available
-	if doMove := fdst.Features().Move; doMove != nil && SameConfig(src.Fs(), fdst) {
+	if doMove := fdst.Features().Move; doMove != nil && canServerSide(fdst, src.Fs()) {
 		// Delete destination if it exists
 		if dst != nil {
-			err = DeleteFile(dst)
+			err = DeleteFile(ctx, dst)
 			if err != nil {
 				return err
 			}
 		}
 		// Move dst <- src
-		_, err := doMove(src, remote)
+		_, err := doMove(ctx, src, remote)
 		switch err {
 		case nil:
 			Infof(src, "Moved (server side)")
@@ -413,19 +456,19 @@ func Move(fdst Fs, dst Object, remote string, src Object) (err error) {
 		case ErrorCantMove:
 			Debugf(src, "Can't move, switching to copy")
 		default:
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(src, "Couldn't move: %v", err)
 			return err
 		}
 	}
 	// Move not found or didn't work so copy dst <- src
-	err = Copy(fdst, dst, remote, src)
+	err = Copy(ctx, fdst, dst, remote, src)
 	if err != nil {
 		Errorf(src, "Not deleting source as copy failed: %v", err)
 		return err
 	}
 	// Delete src if no error on copy
-	return DeleteFile(src)
+	return DeleteFile(ctx, src)
 }
 
 // CanServerSideMove returns true if fdst support server side moves or
@@ -444,8 +487,8 @@ func CanServerSideMove(fdst Fs) bool {
 //
 // If backupDir is set then it moves the file to there instead of
 // deleting
-func deleteFileWithBackupDir(dst Object, backupDir Fs) (err error) {
-	Stats.Checking(dst.Remote())
+func deleteFileWithBackupDir(ctx context.Context, dst Object, backupDir Fs) (err error) {
+	StatsFromContext(ctx).Checking(dst.Remote())
 	action, actioned, actioning := "delete", "Deleted", "deleting"
 	if backupDir != nil {
 		action, actioned, actioning = "move into backup dir", "Moved into backup dir", "moving into backup dir"
@@ -457,19 +500,20 @@ func deleteFileWithBackupDir(dst Object, backupDir Fs) (err error) {
 			err = errors.New("parameter to --backup-dir has to be on the same remote as destination")
 		} else {
 			remoteWithSuffix := dst.Remote() + Config.Suffix
-			overwritten, _ := backupDir.NewObject(remoteWithSuffix)
-			err = Move(backupDir, overwritten, remoteWithSuffix, dst)
+			overwritten, _ := backupDir.NewObject(ctx, remoteWithSuffix)
+			err = Move(ctx, backupDir, overwritten, remoteWithSuffix, dst)
 		}
 	} else {
-		err = dst.Remove()
+		err = dst.Remove(ctx)
 	}
 	if err != nil {
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		Errorf(dst, "Couldn't %s: %v", action, err)
 	} else if !Config.DryRun {
+		StatsFromContext(ctx).Deletes(1)
 		Infof(dst, actioned)
 	}
-	Stats.DoneChecking(dst.Remote())
+	StatsFromContext(ctx).DoneChecking(dst.Remote())
 	return err
 }
 
@@ -477,8 +521,8 @@ func deleteFileWithBackupDir(dst Object, backupDir Fs) (err error) {
 //
 // If useBackupDir is set and --backup-dir is in effect then it moves
 // the file to there instead of deleting
-func DeleteFile(dst Object) (err error) {
-	return deleteFileWithBackupDir(dst, nil)
+func DeleteFile(ctx context.Context, dst Object) (err error) {
+	return deleteFileWithBackupDir(ctx, dst, nil)
 }
 
 // deleteFilesWithBackupDir removes all the files passed in the
@@ -486,7 +530,7 @@ func DeleteFile(dst Object) (err error) {
 //
 // If backupDir is set the files will be placed into that directory
 // instead of being deleted.
-func deleteFilesWithBackupDir(toBeDeleted ObjectsChan, backupDir Fs) error {
+func deleteFilesWithBackupDir(ctx context.Context, toBeDeleted ObjectsChan, backupDir Fs) error {
 	var wg sync.WaitGroup
 	wg.Add(Config.Transfers)
 	var errorCount int32
@@ -494,9 +538,12 @@ func deleteFilesWithBackupDir(toBeDeleted ObjectsChan, backupDir Fs) error {
 		go func() {
 			defer wg.Done()
 			for dst := range toBeDeleted {
-				err := deleteFileWithBackupDir(dst, backupDir)
+				err := deleteFileWithBackupDir(ctx, dst, backupDir)
 				if err != nil {
 					atomic.AddInt32(&errorCount, 1)
+					report(ctx, SigilError, dst.Remote(), err)
+				} else {
+					report(ctx, SigilMissingOnSrc, dst.Remote(), nil)
 				}
 			}
 		}()
@@ -510,8 +557,8 @@ func deleteFilesWithBackupDir(toBeDeleted ObjectsChan, backupDir Fs) error {
 }
 
 // DeleteFiles removes all the files passed in the channel
-func DeleteFiles(toBeDeleted ObjectsChan) error {
-	return deleteFilesWithBackupDir(toBeDeleted, nil)
+func DeleteFiles(ctx context.Context, toBeDeleted ObjectsChan) error {
+	return deleteFilesWithBackupDir(ctx, toBeDeleted, nil)
 }
 
 // Read a Objects into add() for the given Fs.
@@ -521,8 +568,8 @@ func DeleteFiles(toBeDeleted ObjectsChan) error {
 //
 // Each object is passed ito the function provided.  If that returns
 // an error then the listing will be aborted and that error returned.
-func readFilesFn(fs Fs, includeAll bool, dir string, add func(Object) error) (err error) {
-	return Walk(fs, "", includeAll, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
+func readFilesFn(ctx context.Context, fs Fs, includeAll bool, dir string, add func(Object) error) (err error) {
+	return Walk(ctx, fs, "", includeAll, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
@@ -616,9 +663,9 @@ func DirEntryType(d DirEntry) string {
 // files and directories passing the filter will be added.
 //
 // Files will be returned in sorted order
-func ListDirSorted(fs Fs, includeAll bool, dir string) (entries DirEntries, err error) {
+func ListDirSorted(ctx context.Context, fs Fs, includeAll bool, dir string) (entries DirEntries, err error) {
 	// Get unfiltered entries from the fs
-	entries, err = fs.List(dir)
+	entries, err = fs.List(ctx, dir)
 	if err != nil {
 		return nil, err
 	}
@@ -629,7 +676,7 @@ func ListDirSorted(fs Fs, includeAll bool, dir string) (entries DirEntries, err
 		Debugf(dir, "Excluded from sync (and deletion)")
 		return nil, nil
 	}
-	return filterAndSortDir(entries, includeAll, dir, Config.Filter.IncludeObject, Config.Filter.IncludeDirectory(fs))
+	return filterAndSortDir(entries, includeAll, dir, Config.Filter.IncludeObject, Config.Filter.IncludeDirectory(ctx, fs))
 }
 
 // filter (if required) and check the entries, then sort them
@@ -705,6 +752,25 @@ func SameConfig(fdst, fsrc Info) bool {
 	return fdst.Name() == fsrc.Name()
 }
 
+// SameRemoteType returns true if fdst and fsrc are the same type of
+// Fs, eg both s3
+func SameRemoteType(fdst, fsrc Info) bool {
+	return fmt.Sprintf("%T", fdst) == fmt.Sprintf("%T", fsrc)
+}
+
+// canServerSide returns true if objects from fsrc can be copied or
+// moved to fdst with its server side Copy or Move
+//
+// This is possible if they use the same config or if fdst says that
+// it can do server side operations across configs and fsrc is the
+// same type of remote.
+func canServerSide(fdst Fs, fsrc Info) bool {
+	if SameConfig(fdst, fsrc) {
+		return true
+	}
+	return fdst.Features().ServerSideAcrossConfigs && SameRemoteType(fdst, fsrc)
+}
+
 // Same returns true if fdst and fsrc point to the same underlying Fs
 func Same(fdst, fsrc Info) bool {
 	return SameConfig(fdst, fsrc) && fdst.Root() == fsrc.Root()
@@ -733,8 +799,8 @@ func Overlapping(fdst, fsrc Info) bool {
 //
 // it returns true if differences were found
 // it also returns whether it couldn't be hashed
-func checkIdentical(dst, src Object) (differ bool, noHash bool) {
-	same, hash, err := CheckHashes(src, dst)
+func checkIdentical(ctx context.Context, dst, src Object) (differ bool, noHash bool) {
+	same, hash, err := CheckHashes(ctx, src, dst)
 	if err != nil {
 		// CheckHashes will log and count errors
 		return true, false
@@ -745,20 +811,22 @@ func checkIdentical(dst, src Object) (differ bool, noHash bool) {
 	if !same {
 		err = errors.Errorf("%v differ", hash)
 		Errorf(src, "%v", err)
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		return true, false
 	}
 	return false, false
 }
 
 // checkFn is the the type of the checking function used in CheckFn()
-type checkFn func(a, b Object) (differ bool, noHash bool)
+type checkFn func(ctx context.Context, a, b Object) (differ bool, noHash bool)
 
 // checkMarch is used to march over two Fses in the same way as
 // sync/copy
 type checkMarch struct {
+	ctx             context.Context
 	fdst, fsrc      Fs
 	check           checkFn
+	oneway          bool
 	differences     int32
 	noHashes        int32
 	srcFilesMissing int32
@@ -769,13 +837,20 @@ type checkMarch struct {
 func (c *checkMarch) DstOnly(dst DirEntry) (recurse bool) {
 	switch dst.(type) {
 	case Object:
+		if c.oneway {
+			return false
+		}
 		err := errors.Errorf("File not in %v", c.fsrc)
 		Errorf(dst, "%v", err)
-		Stats.Error(err)
+		StatsFromContext(c.ctx).Error(err)
 		atomic.AddInt32(&c.differences, 1)
 		atomic.AddInt32(&c.srcFilesMissing, 1)
+		report(c.ctx, SigilMissingOnSrc, dst.Remote(), nil)
 	case Directory:
 		// Do the same thing to the entire contents of the directory
+		if c.oneway {
+			return false
+		}
 		return true
 	default:
 		panic("Bad object in DirEntries")
@@ -789,9 +864,10 @@ func (c *checkMarch) SrcOnly(src DirEntry) (recurse bool) {
 	case Object:
 		err := errors.Errorf("File not in %v", c.fdst)
 		Errorf(src, "%v", err)
-		Stats.Error(err)
+		StatsFromContext(c.ctx).Error(err)
 		atomic.AddInt32(&c.differences, 1)
 		atomic.AddInt32(&c.dstFilesMissing, 1)
+		report(c.ctx, SigilMissingOnDst, src.Remote(), nil)
 	case Directory:
 		// Do the same thing to the entire contents of the directory
 		return true
@@ -803,18 +879,18 @@ func (c *checkMarch) SrcOnly(src DirEntry) (recurse bool) {
 
 // check to see if two objects are identical using the check function
 func (c *checkMarch) checkIdentical(dst, src Object) (differ bool, noHash bool) {
-	Stats.Checking(src.Remote())
-	defer Stats.DoneChecking(src.Remote())
+	StatsFromContext(c.ctx).Checking(src.Remote())
+	defer StatsFromContext(c.ctx).DoneChecking(src.Remote())
 	if !Config.IgnoreSize && src.Size() != dst.Size() {
 		err := errors.Errorf("Sizes differ")
 		Errorf(src, "%v", err)
-		Stats.Error(err)
+		StatsFromContext(c.ctx).Error(err)
 		return true, false
 	}
 	if Config.SizeOnly {
 		return false, false
 	}
-	return c.check(dst, src)
+	return c.check(c.ctx, dst, src)
 }
 
 // Match is called when src and dst are present, so sync src to dst
@@ -826,8 +902,10 @@ func (c *checkMarch) Match(dst, src DirEntry) (recurse bool) {
 			differ, noHash := c.checkIdentical(dstX, srcX)
 			if differ {
 				atomic.AddInt32(&c.differences, 1)
+				report(c.ctx, SigilDiffer, src.Remote(), nil)
 			} else {
 				Debugf(dstX, "OK")
+				report(c.ctx, SigilMatch, src.Remote(), nil)
 			}
 			if noHash {
 				atomic.AddInt32(&c.noHashes, 1)
@@ -835,9 +913,10 @@ func (c *checkMarch) Match(dst, src DirEntry) (recurse bool) {
 		} else {
 			err := errors.Errorf("is file on %v but directory on %v", c.fsrc, c.fdst)
 			Errorf(src, "%v", err)
-			Stats.Error(err)
+			StatsFromContext(c.ctx).Error(err)
 			atomic.AddInt32(&c.differences, 1)
 			atomic.AddInt32(&c.dstFilesMissing, 1)
+			report(c.ctx, SigilMissingOnDst, src.Remote(), nil)
 		}
 	case Directory:
 		// Do the same thing to the entire contents of the directory
@@ -847,9 +926,10 @@ func (c *checkMarch) Match(dst, src DirEntry) (recurse bool) {
 		}
 		err := errors.Errorf("is file on %v but directory on %v", c.fdst, c.fsrc)
 		Errorf(dst, "%v", err)
-		Stats.Error(err)
+		StatsFromContext(c.ctx).Error(err)
 		atomic.AddInt32(&c.differences, 1)
 		atomic.AddInt32(&c.srcFilesMissing, 1)
+		report(c.ctx, SigilMissingOnSrc, dst.Remote(), nil)
 
 	default:
 		panic("Bad object in DirEntries")
@@ -864,15 +944,20 @@ func (c *checkMarch) Match(dst, src DirEntry) (recurse bool) {
 //
 // it returns true if differences were found
 // it also returns whether it couldn't be hashed
-func CheckFn(fdst, fsrc Fs, check checkFn) error {
+//
+// If oneway is set then files only in fdst aren't counted as
+// differences.
+func CheckFn(ctx context.Context, fdst, fsrc Fs, check checkFn, oneway bool) error {
 	c := &checkMarch{
-		fdst:  fdst,
-		fsrc:  fsrc,
-		check: check,
+		ctx:    ctx,
+		fdst:   fdst,
+		fsrc:   fsrc,
+		check:  check,
+		oneway: oneway,
 	}
 
 	// set up a march over fdst and fsrc
-	m := newMarch(context.Background(), fdst, fsrc, "", c)
+	m := newMarch(ctx, fdst, fsrc, "", c)
 	Infof(fdst, "Waiting for checks to finish")
 	m.run()
 
@@ -883,7 +968,7 @@ func CheckFn(fdst, fsrc Fs, check checkFn) error {
 		Logf(fsrc, "%d files missing", c.srcFilesMissing)
 	}
 
-	Logf(fdst, "%d differences found", Stats.GetErrors())
+	Logf(fdst, "%d differences found", StatsFromContext(ctx).GetErrors())
 	if c.noHashes > 0 {
 		Logf(fdst, "%d hashes could not be checked", c.noHashes)
 	}
@@ -894,8 +979,11 @@ func CheckFn(fdst, fsrc Fs, check checkFn) error {
 }
 
 // Check the files in fsrc and fdst according to Size and hash
-func Check(fdst, fsrc Fs) error {
-	return CheckFn(fdst, fsrc, checkIdentical)
+//
+// If oneway is set then files only in fdst aren't counted as
+// differences.
+func Check(ctx context.Context, fdst, fsrc Fs, oneway bool) error {
+	return CheckFn(ctx, fdst, fsrc, checkIdentical, oneway)
 }
 
 // ReadFill reads as much data from r into buf as it can
@@ -947,19 +1035,19 @@ func CheckEqualReaders(in1, in2 io.Reader) (differ bool, err error) {
 // reading all their bytes if necessary.
 //
 // it returns true if differences were found
-func CheckIdentical(dst, src Object) (differ bool, err error) {
-	in1, err := dst.Open()
+func CheckIdentical(ctx context.Context, dst, src Object) (differ bool, err error) {
+	in1, err := dst.Open(ctx)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to open %q", dst)
 	}
-	in1 = NewAccount(in1, dst).WithBuffer() // account and buffer the transfer
+	in1 = NewAccount(ctx, in1, dst).WithBuffer() // account and buffer the transfer
 	defer CheckClose(in1, &err)
 
-	in2, err := src.Open()
+	in2, err := src.Open(ctx)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to open %q", src)
 	}
-	in2 = NewAccount(in2, src).WithBuffer() // account and buffer the transfer
+	in2 = NewAccount(ctx, in2, src).WithBuffer() // account and buffer the transfer
 	defer CheckClose(in2, &err)
 
 	return CheckEqualReaders(in1, in2)
@@ -967,24 +1055,35 @@ func CheckIdentical(dst, src Object) (differ bool, err error) {
 
 // CheckDownload checks the files in fsrc and fdst according to Size
 // and the actual contents of the files.
-func CheckDownload(fdst, fsrc Fs) error {
-	check := func(a, b Object) (differ bool, noHash bool) {
-		differ, err := CheckIdentical(a, b)
+//
+// The files are compared by streaming them from both remotes at once,
+// --checkers at a time.
+//
+// If oneway is set then files only in fdst aren't counted as
+// differences.
+func CheckDownload(ctx context.Context, fdst, fsrc Fs, oneway bool) error {
+	check := func(ctx context.Context, a, b Object) (differ bool, noHash bool) {
+		differ, err := CheckIdentical(ctx, a, b)
 		if err != nil {
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(a, "Failed to download: %v", err)
 			return true, true
 		}
+		if differ {
+			err = errors.New("contents differ")
+			Errorf(b, "%v", err)
+			StatsFromContext(ctx).Error(err)
+		}
 		return differ, false
 	}
-	return CheckFn(fdst, fsrc, check)
+	return CheckFn(ctx, fdst, fsrc, check, oneway)
 }
 
 // ListFn lists the Fs to the supplied function
 //
 // Lists in parallel which may get them out of order
-func ListFn(f Fs, fn func(Object)) error {
-	return Walk(f, "", false, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
+func ListFn(ctx context.Context, f Fs, fn func(Object)) error {
+	return Walk(ctx, f, "", false, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
 		if err != nil {
 			// FIXME count errors and carry on for listing
 			return err
@@ -1011,8 +1110,8 @@ func syncFprintf(w io.Writer, format string, a ...interface{}) {
 // Shows size and path - obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func List(f Fs, w io.Writer) error {
-	return ListFn(f, func(o Object) {
+func List(ctx context.Context, f Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o Object) {
 		syncFprintf(w, "%9d %s\n", o.Size(), o.Remote())
 	})
 }
@@ -1022,11 +1121,11 @@ func List(f Fs, w io.Writer) error {
 // Shows size, mod time and path - obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func ListLong(f Fs, w io.Writer) error {
-	return ListFn(f, func(o Object) {
-		Stats.Checking(o.Remote())
+func ListLong(ctx context.Context, f Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o Object) {
+		StatsFromContext(ctx).Checking(o.Remote())
 		modTime := o.ModTime()
-		Stats.DoneChecking(o.Remote())
+		StatsFromContext(ctx).DoneChecking(o.Remote())
 		syncFprintf(w, "%9d %s %s\n", o.Size(), modTime.Local().Format("2006-01-02 15:04:05.000000000"), o.Remote())
 	})
 }
@@ -1037,8 +1136,8 @@ func ListLong(f Fs, w io.Writer) error {
 // excludes
 //
 // Lists in parallel which may get them out of order
-func Md5sum(f Fs, w io.Writer) error {
-	return hashLister(HashMD5, f, w)
+func Md5sum(ctx context.Context, f Fs, w io.Writer) error {
+	return hashLister(ctx, HashMD5, f, w)
 }
 
 // Sha1sum list the Fs to the supplied writer
@@ -1046,8 +1145,8 @@ func Md5sum(f Fs, w io.Writer) error {
 // Obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func Sha1sum(f Fs, w io.Writer) error {
-	return hashLister(HashSHA1, f, w)
+func Sha1sum(ctx context.Context, f Fs, w io.Writer) error {
+	return hashLister(ctx, HashSHA1, f, w)
 }
 
 // DropboxHashSum list the Fs to the supplied writer
@@ -1055,15 +1154,20 @@ func Sha1sum(f Fs, w io.Writer) error {
 // Obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func DropboxHashSum(f Fs, w io.Writer) error {
-	return hashLister(HashDropbox, f, w)
+func DropboxHashSum(ctx context.Context, f Fs, w io.Writer) error {
+	return hashLister(ctx, HashDropbox, f, w)
 }
 
-func hashLister(ht HashType, f Fs, w io.Writer) error {
-	return ListFn(f, func(o Object) {
-		Stats.Checking(o.Remote())
-		sum, err := o.Hash(ht)
-		Stats.DoneChecking(o.Remote())
+func hashLister(ctx context.Context, ht HashType, f Fs, w io.Writer) error {
+	return hashListerFn(ctx, ht, f, w, func(o Object) (string, error) {
+		return o.Hash(ht)
+	})
+}
+
+// hashListerFn lists the hashes of type ht of the objects in f to w
+// as read by hash.
+func hashListerFn(ctx context.Context, ht HashType, f Fs, w io.Writer, hash func(o Object) (string, error)) error {
+	return hashObjects(ctx, f, hash, func(o Object, sum string, err error) {
 		if err == ErrHashUnsupported {
 			sum = "UNSUPPORTED"
 		} else if err != nil {
@@ -1074,11 +1178,238 @@ func hashLister(ht HashType, f Fs, w io.Writer) error {
 	})
 }
 
+// hashSum returns the hash of type ht of o.  If download is set then
+// it reads the object to calculate it.
+func hashSum(ctx context.Context, ht HashType, download bool, o Object) (sum string, err error) {
+	if !download {
+		return o.Hash(ht)
+	}
+	in, err := o.Open(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open")
+	}
+	in = NewAccount(ctx, in, o).WithBuffer() // account and buffer the transfer
+	defer CheckClose(in, &err)
+	sums, err := HashStreamTypes(in, NewHashSet(ht))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read")
+	}
+	return sums[ht], nil
+}
+
+// hashSumOrDownload is like hashSum but if o returns an empty hash
+// then o is read to calculate it.
+func hashSumOrDownload(ctx context.Context, ht HashType, download bool, o Object) (sum string, err error) {
+	sum, err = hashSum(ctx, ht, download, o)
+	if err == nil && sum == "" && !download {
+		Debugf(o, "No %v hash - reading the file to calculate it", ht)
+		return hashSum(ctx, ht, true, o)
+	}
+	return sum, err
+}
+
+// hashObjects calls fn with the hash of each object in f as read by
+// hash or the error reading it.  The hashes are read in parallel
+// using --checkers go routines but fn is called in the order the
+// objects were listed and not concurrently.
+func hashObjects(ctx context.Context, f Fs, hash func(o Object) (string, error), fn func(o Object, sum string, err error)) error {
+	type job struct {
+		o    Object
+		sum  string
+		err  error
+		done chan struct{}
+	}
+	var (
+		wg      sync.WaitGroup
+		jobs    = make(chan *job, Config.Checkers)
+		ordered = make(chan *job, Config.Checkers)
+		output  = make(chan struct{})
+	)
+	for i := 0; i < Config.Checkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				StatsFromContext(ctx).Checking(j.o.Remote())
+				j.sum, j.err = hash(j.o)
+				StatsFromContext(ctx).DoneChecking(j.o.Remote())
+				close(j.done)
+			}
+		}()
+	}
+	go func() {
+		for j := range ordered {
+			<-j.done
+			fn(j.o, j.sum, j.err)
+		}
+		close(output)
+	}()
+	err := ListFn(ctx, f, func(o Object) {
+		j := &job{o: o, done: make(chan struct{})}
+		ordered <- j
+		jobs <- j
+	})
+	close(jobs)
+	close(ordered)
+	wg.Wait()
+	<-output
+	return err
+}
+
+// HashSum lists the hashes of type ht of the objects in f to w in
+// the same format as md5sum.
+//
+// If download is set, or f doesn't support ht, the objects are read
+// to calculate the hashes.  They are also read if the hash of an
+// object is empty.
+//
+// Obeys includes and excludes
+//
+// Lists in parallel which may get them out of order
+func HashSum(ctx context.Context, f Fs, ht HashType, download bool, w io.Writer) error {
+	if !f.Hashes().Contains(ht) {
+		download = true
+	}
+	return hashListerFn(ctx, ht, f, w, func(o Object) (string, error) {
+		return hashSumOrDownload(ctx, ht, download, o)
+	})
+}
+
+// ParseSumFile reads a checksum file in the format written by md5sum,
+// sha256sum or HashSum returning a map of paths to lower case hashes.
+//
+// Blank lines and lines starting with '#' are ignored.  A '*' before
+// the path (binary mode) and a leading "./" are removed.
+func ParseSumFile(in io.Reader) (sums map[string]string, err error) {
+	sums = make(map[string]string)
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		i := strings.IndexAny(line, " \t")
+		if i <= 0 || i+1 >= len(line) {
+			return nil, errors.Errorf("malformed line %d in sum file: %q", lineNumber, line)
+		}
+		sum, remote := line[:i], line[i+1:]
+		if remote[0] == ' ' || remote[0] == '*' {
+			remote = remote[1:]
+		}
+		remote = strings.TrimPrefix(remote, "./")
+		if remote == "" {
+			return nil, errors.Errorf("malformed line %d in sum file: %q", lineNumber, line)
+		}
+		sums[remote] = strings.ToLower(sum)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read sum file")
+	}
+	return sums, nil
+}
+
+// HashSumCheck checks the hashes of type ht of the objects in f
+// against the sums read from in which should be in the format read by
+// ParseSumFile.
+//
+// Files in the sum file which are missing from f, files in f which
+// are missing from the sum file and files whose hashes differ are
+// all logged and counted as differences.  These are reported with
+// the sum file being the source and f the destination.
+//
+// If download is set, or f doesn't support ht, the objects are read
+// to calculate the hashes.  They are also read if the hash of an
+// object is empty, as it can't be checked otherwise.
+//
+// If the sum file is in f then pass its path as sumFile and it will
+// be skipped.
+//
+// Obeys includes and excludes
+func HashSumCheck(ctx context.Context, f Fs, ht HashType, download bool, in io.Reader, sumFile string) error {
+	sums, err := ParseSumFile(in)
+	if err != nil {
+		return err
+	}
+	if !f.Hashes().Contains(ht) {
+		download = true
+	}
+	if sumFile != "" {
+		delete(sums, sumFile)
+	}
+	hash := func(o Object) (string, error) {
+		if o.Remote() == sumFile {
+			return "", nil
+		}
+		return hashSumOrDownload(ctx, ht, download, o)
+	}
+	var differences, missing, extra, matches int
+	err = hashObjects(ctx, f, hash, func(o Object, sum string, err error) {
+		remote := o.Remote()
+		if remote == sumFile {
+			Debugf(o, "Skipping sum file")
+			return
+		}
+		want, found := sums[remote]
+		if !found {
+			err = errors.New("File not in sum file")
+			Errorf(o, "%v", err)
+			StatsFromContext(ctx).Error(err)
+			extra++
+			differences++
+			report(ctx, SigilMissingOnSrc, remote, nil)
+			return
+		}
+		delete(sums, remote)
+		if err != nil {
+			Errorf(o, "Failed to read %v: %v", ht, err)
+			StatsFromContext(ctx).Error(err)
+			differences++
+			report(ctx, SigilError, remote, err)
+			return
+		}
+		if sum != want {
+			err = errors.Errorf("%v differ", ht)
+			Errorf(o, "%v", err)
+			StatsFromContext(ctx).Error(err)
+			differences++
+			report(ctx, SigilDiffer, remote, nil)
+			return
+		}
+		Debugf(o, "OK")
+		matches++
+		report(ctx, SigilMatch, remote, nil)
+	})
+	if err != nil {
+		return err
+	}
+	for remote := range sums {
+		err := errors.Errorf("File not in %v", f)
+		Errorf(remote, "%v", err)
+		StatsFromContext(ctx).Error(err)
+		missing++
+		differences++
+		report(ctx, SigilMissingOnDst, remote, nil)
+	}
+	if missing > 0 {
+		Logf(f, "%d files missing", missing)
+	}
+	if extra > 0 {
+		Logf(f, "%d files not in sum file", extra)
+	}
+	Logf(f, "%d matching files", matches)
+	if differences > 0 {
+		return errors.Errorf("%d differences found", differences)
+	}
+	return nil
+}
+
 // Count counts the objects and their sizes in the Fs
 //
 // Obeys includes and excludes
-func Count(f Fs) (objects int64, size int64, err error) {
-	err = ListFn(f, func(o Object) {
+func Count(ctx context.Context, f Fs) (objects int64, size int64, err error) {
+	err = ListFn(ctx, f, func(o Object) {
 		atomic.AddInt64(&objects, 1)
 		atomic.AddInt64(&size, o.Size())
 	})
@@ -1095,8 +1426,8 @@ func ConfigMaxDepth(recursive bool) int {
 }
 
 // ListDir lists the directories/buckets/containers in the Fs to the supplied writer
-func ListDir(f Fs, w io.Writer) error {
-	return Walk(f, "", false, ConfigMaxDepth(false), func(dirPath string, entries DirEntries, err error) error {
+func ListDir(ctx context.Context, f Fs, w io.Writer) error {
+	return Walk(ctx, f, "", false, ConfigMaxDepth(false), func(dirPath string, entries DirEntries, err error) error {
 		if err != nil {
 			// FIXME count errors and carry on for listing
 			return err
@@ -1119,15 +1450,15 @@ func logDirName(f Fs, dir string) interface{} {
 }
 
 // Mkdir makes a destination directory or container
-func Mkdir(f Fs, dir string) error {
+func Mkdir(ctx context.Context, f Fs, dir string) error {
 	if Config.DryRun {
 		Logf(logDirName(f, dir), "Not making directory as dry run is set")
 		return nil
 	}
 	Debugf(logDirName(f, dir), "Making directory")
-	err := f.Mkdir(dir)
+	err := f.Mkdir(ctx, dir)
 	if err != nil {
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		return err
 	}
 	return nil
@@ -1135,27 +1466,27 @@ func Mkdir(f Fs, dir string) error {
 
 // TryRmdir removes a container but not if not empty.  It doesn't
 // count errors but may return one.
-func TryRmdir(f Fs, dir string) error {
+func TryRmdir(ctx context.Context, f Fs, dir string) error {
 	if Config.DryRun {
 		Logf(logDirName(f, dir), "Not deleting as dry run is set")
 		return nil
 	}
 	Debugf(logDirName(f, dir), "Removing directory")
-	return f.Rmdir(dir)
+	return f.Rmdir(ctx, dir)
 }
 
 // Rmdir removes a container but not if not empty
-func Rmdir(f Fs, dir string) error {
-	err := TryRmdir(f, dir)
+func Rmdir(ctx context.Context, f Fs, dir string) error {
+	err := TryRmdir(ctx, f, dir)
 	if err != nil {
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		return err
 	}
 	return err
 }
 
 // Purge removes a container and all of its contents
-func Purge(f Fs) error {
+func Purge(ctx context.Context, f Fs) error {
 	doFallbackPurge := true
 	var err error
 	if doPurge := f.Features().Purge; doPurge != nil {
@@ -1163,7 +1494,7 @@ func Purge(f Fs) error {
 		if Config.DryRun {
 			Logf(f, "Not purging as --dry-run set")
 		} else {
-			err = doPurge()
+			err = doPurge(ctx)
 			if err == ErrorCantPurge {
 				doFallbackPurge = true
 			}
@@ -1171,14 +1502,14 @@ func Purge(f Fs) error {
 	}
 	if doFallbackPurge {
 		// DeleteFiles and Rmdir observe --dry-run
-		err = DeleteFiles(listToChan(f))
+		err = DeleteFiles(ctx, listToChan(ctx, f))
 		if err != nil {
 			return err
 		}
-		err = Rmdirs(f, "")
+		err = Rmdirs(ctx, f, "")
 	}
 	if err != nil {
-		Stats.Error(err)
+		StatsFromContext(ctx).Error(err)
 		return err
 	}
 	return nil
@@ -1186,13 +1517,13 @@ func Purge(f Fs) error {
 
 // Delete removes all the contents of a container.  Unlike Purge, it
 // obeys includes and excludes.
-func Delete(f Fs) error {
+func Delete(ctx context.Context, f Fs) error {
 	delete := make(ObjectsChan, Config.Transfers)
 	delErr := make(chan error, 1)
 	go func() {
-		delErr <- DeleteFiles(delete)
+		delErr <- DeleteFiles(ctx, delete)
 	}()
-	err := ListFn(f, func(o Object) {
+	err := ListFn(ctx, f, func(o Object) {
 		delete <- o
 	})
 	close(delete)
@@ -1204,7 +1535,7 @@ func Delete(f Fs) error {
 }
 
 // dedupeRename renames the objs slice to different names
-func dedupeRename(remote string, objs []Object) {
+func dedupeRename(ctx context.Context, remote string, objs []Object) {
 	f := objs[0].Fs()
 	doMove := f.Features().Move
 	if doMove == nil {
@@ -1215,9 +1546,9 @@ func dedupeRename(remote string, objs []Object) {
 	for i, o := range objs {
 		newName := fmt.Sprintf("%s-%d%s", base, i+1, ext)
 		if !Config.DryRun {
-			newObj, err := doMove(o, newName)
+			newObj, err := doMove(ctx, o, newName)
 			if err != nil {
-				Stats.Error(err)
+				StatsFromContext(ctx).Error(err)
 				Errorf(o, "Failed to rename: %v", err)
 				continue
 			}
@@ -1229,18 +1560,18 @@ func dedupeRename(remote string, objs []Object) {
 }
 
 // dedupeDeleteAllButOne deletes all but the one in keep
-func dedupeDeleteAllButOne(keep int, remote string, objs []Object) {
+func dedupeDeleteAllButOne(ctx context.Context, keep int, remote string, objs []Object) {
 	for i, o := range objs {
 		if i == keep {
 			continue
 		}
-		_ = DeleteFile(o)
+		_ = DeleteFile(ctx, o)
 	}
 	Logf(remote, "Deleted %d extra copies", len(objs)-1)
 }
 
 // dedupeDeleteIdentical deletes all but one of identical (by hash) copies
-func dedupeDeleteIdentical(remote string, objs []Object) []Object {
+func dedupeDeleteIdentical(ctx context.Context, remote string, objs []Object) []Object {
 	// See how many of these duplicates are identical
 	byHash := make(map[string][]Object, len(objs))
 	for _, o := range objs {
@@ -1256,7 +1587,7 @@ func dedupeDeleteIdentical(remote string, objs []Object) []Object {
 		if len(hashObjs) > 1 {
 			Logf(remote, "Deleting %d/%d identical duplicates (md5sum %q)", len(hashObjs)-1, len(hashObjs), md5sum)
 			for _, o := range hashObjs[1:] {
-				_ = DeleteFile(o)
+				_ = DeleteFile(ctx, o)
 			}
 		}
 		objs = append(objs, hashObjs[0])
@@ -1266,7 +1597,7 @@ func dedupeDeleteIdentical(remote string, objs []Object) []Object {
 }
 
 // dedupeInteractive interactively dedupes the slice of objects
-func dedupeInteractive(remote string, objs []Object) {
+func dedupeInteractive(ctx context.Context, remote string, objs []Object) {
 	fmt.Printf("%s: %d duplicates remain\n", remote, len(objs))
 	for i, o := range objs {
 		md5sum, err := o.Hash(HashMD5)
@@ -1279,9 +1610,9 @@ func dedupeInteractive(remote string, objs []Object) {
 	case 's':
 	case 'k':
 		keep := ChooseNumber("Enter the number of the file to keep", 1, len(objs))
-		dedupeDeleteAllButOne(keep-1, remote, objs)
+		dedupeDeleteAllButOne(ctx, keep-1, remote, objs)
 	case 'r':
-		dedupeRename(remote, objs)
+		dedupeRename(ctx, remote, objs)
 	}
 }
 
@@ -1354,9 +1685,9 @@ func (x *DeduplicateMode) Type() string {
 var _ pflag.Value = (*DeduplicateMode)(nil)
 
 // dedupeFindDuplicateDirs scans f for duplicate directories
-func dedupeFindDuplicateDirs(f Fs) ([][]Directory, error) {
+func dedupeFindDuplicateDirs(ctx context.Context, f Fs) ([][]Directory, error) {
 	duplicateDirs := [][]Directory{}
-	err := Walk(f, "", true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
+	err := Walk(ctx, f, "", true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
@@ -1378,7 +1709,7 @@ func dedupeFindDuplicateDirs(f Fs) ([][]Directory, error) {
 }
 
 // dedupeMergeDuplicateDirs merges all the duplicate directories found
-func dedupeMergeDuplicateDirs(f Fs, duplicateDirs [][]Directory) error {
+func dedupeMergeDuplicateDirs(ctx context.Context, f Fs, duplicateDirs [][]Directory) error {
 	mergeDirs := f.Features().MergeDirs
 	if mergeDirs == nil {
 		return errors.Errorf("%v: can't merge directories", f)
@@ -1390,7 +1721,7 @@ func dedupeMergeDuplicateDirs(f Fs, duplicateDirs [][]Directory) error {
 	for _, dirs := range duplicateDirs {
 		if !Config.DryRun {
 			Infof(dirs[0], "Merging contents of duplicate directories")
-			err := mergeDirs(dirs)
+			err := mergeDirs(ctx, dirs)
 			if err != nil {
 				return errors.Wrap(err, "merge duplicate dirs")
 			}
@@ -1405,20 +1736,20 @@ func dedupeMergeDuplicateDirs(f Fs, duplicateDirs [][]Directory) error {
 // Deduplicate interactively finds duplicate files and offers to
 // delete all but one or rename them to be different. Only useful with
 // Google Drive which can have duplicate file names.
-func Deduplicate(f Fs, mode DeduplicateMode) error {
+func Deduplicate(ctx context.Context, f Fs, mode DeduplicateMode) error {
 	Infof(f, "Looking for duplicates using %v mode.", mode)
 
 	// Find duplicate directories first and fix them - repeat
 	// until all fixed
 	for {
-		duplicateDirs, err := dedupeFindDuplicateDirs(f)
+		duplicateDirs, err := dedupeFindDuplicateDirs(ctx, f)
 		if err != nil {
 			return err
 		}
 		if len(duplicateDirs) == 0 {
 			break
 		}
-		err = dedupeMergeDuplicateDirs(f, duplicateDirs)
+		err = dedupeMergeDuplicateDirs(ctx, f, duplicateDirs)
 		if err != nil {
 			return err
 		}
@@ -1429,7 +1760,7 @@ func Deduplicate(f Fs, mode DeduplicateMode) error {
 
 	// Now find duplicate files
 	files := map[string][]Object{}
-	err := Walk(f, "", true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
+	err := Walk(ctx, f, "", true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
@@ -1445,24 +1776,24 @@ func Deduplicate(f Fs, mode DeduplicateMode) error {
 	for remote, objs := range files {
 		if len(objs) > 1 {
 			Logf(remote, "Found %d duplicates - deleting identical copies", len(objs))
-			objs = dedupeDeleteIdentical(remote, objs)
+			objs = dedupeDeleteIdentical(ctx, remote, objs)
 			if len(objs) <= 1 {
 				Logf(remote, "All duplicates removed")
 				continue
 			}
 			switch mode {
 			case DeduplicateInteractive:
-				dedupeInteractive(remote, objs)
+				dedupeInteractive(ctx, remote, objs)
 			case DeduplicateFirst:
-				dedupeDeleteAllButOne(0, remote, objs)
+				dedupeDeleteAllButOne(ctx, 0, remote, objs)
 			case DeduplicateNewest:
 				sort.Sort(objectsSortedByModTime(objs)) // sort oldest first
-				dedupeDeleteAllButOne(len(objs)-1, remote, objs)
+				dedupeDeleteAllButOne(ctx, len(objs)-1, remote, objs)
 			case DeduplicateOldest:
 				sort.Sort(objectsSortedByModTime(objs)) // sort oldest first
-				dedupeDeleteAllButOne(0, remote, objs)
+				dedupeDeleteAllButOne(ctx, 0, remote, objs)
 			case DeduplicateRename:
-				dedupeRename(remote, objs)
+				dedupeRename(ctx, remote, objs)
 			case DeduplicateSkip:
 				// skip
 			default:
@@ -1479,17 +1810,17 @@ func Deduplicate(f Fs, mode DeduplicateMode) error {
 // channel.
 //
 // If the error was ErrorDirNotFound then it will be ignored
-func listToChan(f Fs) ObjectsChan {
+func listToChan(ctx context.Context, f Fs) ObjectsChan {
 	o := make(ObjectsChan, Config.Checkers)
 	go func() {
 		defer close(o)
-		_ = Walk(f, "", true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
+		_ = Walk(ctx, f, "", true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
 			if err != nil {
 				if err == ErrorDirNotFound {
 					return nil
 				}
 				err = errors.Errorf("Failed to list: %v", err)
-				Stats.Error(err)
+				StatsFromContext(ctx).Error(err)
 				Errorf(nil, "%v", err)
 				return nil
 			}
@@ -1502,8 +1833,19 @@ func listToChan(f Fs) ObjectsChan {
 	return o
 }
 
+// PublicLink makes a link which can be used to read remote by
+// anyone, which stops working after expire if it is non zero and the
+// remote supports it.
+func PublicLink(ctx context.Context, f Fs, remote string, expire time.Duration) (string, error) {
+	doPublicLink := f.Features().PublicLink
+	if doPublicLink == nil {
+		return "", errors.Errorf("%v doesn't support public links", f)
+	}
+	return doPublicLink(ctx, remote, expire)
+}
+
 // CleanUp removes the trash for the Fs
-func CleanUp(f Fs) error {
+func CleanUp(ctx context.Context, f Fs) error {
 	doCleanUp := f.Features().CleanUp
 	if doCleanUp == nil {
 		return errors.Errorf("%v doesn't support cleanup", f)
@@ -1512,7 +1854,7 @@ func CleanUp(f Fs) error {
 		Logf(f, "Not running cleanup as --dry-run set")
 		return nil
 	}
-	return doCleanUp()
+	return doCleanUp(ctx)
 }
 
 // wrap a Reader and a Closer together into a ReadCloser
@@ -1529,13 +1871,13 @@ type readCloser struct {
 //
 // if count < 0 then it will be ignored
 // if count >= 0 then only that many characters will be output
-func Cat(f Fs, w io.Writer, offset, count int64) error {
+func Cat(ctx context.Context, f Fs, w io.Writer, offset, count int64) error {
 	var mu sync.Mutex
-	return ListFn(f, func(o Object) {
+	return ListFn(ctx, f, func(o Object) {
 		var err error
-		Stats.Transferring(o.Remote())
+		StatsFromContext(ctx).Transferring(o.Remote())
 		defer func() {
-			Stats.DoneTransferring(o.Remote(), err == nil)
+			StatsFromContext(ctx).DoneTransferring(o.Remote(), err == nil)
 		}()
 		size := o.Size()
 		thisOffset := offset
@@ -1548,9 +1890,9 @@ func Cat(f Fs, w io.Writer, offset, count int64) error {
 		if thisOffset > 0 {
 			options = append(options, &SeekOption{Offset: thisOffset})
 		}
-		in, err := o.Open(options...)
+		in, err := o.Open(ctx, options...)
 		if err != nil {
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(o, "Failed to open: %v", err)
 			return
 		}
@@ -1561,11 +1903,11 @@ func Cat(f Fs, w io.Writer, offset, count int64) error {
 				size = count
 			}
 		}
-		in = NewAccountSizeName(in, size, o.Remote()).WithBuffer() // account and buffer the transfer
+		in = NewAccountSizeName(ctx, in, size, o.Remote()).WithBuffer() // account and buffer the transfer
 		defer func() {
 			err = in.Close()
 			if err != nil {
-				Stats.Error(err)
+				StatsFromContext(ctx).Error(err)
 				Errorf(o, "Failed to close: %v", err)
 			}
 		}()
@@ -1574,18 +1916,18 @@ func Cat(f Fs, w io.Writer, offset, count int64) error {
 		defer mu.Unlock()
 		_, err = io.Copy(w, in)
 		if err != nil {
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(o, "Failed to send to output: %v", err)
 		}
 	})
 }
 
 // Rcat reads data from the Reader until EOF and uploads it to a file on remote
-func Rcat(fdst Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst Object, err error) {
-	Stats.Transferring(dstFileName)
-	in = NewAccountSizeName(in, -1, dstFileName).WithBuffer()
+func Rcat(ctx context.Context, fdst Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst Object, err error) {
+	StatsFromContext(ctx).Transferring(dstFileName)
+	in = NewAccountSizeName(ctx, in, -1, dstFileName).WithBuffer()
 	defer func() {
-		Stats.DoneTransferring(dstFileName, err == nil)
+		StatsFromContext(ctx).DoneTransferring(dstFileName, err == nil)
 		if otherErr := in.Close(); otherErr != nil {
 			Debugf(fdst, "Rcat: failed to close source: %v", err)
 		}
@@ -1601,9 +1943,9 @@ func Rcat(fdst Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst
 
 	compare := func(dst Object) error {
 		src := NewStaticObjectInfo(dstFileName, modTime, int64(readCounter.BytesRead()), false, hash.Sums(), fdst)
-		if !Equal(src, dst) {
+		if !Equal(ctx, src, dst) {
 			err = errors.Errorf("corrupted on transfer")
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(dst, "%v", err)
 			return err
 		}
@@ -1620,7 +1962,7 @@ func Rcat(fdst Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst
 			Logf("stdin", "Not uploading as --dry-run")
 			return nil, nil
 		}
-		dst, err := fdst.Put(in, objInfo, hashOption)
+		dst, err := fdst.Put(ctx, in, objInfo, hashOption)
 		if err != nil {
 			return dst, err
 		}
@@ -1642,7 +1984,7 @@ func Rcat(fdst Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst
 			return nil, errors.Wrap(err, "Failed to create temporary local FS to spool file")
 		}
 		defer func() {
-			err := Purge(tmpLocalFs)
+			err := Purge(ctx, tmpLocalFs)
 			if err != nil {
 				Infof(tmpLocalFs, "Failed to cleanup temporary FS: %v", err)
 			}
@@ -1658,26 +2000,140 @@ func Rcat(fdst Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst
 	}
 
 	objInfo := NewStaticObjectInfo(dstFileName, modTime, -1, false, nil, nil)
-	if dst, err = fStreamTo.Features().PutStream(in, objInfo, hashOption); err != nil {
+	if dst, err = fStreamTo.Features().PutStream(ctx, in, objInfo, hashOption); err != nil {
 		return dst, err
 	}
 	if err = compare(dst); err != nil {
 		return dst, err
 	}
 	if !canStream {
-		return dst, Copy(fdst, nil, dstFileName, dst)
+		return dst, Copy(ctx, fdst, nil, dstFileName, dst)
+	}
+	return dst, nil
+}
+
+// urlRetryErrorCodes are the HTTP status codes which are retried
+// when fetching a URL
+var urlRetryErrorCodes = []int{
+	429, // Too Many Requests
+	500, // Internal Server Error
+	502, // Bad Gateway
+	503, // Service Unavailable
+	504, // Gateway Timeout
+}
+
+// urlFileName works out the file name to use for the response - this
+// is the filename in the Content-Disposition header if set, otherwise
+// the last element of the URL path.
+func urlFileName(resp *http.Response) (string, error) {
+	var fileName string
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		fileName = params["filename"]
+	}
+	if fileName == "" {
+		fileName = resp.Request.URL.Path
+	}
+	fileName = path.Base(fileName)
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "", errors.New("file name wasn't found in url")
+	}
+	return fileName, nil
+}
+
+// copyURL does a single attempt at fetching url and uploading it
+func copyURL(ctx context.Context, fdst Fs, dstFileName string, url string, autoFilename bool) (dst Object, err error) {
+	resp, err := ctxhttp.Get(ctx, Config.Client(), url)
+	if err != nil {
+		return nil, err
+	}
+	defer CheckClose(resp.Body, &err)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err = errors.Errorf("failed to fetch url: %s", resp.Status)
+		if ShouldRetryHTTP(resp, urlRetryErrorCodes) {
+			err = RetryError(err)
+		}
+		return nil, err
+	}
+	if autoFilename {
+		dstFileName, err = urlFileName(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	modTime, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	if err != nil {
+		modTime = time.Now()
+	}
+	size := resp.ContentLength
+	if size < 0 {
+		// Size unknown so stream the upload
+		return Rcat(ctx, fdst, dstFileName, resp.Body, modTime)
+	}
+	if err = checkMaxTransfer(StatsFromContext(ctx), size); err != nil {
+		return nil, err
+	}
+	StatsFromContext(ctx).Transferring(dstFileName)
+	defer func() {
+		StatsFromContext(ctx).DoneTransferring(dstFileName, err == nil)
+	}()
+	in := NewAccountSizeName(ctx, resp.Body, size, dstFileName).WithBuffer()
+	objInfo := NewStaticObjectInfo(dstFileName, modTime, size, true, nil, fdst)
+	dst, err = fdst.Put(ctx, in, objInfo)
+	closeErr := in.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return dst, err
+	}
+	if dst.Size() != size {
+		return dst, errors.Errorf("corrupted on transfer: sizes differ %d vs %d", size, dst.Size())
+	}
+	return dst, nil
+}
+
+// CopyURL copies the data from url to (fdst, dstFileName)
+//
+// The size is read from the Content-Length and the modification time
+// from the Last-Modified header of the response.  If the size isn't
+// known the upload is streamed as in Rcat.
+//
+// If autoFilename is set then dstFileName is ignored and the file name
+// is read from the Content-Disposition header or the url.
+func CopyURL(ctx context.Context, fdst Fs, dstFileName string, url string, autoFilename bool) (dst Object, err error) {
+	if Config.DryRun {
+		Logf(url, "Not copying as --dry-run")
+		return nil, nil
+	}
+	maxTries := Config.LowLevelRetries
+	for tries := 1; ; tries++ {
+		dst, err = copyURL(ctx, fdst, dstFileName, url, autoFilename)
+		if err == nil || tries >= maxTries || ctx.Err() != nil {
+			break
+		}
+		// Retry if err returned a retry error
+		if !IsRetryError(err) && !ShouldRetry(err) {
+			break
+		}
+		Debugf(url, "Received error: %v - low level retry %d/%d", err, tries, maxTries)
+	}
+	if err != nil {
+		StatsFromContext(ctx).Error(err)
+		Errorf(url, "Failed to copy: %v", err)
+		return dst, err
 	}
+	Infof(dst, "Copied (new) from url")
 	return dst, nil
 }
 
 // Rmdirs removes any empty directories (or directories only
 // containing empty directories) under f, including f.
-func Rmdirs(f Fs, dir string) error {
+func Rmdirs(ctx context.Context, f Fs, dir string) error {
 	dirEmpty := make(map[string]bool)
 	dirEmpty[""] = true
-	err := Walk(f, dir, true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
+	err := Walk(ctx, f, dir, true, Config.MaxDepth, func(dirPath string, entries DirEntries, err error) error {
 		if err != nil {
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(f, "Failed to list %q: %v", dirPath, err)
 			return nil
 		}
@@ -1722,9 +2178,9 @@ func Rmdirs(f Fs, dir string) error {
 	sort.Strings(toDelete)
 	for i := len(toDelete) - 1; i >= 0; i-- {
 		dir := toDelete[i]
-		err := TryRmdir(f, dir)
+		err := TryRmdir(ctx, f, dir)
 		if err != nil {
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(dir, "Failed to rmdir: %v", err)
 			return err
 		}
@@ -1733,7 +2189,7 @@ func Rmdirs(f Fs, dir string) error {
 }
 
 // moveOrCopyFile moves or copies a single file possibly to a new name
-func moveOrCopyFile(fdst Fs, fsrc Fs, dstFileName string, srcFileName string, cp bool) (err error) {
+func moveOrCopyFile(ctx context.Context, fdst Fs, fsrc Fs, dstFileName string, srcFileName string, cp bool) (err error) {
 	dstFilePath := path.Join(fdst.Root(), dstFileName)
 	srcFilePath := path.Join(fsrc.Root(), srcFileName)
 	if fdst.Name() == fsrc.Name() && dstFilePath == srcFilePath {
@@ -1748,39 +2204,39 @@ func moveOrCopyFile(fdst Fs, fsrc Fs, dstFileName string, srcFileName string, cp
 	}
 
 	// Find src object
-	srcObj, err := fsrc.NewObject(srcFileName)
+	srcObj, err := fsrc.NewObject(ctx, srcFileName)
 	if err != nil {
 		return err
 	}
 
 	// Find dst object if it exists
-	dstObj, err := fdst.NewObject(dstFileName)
+	dstObj, err := fdst.NewObject(ctx, dstFileName)
 	if err == ErrorObjectNotFound {
 		dstObj = nil
 	} else if err != nil {
 		return err
 	}
 
-	if NeedTransfer(dstObj, srcObj) {
-		Stats.Transferring(srcFileName)
-		err = Op(fdst, dstObj, dstFileName, srcObj)
-		Stats.DoneTransferring(srcFileName, err == nil)
+	if NeedTransfer(ctx, dstObj, srcObj) {
+		StatsFromContext(ctx).Transferring(srcFileName)
+		err = Op(ctx, fdst, dstObj, dstFileName, srcObj)
+		StatsFromContext(ctx).DoneTransferring(srcFileName, err == nil)
 	} else {
-		Stats.Checking(srcFileName)
+		StatsFromContext(ctx).Checking(srcFileName)
 		if !cp {
-			err = DeleteFile(srcObj)
+			err = DeleteFile(ctx, srcObj)
 		}
-		defer Stats.DoneChecking(srcFileName)
+		defer StatsFromContext(ctx).DoneChecking(srcFileName)
 	}
 	return err
 }
 
 // MoveFile moves a single file possibly to a new name
-func MoveFile(fdst Fs, fsrc Fs, dstFileName string, srcFileName string) (err error) {
-	return moveOrCopyFile(fdst, fsrc, dstFileName, srcFileName, false)
+func MoveFile(ctx context.Context, fdst Fs, fsrc Fs, dstFileName string, srcFileName string) (err error) {
+	return moveOrCopyFile(ctx, fdst, fsrc, dstFileName, srcFileName, false)
 }
 
 // CopyFile moves a single file possibly to a new name
-func CopyFile(fdst Fs, fsrc Fs, dstFileName string, srcFileName string) (err error) {
-	return moveOrCopyFile(fdst, fsrc, dstFileName, srcFileName, true)
+func CopyFile(ctx context.Context, fdst Fs, fsrc Fs, dstFileName string, srcFileName string) (err error) {
+	return moveOrCopyFile(ctx, fdst, fsrc, dstFileName, srcFileName, true)
 }
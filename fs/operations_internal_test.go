@@ -3,10 +3,14 @@
 package fs
 
 import (
+	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 func TestFilterAndSortIncludeAll(t *testing.T) {
@@ -90,3 +94,29 @@ func TestFilterAndSortUnknown(t *testing.T) {
 	assert.Error(t, err, "error")
 	assert.Nil(t, newEntries)
 }
+
+// noHashObject is an Object which can be read but returns an empty
+// hash
+type noHashObject struct {
+	mockObject
+	contents string
+}
+
+func (o noHashObject) Hash(HashType) (string, error) { return "", nil }
+func (o noHashObject) Size() int64                   { return int64(len(o.contents)) }
+func (o noHashObject) Open(ctx context.Context, options ...OpenOption) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(o.contents)), nil
+}
+
+func TestHashSumOrDownload(t *testing.T) {
+	ctx := context.Background()
+	o := noHashObject{mockObject: "potato", contents: "hello"}
+
+	sum, err := hashSum(ctx, HashMD5, false, o)
+	require.NoError(t, err)
+	assert.Equal(t, "", sum)
+
+	sum, err = hashSumOrDownload(ctx, HashMD5, false, o)
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", sum)
+}
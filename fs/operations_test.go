@@ -25,7 +25,12 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -35,6 +40,7 @@ import (
 	"github.com/ncw/rclone/fstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 // Some times used in the tests
@@ -63,7 +69,7 @@ func TestLsd(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	var buf bytes.Buffer
-	err := fs.ListDir(r.Fremote, &buf)
+	err := fs.ListDir(context.Background(), r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	assert.Contains(t, res, "sub dir\n")
@@ -78,7 +84,7 @@ func TestLs(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	var buf bytes.Buffer
-	err := fs.List(r.Fremote, &buf)
+	err := fs.List(context.Background(), r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	assert.Contains(t, res, "        0 empty space\n")
@@ -94,7 +100,7 @@ func TestLsLong(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	var buf bytes.Buffer
-	err := fs.ListLong(r.Fremote, &buf)
+	err := fs.ListLong(context.Background(), r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	lines := strings.Split(strings.Trim(res, "\n"), "\n")
@@ -141,7 +147,7 @@ func TestHashSums(t *testing.T) {
 	// MD5 Sum
 
 	var buf bytes.Buffer
-	err := fs.Md5sum(r.Fremote, &buf)
+	err := fs.Md5sum(context.Background(), r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	if !strings.Contains(res, "d41d8cd98f00b204e9800998ecf8427e  empty space\n") &&
@@ -158,7 +164,7 @@ func TestHashSums(t *testing.T) {
 	// SHA1 Sum
 
 	buf.Reset()
-	err = fs.Sha1sum(r.Fremote, &buf)
+	err = fs.Sha1sum(context.Background(), r.Fremote, &buf)
 	require.NoError(t, err)
 	res = buf.String()
 	if !strings.Contains(res, "da39a3ee5e6b4b0d3255bfef95601890afd80709  empty space\n") &&
@@ -175,7 +181,7 @@ func TestHashSums(t *testing.T) {
 	// Dropbox Hash Sum
 
 	buf.Reset()
-	err = fs.DropboxHashSum(r.Fremote, &buf)
+	err = fs.DropboxHashSum(context.Background(), r.Fremote, &buf)
 	require.NoError(t, err)
 	res = buf.String()
 	if !strings.Contains(res, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  empty space\n") &&
@@ -190,6 +196,105 @@ func TestHashSums(t *testing.T) {
 	}
 }
 
+func TestHashSum(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
+	file2 := r.WriteBoth("empty space", "", t2)
+
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+
+	// HashSum downloads the files if the remote doesn't support
+	// the hash so should always produce the sums
+	for _, download := range []bool{false, true} {
+		var buf bytes.Buffer
+		err := fs.HashSum(context.Background(), r.Fremote, fs.HashSHA256, download, &buf)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		sort.Strings(lines)
+		assert.Equal(t, []string{
+			"d398f81cd00b370b116d049d2f3b73a3a7ed35446486effb789791a7e0b98e9c  potato2",
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  empty space",
+		}, lines)
+	}
+}
+
+func TestHashSumOrder(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	// Make the first files the slowest to hash so they finish last
+	var items []fstest.Item
+	var want []string
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("file%d", i)
+		item := r.WriteObject(name, strings.Repeat("x", (8-i)<<19), t1)
+		items = append(items, item)
+		want = append(want, item.Hashes[fs.HashMD5]+"  "+name)
+	}
+	fstest.CheckItems(t, r.Fremote, items...)
+
+	var buf bytes.Buffer
+	err := fs.HashSum(context.Background(), r.Fremote, fs.HashMD5, true, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, want, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestParseSumFile(t *testing.T) {
+	sums, err := fs.ParseSumFile(strings.NewReader("# comment\n" +
+		"D41D8CD98F00B204E9800998ECF8427E  empty space\n" +
+		"\n" +
+		"d6548b156ea68a4e003e786df99eee76 *./dir/potato2\r\n" +
+		"0123\tfile with  two spaces\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"empty space":           "d41d8cd98f00b204e9800998ecf8427e",
+		"dir/potato2":           "d6548b156ea68a4e003e786df99eee76",
+		"file with  two spaces": "0123",
+	}, sums)
+
+	_, err = fs.ParseSumFile(strings.NewReader("d41d8cd98f00b204e9800998ecf8427e\n"))
+	assert.EqualError(t, err, `malformed line 1 in sum file: "d41d8cd98f00b204e9800998ecf8427e"`)
+}
+
+func TestHashSumCheck(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteObject("potato2", "------------------------------------------------------------", t1)
+	file2 := r.WriteObject("empty space", "", t2)
+	file3 := r.WriteObject("extra", "extra", t2)
+	// the sum file is skipped
+	file4 := r.WriteObject("SHA256SUMS", "sums", t2)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3, file4)
+
+	check := func(sumFile string, wantErr string, wantReport []string) {
+		var combined bytes.Buffer
+		report := fs.NewReportWriter(false)
+		report.SetCombined(&combined)
+		ctx := fs.WithReporter(context.Background(), report)
+		fs.Stats.ResetCounters()
+		err := fs.HashSumCheck(ctx, r.Fremote, fs.HashSHA256, false, strings.NewReader(sumFile), "SHA256SUMS")
+		if wantErr == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, wantErr)
+		}
+		lines := strings.Split(strings.TrimSpace(combined.String()), "\n")
+		sort.Strings(lines)
+		assert.Equal(t, wantReport, lines)
+	}
+
+	check(`d398f81cd00b370b116d049d2f3b73a3a7ed35446486effb789791a7e0b98e9c  potato2
+e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  empty space
+c8dee78f8c7b466c881847accc196998bad00e2b96c5ef913dfbe454d3807c96  extra
+`, "", []string{"= empty space", "= extra", "= potato2"})
+
+	check(`d398f81cd00b370b116d049d2f3b73a3a7ed35446486effb789791a7e0b98e9c  potato2
+d398f81cd00b370b116d049d2f3b73a3a7ed35446486effb789791a7e0b98e9c  empty space
+e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  missing
+`, "3 differences found", []string{"* empty space", "+ missing", "- extra", "= potato2"})
+}
+
 func TestCount(t *testing.T) {
 	r := fstest.NewRun(t)
 	defer r.Finalise()
@@ -203,7 +308,7 @@ func TestCount(t *testing.T) {
 	fs.Config.MaxDepth = 1
 	defer func() { fs.Config.MaxDepth = -1 }()
 
-	objects, size, err := fs.Count(r.Fremote)
+	objects, size, err := fs.Count(context.Background(), r.Fremote)
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), objects)
 	assert.Equal(t, int64(60), size)
@@ -222,19 +327,19 @@ func TestDelete(t *testing.T) {
 		fs.Config.Filter.MaxSize = -1
 	}()
 
-	err := fs.Delete(r.Fremote)
+	err := fs.Delete(context.Background(), r.Fremote)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, file3)
 }
 
-func testCheck(t *testing.T, checkFunction func(fdst, fsrc fs.Fs) error) {
+func testCheck(t *testing.T, checkFunction func(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error) {
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
 	check := func(i int, wantErrors int64) {
 		fs.Debugf(r.Fremote, "%d: Starting check test", i)
 		oldErrors := fs.Stats.GetErrors()
-		err := checkFunction(r.Flocal, r.Fremote)
+		err := checkFunction(context.Background(), r.Flocal, r.Fremote, false)
 		gotErrors := fs.Stats.GetErrors() - oldErrors
 		if wantErrors == 0 && err != nil {
 			t.Errorf("%d: Got error when not expecting one: %v", i, err)
@@ -283,12 +388,89 @@ func TestCheckDownload(t *testing.T) {
 	testCheck(t, fs.CheckDownload)
 }
 
+func testCheckOneWay(t *testing.T, checkFunction func(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	check := func(i int, wantErrors int64) {
+		oldErrors := fs.Stats.GetErrors()
+		err := checkFunction(context.Background(), r.Fremote, r.Flocal, true)
+		gotErrors := fs.Stats.GetErrors() - oldErrors
+		if wantErrors == 0 && err != nil {
+			t.Errorf("%d: Got error when not expecting one: %v", i, err)
+		}
+		if wantErrors != 0 && err == nil {
+			t.Errorf("%d: No error when expecting one", i)
+		}
+		if wantErrors != gotErrors {
+			t.Errorf("%d: Expecting %d errors but got %d", i, wantErrors, gotErrors)
+		}
+	}
+
+	file1 := r.WriteBoth("rutabaga", "is tasty", t3)
+	file2 := r.WriteObject("archived/potato2", "------------------------------------------------------------", t1)
+	fstest.CheckItems(t, r.Flocal, file1)
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+	check(1, 0)
+
+	file3 := r.WriteFile("empty space", "", t2)
+	fstest.CheckItems(t, r.Flocal, file1, file3)
+	check(2, 1)
+
+	r.WriteObject("empty space", "", t2)
+	file4 := r.WriteFile("beetroot", "beet", t1)
+	r.WriteObject("beetroot", "toor", t1)
+	fstest.CheckItems(t, r.Flocal, file1, file3, file4)
+	check(3, 1)
+}
+
+func TestCheckOneWay(t *testing.T) {
+	testCheckOneWay(t, fs.Check)
+}
+
+func TestCheckDownloadOneWay(t *testing.T) {
+	testCheckOneWay(t, fs.CheckDownload)
+}
+
 func TestCheckSizeOnly(t *testing.T) {
 	fs.Config.SizeOnly = true
 	defer func() { fs.Config.SizeOnly = false }()
 	TestCheck(t)
 }
 
+func TestCheckReport(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	file1 := r.WriteBoth("rutabaga", "is tasty", t3)
+	file2 := r.WriteFile("potato2", "------------------------------------------------------------", t1)
+	file3 := r.WriteObject("empty space", "", t2)
+	file4 := r.WriteFile("beetroot", "beet", t1)
+	file5 := r.WriteObject("beetroot", "beets", t1)
+	fstest.CheckItems(t, r.Flocal, file1, file2, file4)
+	fstest.CheckItems(t, r.Fremote, file1, file3, file5)
+
+	var combined, match bytes.Buffer
+	report := fs.NewReportWriter(true)
+	report.SetCombined(&combined)
+	report.SetOutput(fs.SigilMatch, &match)
+	ctx := fs.WithReporter(context.Background(), report)
+
+	fs.Stats.ResetCounters()
+	err := fs.Check(ctx, r.Fremote, r.Flocal, false)
+	require.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(combined.String()), "\n")
+	sort.Strings(lines)
+	assert.Equal(t, []string{
+		`{"sigil":"*","path":"beetroot"}`,
+		`{"sigil":"+","path":"potato2"}`,
+		`{"sigil":"-","path":"empty space"}`,
+		`{"sigil":"=","path":"rutabaga"}`,
+	}, lines)
+	assert.Equal(t, `{"sigil":"=","path":"rutabaga"}`+"\n", match.String())
+}
+
 func skipIfCantDedupe(t *testing.T, f fs.Fs) {
 	if f.Features().PutUnchecked == nil {
 		t.Skip("Can't test deduplicate - no PutUnchecked")
@@ -311,7 +493,7 @@ func TestDeduplicateInteractive(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is one", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := fs.Deduplicate(r.Fremote, fs.DeduplicateInteractive)
+	err := fs.Deduplicate(context.Background(), r.Fremote, fs.DeduplicateInteractive)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1)
@@ -327,7 +509,7 @@ func TestDeduplicateSkip(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is another one", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := fs.Deduplicate(r.Fremote, fs.DeduplicateSkip)
+	err := fs.Deduplicate(context.Background(), r.Fremote, fs.DeduplicateSkip)
 	require.NoError(t, err)
 
 	r.CheckWithDuplicates(t, file1, file3)
@@ -343,10 +525,10 @@ func TestDeduplicateFirst(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is one BB", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := fs.Deduplicate(r.Fremote, fs.DeduplicateFirst)
+	err := fs.Deduplicate(context.Background(), r.Fremote, fs.DeduplicateFirst)
 	require.NoError(t, err)
 
-	objects, size, err := fs.Count(r.Fremote)
+	objects, size, err := fs.Count(context.Background(), r.Fremote)
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), objects)
 	if size != file1.Size && size != file2.Size && size != file3.Size {
@@ -364,7 +546,7 @@ func TestDeduplicateNewest(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := fs.Deduplicate(r.Fremote, fs.DeduplicateNewest)
+	err := fs.Deduplicate(context.Background(), r.Fremote, fs.DeduplicateNewest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file3)
@@ -380,7 +562,7 @@ func TestDeduplicateOldest(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := fs.Deduplicate(r.Fremote, fs.DeduplicateOldest)
+	err := fs.Deduplicate(context.Background(), r.Fremote, fs.DeduplicateOldest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1)
@@ -396,10 +578,10 @@ func TestDeduplicateRename(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one.txt", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := fs.Deduplicate(r.Fremote, fs.DeduplicateRename)
+	err := fs.Deduplicate(context.Background(), r.Fremote, fs.DeduplicateRename)
 	require.NoError(t, err)
 
-	require.NoError(t, fs.Walk(r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+	require.NoError(t, fs.Walk(context.Background(), r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
@@ -434,19 +616,19 @@ func TestMergeDirs(t *testing.T) {
 	file2 := r.WriteObject("dupe2/two.txt", "This is one too", t2)
 	file3 := r.WriteObject("dupe3/three.txt", "This is another one", t3)
 
-	objs, dirs, err := fs.WalkGetAll(r.Fremote, "", true, 1)
+	objs, dirs, err := fs.WalkGetAll(context.Background(), r.Fremote, "", true, 1)
 	require.NoError(t, err)
 	assert.Equal(t, 3, len(dirs))
 	assert.Equal(t, 0, len(objs))
 
-	err = mergeDirs(dirs)
+	err = mergeDirs(context.Background(), dirs)
 	require.NoError(t, err)
 
 	file2.Path = "dupe1/two.txt"
 	file3.Path = "dupe1/three.txt"
 	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
 
-	objs, dirs, err = fs.WalkGetAll(r.Fremote, "", true, 1)
+	objs, dirs, err = fs.WalkGetAll(context.Background(), r.Fremote, "", true, 1)
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(dirs))
 	assert.Equal(t, 0, len(objs))
@@ -473,7 +655,7 @@ func TestCat(t *testing.T) {
 		{1, 3, "BCD", "123"},
 	} {
 		var buf bytes.Buffer
-		err := fs.Cat(r.Fremote, &buf, test.offset, test.count)
+		err := fs.Cat(context.Background(), r.Fremote, &buf, test.offset, test.count)
 		require.NoError(t, err)
 		res := buf.String()
 
@@ -506,11 +688,11 @@ func TestRcat(t *testing.T) {
 		path2 := prefix + "big_file_from_pipe"
 
 		in := ioutil.NopCloser(strings.NewReader(data1))
-		_, err := fs.Rcat(r.Fremote, path1, in, t1)
+		_, err := fs.Rcat(context.Background(), r.Fremote, path1, in, t1)
 		require.NoError(t, err)
 
 		in = ioutil.NopCloser(strings.NewReader(data2))
-		_, err = fs.Rcat(r.Fremote, path2, in, t2)
+		_, err = fs.Rcat(context.Background(), r.Fremote, path2, in, t2)
 		require.NoError(t, err)
 
 		file1 := fstest.NewItem(path1, data1, t1)
@@ -522,6 +704,59 @@ func TestRcat(t *testing.T) {
 	check(false)
 }
 
+func TestCopyURL(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	contents := "file contents\n"
+	file1 := fstest.NewItem("file1", contents, t3)
+	file2 := fstest.NewItem("file2", contents, t3)
+	file3 := fstest.NewItem("file3", contents, t3)
+	r.Mkdir(r.Fremote)
+	fstest.CheckItems(t, r.Fremote)
+
+	// check when reading from regular HTTP server
+	status := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != 0 {
+			http.Error(w, "an error occurred", status)
+			return
+		}
+		if r.URL.Path == "/chunked" {
+			// no Content-Length so the upload is streamed
+			w.Header().Set("Last-Modified", t3.UTC().Format(http.TimeFormat))
+			_, _ = w.Write([]byte(contents))
+			w.(http.Flusher).Flush()
+			return
+		}
+		if r.URL.Path == "/disposition" {
+			w.Header().Set("Content-Disposition", `attachment; filename="file3"`)
+		}
+		http.ServeContent(w, r, "", t3, strings.NewReader(contents))
+	})
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	o, err := fs.CopyURL(context.Background(), r.Fremote, "file1", ts.URL, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(contents)), o.Size())
+
+	o, err = fs.CopyURL(context.Background(), r.Fremote, "file2", ts.URL+"/chunked", false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(contents)), o.Size())
+
+	o, err = fs.CopyURL(context.Background(), r.Fremote, "", ts.URL+"/disposition", true)
+	require.NoError(t, err)
+	assert.Equal(t, "file3", o.Remote())
+
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	// check that an error is returned for a bad status
+	status = http.StatusNotFound
+	_, err = fs.CopyURL(context.Background(), r.Fremote, "file4", ts.URL, false)
+	assert.Error(t, err)
+}
+
 func TestRmdirs(t *testing.T) {
 	r := fstest.NewRun(t)
 	defer r.Finalise()
@@ -531,13 +766,13 @@ func TestRmdirs(t *testing.T) {
 	r.ForceMkdir(r.Fremote)
 	file1 := r.WriteObject("A1/B1/C1/one", "aaa", t1)
 	//..and dirs we expect to delete
-	require.NoError(t, fs.Mkdir(r.Fremote, "A2"))
-	require.NoError(t, fs.Mkdir(r.Fremote, "A1/B2"))
-	require.NoError(t, fs.Mkdir(r.Fremote, "A1/B2/C2"))
-	require.NoError(t, fs.Mkdir(r.Fremote, "A1/B1/C3"))
-	require.NoError(t, fs.Mkdir(r.Fremote, "A3"))
-	require.NoError(t, fs.Mkdir(r.Fremote, "A3/B3"))
-	require.NoError(t, fs.Mkdir(r.Fremote, "A3/B3/C4"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "A2"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "A1/B2"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "A1/B2/C2"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "A1/B1/C3"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "A3"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "A3/B3"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "A3/B3/C4"))
 	//..and one more file at the end
 	file2 := r.WriteObject("A1/two", "bbb", t2)
 
@@ -562,7 +797,7 @@ func TestRmdirs(t *testing.T) {
 		fs.Config.ModifyWindow,
 	)
 
-	require.NoError(t, fs.Rmdirs(r.Fremote, ""))
+	require.NoError(t, fs.Rmdirs(context.Background(), r.Fremote, ""))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -590,7 +825,7 @@ func TestMoveFile(t *testing.T) {
 	file2 := file1
 	file2.Path = "sub/file2"
 
-	err := fs.MoveFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err := fs.MoveFile(context.Background(), r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -598,17 +833,117 @@ func TestMoveFile(t *testing.T) {
 	r.WriteFile("file1", "file1 contents", t1)
 	fstest.CheckItems(t, r.Flocal, file1)
 
-	err = fs.MoveFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err = fs.MoveFile(context.Background(), r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = fs.MoveFile(r.Fremote, r.Fremote, file2.Path, file2.Path)
+	err = fs.MoveFile(context.Background(), r.Fremote, r.Fremote, file2.Path, file2.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
 }
 
+func TestCopyFileMultiThread(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	oldCutoff, oldStreams := fs.Config.MultiThreadCutoff, fs.Config.MultiThreadStreams
+	fs.Config.MultiThreadCutoff, fs.Config.MultiThreadStreams = 1, 4
+	defer func() {
+		fs.Config.MultiThreadCutoff, fs.Config.MultiThreadStreams = oldCutoff, oldStreams
+	}()
+
+	// Make a file big enough to be split into several parts
+	contents := strings.Repeat("multi-thread copy ", 20000)
+	file1 := r.WriteFile("file1", contents, t1)
+	fstest.CheckItems(t, r.Flocal, file1)
+
+	fs.Stats.ResetCounters()
+	err := fs.CopyFile(context.Background(), r.Fremote, r.Flocal, file1.Path, file1.Path)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Flocal, file1)
+	fstest.CheckItems(t, r.Fremote, file1)
+	assert.Equal(t, int64(len(contents)), fs.Stats.RemoteStats()["bytes"])
+}
+
+// rangeIgnoringObject is an Object which ignores the options passed
+// to Open, like a source which can't do ranged reads
+type rangeIgnoringObject struct {
+	fs.Object
+	info fs.Info
+}
+
+// Fs returns the Fs the object is reported to come from
+func (o *rangeIgnoringObject) Fs() fs.Info { return o.info }
+
+// Open opens the object ignoring any options
+func (o *rangeIgnoringObject) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	return o.Object.Open(ctx)
+}
+
+func TestCopyFileMultiThreadNoRange(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ctx := context.Background()
+
+	oldCutoff, oldStreams, oldRetries := fs.Config.MultiThreadCutoff, fs.Config.MultiThreadStreams, fs.Config.LowLevelRetries
+	fs.Config.MultiThreadCutoff, fs.Config.MultiThreadStreams, fs.Config.LowLevelRetries = 1, 4, 1
+	defer func() {
+		fs.Config.MultiThreadCutoff, fs.Config.MultiThreadStreams, fs.Config.LowLevelRetries = oldCutoff, oldStreams, oldRetries
+	}()
+
+	contents := strings.Repeat("multi-thread copy ", 20000)
+	file1 := r.WriteFile("file1", contents, t1)
+	o, err := r.Flocal.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	info := &testFsInfo{name: "norange", root: "norange"}
+	src := &rangeIgnoringObject{Object: o, info: info}
+
+	// A source without RangeRead is copied in a single stream
+	err = fs.Copy(ctx, r.Fremote, nil, file1.Path, src)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	// A source which claims RangeRead but ignores the range is
+	// detected and the partial file removed
+	info.features.RangeRead = true
+	err = fs.Copy(ctx, r.Fremote, nil, "file2", src)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "doesn't support ranged reads")
+	fstest.CheckItems(t, r.Fremote, file1)
+}
+
+func TestCopyFileMetadata(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ctx := context.Background()
+
+	oldMetadata := fs.Config.Metadata
+	fs.Config.Metadata = true
+	defer func() {
+		fs.Config.Metadata = oldMetadata
+	}()
+
+	file1 := r.WriteFile("file1", "file1 contents", t1)
+	require.NoError(t, os.Chmod(filepath.Join(r.LocalName, file1.Path), 0600))
+
+	err := fs.CopyFile(ctx, r.Fremote, r.Flocal, file1.Path, file1.Path)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	src, err := r.Flocal.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	dst, err := r.Fremote.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	srcMetadata, err := fs.GetMetadata(ctx, src)
+	require.NoError(t, err)
+	dstMetadata, err := fs.GetMetadata(ctx, dst)
+	require.NoError(t, err)
+	assert.NotEqual(t, "", srcMetadata["mode"])
+	assert.Equal(t, srcMetadata["mode"], dstMetadata["mode"])
+}
+
 func TestCopyFile(t *testing.T) {
 	r := fstest.NewRun(t)
 	defer r.Finalise()
@@ -619,17 +954,17 @@ func TestCopyFile(t *testing.T) {
 	file2 := file1
 	file2.Path = "sub/file2"
 
-	err := fs.CopyFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err := fs.CopyFile(context.Background(), r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = fs.CopyFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err = fs.CopyFile(context.Background(), r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = fs.CopyFile(r.Fremote, r.Fremote, file2.Path, file2.Path)
+	err = fs.CopyFile(context.Background(), r.Fremote, r.Fremote, file2.Path, file2.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -703,6 +1038,21 @@ func TestSame(t *testing.T) {
 	}
 }
 
+// testFsInfo2 is a different type of fs.Info for unit testing
+type testFsInfo2 struct {
+	testFsInfo
+}
+
+func TestSameRemoteType(t *testing.T) {
+	a := &testFsInfo{name: "name", root: "root"}
+	b := &testFsInfo{name: "namey", root: "rooty"}
+	c := &testFsInfo2{testFsInfo{name: "name", root: "root"}}
+	assert.True(t, fs.SameRemoteType(a, b))
+	assert.True(t, fs.SameRemoteType(b, a))
+	assert.False(t, fs.SameRemoteType(a, c))
+	assert.False(t, fs.SameRemoteType(c, a))
+}
+
 func TestOverlapping(t *testing.T) {
 	a := &testFsInfo{name: "name", root: "root"}
 	for _, test := range []struct {
@@ -766,20 +1116,20 @@ func TestListDirSorted(t *testing.T) {
 		return name
 	}
 
-	items, err = fs.ListDirSorted(r.Fremote, true, "")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, true, "")
 	require.NoError(t, err)
 	require.Len(t, items, 3)
 	assert.Equal(t, "a.txt", str(0))
 	assert.Equal(t, "sub dir/", str(1))
 	assert.Equal(t, "zend.txt", str(2))
 
-	items, err = fs.ListDirSorted(r.Fremote, false, "")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, false, "")
 	require.NoError(t, err)
 	require.Len(t, items, 2)
 	assert.Equal(t, "sub dir/", str(0))
 	assert.Equal(t, "zend.txt", str(1))
 
-	items, err = fs.ListDirSorted(r.Fremote, true, "sub dir")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, true, "sub dir")
 	require.NoError(t, err)
 	require.Len(t, items, 4)
 	assert.Equal(t, "sub dir/hello world", str(0))
@@ -787,7 +1137,7 @@ func TestListDirSorted(t *testing.T) {
 	assert.Equal(t, "sub dir/ignore dir/", str(2))
 	assert.Equal(t, "sub dir/sub sub dir/", str(3))
 
-	items, err = fs.ListDirSorted(r.Fremote, false, "sub dir")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, false, "sub dir")
 	require.NoError(t, err)
 	require.Len(t, items, 2)
 	assert.Equal(t, "sub dir/ignore dir/", str(0))
@@ -796,23 +1146,23 @@ func TestListDirSorted(t *testing.T) {
 	// testing ignore file
 	fs.Config.Filter.ExcludeFile = ".ignore"
 
-	items, err = fs.ListDirSorted(r.Fremote, false, "sub dir")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, false, "sub dir")
 	require.NoError(t, err)
 	require.Len(t, items, 1)
 	assert.Equal(t, "sub dir/sub sub dir/", str(0))
 
-	items, err = fs.ListDirSorted(r.Fremote, false, "sub dir/ignore dir")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, false, "sub dir/ignore dir")
 	require.NoError(t, err)
 	require.Len(t, items, 0)
 
-	items, err = fs.ListDirSorted(r.Fremote, true, "sub dir/ignore dir")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, true, "sub dir/ignore dir")
 	require.NoError(t, err)
 	require.Len(t, items, 2)
 	assert.Equal(t, "sub dir/ignore dir/.ignore", str(0))
 	assert.Equal(t, "sub dir/ignore dir/should be ignored", str(1))
 
 	fs.Config.Filter.ExcludeFile = ""
-	items, err = fs.ListDirSorted(r.Fremote, false, "sub dir/ignore dir")
+	items, err = fs.ListDirSorted(context.Background(), r.Fremote, false, "sub dir/ignore dir")
 	require.NoError(t, err)
 	require.Len(t, items, 2)
 	assert.Equal(t, "sub dir/ignore dir/.ignore", str(0))
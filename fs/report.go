@@ -0,0 +1,148 @@
+// Change reports for sync, copy, move and check
+
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+// Sigil marks what happened to a path in a change report
+type Sigil byte
+
+// Sigils for the change report
+const (
+	SigilMatch        Sigil = '=' // identical in source and destination
+	SigilDiffer       Sigil = '*' // differs - updated in the destination by sync
+	SigilMissingOnSrc Sigil = '-' // only in the destination - deleted by sync
+	SigilMissingOnDst Sigil = '+' // only in the source - added to the destination by sync
+	SigilError        Sigil = '!' // error while processing the path
+)
+
+// String turns a Sigil into a string
+func (s Sigil) String() string {
+	return string(s)
+}
+
+// Reporter is told what happened to each path in a sync, copy, move
+// or check
+type Reporter interface {
+	// Report should be safe to call from multiple go routines
+	Report(sigil Sigil, remote string, err error)
+}
+
+// reporterKeyType is the type of the key used to store a Reporter in
+// a context.Context
+type reporterKeyType struct{}
+
+// reporterKey is the key used to store a Reporter in a context.Context
+var reporterKey = reporterKeyType{}
+
+// WithReporter returns a copy of ctx which reports the paths
+// processed to r
+func WithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey, r)
+}
+
+// report tells the Reporter in ctx, if any, what happened to remote
+func report(ctx context.Context, sigil Sigil, remote string, err error) {
+	if r, ok := ctx.Value(reporterKey).(Reporter); ok {
+		r.Report(sigil, remote, err)
+	}
+}
+
+// reportLine is a line of a JSON report
+type reportLine struct {
+	Sigil string `json:"sigil"`
+	Path  string `json:"path"`
+	Error string `json:"error,omitempty"`
+}
+
+// ReportWriter is a Reporter which writes the paths to io.Writers
+// one per line.
+//
+// The combined output has each path prefixed by its sigil and a
+// space, eg "+ path/to/file", and the outputs for a single sigil have
+// just the path.  If it was made with useJSON set then each line is a
+// JSON object with "sigil", "path" and "error" if there was one.
+type ReportWriter struct {
+	mu       sync.Mutex
+	json     bool
+	combined io.Writer
+	outputs  map[Sigil]io.Writer
+	err      error
+}
+
+// NewReportWriter makes a new ReportWriter with no outputs
+func NewReportWriter(useJSON bool) *ReportWriter {
+	return &ReportWriter{
+		json:    useJSON,
+		outputs: make(map[Sigil]io.Writer),
+	}
+}
+
+// SetCombined sets w to receive all the paths with their sigils
+func (r *ReportWriter) SetCombined(w io.Writer) {
+	r.mu.Lock()
+	r.combined = w
+	r.mu.Unlock()
+}
+
+// SetOutput sets w to receive the paths marked with sigil only
+func (r *ReportWriter) SetOutput(sigil Sigil, w io.Writer) {
+	r.mu.Lock()
+	r.outputs[sigil] = w
+	r.mu.Unlock()
+}
+
+// write writes line to w, remembering the first error - call with mu
+// held
+func (r *ReportWriter) write(w io.Writer, line []byte) {
+	_, err := w.Write(line)
+	if err != nil && r.err == nil {
+		Errorf(nil, "Failed to write report: %v", err)
+		r.err = err
+	}
+}
+
+// Report writes remote to the outputs for sigil - satisfies Reporter
+func (r *ReportWriter) Report(sigil Sigil, remote string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var line, combinedLine []byte
+	if r.json {
+		item := reportLine{
+			Sigil: sigil.String(),
+			Path:  remote,
+		}
+		if err != nil {
+			item.Error = err.Error()
+		}
+		line, _ = json.Marshal(item)
+		line = append(line, '\n')
+		combinedLine = line
+	} else {
+		line = []byte(remote + "\n")
+		combinedLine = []byte(fmt.Sprintf("%v %s\n", sigil, remote))
+	}
+	if r.combined != nil {
+		r.write(r.combined, combinedLine)
+	}
+	if w := r.outputs[sigil]; w != nil {
+		r.write(w, line)
+	}
+}
+
+// Err returns the first error writing the report if any
+func (r *ReportWriter) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Check interface
+var _ Reporter = (*ReportWriter)(nil)
@@ -0,0 +1,64 @@
+package fs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestReportWriterText(t *testing.T) {
+	var combined, differ bytes.Buffer
+	r := NewReportWriter(false)
+	r.SetCombined(&combined)
+	r.SetOutput(SigilDiffer, &differ)
+	r.Report(SigilMatch, "one", nil)
+	r.Report(SigilDiffer, "dir/two", nil)
+	r.Report(SigilError, "three", errors.New("boom"))
+	assert.Equal(t, "= one\n* dir/two\n! three\n", combined.String())
+	assert.Equal(t, "dir/two\n", differ.String())
+	assert.NoError(t, r.Err())
+}
+
+func TestReportWriterJSON(t *testing.T) {
+	var combined, errs bytes.Buffer
+	r := NewReportWriter(true)
+	r.SetCombined(&combined)
+	r.SetOutput(SigilError, &errs)
+	r.Report(SigilMissingOnDst, "one", nil)
+	r.Report(SigilError, "three", errors.New("boom"))
+	assert.Equal(t, `{"sigil":"+","path":"one"}
+{"sigil":"!","path":"three","error":"boom"}
+`, combined.String())
+	assert.Equal(t, `{"sigil":"!","path":"three","error":"boom"}
+`, errs.String())
+}
+
+// failWriter fails all writes
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestReportWriterErr(t *testing.T) {
+	r := NewReportWriter(false)
+	r.SetCombined(failWriter{})
+	r.Report(SigilMatch, "one", nil)
+	assert.EqualError(t, r.Err(), "write failed")
+}
+
+func TestReportContext(t *testing.T) {
+	var combined bytes.Buffer
+	r := NewReportWriter(false)
+	r.SetCombined(&combined)
+
+	// No reporter set - should do nothing
+	report(context.Background(), SigilMatch, "one", nil)
+
+	ctx := WithReporter(context.Background(), r)
+	report(ctx, SigilMissingOnSrc, "two", nil)
+	assert.Equal(t, "- two\n", combined.String())
+}
@@ -46,6 +46,7 @@ type syncCopyMove struct {
 	err            error               // normal error from copy process
 	noRetryErr     error               // error with NoRetry set
 	fatalErr       error               // fatal error
+	maxTransferHit bool                // set when --max-transfer stops new transfers starting
 	commonHash     HashType            // common hash type between src and dst
 	renameMapMu    sync.Mutex          // mutex to protect the below
 	renameMap      map[string][]Object // dst files by hash - only used by trackRenames
@@ -56,9 +57,11 @@ type syncCopyMove struct {
 	renameCheck    []Object            // accumulate files to check for rename here
 	backupDir      Fs                  // place to store overwrites/deletes
 	suffix         string              // suffix to add to files placed in backupDir
+	compareDirs    []Fs                // places to look for unchanged files in --compare-dest/--copy-dest
+	copyDest       bool                // set if compareDirs are --copy-dest so files should be copied from them
 }
 
-func newSyncCopyMove(fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) (*syncCopyMove, error) {
+func newSyncCopyMove(ctx context.Context, fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) (*syncCopyMove, error) {
 	s := &syncCopyMove{
 		fdst:           fdst,
 		fsrc:           fsrc,
@@ -77,7 +80,7 @@ func newSyncCopyMove(fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) (*syncCo
 		toBeRenamed:    make(ObjectPairChan, Config.Transfers),
 		trackRenamesCh: make(chan Object, Config.Checkers),
 	}
-	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.ctx, s.cancel = context.WithCancel(ctx)
 	if s.noTraverse && s.deleteMode != DeleteModeOff {
 		Errorf(nil, "Ignoring --no-traverse with sync")
 		s.noTraverse = false
@@ -124,6 +127,25 @@ func newSyncCopyMove(fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) (*syncCo
 		}
 		s.suffix = Config.Suffix
 	}
+	// Make Fs for --compare-dest or --copy-dest if required
+	flagName, dests := "--compare-dest", Config.CompareDest
+	if len(Config.CopyDest) > 0 {
+		flagName, dests = "--copy-dest", Config.CopyDest
+		s.copyDest = true
+	}
+	for _, dest := range dests {
+		f, err := NewFs(dest)
+		if err != nil {
+			return nil, FatalError(errors.Errorf("Failed to make fs for %s %q: %v", flagName, dest, err))
+		}
+		if s.copyDest && !canServerSide(fdst, f) {
+			return nil, FatalError(errors.New("parameter to --copy-dest has to be on the same remote as destination"))
+		}
+		if Overlapping(fdst, f) {
+			return nil, FatalError(errors.Errorf("destination and parameter to %s mustn't overlap", flagName))
+		}
+		s.compareDirs = append(s.compareDirs, f)
+	}
 	return s, nil
 }
 
@@ -160,7 +182,7 @@ outer:
 //
 // Returns a flag which indicates whether the file needs to be
 // transferred or not.
-func NeedTransfer(dst, src Object) bool {
+func NeedTransfer(ctx context.Context, dst, src Object) bool {
 	if dst == nil {
 		Debugf(src, "Couldn't find file - need to transfer")
 		return true
@@ -203,7 +225,7 @@ func NeedTransfer(dst, src Object) bool {
 		}
 	} else {
 		// Check to see if changed or not
-		if Equal(src, dst) {
+		if Equal(ctx, src, dst) {
 			Debugf(src, "Unchanged skipping")
 			return false
 		}
@@ -219,7 +241,18 @@ func (s *syncCopyMove) processError(err error) {
 	s.errorMu.Lock()
 	defer s.errorMu.Unlock()
 	switch {
+	case err == ErrorMaxTransferLimitReached && Config.CutoffMode != CutoffModeHard:
+		// let the transfers in progress finish but don't start
+		// any more
+		if !s.maxTransferHit {
+			Infof(s.fdst, "Max transfer limit reached - not starting any more transfers")
+		}
+		s.maxTransferHit = true
+		s.fatalErr = err
 	case IsFatalError(err):
+		if err == ErrorMaxTransferLimitReached {
+			s.maxTransferHit = true
+		}
 		if !s.aborting() {
 			s.cancel()
 		}
@@ -231,6 +264,14 @@ func (s *syncCopyMove) processError(err error) {
 	}
 }
 
+// maxTransferReached returns whether --max-transfer has been reached
+// so no more files should be queued or transferred
+func (s *syncCopyMove) maxTransferReached() bool {
+	s.errorMu.Lock()
+	defer s.errorMu.Unlock()
+	return s.maxTransferHit
+}
+
 // Returns the current error (if any) in the order of prececedence
 //   fatalErr
 //   normal error
@@ -262,46 +303,134 @@ func (s *syncCopyMove) pairChecker(in ObjectPairChan, out ObjectPairChan, wg *sy
 				return
 			}
 			src := pair.src
-			Stats.Checking(src.Remote())
+			if s.maxTransferReached() {
+				continue
+			}
+			StatsFromContext(s.ctx).Checking(src.Remote())
 			// Check to see if can store this
 			if src.Storable() {
-				if NeedTransfer(pair.dst, pair.src) {
+				needTransfer := NeedTransfer(s.ctx, pair.dst, pair.src)
+				if !needTransfer {
+					report(s.ctx, SigilMatch, src.Remote(), nil)
+				}
+				var err error
+				if needTransfer && len(s.compareDirs) > 0 {
+					// See if it is in --compare-dest or --copy-dest
+					var found bool
+					found, err = s.compareOrCopyDest(pair)
+					if err != nil {
+						report(s.ctx, SigilError, src.Remote(), err)
+					}
+					s.processError(err)
+					needTransfer = !found && err == nil
+				}
+				if needTransfer {
 					// If files are treated as immutable, fail if destination exists and does not match
 					if Config.Immutable && pair.dst != nil {
 						Errorf(pair.dst, "Source and destination exist but do not match: immutable file modified")
+						report(s.ctx, SigilError, src.Remote(), ErrorImmutableModified)
 						s.processError(ErrorImmutableModified)
 					} else {
-						// If destination already exists, then we must move it into --backup-dir if required
-						if pair.dst != nil && s.backupDir != nil {
-							remoteWithSuffix := pair.dst.Remote() + s.suffix
-							overwritten, _ := s.backupDir.NewObject(remoteWithSuffix)
-							err := Move(s.backupDir, overwritten, remoteWithSuffix, pair.dst)
-							if err != nil {
-								s.processError(err)
-							} else {
-								// If successful zero out the dst as it is no longer there and copy the file
-								pair.dst = nil
-								out <- pair
-							}
-						} else {
-							out <- pair
-						}
+						out <- pair
 					}
-				} else {
+				} else if err == nil {
 					// If moving need to delete the files we don't need to copy
 					if s.DoMove {
 						// Delete src if no error on copy
-						s.processError(DeleteFile(src))
+						s.processError(DeleteFile(s.ctx, src))
 					}
 				}
 			}
-			Stats.DoneChecking(src.Remote())
+			StatsFromContext(s.ctx).DoneChecking(src.Remote())
 		case <-s.ctx.Done():
 			return
 		}
 	}
 }
 
+// compareOrCopyDest looks for an identical copy of pair.src in the
+// --compare-dest or --copy-dest remotes.
+//
+// It returns true if one was found so pair.src doesn't need to be
+// uploaded.  For --copy-dest the file found is first server side
+// copied into the destination, moving the existing file into
+// --backup-dir if required.
+func (s *syncCopyMove) compareOrCopyDest(pair ObjectPair) (found bool, err error) {
+	src, dst := pair.src, pair.dst
+	for _, f := range s.compareDirs {
+		o, err := f.NewObject(s.ctx, src.Remote())
+		if err == ErrorObjectNotFound {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		// Don't update the mod time on the file we are comparing with
+		if !equal(s.ctx, src, o, Config.SizeOnly, Config.CheckSum, false) {
+			continue
+		}
+		if !s.copyDest {
+			Debugf(src, "Unchanged in --compare-dest %v, skipping", f)
+			report(s.ctx, SigilMatch, src.Remote(), nil)
+			return true, nil
+		}
+		sigil := SigilMissingOnDst
+		if dst != nil {
+			// If files are treated as immutable, fail rather than replace dst
+			if Config.Immutable {
+				Errorf(dst, "Source and destination exist but do not match: immutable file modified")
+				return false, ErrorImmutableModified
+			}
+			sigil = SigilDiffer
+		}
+		if dst != nil && s.backupDir != nil {
+			remoteWithSuffix := dst.Remote() + s.suffix
+			overwritten, _ := s.backupDir.NewObject(s.ctx, remoteWithSuffix)
+			err = Move(s.ctx, s.backupDir, overwritten, remoteWithSuffix, dst)
+			if err != nil {
+				return false, err
+			}
+			dst = nil
+		}
+		Debugf(src, "Unchanged in --copy-dest %v, copying from there", f)
+		err = Copy(s.ctx, s.fdst, dst, src.Remote(), o)
+		if err == nil {
+			err = s.fixCopyDestModTime(src)
+		}
+		if err != nil {
+			return false, err
+		}
+		report(s.ctx, sigil, src.Remote(), nil)
+		return true, nil
+	}
+	return false, nil
+}
+
+// fixCopyDestModTime sets the mod time of the file copied from
+// --copy-dest to that of src if they differ, which happens when
+// they were found identical by hash
+func (s *syncCopyMove) fixCopyDestModTime(src Object) error {
+	if Config.NoUpdateModTime || Config.DryRun || Config.ModifyWindow == ModTimeNotSupported {
+		return nil
+	}
+	dst, err := s.fdst.NewObject(s.ctx, src.Remote())
+	if err != nil {
+		return err
+	}
+	srcModTime := src.ModTime()
+	dt := dst.ModTime().Sub(srcModTime)
+	if dt < Config.ModifyWindow && dt > -Config.ModifyWindow {
+		return nil
+	}
+	err = dst.SetModTime(s.ctx, srcModTime)
+	switch err {
+	case ErrorCantSetModTime, ErrorCantSetModTimeWithoutDelete:
+		// the next sync will upload it again
+		return nil
+	}
+	return err
+}
+
 // pairRenamer reads Objects~s on in and attempts to rename them,
 // otherwise it sends them out if they need transferring.
 func (s *syncCopyMove) pairRenamer(in ObjectPairChan, out ObjectPairChan, wg *sync.WaitGroup) {
@@ -339,15 +468,46 @@ func (s *syncCopyMove) pairCopyOrMove(in ObjectPairChan, fdst Fs, wg *sync.WaitG
 			if !ok {
 				return
 			}
-			src := pair.src
-			Stats.Transferring(src.Remote())
-			if s.DoMove {
-				err = Move(fdst, pair.dst, src.Remote(), src)
-			} else {
-				err = Copy(fdst, pair.dst, src.Remote(), src)
+			src, dst := pair.src, pair.dst
+			if s.maxTransferReached() {
+				Debugf(src, "Not transferring as --max-transfer reached")
+				continue
+			}
+			sigil := SigilMissingOnDst
+			if dst != nil {
+				sigil = SigilDiffer
+			}
+			StatsFromContext(s.ctx).Transferring(src.Remote())
+			err = nil
+			// If destination already exists, then we must move it into --backup-dir if required
+			if dst != nil && s.backupDir != nil {
+				remoteWithSuffix := dst.Remote() + s.suffix
+				overwritten, _ := s.backupDir.NewObject(s.ctx, remoteWithSuffix)
+				err = Move(s.ctx, s.backupDir, overwritten, remoteWithSuffix, dst)
+				if err == nil {
+					// If successful zero out the dst as it is no longer there and copy the file
+					dst = nil
+				}
+			}
+			if err == nil {
+				if s.DoMove {
+					err = Move(s.ctx, fdst, dst, src.Remote(), src)
+				} else {
+					err = Copy(s.ctx, fdst, dst, src.Remote(), src)
+				}
 			}
+			if err == ErrorMaxTransferLimitReached && Config.CutoffMode != CutoffModeHard {
+				// The transfer wasn't started so this file isn't in error
+				s.processError(err)
+				StatsFromContext(s.ctx).DoneTransferring(src.Remote(), false)
+				continue
+			}
+			if err != nil {
+				sigil = SigilError
+			}
+			report(s.ctx, sigil, src.Remote(), err)
 			s.processError(err)
-			Stats.DoneTransferring(src.Remote(), err == nil)
+			StatsFromContext(s.ctx).DoneTransferring(src.Remote(), err == nil)
 		case <-s.ctx.Done():
 			return
 		}
@@ -436,7 +596,7 @@ func (s *syncCopyMove) startDeleters() {
 	s.deletersWg.Add(1)
 	go func() {
 		defer s.deletersWg.Done()
-		err := deleteFilesWithBackupDir(s.deleteFilesCh, s.backupDir)
+		err := deleteFilesWithBackupDir(s.ctx, s.deleteFilesCh, s.backupDir)
 		s.processError(err)
 	}()
 }
@@ -456,7 +616,7 @@ func (s *syncCopyMove) stopDeleters() {
 // checkSrcMap is clear then it assumes that the any source files that
 // have been found have been removed from dstFiles already.
 func (s *syncCopyMove) deleteFiles(checkSrcMap bool) error {
-	if Stats.Errored() {
+	if StatsFromContext(s.ctx).Errored() {
 		Errorf(s.fdst, "%v", ErrorNotDeleting)
 		return ErrorNotDeleting
 	}
@@ -478,16 +638,16 @@ func (s *syncCopyMove) deleteFiles(checkSrcMap bool) error {
 		}
 		close(toDelete)
 	}()
-	return deleteFilesWithBackupDir(toDelete, s.backupDir)
+	return deleteFilesWithBackupDir(s.ctx, toDelete, s.backupDir)
 }
 
 // This deletes the empty directories in the slice passed in.  It
 // ignores any errors deleting directories
-func deleteEmptyDirectories(f Fs, entries DirEntries) error {
+func deleteEmptyDirectories(ctx context.Context, f Fs, entries DirEntries) error {
 	if len(entries) == 0 {
 		return nil
 	}
-	if Stats.Errored() {
+	if StatsFromContext(ctx).Errored() {
 		Errorf(f, "%v", ErrorNotDeletingDirs)
 		return ErrorNotDeletingDirs
 	}
@@ -501,7 +661,7 @@ func deleteEmptyDirectories(f Fs, entries DirEntries) error {
 		dir, ok := entry.(Directory)
 		if ok {
 			// TryRmdir only deletes empty directories
-			err := TryRmdir(f, dir.Remote())
+			err := TryRmdir(ctx, f, dir.Remote())
 			if err != nil {
 				Debugf(logDirName(f, dir.Remote()), "Failed to Rmdir: %v", err)
 				errorCount++
@@ -586,12 +746,12 @@ func (s *syncCopyMove) makeRenameMap() {
 			for obj := range in {
 				// only create hash for dst Object if its size could match
 				if _, found := possibleSizes[obj.Size()]; found {
-					Stats.Checking(obj.Remote())
+					StatsFromContext(s.ctx).Checking(obj.Remote())
 					hash := s.renameHash(obj)
 					if hash != "" {
 						s.pushRenameMap(hash, obj)
 					}
-					Stats.DoneChecking(obj.Remote())
+					StatsFromContext(s.ctx).DoneChecking(obj.Remote())
 				}
 			}
 		}()
@@ -603,8 +763,8 @@ func (s *syncCopyMove) makeRenameMap() {
 // tryRename renames a src object when doing track renames if
 // possible, it returns true if the object was renamed.
 func (s *syncCopyMove) tryRename(src Object) bool {
-	Stats.Checking(src.Remote())
-	defer Stats.DoneChecking(src.Remote())
+	StatsFromContext(s.ctx).Checking(src.Remote())
+	defer StatsFromContext(s.ctx).DoneChecking(src.Remote())
 
 	// Calculate the hash of the src object
 	hash := s.renameHash(src)
@@ -619,10 +779,10 @@ func (s *syncCopyMove) tryRename(src Object) bool {
 	}
 
 	// Find dst object we are about to overwrite if it exists
-	dstOverwritten, _ := s.fdst.NewObject(src.Remote())
+	dstOverwritten, _ := s.fdst.NewObject(s.ctx, src.Remote())
 
 	// Rename dst to have name src.Remote()
-	err := Move(s.fdst, dstOverwritten, src.Remote(), dst)
+	err := Move(s.ctx, s.fdst, dstOverwritten, src.Remote(), dst)
 	if err != nil {
 		Debugf(src, "Failed to rename to %q: %v", dst.Remote(), err)
 		return false
@@ -634,6 +794,12 @@ func (s *syncCopyMove) tryRename(src Object) bool {
 	s.dstFilesMu.Unlock()
 
 	Infof(src, "Renamed from %q", dst.Remote())
+	report(s.ctx, SigilMissingOnSrc, dst.Remote(), nil)
+	if dstOverwritten != nil {
+		report(s.ctx, SigilDiffer, src.Remote(), nil)
+	} else {
+		report(s.ctx, SigilMissingOnDst, src.Remote(), nil)
+	}
 	return true
 }
 
@@ -693,14 +859,14 @@ func (s *syncCopyMove) run() error {
 		if s.currentError() != nil {
 			Errorf(s.fdst, "%v", ErrorNotDeletingDirs)
 		} else {
-			s.processError(deleteEmptyDirectories(s.fdst, s.dstEmptyDirs))
+			s.processError(deleteEmptyDirectories(s.ctx, s.fdst, s.dstEmptyDirs))
 		}
 	}
 
 	// if DoMove, delete empty fsrc subdirectories after
 	if s.DoMove {
 		//delete empty subdirectories that were part of the move
-		s.processError(deleteEmptyDirectories(s.fsrc, s.srcEmptyDirs))
+		s.processError(deleteEmptyDirectories(s.ctx, s.fsrc, s.srcEmptyDirs))
 	}
 	return s.currentError()
 }
@@ -741,7 +907,7 @@ func (s *syncCopyMove) DstOnly(dst DirEntry) (recurse bool) {
 
 // SrcOnly have an object which is in the source only
 func (s *syncCopyMove) SrcOnly(src DirEntry) (recurse bool) {
-	if s.deleteMode == DeleteModeOnly {
+	if s.deleteMode == DeleteModeOnly || s.maxTransferReached() {
 		return false
 	}
 	switch x := src.(type) {
@@ -749,6 +915,9 @@ func (s *syncCopyMove) SrcOnly(src DirEntry) (recurse bool) {
 		if s.trackRenames {
 			// Save object to check for a rename later
 			s.trackRenamesCh <- x
+		} else if len(s.compareDirs) > 0 {
+			// Check to see if it is in --compare-dest or --copy-dest
+			s.toBeChecked <- ObjectPair{x, nil}
 		} else {
 			// No need to check since doesn't exist
 			s.toBeUploaded <- ObjectPair{x, nil}
@@ -768,6 +937,9 @@ func (s *syncCopyMove) SrcOnly(src DirEntry) (recurse bool) {
 
 // Match is called when src and dst are present, so sync src to dst
 func (s *syncCopyMove) Match(dst, src DirEntry) (recurse bool) {
+	if s.maxTransferReached() {
+		return false
+	}
 	switch srcX := src.(type) {
 	case Object:
 		if s.deleteMode == DeleteModeOnly {
@@ -809,7 +981,7 @@ func (s *syncCopyMove) Match(dst, src DirEntry) (recurse bool) {
 // If DoMove is true then files will be moved instead of copied
 //
 // dir is the start directory, "" for root
-func runSyncCopyMove(fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) error {
+func runSyncCopyMove(ctx context.Context, fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) error {
 	if *oldSyncMethod {
 		return FatalError(errors.New("--old-sync-method is deprecated use --fast-list instead"))
 	}
@@ -822,7 +994,7 @@ func runSyncCopyMove(fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) error {
 			return FatalError(errors.New("can't use --delete-before with --track-renames"))
 		}
 		// only delete stuff during in this pass
-		do, err := newSyncCopyMove(fdst, fsrc, DeleteModeOnly, false)
+		do, err := newSyncCopyMove(ctx, fdst, fsrc, DeleteModeOnly, false)
 		if err != nil {
 			return err
 		}
@@ -833,7 +1005,7 @@ func runSyncCopyMove(fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) error {
 		// Next pass does a copy only
 		deleteMode = DeleteModeOff
 	}
-	do, err := newSyncCopyMove(fdst, fsrc, deleteMode, DoMove)
+	do, err := newSyncCopyMove(ctx, fdst, fsrc, deleteMode, DoMove)
 	if err != nil {
 		return err
 	}
@@ -841,35 +1013,37 @@ func runSyncCopyMove(fdst, fsrc Fs, deleteMode DeleteMode, DoMove bool) error {
 }
 
 // Sync fsrc into fdst
-func Sync(fdst, fsrc Fs) error {
-	return runSyncCopyMove(fdst, fsrc, Config.DeleteMode, false)
+func Sync(ctx context.Context, fdst, fsrc Fs) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, Config.DeleteMode, false)
 }
 
 // CopyDir copies fsrc into fdst
-func CopyDir(fdst, fsrc Fs) error {
-	return runSyncCopyMove(fdst, fsrc, DeleteModeOff, false)
+func CopyDir(ctx context.Context, fdst, fsrc Fs) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, DeleteModeOff, false)
 }
 
 // moveDir moves fsrc into fdst
-func moveDir(fdst, fsrc Fs) error {
-	return runSyncCopyMove(fdst, fsrc, DeleteModeOff, true)
+func moveDir(ctx context.Context, fdst, fsrc Fs) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, DeleteModeOff, true)
 }
 
 // MoveDir moves fsrc into fdst
-func MoveDir(fdst, fsrc Fs) error {
+func MoveDir(ctx context.Context, fdst, fsrc Fs) error {
 	if Same(fdst, fsrc) {
 		Errorf(fdst, "Nothing to do as source and destination are the same")
 		return nil
 	}
 
-	// First attempt to use DirMover if exists, same Fs and no filters are active
-	if fdstDirMove := fdst.Features().DirMove; fdstDirMove != nil && SameConfig(fsrc, fdst) && Config.Filter.InActive() {
+	// First attempt to use DirMover if exists, same Fs, no filters
+	// are active and no report of the files moved is needed
+	_, reporting := ctx.Value(reporterKey).(Reporter)
+	if fdstDirMove := fdst.Features().DirMove; fdstDirMove != nil && SameConfig(fsrc, fdst) && Config.Filter.InActive() && !reporting {
 		if Config.DryRun {
 			Logf(fdst, "Not doing server side directory move as --dry-run")
 			return nil
 		}
 		Debugf(fdst, "Using server side directory move")
-		err := fdstDirMove(fsrc, "", "")
+		err := fdstDirMove(ctx, fsrc, "", "")
 		switch err {
 		case ErrorCantDirMove, ErrorDirExists:
 			Infof(fdst, "Server side directory move failed - fallback to file moves: %v", err)
@@ -877,7 +1051,7 @@ func MoveDir(fdst, fsrc Fs) error {
 			Infof(fdst, "Server side directory move succeeded")
 			return nil
 		default:
-			Stats.Error(err)
+			StatsFromContext(ctx).Error(err)
 			Errorf(fdst, "Server side directory move failed: %v", err)
 			return err
 		}
@@ -891,5 +1065,5 @@ func MoveDir(fdst, fsrc Fs) error {
 	}
 
 	// Otherwise move the files one by one
-	return moveDir(fdst, fsrc)
+	return moveDir(ctx, fdst, fsrc)
 }
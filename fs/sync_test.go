@@ -3,14 +3,19 @@
 package fs_test
 
 import (
+	"bytes"
 	"runtime"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/fstest"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 	"golang.org/x/text/unicode/norm"
 )
 
@@ -22,7 +27,7 @@ func TestCopyWithDryRun(t *testing.T) {
 	r.Mkdir(r.Fremote)
 
 	fs.Config.DryRun = true
-	err := fs.CopyDir(r.Fremote, r.Flocal)
+	err := fs.CopyDir(context.Background(), r.Fremote, r.Flocal)
 	fs.Config.DryRun = false
 	require.NoError(t, err)
 
@@ -37,7 +42,7 @@ func TestCopy(t *testing.T) {
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 	r.Mkdir(r.Fremote)
 
-	err := fs.CopyDir(r.Fremote, r.Flocal)
+	err := fs.CopyDir(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -54,7 +59,7 @@ func TestCopyNoTraverse(t *testing.T) {
 
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 
-	err := fs.CopyDir(r.Fremote, r.Flocal)
+	err := fs.CopyDir(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -72,7 +77,7 @@ func TestSyncNoTraverse(t *testing.T) {
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -90,7 +95,7 @@ func TestCopyWithDepth(t *testing.T) {
 	fs.Config.MaxDepth = 1
 	defer func() { fs.Config.MaxDepth = -1 }()
 
-	err := fs.CopyDir(r.Fremote, r.Flocal)
+	err := fs.CopyDir(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1, file2)
@@ -109,7 +114,7 @@ func TestServerSideCopy(t *testing.T) {
 	defer finaliseCopy()
 	t.Logf("Server side copy (if possible) %v -> %v", r.Fremote, FremoteCopy)
 
-	err = fs.CopyDir(FremoteCopy, r.Fremote)
+	err = fs.CopyDir(context.Background(), FremoteCopy, r.Fremote)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, FremoteCopy, file1)
@@ -124,10 +129,10 @@ func TestCopyAfterDelete(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file1)
 
-	err := fs.Mkdir(r.Flocal, "")
+	err := fs.Mkdir(context.Background(), r.Flocal, "")
 	require.NoError(t, err)
 
-	err = fs.CopyDir(r.Fremote, r.Flocal)
+	err = fs.CopyDir(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal)
@@ -141,7 +146,7 @@ func TestCopyRedownload(t *testing.T) {
 	file1 := r.WriteObject("sub dir/hello world", "hello world", t1)
 	fstest.CheckItems(t, r.Fremote, file1)
 
-	err := fs.CopyDir(r.Flocal, r.Fremote)
+	err := fs.CopyDir(context.Background(), r.Flocal, r.Fremote)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -160,7 +165,7 @@ func TestSyncBasedOnCheckSum(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file.
@@ -172,7 +177,7 @@ func TestSyncBasedOnCheckSum(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Fremote, r.Flocal)
+	err = fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred no files
@@ -194,7 +199,7 @@ func TestSyncSizeOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file.
@@ -206,7 +211,7 @@ func TestSyncSizeOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Fremote, r.Flocal)
+	err = fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred no files
@@ -228,7 +233,7 @@ func TestSyncIgnoreSize(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file.
@@ -240,7 +245,7 @@ func TestSyncIgnoreSize(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Fremote, r.Flocal)
+	err = fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred no files
@@ -256,7 +261,7 @@ func TestSyncIgnoreTimes(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly 0 files because the
@@ -267,7 +272,7 @@ func TestSyncIgnoreTimes(t *testing.T) {
 	defer func() { fs.Config.IgnoreTimes = false }()
 
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Fremote, r.Flocal)
+	err = fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file even though the
@@ -287,7 +292,7 @@ func TestSyncIgnoreExisting(t *testing.T) {
 	defer func() { fs.Config.IgnoreExisting = false }()
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file1)
@@ -295,7 +300,7 @@ func TestSyncIgnoreExisting(t *testing.T) {
 	// Change everything
 	r.WriteFile("existing", "newpotatoes", t2)
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Fremote, r.Flocal)
+	err = fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	// Items should not change
 	fstest.CheckItems(t, r.Fremote, file1)
@@ -314,7 +319,7 @@ func TestSyncAfterChangingModtimeOnly(t *testing.T) {
 	defer func() { fs.Config.DryRun = false }()
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -323,7 +328,7 @@ func TestSyncAfterChangingModtimeOnly(t *testing.T) {
 	fs.Config.DryRun = false
 
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Fremote, r.Flocal)
+	err = fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -351,7 +356,7 @@ func TestSyncAfterChangingModtimeOnlyWithNoUpdateModTime(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -372,7 +377,7 @@ func TestSyncDoesntUpdateModtime(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -392,7 +397,7 @@ func TestSyncAfterAddingAFile(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1, file2)
 	fstest.CheckItems(t, r.Fremote, file1, file2)
@@ -407,7 +412,7 @@ func TestSyncAfterChangingFilesSizeOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -430,7 +435,7 @@ func TestSyncAfterChangingContentsOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -446,7 +451,7 @@ func TestSyncAfterRemovingAFileAndAddingAFileDryRun(t *testing.T) {
 
 	fs.Config.DryRun = true
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	fs.Config.DryRun = false
 	require.NoError(t, err)
 
@@ -465,7 +470,7 @@ func TestSyncAfterRemovingAFileAndAddingAFile(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1, file3)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1, file3)
 	fstest.CheckItems(t, r.Fremote, file1, file3)
@@ -478,8 +483,8 @@ func TestSyncAfterRemovingAFileAndAddingAFileSubDir(t *testing.T) {
 	file1 := r.WriteFile("a/potato2", "------------------------------------------------------------", t1)
 	file2 := r.WriteObject("b/potato", "SMALLER BUT SAME DATE", t2)
 	file3 := r.WriteBoth("c/non empty space", "AhHa!", t2)
-	require.NoError(t, fs.Mkdir(r.Fremote, "d"))
-	require.NoError(t, fs.Mkdir(r.Fremote, "d/e"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "d"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "d/e"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -511,7 +516,7 @@ func TestSyncAfterRemovingAFileAndAddingAFileSubDir(t *testing.T) {
 	)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckListingWithPrecision(
@@ -549,7 +554,7 @@ func TestSyncAfterRemovingAFileAndAddingAFileSubDirWithErrors(t *testing.T) {
 	file1 := r.WriteFile("a/potato2", "------------------------------------------------------------", t1)
 	file2 := r.WriteObject("b/potato", "SMALLER BUT SAME DATE", t2)
 	file3 := r.WriteBoth("c/non empty space", "AhHa!", t2)
-	require.NoError(t, fs.Mkdir(r.Fremote, "d"))
+	require.NoError(t, fs.Mkdir(context.Background(), r.Fremote, "d"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -581,7 +586,7 @@ func TestSyncAfterRemovingAFileAndAddingAFileSubDirWithErrors(t *testing.T) {
 
 	fs.Stats.ResetCounters()
 	fs.Stats.Error(nil)
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	assert.Equal(t, fs.ErrorNotDeleting, err)
 
 	fstest.CheckListingWithPrecision(
@@ -658,7 +663,7 @@ func TestCopyDeleteBefore(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	fs.Stats.ResetCounters()
-	err := fs.CopyDir(r.Fremote, r.Flocal)
+	err := fs.CopyDir(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
@@ -681,14 +686,14 @@ func TestSyncWithExclude(t *testing.T) {
 	}()
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, file2, file1)
 
 	// Now sync the other way round and check enormous doesn't get
 	// deleted as it is excluded from the sync
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Flocal, r.Fremote)
+	err = fs.Sync(context.Background(), r.Flocal, r.Fremote)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2, file1, file3)
 }
@@ -711,18 +716,57 @@ func TestSyncWithExcludeAndDeleteExcluded(t *testing.T) {
 	}()
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	// Check sync the other way round to make sure enormous gets
 	// deleted even though it is excluded
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Flocal, r.Fremote)
+	err = fs.Sync(context.Background(), r.Flocal, r.Fremote)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2)
 }
 
+// Test sync with per directory ignore files
+func TestSyncWithIgnoreFile(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ignore1 := r.WriteFile(".rcloneignore", "*.o\n/cache/\n", t1)
+	file1 := r.WriteFile("main.c", "main", t1)
+	r.WriteFile("main.o", "object", t1)
+	r.WriteFile("cache/data", "data", t1)
+	ignore2 := r.WriteFile("sub/.rcloneignore", "!*.o\n*.c\n", t1)
+	file2 := r.WriteFile("sub/lib.o", "lib", t1)
+	r.WriteFile("sub/lib.c", "lib source", t1)
+	file3 := r.WriteFile("sub/cache/data", "sub data", t1)
+	rfile1 := r.WriteObject("old.o", "old object", t1)
+	r.WriteObject("gone.c", "gone", t1)
+
+	fs.Config.Filter.IgnoreFile = ".rcloneignore"
+	defer func() {
+		fs.Config.Filter.IgnoreFile = ""
+	}()
+
+	fs.Stats.ResetCounters()
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
+	require.NoError(t, err)
+	// old.o is ignored so shouldn't be deleted
+	fstest.CheckItems(t, r.Fremote, ignore1, file1, ignore2, file2, file3, rfile1)
+
+	// Check walking the source obeys the ignore files too
+	var objects []string
+	err = fs.Walk(context.Background(), r.Flocal, "", false, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+		entries.ForObject(func(o fs.Object) {
+			objects = append(objects, o.Remote())
+		})
+		return err
+	})
+	require.NoError(t, err)
+	sort.Strings(objects)
+	assert.Equal(t, []string{".rcloneignore", "main.c", "sub/.rcloneignore", "sub/cache/data", "sub/lib.o"}, objects)
+}
+
 // Test with UpdateOlder set
 func TestSyncWithUpdateOlder(t *testing.T) {
 	if fs.Config.ModifyWindow == fs.ModTimeNotSupported {
@@ -753,7 +797,7 @@ func TestSyncWithUpdateOlder(t *testing.T) {
 	}()
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, oneO, twoF, threeO, fourF, fiveF)
 }
@@ -777,7 +821,7 @@ func TestSyncWithTrackRenames(t *testing.T) {
 	f2 := r.WriteFile("yam", "Yam Content", t2)
 
 	fs.Stats.ResetCounters()
-	require.NoError(t, fs.Sync(r.Fremote, r.Flocal))
+	require.NoError(t, fs.Sync(context.Background(), r.Fremote, r.Flocal))
 
 	fstest.CheckItems(t, r.Fremote, f1, f2)
 	fstest.CheckItems(t, r.Flocal, f1, f2)
@@ -786,7 +830,7 @@ func TestSyncWithTrackRenames(t *testing.T) {
 	f2 = r.RenameFile(f2, "yaml")
 
 	fs.Stats.ResetCounters()
-	require.NoError(t, fs.Sync(r.Fremote, r.Flocal))
+	require.NoError(t, fs.Sync(context.Background(), r.Fremote, r.Flocal))
 
 	fstest.CheckItems(t, r.Fremote, f1, f2)
 
@@ -818,7 +862,7 @@ func testServerSideMove(t *testing.T, r *fstest.Run, withFilter bool) {
 
 	// Do server side move
 	fs.Stats.ResetCounters()
-	err = fs.MoveDir(FremoteMove, r.Fremote)
+	err = fs.MoveDir(context.Background(), FremoteMove, r.Fremote)
 	require.NoError(t, err)
 
 	if withFilter {
@@ -835,7 +879,7 @@ func testServerSideMove(t *testing.T, r *fstest.Run, withFilter bool) {
 
 	// Move it back to a new empty remote, dst does not exist this time
 	fs.Stats.ResetCounters()
-	err = fs.MoveDir(FremoteMove2, FremoteMove)
+	err = fs.MoveDir(context.Background(), FremoteMove2, FremoteMove)
 	require.NoError(t, err)
 
 	if withFilter {
@@ -884,7 +928,7 @@ func TestServerSideMoveOverlap(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	// Subdir move with no filters should return ErrorCantMoveOverlapping
-	err = fs.MoveDir(FremoteMove, r.Fremote)
+	err = fs.MoveDir(context.Background(), FremoteMove, r.Fremote)
 	assert.EqualError(t, err, fs.ErrorCantMoveOverlapping.Error())
 
 	// Now try with a filter which should also fail with ErrorCantMoveOverlapping
@@ -892,7 +936,7 @@ func TestServerSideMoveOverlap(t *testing.T) {
 	defer func() {
 		fs.Config.Filter.MinSize = -1
 	}()
-	err = fs.MoveDir(FremoteMove, r.Fremote)
+	err = fs.MoveDir(context.Background(), FremoteMove, r.Fremote)
 	assert.EqualError(t, err, fs.ErrorCantMoveOverlapping.Error())
 }
 
@@ -928,7 +972,7 @@ func testSyncBackupDir(t *testing.T, suffix string) {
 	require.NoError(t, err)
 
 	fs.Stats.ResetCounters()
-	err = fs.Sync(fdst, r.Flocal)
+	err = fs.Sync(context.Background(), fdst, r.Flocal)
 	require.NoError(t, err)
 
 	// one should be moved to the backup dir and the new one installed
@@ -949,7 +993,7 @@ func testSyncBackupDir(t *testing.T, suffix string) {
 	// This should delete three and overwrite one again, checking
 	// the files got overwritten correctly in backup-dir
 	fs.Stats.ResetCounters()
-	err = fs.Sync(fdst, r.Flocal)
+	err = fs.Sync(context.Background(), fdst, r.Flocal)
 	require.NoError(t, err)
 
 	// one should be moved to the backup dir and the new one installed
@@ -964,6 +1008,144 @@ func testSyncBackupDir(t *testing.T, suffix string) {
 func TestSyncBackupDir(t *testing.T)           { testSyncBackupDir(t, "") }
 func TestSyncBackupDirWithSuffix(t *testing.T) { testSyncBackupDir(t, ".bak") }
 
+// Test with CompareDest set
+func TestSyncCompareDest(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	r.Mkdir(r.Fremote)
+
+	fs.Config.CompareDest = []string{r.FremoteName + "/old1", r.FremoteName + "/old2"}
+	defer func() {
+		fs.Config.CompareDest = nil
+	}()
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	// one is unchanged in old2, two has changed and three is new
+	old1 := r.WriteObject("old1/two", "two", t1)
+	old2 := r.WriteObject("old2/one", "one", t1)
+	file1 := r.WriteFile("one", "one", t1)
+	file2 := r.WriteFile("two", "twoA", t2)
+	file3 := r.WriteFile("three", "three", t2)
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+
+	fs.Stats.ResetCounters()
+	err = fs.Sync(context.Background(), fdst, r.Flocal)
+	require.NoError(t, err)
+
+	file2.Path = "dst/two"
+	file3.Path = "dst/three"
+	fstest.CheckItems(t, r.Fremote, old1, old2, file2, file3)
+}
+
+// Test with CopyDest set
+func TestSyncCopyDest(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	r.Mkdir(r.Fremote)
+
+	fs.Config.CopyDest = []string{r.FremoteName + "/old"}
+	defer func() {
+		fs.Config.CopyDest = nil
+	}()
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	// one is unchanged in old, two has changed and three is new
+	old1 := r.WriteObject("old/one", "one", t1)
+	old2 := r.WriteObject("old/two", "two", t1)
+	file1 := r.WriteFile("one", "one", t1)
+	file2 := r.WriteFile("two", "twoA", t2)
+	file3 := r.WriteFile("three", "three", t2)
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+
+	fs.Stats.ResetCounters()
+	err = fs.Sync(context.Background(), fdst, r.Flocal)
+	require.NoError(t, err)
+
+	file1.Path = "dst/one"
+	file2.Path = "dst/two"
+	file3.Path = "dst/three"
+	fstest.CheckItems(t, r.Fremote, old1, old2, file1, file2, file3)
+}
+
+// Test --copy-dest doesn't replace an existing file with --immutable
+func TestSyncCopyDestImmutable(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	r.Mkdir(r.Fremote)
+
+	fs.Config.CopyDest = []string{r.FremoteName + "/old"}
+	fs.Config.Immutable = true
+	defer func() {
+		fs.Config.CopyDest = nil
+		fs.Config.Immutable = false
+	}()
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	// one is unchanged in old but differs in dst
+	old1 := r.WriteObject("old/one", "one", t1)
+	dst1 := r.WriteObject("dst/one", "oneA", t2)
+	file1 := r.WriteFile("one", "one", t1)
+	fstest.CheckItems(t, r.Flocal, file1)
+
+	// Should fail with ErrorImmutableModified and not modify dst
+	fs.Stats.ResetCounters()
+	err = fs.Sync(context.Background(), fdst, r.Flocal)
+	assert.EqualError(t, err, fs.ErrorImmutableModified.Error())
+	fstest.CheckItems(t, r.Fremote, old1, dst1)
+}
+
+// sortedReport returns the lines of a report sorted
+func sortedReport(buf *bytes.Buffer) []string {
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+// Test the change report from sync
+func testSyncReport(t *testing.T, dryRun bool) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	file1 := r.WriteFile("one", "one", t1)
+	file2 := r.WriteFile("two", "twoA", t2)
+	file3 := r.WriteFile("three", "three", t2)
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+	rfile1 := r.WriteObject("one", "one", t1)
+	rfile2 := r.WriteObject("two", "two", t1)
+	rfile4 := r.WriteObject("four", "four", t1)
+	fstest.CheckItems(t, r.Fremote, rfile1, rfile2, rfile4)
+
+	var combined, missingOnSrc bytes.Buffer
+	report := fs.NewReportWriter(false)
+	report.SetCombined(&combined)
+	report.SetOutput(fs.SigilMissingOnSrc, &missingOnSrc)
+	ctx := fs.WithReporter(context.Background(), report)
+
+	fs.Config.DryRun = dryRun
+	fs.Stats.ResetCounters()
+	err := fs.Sync(ctx, r.Fremote, r.Flocal)
+	fs.Config.DryRun = false
+	require.NoError(t, err)
+	require.NoError(t, report.Err())
+
+	assert.Equal(t, []string{"* two", "+ three", "- four", "= one"}, sortedReport(&combined))
+	assert.Equal(t, "four\n", missingOnSrc.String())
+	if dryRun {
+		fstest.CheckItems(t, r.Fremote, rfile1, rfile2, rfile4)
+	} else {
+		fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+	}
+}
+
+func TestSyncReport(t *testing.T)       { testSyncReport(t, false) }
+func TestSyncReportDryRun(t *testing.T) { testSyncReport(t, true) }
+
 // Check we can sync two files with differing UTF-8 representations
 func TestSyncUTFNorm(t *testing.T) {
 	if runtime.GOOS == "darwin" {
@@ -986,7 +1168,7 @@ func TestSyncUTFNorm(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file, but kept the
@@ -1012,7 +1194,7 @@ func TestSyncImmutable(t *testing.T) {
 
 	// Should succeed
 	fs.Stats.ResetCounters()
-	err := fs.Sync(r.Fremote, r.Flocal)
+	err := fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file1)
@@ -1024,8 +1206,41 @@ func TestSyncImmutable(t *testing.T) {
 
 	// Should fail with ErrorImmutableModified and not modify local or remote files
 	fs.Stats.ResetCounters()
-	err = fs.Sync(r.Fremote, r.Flocal)
+	err = fs.Sync(context.Background(), r.Fremote, r.Flocal)
 	assert.EqualError(t, err, fs.ErrorImmutableModified.Error())
 	fstest.CheckItems(t, r.Flocal, file2)
 	fstest.CheckItems(t, r.Fremote, file1)
 }
+
+// Test --max-transfer
+func TestSyncMaxTransfer(t *testing.T) {
+	oldMaxTransfer, oldCutoffMode, oldTransfers := fs.Config.MaxTransfer, fs.Config.CutoffMode, fs.Config.Transfers
+	fs.Config.MaxTransfer, fs.Config.Transfers = 150, 1
+	defer func() {
+		fs.Config.MaxTransfer, fs.Config.CutoffMode, fs.Config.Transfers = oldMaxTransfer, oldCutoffMode, oldTransfers
+	}()
+
+	test := func(cutoffMode fs.CutoffMode, expectedFiles int, expectedErrors int64) {
+		r := fstest.NewRun(t)
+		defer r.Finalise()
+		fs.Config.CutoffMode = cutoffMode
+
+		var files []fstest.Item
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			files = append(files, r.WriteFile(name, strings.Repeat(name, 100), t1))
+		}
+		r.Mkdir(r.Fremote)
+
+		fs.Stats.ResetCounters()
+		err := fs.CopyDir(context.Background(), r.Fremote, r.Flocal)
+		require.Error(t, err, cutoffMode.String())
+		assert.Equal(t, fs.ErrorMaxTransferLimitReached, errors.Cause(err), cutoffMode.String())
+		fstest.CheckItems(t, r.Fremote, files[:expectedFiles]...)
+		// only a transfer stopped part way is an error - the
+		// files which weren't started aren't
+		assert.Equal(t, expectedErrors, fs.Stats.GetErrors(), cutoffMode.String())
+	}
+	test(fs.CutoffModeHard, 1, 1)
+	test(fs.CutoffModeSoft, 2, 0)
+	test(fs.CutoffModeCautious, 1, 0)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // ErrorSkipDir is used as a return value from Walk to indicate that the
@@ -50,38 +51,40 @@ type WalkFunc func(path string, entries DirEntries, err error) error
 // Parent directories are always listed before their children
 //
 // This is implemented by WalkR if Config.UseRecursiveListing is true
-// and f supports it and level > 1, or WalkN otherwise.
+// and f supports it and level > 1, or WalkN otherwise.  WalkN is
+// always used if ignore files are in use as they need to be read
+// before the directories below them are listed.
 //
 // NB (f, path) to be replaced by fs.Dir at some point
-func Walk(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc) error {
-	if (maxLevel < 0 || maxLevel > 1) && Config.UseListR && f.Features().ListR != nil {
-		return WalkR(f, path, includeAll, maxLevel, fn)
+func Walk(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc) error {
+	if (maxLevel < 0 || maxLevel > 1) && Config.UseListR && f.Features().ListR != nil && Config.Filter.IgnoreFile == "" {
+		return WalkR(ctx, f, path, includeAll, maxLevel, fn)
 	}
-	return WalkN(f, path, includeAll, maxLevel, fn)
+	return WalkN(ctx, f, path, includeAll, maxLevel, fn)
 }
 
 // WalkN lists the directory.
 //
 // It implements Walk using non recursive directory listing.
-func WalkN(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc) error {
-	return walk(f, path, includeAll, maxLevel, fn, ListDirSorted)
+func WalkN(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc) error {
+	return walk(ctx, f, path, includeAll, maxLevel, fn, listDirFor(includeAll))
 }
 
 // WalkR lists the directory.
 //
 // It implements Walk using recursive directory listing if
 // available, or returns ErrorCantListR if not.
-func WalkR(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc) error {
+func WalkR(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc) error {
 	listR := f.Features().ListR
 	if listR == nil {
 		return ErrorCantListR
 	}
-	return walkR(f, path, includeAll, maxLevel, fn, listR)
+	return walkR(ctx, f, path, includeAll, maxLevel, fn, listR)
 }
 
-type listDirFunc func(fs Fs, includeAll bool, dir string) (entries DirEntries, err error)
+type listDirFunc func(ctx context.Context, fs Fs, includeAll bool, dir string) (entries DirEntries, err error)
 
-func walk(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc, listDir listDirFunc) error {
+func walk(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc, listDir listDirFunc) error {
 	var (
 		wg         sync.WaitGroup // sync closing of go routines
 		traversing sync.WaitGroup // running directory traversals
@@ -117,7 +120,7 @@ func walk(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc, listDir
 					if !ok {
 						return
 					}
-					entries, err := listDir(f, includeAll, job.remote)
+					entries, err := listDir(ctx, f, includeAll, job.remote)
 					var jobs []listJob
 					if err == nil && job.depth != 0 {
 						entries.ForDir(func(dir Directory) {
@@ -134,7 +137,7 @@ func walk(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc, listDir
 					// NB once we have passed entries to fn we mustn't touch it again
 					if err != nil && err != ErrorSkipDir {
 						traversing.Done()
-						Stats.Error(err)
+						StatsFromContext(ctx).Error(err)
 						Errorf(job.remote, "error listing: %v", err)
 						closeQuit()
 						// Send error to error channel if space
@@ -347,14 +350,14 @@ func (dt DirTree) String() string {
 	return out.String()
 }
 
-func walkRDirTree(f Fs, startPath string, includeAll bool, maxLevel int, listR ListRFn) (DirTree, error) {
+func walkRDirTree(ctx context.Context, f Fs, startPath string, includeAll bool, maxLevel int, listR ListRFn) (DirTree, error) {
 	dirs := make(DirTree)
 	// Entries can come in arbitrary order. We use toPrune to keep
 	// all directories to exclude later.
 	toPrune := make(map[string]bool)
-	includeDirectory := Config.Filter.IncludeDirectory(f)
+	includeDirectory := Config.Filter.IncludeDirectory(ctx, f)
 	var mu sync.Mutex
-	err := listR(startPath, func(entries DirEntries) error {
+	err := listR(ctx, startPath, func(entries DirEntries) error {
 		mu.Lock()
 		defer mu.Unlock()
 		for _, entry := range entries {
@@ -424,7 +427,7 @@ func walkRDirTree(f Fs, startPath string, includeAll bool, maxLevel int, listR L
 }
 
 // Create a DirTree using List
-func walkNDirTree(f Fs, path string, includeAll bool, maxLevel int, listDir listDirFunc) (DirTree, error) {
+func walkNDirTree(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int, listDir listDirFunc) (DirTree, error) {
 	dirs := make(DirTree)
 	fn := func(dirPath string, entries DirEntries, err error) error {
 		if err == nil {
@@ -432,7 +435,7 @@ func walkNDirTree(f Fs, path string, includeAll bool, maxLevel int, listDir list
 		}
 		return err
 	}
-	err := walk(f, path, includeAll, maxLevel, fn, listDir)
+	err := walk(ctx, f, path, includeAll, maxLevel, fn, listDir)
 	if err != nil {
 		return nil, err
 	}
@@ -451,15 +454,15 @@ func walkNDirTree(f Fs, path string, includeAll bool, maxLevel int, listDir list
 // and f supports it and level > 1, or WalkN otherwise.
 //
 // NB (f, path) to be replaced by fs.Dir at some point
-func NewDirTree(f Fs, path string, includeAll bool, maxLevel int) (DirTree, error) {
-	if ListR := f.Features().ListR; (maxLevel < 0 || maxLevel > 1) && Config.UseListR && ListR != nil {
-		return walkRDirTree(f, path, includeAll, maxLevel, ListR)
+func NewDirTree(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int) (DirTree, error) {
+	if ListR := f.Features().ListR; (maxLevel < 0 || maxLevel > 1) && Config.UseListR && ListR != nil && Config.Filter.IgnoreFile == "" {
+		return walkRDirTree(ctx, f, path, includeAll, maxLevel, ListR)
 	}
-	return walkNDirTree(f, path, includeAll, maxLevel, ListDirSorted)
+	return walkNDirTree(ctx, f, path, includeAll, maxLevel, listDirFor(includeAll))
 }
 
-func walkR(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc, listR ListRFn) error {
-	dirs, err := walkRDirTree(f, path, includeAll, maxLevel, listR)
+func walkR(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc, listR ListRFn) error {
+	dirs, err := walkRDirTree(ctx, f, path, includeAll, maxLevel, listR)
 	if err != nil {
 		return err
 	}
@@ -493,8 +496,8 @@ func walkR(f Fs, path string, includeAll bool, maxLevel int, fn WalkFunc, listR
 }
 
 // WalkGetAll runs Walk getting all the results
-func WalkGetAll(f Fs, path string, includeAll bool, maxLevel int) (objs []Object, dirs []Directory, err error) {
-	err = Walk(f, path, includeAll, maxLevel, func(dirPath string, entries DirEntries, err error) error {
+func WalkGetAll(ctx context.Context, f Fs, path string, includeAll bool, maxLevel int) (objs []Object, dirs []Directory, err error) {
+	err = Walk(ctx, f, path, includeAll, maxLevel, func(dirPath string, entries DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
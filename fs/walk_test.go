@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 type (
@@ -47,12 +48,14 @@ func (o mockObject) Hash(HashType) (string, error)                     { return
 func (o mockObject) ModTime() (t time.Time)                            { return t }
 func (o mockObject) Size() int64                                       { return 0 }
 func (o mockObject) Storable() bool                                    { return true }
-func (o mockObject) SetModTime(time.Time) error                        { return errNotImpl }
-func (o mockObject) Open(options ...OpenOption) (io.ReadCloser, error) { return nil, errNotImpl }
-func (o mockObject) Update(in io.Reader, src ObjectInfo, options ...OpenOption) error {
+func (o mockObject) SetModTime(ctx context.Context, t time.Time) error { return errNotImpl }
+func (o mockObject) Open(ctx context.Context, options ...OpenOption) (io.ReadCloser, error) {
+	return nil, errNotImpl
+}
+func (o mockObject) Update(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) error {
 	return errNotImpl
 }
-func (o mockObject) Remove() error { return errNotImpl }
+func (o mockObject) Remove(ctx context.Context) error { return errNotImpl }
 
 type unknownDirEntry string
 
@@ -88,7 +91,7 @@ func (ls *listDirs) SetLevel(maxLevel int) *listDirs {
 }
 
 // ListDir returns the expected listing for the directory
-func (ls *listDirs) ListDir(f Fs, includeAll bool, dir string) (entries DirEntries, err error) {
+func (ls *listDirs) ListDir(ctx context.Context, f Fs, includeAll bool, dir string) (entries DirEntries, err error) {
 	ls.mu.Lock()
 	defer ls.mu.Unlock()
 	assert.Equal(ls.t, ls.fs, f)
@@ -109,7 +112,7 @@ func (ls *listDirs) ListDir(f Fs, includeAll bool, dir string) (entries DirEntri
 }
 
 // ListR returns the expected listing for the directory using ListR
-func (ls *listDirs) ListR(dir string, callback ListRCallback) (err error) {
+func (ls *listDirs) ListR(ctx context.Context, dir string, callback ListRCallback) (err error) {
 	ls.mu.Lock()
 	defer ls.mu.Unlock()
 
@@ -170,14 +173,14 @@ func (ls *listDirs) WalkFn(dir string, entries DirEntries, err error) error {
 
 // Walk does the walk and tests the expectations
 func (ls *listDirs) Walk() {
-	err := walk(nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListDir)
+	err := walk(context.Background(), nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListDir)
 	assert.Equal(ls.t, ls.finalError, err)
 	ls.IsFinished()
 }
 
 // WalkR does the walkR and tests the expectations
 func (ls *listDirs) WalkR() {
-	err := walkR(nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListR)
+	err := walkR(context.Background(), nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListR)
 	assert.Equal(ls.t, ls.finalError, err)
 	if ls.finalError == nil {
 		ls.IsFinished()
@@ -290,7 +293,7 @@ func TestWalkRLevelsNoRecursive10(t *testing.T) { testWalkLevels(t, 10).WalkR()
 
 func TestWalkNDirTree(t *testing.T) {
 	ls := testWalkLevels(t, -1)
-	entries, err := walkNDirTree(nil, "", ls.includeAll, ls.maxLevel, ls.ListDir)
+	entries, err := walkNDirTree(context.Background(), nil, "", ls.includeAll, ls.maxLevel, ls.ListDir)
 	require.NoError(t, err)
 	assert.Equal(t, `/
   A
@@ -438,7 +441,7 @@ func TestWalkRMultiErrors(t *testing.T) { testWalkMultiErrors(t).Walk() }
 
 // a very simple listRcallback function
 func makeListRCallback(entries DirEntries, err error) ListRFn {
-	return func(dir string, callback ListRCallback) error {
+	return func(ctx context.Context, dir string, callback ListRCallback) error {
 		if err == nil {
 			err = callback(entries)
 		}
@@ -583,7 +586,7 @@ a/
   b/
 `, nil, "", 2},
 	} {
-		r, err := walkRDirTree(nil, test.root, true, test.level, makeListRCallback(test.entries, test.err))
+		r, err := walkRDirTree(context.Background(), nil, test.root, true, test.level, makeListRCallback(test.entries, test.err))
 		assert.Equal(t, test.err, err, fmt.Sprintf("%+v", test))
 		assert.Equal(t, test.want, r.String(), fmt.Sprintf("%+v", test))
 	}
@@ -654,7 +657,7 @@ b/c/d/
 `, nil, "", -1, "ign", true},
 	} {
 		Config.Filter.ExcludeFile = test.excludeFile
-		r, err := walkRDirTree(nil, test.root, test.includeAll, test.level, makeListRCallback(test.entries, test.err))
+		r, err := walkRDirTree(context.Background(), nil, test.root, test.includeAll, test.level, makeListRCallback(test.entries, test.err))
 		assert.Equal(t, test.err, err, fmt.Sprintf("%+v", test))
 		assert.Equal(t, test.want, r.String(), fmt.Sprintf("%+v", test))
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 	"golang.org/x/text/unicode/norm"
 )
 
@@ -262,7 +263,7 @@ func CheckListingWithPrecision(t *testing.T, f fs.Fs, items []Item, expectedDirs
 	gotListing := "<unset>"
 	listingOK := false
 	for i := 1; i <= retries; i++ {
-		objs, dirs, err = fs.WalkGetAll(f, "", true, -1)
+		objs, dirs, err = fs.WalkGetAll(context.Background(), f, "", true, -1)
 		if err != nil && err != fs.ErrorDirNotFound {
 			t.Fatalf("Error listing: %v", err)
 		}
@@ -418,9 +419,9 @@ func RandomRemote(remoteName string, subdir bool) (fs.Fs, string, func(), error)
 	}
 
 	finalise := func() {
-		_ = fs.Purge(remote) // ignore error
+		_ = fs.Purge(context.Background(), remote) // ignore error
 		if parentRemote != nil {
-			err = fs.Purge(parentRemote) // ignore error
+			err = fs.Purge(context.Background(), parentRemote) // ignore error
 			if err != nil {
 				log.Printf("Failed to purge %v: %v", parentRemote, err)
 			}
@@ -432,20 +433,20 @@ func RandomRemote(remoteName string, subdir bool) (fs.Fs, string, func(), error)
 
 // TestMkdir tests Mkdir works
 func TestMkdir(t *testing.T, remote fs.Fs) {
-	err := fs.Mkdir(remote, "")
+	err := fs.Mkdir(context.Background(), remote, "")
 	require.NoError(t, err)
 	CheckListing(t, remote, []Item{})
 }
 
 // TestPurge tests Purge works
 func TestPurge(t *testing.T, remote fs.Fs) {
-	err := fs.Purge(remote)
+	err := fs.Purge(context.Background(), remote)
 	require.NoError(t, err)
 	CheckListing(t, remote, []Item{})
 }
 
 // TestRmdir tests Rmdir works
 func TestRmdir(t *testing.T, remote fs.Fs) {
-	err := fs.Rmdir(remote, "")
+	err := fs.Rmdir(context.Background(), remote, "")
 	require.NoError(t, err)
 }
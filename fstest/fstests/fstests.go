@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -162,7 +163,7 @@ func TestFsRmdirEmpty(t *testing.T) {
 // TestFsRmdirNotFound tests deleting a non existent directory
 func TestFsRmdirNotFound(t *testing.T) {
 	skipIfNotOk(t)
-	err := remote.Rmdir("")
+	err := remote.Rmdir(context.Background(), "")
 	assert.Error(t, err, "Expecting error on Rmdir non existent")
 }
 
@@ -183,15 +184,15 @@ func TestFsMkdir(t *testing.T) {
 func TestFsMkdirRmdirSubdir(t *testing.T) {
 	skipIfNotOk(t)
 	dir := "dir/subdir"
-	err := fs.Mkdir(remote, dir)
+	err := fs.Mkdir(context.Background(), remote, dir)
 	require.NoError(t, err)
 	fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{"dir", "dir/subdir"}, fs.Config.ModifyWindow)
 
-	err = fs.Rmdir(remote, dir)
+	err = fs.Rmdir(context.Background(), remote, dir)
 	require.NoError(t, err)
 	fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{"dir"}, fs.Config.ModifyWindow)
 
-	err = fs.Rmdir(remote, "dir")
+	err = fs.Rmdir(context.Background(), remote, "dir")
 	require.NoError(t, err)
 	fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{}, fs.Config.ModifyWindow)
 }
@@ -236,7 +237,7 @@ func objsToNames(objs []fs.Object) []string {
 // TestFsListDirEmpty tests listing the directories from an empty directory
 func TestFsListDirEmpty(t *testing.T) {
 	skipIfNotOk(t)
-	objs, dirs, err := fs.WalkGetAll(remote, "", true, 1)
+	objs, dirs, err := fs.WalkGetAll(context.Background(), remote, "", true, 1)
 	require.NoError(t, err)
 	assert.Equal(t, []string{}, objsToNames(objs))
 	assert.Equal(t, []string{}, dirsToNames(dirs))
@@ -252,11 +253,11 @@ func TestFsListRDirEmpty(t *testing.T) {
 func TestFsNewObjectNotFound(t *testing.T) {
 	skipIfNotOk(t)
 	// Object in an existing directory
-	o, err := remote.NewObject("potato")
+	o, err := remote.NewObject(context.Background(), "potato")
 	assert.Nil(t, o)
 	assert.Equal(t, fs.ErrorObjectNotFound, err)
 	// Now try an object in a non existing directory
-	o, err = remote.NewObject("directory/not/found/potato")
+	o, err = remote.NewObject(context.Background(), "directory/not/found/potato")
 	assert.Nil(t, o)
 	assert.Equal(t, fs.ErrorObjectNotFound, err)
 }
@@ -265,7 +266,7 @@ func findObject(t *testing.T, Name string) fs.Object {
 	var obj fs.Object
 	var err error
 	for i := 1; i <= *fstest.ListRetries; i++ {
-		obj, err = remote.NewObject(Name)
+		obj, err = remote.NewObject(context.Background(), Name)
 		if err == nil {
 			break
 		}
@@ -287,7 +288,7 @@ again:
 
 	file.Size = int64(buf.Len())
 	obji := fs.NewStaticObjectInfo(file.Path, file.ModTime, file.Size, true, nil, nil)
-	obj, err := remote.Put(in, obji)
+	obj, err := remote.Put(context.Background(), in, obji)
 	if err != nil {
 		// Retry if err returned a retry error
 		if fs.IsRetryError(err) && tries < maxTries {
@@ -335,11 +336,11 @@ func TestFsPutError(t *testing.T) {
 	in := io.MultiReader(buf, er)
 
 	obji := fs.NewStaticObjectInfo(file2.Path, file2.ModTime, 100, true, nil, nil)
-	_, err := remote.Put(in, obji)
+	_, err := remote.Put(context.Background(), in, obji)
 	// assert.Nil(t, obj) - FIXME some remotes return the object even on nil
 	assert.NotNil(t, err)
 
-	obj, err := remote.NewObject(file2.Path)
+	obj, err := remote.NewObject(context.Background(), file2.Path)
 	assert.Nil(t, obj)
 	assert.Equal(t, fs.ErrorObjectNotFound, err)
 }
@@ -364,9 +365,9 @@ func TestFsListDirFile2(t *testing.T) {
 	list := func(dir string, expectedDirNames, expectedObjNames []string) {
 		var objNames, dirNames []string
 		for i := 1; i <= *fstest.ListRetries; i++ {
-			objs, dirs, err := fs.WalkGetAll(remote, dir, true, 1)
+			objs, dirs, err := fs.WalkGetAll(context.Background(), remote, dir, true, 1)
 			if errors.Cause(err) == fs.ErrorDirNotFound {
-				objs, dirs, err = fs.WalkGetAll(remote, winPath(dir), true, 1)
+				objs, dirs, err = fs.WalkGetAll(context.Background(), remote, winPath(dir), true, 1)
 			}
 			require.NoError(t, err)
 			objNames = objsToNames(objs)
@@ -413,7 +414,7 @@ func TestFsListDirRoot(t *testing.T) {
 	skipIfNotOk(t)
 	rootRemote, err := fs.NewFs(RemoteName)
 	require.NoError(t, err)
-	_, dirs, err := fs.WalkGetAll(rootRemote, "", true, 1)
+	_, dirs, err := fs.WalkGetAll(context.Background(), rootRemote, "", true, 1)
 	require.NoError(t, err)
 	assert.Contains(t, dirsToNames(dirs), subRemoteLeaf, "Remote leaf not found")
 }
@@ -434,7 +435,7 @@ func TestFsListSubdir(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		dir, _ := path.Split(fileName)
 		dir = dir[:len(dir)-1]
-		objs, dirs, err = fs.WalkGetAll(remote, dir, true, -1)
+		objs, dirs, err = fs.WalkGetAll(context.Background(), remote, dir, true, -1)
 		if err != fs.ErrorDirNotFound {
 			break
 		}
@@ -455,7 +456,7 @@ func TestFsListRSubdir(t *testing.T) {
 // TestFsListLevel2 tests List works for 2 levels
 func TestFsListLevel2(t *testing.T) {
 	skipIfNotOk(t)
-	objs, dirs, err := fs.WalkGetAll(remote, "", true, 2)
+	objs, dirs, err := fs.WalkGetAll(context.Background(), remote, "", true, 2)
 	if err == fs.ErrorLevelNotSupported {
 		return
 	}
@@ -493,7 +494,7 @@ func TestFsListFile1and2(t *testing.T) {
 func TestFsNewObjectDir(t *testing.T) {
 	skipIfNotOk(t)
 	dir := path.Dir(file2.Path)
-	obj, err := remote.NewObject(dir)
+	obj, err := remote.NewObject(context.Background(), dir)
 	assert.Nil(t, obj)
 	assert.NotNil(t, err)
 }
@@ -513,7 +514,7 @@ func TestFsCopy(t *testing.T) {
 
 	// do the copy
 	src := findObject(t, file1.Path)
-	dst, err := doCopy(src, file1Copy.Path)
+	dst, err := doCopy(context.Background(), src, file1Copy.Path)
 	if err == fs.ErrorCantCopy {
 		t.Skip("FS can't copy")
 	}
@@ -526,7 +527,7 @@ func TestFsCopy(t *testing.T) {
 	assert.Equal(t, file1Copy.Path, dst.Remote())
 
 	// Delete copy
-	err = dst.Remove()
+	err = dst.Remove(context.Background())
 	require.NoError(t, err)
 
 }
@@ -553,7 +554,7 @@ func TestFsMove(t *testing.T) {
 	file2Move.Path = "other.txt"
 	file2Move.WinPath = ""
 	src := findObject(t, file2.Path)
-	dst, err := doMove(src, file2Move.Path)
+	dst, err := doMove(context.Background(), src, file2Move.Path)
 	if err == fs.ErrorCantMove {
 		t.Skip("FS can't move")
 	}
@@ -568,7 +569,7 @@ func TestFsMove(t *testing.T) {
 	// Check conflict on "rename, then move"
 	file1Move.Path = "moveTest/other.txt"
 	src = findObject(t, file1.Path)
-	_, err = doMove(src, file1Move.Path)
+	_, err = doMove(context.Background(), src, file1Move.Path)
 	require.NoError(t, err)
 	fstest.CheckListing(t, remote, []fstest.Item{file1Move, file2Move})
 	// 1: moveTest/other.txt
@@ -576,14 +577,14 @@ func TestFsMove(t *testing.T) {
 
 	// Check conflict on "move, then rename"
 	src = findObject(t, file1Move.Path)
-	_, err = doMove(src, file1.Path)
+	_, err = doMove(context.Background(), src, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckListing(t, remote, []fstest.Item{file1, file2Move})
 	// 1: file name.txt
 	// 2: other.txt
 
 	src = findObject(t, file2Move.Path)
-	_, err = doMove(src, file2.Path)
+	_, err = doMove(context.Background(), src, file2.Path)
 	require.NoError(t, err)
 	fstest.CheckListing(t, remote, []fstest.Item{file1, file2})
 	// 1: file name.txt
@@ -611,7 +612,7 @@ func TestFsDirMove(t *testing.T) {
 	}
 
 	// Check it can't move onto itself
-	err := doDirMove(remote, "", "")
+	err := doDirMove(context.Background(), remote, "", "")
 	require.Equal(t, fs.ErrorDirExists, err)
 
 	// new remote
@@ -621,7 +622,7 @@ func TestFsDirMove(t *testing.T) {
 
 	const newName = "new_name/sub_new_name"
 	// try the move
-	err = newRemote.Features().DirMove(remote, "", newName)
+	err = newRemote.Features().DirMove(context.Background(), remote, "", newName)
 	require.NoError(t, err)
 
 	// check remotes
@@ -635,7 +636,7 @@ func TestFsDirMove(t *testing.T) {
 	fstest.CheckListing(t, newRemote, []fstest.Item{file2Copy, file1Copy})
 
 	// move it back
-	err = doDirMove(newRemote, newName, "")
+	err = doDirMove(context.Background(), newRemote, newName, "")
 	require.NoError(t, err)
 
 	// check remotes
@@ -646,7 +647,7 @@ func TestFsDirMove(t *testing.T) {
 // TestFsRmdirFull tests removing a non empty directory
 func TestFsRmdirFull(t *testing.T) {
 	skipIfNotOk(t)
-	err := remote.Rmdir("")
+	err := remote.Rmdir(context.Background(), "")
 	require.Error(t, err, "Expecting error on RMdir on non empty remote")
 }
 
@@ -676,7 +677,7 @@ func TestFsDirChangeNotify(t *testing.T) {
 		t.Skip("FS has no DirChangeNotify interface")
 	}
 
-	err := fs.Mkdir(remote, "dir")
+	err := fs.Mkdir(context.Background(), remote, "dir")
 	require.NoError(t, err)
 
 	changes := []string{}
@@ -685,7 +686,7 @@ func TestFsDirChangeNotify(t *testing.T) {
 	}, time.Second)
 	defer func() { close(quitChannel) }()
 
-	err = fs.Mkdir(remote, "dir/subdir")
+	err = fs.Mkdir(context.Background(), remote, "dir/subdir")
 	require.NoError(t, err)
 
 	time.Sleep(2 * time.Second)
@@ -693,6 +694,26 @@ func TestFsDirChangeNotify(t *testing.T) {
 	assert.Equal(t, []string{"dir"}, changes)
 }
 
+// TestFsPublicLink tests creating public links to a file
+func TestFsPublicLink(t *testing.T) {
+	skipIfNotOk(t)
+
+	// Check have PublicLink
+	doPublicLink := remote.Features().PublicLink
+	if doPublicLink == nil {
+		t.Skip("FS has no PublicLinker interface")
+	}
+
+	link1, err := doPublicLink(context.Background(), file1.Path, 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, "", link1, "Link should not be empty")
+
+	// Making a link to the same file again should work too
+	link2, err := doPublicLink(context.Background(), file1.Path, 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, "", link2, "Link should not be empty")
+}
+
 // TestObjectString tests the Object String method
 func TestObjectString(t *testing.T) {
 	skipIfNotOk(t)
@@ -757,7 +778,7 @@ func TestObjectSetModTime(t *testing.T) {
 	skipIfNotOk(t)
 	newModTime := fstest.Time("2011-12-13T14:15:16.999999999Z")
 	obj := findObject(t, file1.Path)
-	err := obj.SetModTime(newModTime)
+	err := obj.SetModTime(context.Background(), newModTime)
 	if err == fs.ErrorCantSetModTime || err == fs.ErrorCantSetModTimeWithoutDelete {
 		t.Log(err)
 		return
@@ -778,7 +799,7 @@ func TestObjectSize(t *testing.T) {
 
 // read the contents of an object as a string
 func readObject(t *testing.T, obj fs.Object, limit int64, options ...fs.OpenOption) string {
-	in, err := obj.Open(options...)
+	in, err := obj.Open(context.Background(), options...)
 	require.NoError(t, err)
 	var r io.Reader = in
 	if limit >= 0 {
@@ -823,7 +844,7 @@ func TestObjectUpdate(t *testing.T) {
 	file1.Size = int64(buf.Len())
 	obj := findObject(t, file1.Path)
 	obji := fs.NewStaticObjectInfo(file1.Path, file1.ModTime, int64(len(contents)), true, nil, obj.Fs())
-	err := obj.Update(in, obji)
+	err := obj.Update(context.Background(), in, obji)
 	require.NoError(t, err)
 	file1.Hashes = hash.Sums()
 
@@ -872,7 +893,7 @@ func TestFsIsFileNotFound(t *testing.T) {
 func TestObjectRemove(t *testing.T) {
 	skipIfNotOk(t)
 	obj := findObject(t, file1.Path)
-	err := obj.Remove()
+	err := obj.Remove(context.Background())
 	require.NoError(t, err)
 	fstest.CheckListing(t, remote, []fstest.Item{file2})
 }
@@ -901,7 +922,7 @@ again:
 
 	file.Size = -1
 	obji := fs.NewStaticObjectInfo(file.Path, file.ModTime, file.Size, true, nil, nil)
-	obj, err := remote.Features().PutStream(in, obji)
+	obj, err := remote.Features().PutStream(context.Background(), in, obji)
 	if err != nil {
 		// Retry if err returned a retry error
 		if fs.IsRetryError(err) && tries < maxTries {
@@ -925,7 +946,7 @@ again:
 func TestObjectPurge(t *testing.T) {
 	skipIfNotOk(t)
 	fstest.TestPurge(t, remote)
-	err := fs.Purge(remote)
+	err := fs.Purge(context.Background(), remote)
 	assert.Error(t, err, "Expecting error after on second purge")
 }
 
@@ -39,6 +39,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 // Run holds the remotes for a test run
@@ -127,7 +128,7 @@ func NewRun(t *testing.T) *Run {
 		*r = *oneRun
 		r.cleanRemote = func() {
 			var toDelete dirsToRemove
-			require.NoError(t, fs.Walk(r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+			require.NoError(t, fs.Walk(context.Background(), r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
 				if err != nil {
 					if err == fs.ErrorDirNotFound {
 						return nil
@@ -137,7 +138,7 @@ func NewRun(t *testing.T) *Run {
 				for _, entry := range entries {
 					switch x := entry.(type) {
 					case fs.Object:
-						err = x.Remove()
+						err = x.Remove(context.Background())
 						if err != nil {
 							t.Errorf("Error removing file %q: %v", x.Remote(), err)
 						}
@@ -149,7 +150,7 @@ func NewRun(t *testing.T) *Run {
 			}))
 			sort.Sort(toDelete)
 			for _, dir := range toDelete {
-				err := r.Fremote.Rmdir(dir)
+				err := r.Fremote.Rmdir(context.Background(), dir)
 				if err != nil {
 					t.Errorf("Error removing dir %q: %v", dir, err)
 				}
@@ -200,7 +201,7 @@ func (r *Run) WriteFile(filePath, content string, t time.Time) Item {
 
 // ForceMkdir creates the remote
 func (r *Run) ForceMkdir(f fs.Fs) {
-	err := f.Mkdir("")
+	err := f.Mkdir(context.Background(), "")
 	if err != nil {
 		r.Fatalf("Failed to mkdir %q: %v", f, err)
 	}
@@ -228,7 +229,7 @@ func (r *Run) WriteObjectTo(f fs.Fs, remote, content string, modTime time.Time,
 	for tries := 1; ; tries++ {
 		in := bytes.NewBufferString(content)
 		objinfo := fs.NewStaticObjectInfo(remote, modTime, int64(len(content)), true, nil, nil)
-		_, err := put(in, objinfo)
+		_, err := put(context.Background(), in, objinfo)
 		if err == nil {
 			break
 		}
@@ -261,7 +262,7 @@ func (r *Run) WriteBoth(remote, content string, modTime time.Time) Item {
 
 // CheckWithDuplicates does a test but allows duplicates
 func (r *Run) CheckWithDuplicates(t *testing.T, items ...Item) {
-	objects, size, err := fs.Count(r.Fremote)
+	objects, size, err := fs.Count(context.Background(), r.Fremote)
 	require.NoError(t, err)
 	assert.Equal(t, int64(len(items)), objects)
 	wantSize := int64(0)
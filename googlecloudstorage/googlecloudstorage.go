@@ -13,16 +13,24 @@ FIXME Patch/Delete/Get isn't working with files with spaces in - giving 404 erro
 */
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -30,8 +38,10 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/oauthutil"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/jwt"
 	"google.golang.org/api/googleapi"
 	storage "google.golang.org/api/storage/v1"
 )
@@ -41,8 +51,9 @@ const (
 	rcloneEncryptedClientSecret = "Uj7C9jGfb9gmeaV70Lh058cNkWvepr-Es9sBm0zdgil7JaOWF1VySw"
 	timeFormatIn                = time.RFC3339
 	timeFormatOut               = "2006-01-02T15:04:05.000000000Z07:00"
-	metaMtime                   = "mtime" // key to store mtime under in metadata
-	listChunks                  = 1000    // chunk size to read directory listings
+	metaMtime                   = "mtime"            // key to store mtime under in metadata
+	defaultLinkExpire           = 7 * 24 * time.Hour // how long a signed URL lasts by default
+	listChunks                  = 1000               // chunk size to read directory listings
 )
 
 var (
@@ -213,6 +224,7 @@ type Fs struct {
 	bucketACL     string           // used when creating new buckets
 	location      string           // location of new buckets
 	storageClass  string           // storage class of new buckets
+	jwtConfig     *jwt.Config      // service account credentials if set
 }
 
 // Object describes a storage object
@@ -223,9 +235,11 @@ type Object struct {
 	remote   string    // The remote path
 	url      string    // download path
 	md5sum   string    // The MD5Sum of the object
+	crc32c   string    // The CRC-32C of the object
 	bytes    int64     // Bytes in the object
 	modTime  time.Time // Modified time of the object
 	mimeType string
+	meta     fs.Metadata // Metadata other than the mtime - may be nil
 }
 
 // ------------------------------------------------------------
@@ -271,27 +285,29 @@ func parsePath(path string) (bucket, directory string, err error) {
 	return
 }
 
-func getServiceAccountClient(keyJsonfilePath string) (*http.Client, error) {
+func getServiceAccountClient(name, keyJsonfilePath string) (*http.Client, *jwt.Config, error) {
 	data, err := ioutil.ReadFile(os.ExpandEnv(keyJsonfilePath))
 	if err != nil {
-		return nil, errors.Wrap(err, "error opening credentials file")
+		return nil, nil, errors.Wrap(err, "error opening credentials file")
 	}
 	conf, err := google.JWTConfigFromJSON(data, storageConfig.Scopes...)
 	if err != nil {
-		return nil, errors.Wrap(err, "error processing credentials")
+		return nil, nil, errors.Wrap(err, "error processing credentials")
 	}
-	ctxWithSpecialClient := oauthutil.Context(fs.Config.Client())
-	return oauth2.NewClient(ctxWithSpecialClient, conf.TokenSource(ctxWithSpecialClient)), nil
+	ctxWithSpecialClient := oauthutil.Context(fs.Config.ClientRemote(name))
+	return oauth2.NewClient(ctxWithSpecialClient, conf.TokenSource(ctxWithSpecialClient)), conf, nil
 }
 
 // NewFs contstructs an Fs from the path, bucket:path
 func NewFs(name, root string) (fs.Fs, error) {
+	ctx := context.Background()
 	var oAuthClient *http.Client
+	var jwtConfig *jwt.Config
 	var err error
 
 	serviceAccountPath := fs.ConfigFileGet(name, "service_account_file")
 	if serviceAccountPath != "" {
-		oAuthClient, err = getServiceAccountClient(serviceAccountPath)
+		oAuthClient, jwtConfig, err = getServiceAccountClient(name, serviceAccountPath)
 		if err != nil {
 			log.Fatalf("Failed configuring Google Cloud Storage Service Account: %v", err)
 		}
@@ -316,11 +332,16 @@ func NewFs(name, root string) (fs.Fs, error) {
 		bucketACL:     fs.ConfigFileGet(name, "bucket_acl"),
 		location:      fs.ConfigFileGet(name, "location"),
 		storageClass:  fs.ConfigFileGet(name, "storage_class"),
+		jwtConfig:     jwtConfig,
 	}
 	f.features = (&fs.Features{
-		ReadMimeType:  true,
-		WriteMimeType: true,
-		BucketBased:   true,
+		ReadMimeType:            true,
+		WriteMimeType:           true,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
+		BucketBased:             true,
+		ServerSideAcrossConfigs: fs.ConfigFileGetBool(name, "server_side_across_configs", false),
+		RangeRead:               true,
 	}).Fill(f)
 	if f.objectACL == "" {
 		f.objectACL = "private"
@@ -345,7 +366,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 	if f.root != "" {
 		f.root += "/"
 		// Check to see if the object exists
-		_, err = f.svc.Objects.Get(bucket, directory).Do()
+		_, err = f.svc.Objects.Get(bucket, directory).Context(ctx).Do()
 		if err == nil {
 			f.root = path.Dir(directory)
 			if f.root == "." {
@@ -363,7 +384,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 // Return an Object from a path
 //
 // If it can't be found it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *storage.Object) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *storage.Object) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -371,7 +392,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *storage.Object) (fs.Object,
 	if info != nil {
 		o.setMetaData(info)
 	} else {
-		err := o.readMetaData() // reads info and meta, returning an error
+		err := o.readMetaData(ctx) // reads info and meta, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -381,8 +402,8 @@ func (f *Fs) newObjectWithInfo(remote string, info *storage.Object) (fs.Object,
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // listFn is called from list to handle an object.
@@ -393,7 +414,7 @@ type listFn func(remote string, object *storage.Object, isDirectory bool) error
 // dir is the starting directory, "" for root
 //
 // Set recurse to read sub directories
-func (f *Fs) list(dir string, recurse bool, fn listFn) error {
+func (f *Fs) list(ctx context.Context, dir string, recurse bool, fn listFn) error {
 	root := f.root
 	rootLength := len(root)
 	if dir != "" {
@@ -404,7 +425,7 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 		list = list.Delimiter("/")
 	}
 	for {
-		objects, err := list.Do()
+		objects, err := list.Context(ctx).Do()
 		if err != nil {
 			if gErr, ok := err.(*googleapi.Error); ok {
 				if gErr.Code == http.StatusNotFound {
@@ -445,12 +466,12 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 }
 
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(remote string, object *storage.Object, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *storage.Object, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		d := fs.NewDir(remote, time.Time{}).SetSize(int64(object.Size))
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object)
 	if err != nil {
 		return nil, err
 	}
@@ -458,10 +479,10 @@ func (f *Fs) itemToDirEntry(remote string, object *storage.Object, isDirectory b
 }
 
 // listDir lists a single directory
-func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listDir(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	// List the objects
-	err = f.list(dir, false, func(remote string, object *storage.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, false, func(remote string, object *storage.Object, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -477,7 +498,7 @@ func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
 }
 
 // listBuckets lists the buckets
-func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listBuckets(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if dir != "" {
 		return nil, fs.ErrorListBucketRequired
 	}
@@ -486,7 +507,7 @@ func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
 	}
 	listBuckets := f.svc.Buckets.List(f.projectNumber).MaxResults(listChunks)
 	for {
-		buckets, err := listBuckets.Do()
+		buckets, err := listBuckets.Context(ctx).Do()
 		if err != nil {
 			return nil, err
 		}
@@ -511,11 +532,11 @@ func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.bucket == "" {
-		return f.listBuckets(dir)
+		return f.listBuckets(ctx, dir)
 	}
-	return f.listDir(dir)
+	return f.listDir(ctx, dir)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -534,13 +555,13 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.bucket == "" {
 		return fs.ErrorListBucketRequired
 	}
 	list := fs.NewListRHelper(callback)
-	err = f.list(dir, true, func(remote string, object *storage.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, true, func(remote string, object *storage.Object, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -557,28 +578,28 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	o := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return o, o.Update(in, src, options...)
+	return o, o.Update(ctx, in, src, options...)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Mkdir creates the bucket if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.bucketOK {
 		return nil
 	}
-	_, err := f.svc.Buckets.Get(f.bucket).Do()
+	_, err := f.svc.Buckets.Get(f.bucket).Context(ctx).Do()
 	if err == nil {
 		// Bucket already exists
 		f.bucketOK = true
@@ -600,7 +621,7 @@ func (f *Fs) Mkdir(dir string) error {
 		Location:     f.location,
 		StorageClass: f.storageClass,
 	}
-	_, err = f.svc.Buckets.Insert(f.projectNumber, &bucket).PredefinedAcl(f.bucketACL).Do()
+	_, err = f.svc.Buckets.Insert(f.projectNumber, &bucket).PredefinedAcl(f.bucketACL).Context(ctx).Do()
 	if err == nil {
 		f.bucketOK = true
 	}
@@ -611,13 +632,13 @@ func (f *Fs) Mkdir(dir string) error {
 //
 // Returns an error if it isn't empty: Error 409: The bucket you tried
 // to delete was not empty.
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.root != "" || dir != "" {
 		return nil
 	}
-	err := f.svc.Buckets.Delete(f.bucket).Do()
+	err := f.svc.Buckets.Delete(f.bucket).Context(ctx).Do()
 	if err == nil {
 		f.bucketOK = false
 	}
@@ -635,11 +656,13 @@ func (f *Fs) Precision() time.Duration {
 //
 // It returns the destination Object and a possible error
 //
-// Will only be called if src.Fs().Name() == f.Name()
+// Will only be called if src.Fs().Name() == f.Name() or if
+// server_side_across_configs is set and src is another google cloud
+// storage remote
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -659,7 +682,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	srcObject := srcObj.fs.root + srcObj.remote
 	dstBucket := f.bucket
 	dstObject := f.root + remote
-	newObject, err := f.svc.Objects.Copy(srcBucket, srcObject, dstBucket, dstObject, nil).Do()
+	newObject, err := f.svc.Objects.Copy(srcBucket, srcObject, dstBucket, dstObject, nil).Context(ctx).Do()
 	if err != nil {
 		return nil, err
 	}
@@ -668,9 +691,65 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	return dstObj, nil
 }
 
+// parsePrivateKey parses the PEM encoded RSA key from the service
+// account credentials
+func parsePrivateKey(key []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(key)
+	if block != nil {
+		key = block.Bytes
+	}
+	parsedKey, err := x509.ParsePKCS8PrivateKey(key)
+	if err != nil {
+		parsedKey, err = x509.ParsePKCS1PrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "private key should be a PEM or plain PKCS1 or PKCS8")
+		}
+	}
+	parsed, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is invalid")
+	}
+	return parsed, nil
+}
+
+// PublicLink generates a signed URL for the object at remote which
+// lasts for expire, or 7 days if expire is 0
+//
+// This needs service account credentials to sign the URL with.
+func (f *Fs) PublicLink(ctx context.Context, remote string, expire time.Duration) (string, error) {
+	if f.jwtConfig == nil {
+		return "", errors.New("signed URLs need service_account_file to be set")
+	}
+	if expire <= 0 {
+		expire = defaultLinkExpire
+	}
+	// Check the object exists
+	_, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+	key, err := parsePrivateKey(f.jwtConfig.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	resource := (&url.URL{Path: "/" + f.bucket + "/" + f.root + remote}).EscapedPath()
+	expires := strconv.FormatInt(time.Now().Add(expire).Unix(), 10)
+	toSign := "GET\n\n\n" + expires + "\n" + resource
+	digest := sha256.Sum256([]byte(toSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign URL")
+	}
+	query := url.Values{}
+	query.Set("GoogleAccessId", f.jwtConfig.Email)
+	query.Set("Expires", expires)
+	query.Set("Signature", base64.StdEncoding.EncodeToString(signature))
+	return "https://storage.googleapis.com" + resource + "?" + query.Encode(), nil
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() fs.HashSet {
-	return fs.HashSet(fs.HashMD5)
+	return fs.NewHashSet(fs.HashMD5, fs.HashCRC32C)
 }
 
 // ------------------------------------------------------------
@@ -693,12 +772,16 @@ func (o *Object) Remote() string {
 	return o.remote
 }
 
-// Hash returns the Md5sum of an object returning a lowercase hex string
+// Hash returns the Md5sum or CRC-32C of an object returning a
+// lowercase hex string
 func (o *Object) Hash(t fs.HashType) (string, error) {
-	if t != fs.HashMD5 {
-		return "", fs.ErrHashUnsupported
+	switch t {
+	case fs.HashMD5:
+		return o.md5sum, nil
+	case fs.HashCRC32C:
+		return o.crc32c, nil
 	}
-	return o.md5sum, nil
+	return "", fs.ErrHashUnsupported
 }
 
 // Size returns the size of an object in bytes
@@ -712,6 +795,25 @@ func (o *Object) setMetaData(info *storage.Object) {
 	o.bytes = int64(info.Size)
 	o.mimeType = info.ContentType
 
+	// Read the metadata and the standard headers
+	o.meta = nil
+	for k, v := range info.Metadata {
+		if k != metaMtime {
+			o.meta.Set(k, v)
+		}
+	}
+	for k, v := range map[string]string{
+		"content-type":        info.ContentType,
+		"cache-control":       info.CacheControl,
+		"content-disposition": info.ContentDisposition,
+		"content-encoding":    info.ContentEncoding,
+		"content-language":    info.ContentLanguage,
+	} {
+		if v != "" {
+			o.meta.Set(k, v)
+		}
+	}
+
 	// Read md5sum
 	md5sumData, err := base64.StdEncoding.DecodeString(info.Md5Hash)
 	if err != nil {
@@ -720,6 +822,14 @@ func (o *Object) setMetaData(info *storage.Object) {
 		o.md5sum = hex.EncodeToString(md5sumData)
 	}
 
+	// Read crc32c - this is big endian so is the same as the hex
+	crc32cData, err := base64.StdEncoding.DecodeString(info.Crc32c)
+	if err != nil {
+		fs.Logf(o, "Bad CRC-32C decode: %v", err)
+	} else {
+		o.crc32c = hex.EncodeToString(crc32cData)
+	}
+
 	// read mtime out of metadata if available
 	mtimeString, ok := info.Metadata[metaMtime]
 	if ok {
@@ -743,11 +853,11 @@ func (o *Object) setMetaData(info *storage.Object) {
 // readMetaData gets the metadata if it hasn't already been fetched
 //
 // it also sets the info
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if !o.modTime.IsZero() {
 		return nil
 	}
-	object, err := o.fs.svc.Objects.Get(o.fs.bucket, o.fs.root+o.remote).Do()
+	object, err := o.fs.svc.Objects.Get(o.fs.bucket, o.fs.root+o.remote).Context(ctx).Do()
 	if err != nil {
 		if gErr, ok := err.(*googleapi.Error); ok {
 			if gErr.Code == http.StatusNotFound {
@@ -765,7 +875,7 @@ func (o *Object) readMetaData() (err error) {
 // It attempts to read the objects mtime and if that isn't present the
 // LastModified returned in the http headers
 func (o *Object) ModTime() time.Time {
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		// fs.Logf(o, "Failed to read metadata: %v", err)
 		return time.Now()
@@ -781,14 +891,14 @@ func metadataFromModTime(modTime time.Time) map[string]string {
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	// This only adds metadata so will perserve other metadata
 	object := storage.Object{
 		Bucket:   o.fs.bucket,
 		Name:     o.fs.root + o.remote,
 		Metadata: metadataFromModTime(modTime),
 	}
-	newObject, err := o.fs.svc.Objects.Patch(o.fs.bucket, o.fs.root+o.remote, &object).Do()
+	newObject, err := o.fs.svc.Objects.Patch(o.fs.bucket, o.fs.root+o.remote, &object).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
@@ -802,7 +912,7 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	req, err := http.NewRequest("GET", o.url, nil)
 	if err != nil {
 		return nil, err
@@ -823,8 +933,8 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 // Update the object with the contents of the io.Reader, modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	err := o.fs.Mkdir("")
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	err := o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
@@ -837,7 +947,25 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		Updated:     modTime.Format(timeFormatOut), // Doesn't get set
 		Metadata:    metadataFromModTime(modTime),
 	}
-	newObject, err := o.fs.svc.Objects.Insert(o.fs.bucket, &object).Media(in, googleapi.ContentType("")).Name(object.Name).PredefinedAcl(o.fs.objectACL).Do()
+	for k, v := range fs.GetMetadataOptions(options) {
+		switch k {
+		case "content-type":
+			object.ContentType = v
+		case "cache-control":
+			object.CacheControl = v
+		case "content-disposition":
+			object.ContentDisposition = v
+		case "content-encoding":
+			object.ContentEncoding = v
+		case "content-language":
+			object.ContentLanguage = v
+		case metaMtime:
+			// the modification time is set from src
+		default:
+			object.Metadata[k] = v
+		}
+	}
+	newObject, err := o.fs.svc.Objects.Insert(o.fs.bucket, &object).Media(in, googleapi.ContentType("")).Name(object.Name).PredefinedAcl(o.fs.objectACL).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
@@ -847,8 +975,8 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove an object
-func (o *Object) Remove() error {
-	return o.fs.svc.Objects.Delete(o.fs.bucket, o.fs.root+o.remote).Do()
+func (o *Object) Remove(ctx context.Context) error {
+	return o.fs.svc.Objects.Delete(o.fs.bucket, o.fs.root+o.remote).Context(ctx).Do()
 }
 
 // MimeType of an Object if known, "" otherwise
@@ -856,12 +984,27 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the object metadata along with the standard HTTP headers.
+// The mtime is returned by ModTime so isn't included.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Merge(o.meta)
+	return metadata, nil
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.Copier      = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.ListRer     = &Fs{}
-	_ fs.Object      = &Object{}
-	_ fs.MimeTyper   = &Object{}
+	_ fs.Fs           = &Fs{}
+	_ fs.Copier       = &Fs{}
+	_ fs.PutStreamer  = &Fs{}
+	_ fs.ListRer      = &Fs{}
+	_ fs.PublicLinker = &Fs{}
+	_ fs.Object       = &Object{}
+	_ fs.MimeTyper    = &Object{}
+	_ fs.Metadataer   = &Object{}
 )
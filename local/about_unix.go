@@ -0,0 +1,39 @@
+// Quota reading functions
+
+// +build darwin dragonfly freebsd linux
+
+package local
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// About gets quota information
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	var s syscall.Statfs_t
+	err := syscall.Statfs(f.root, &s)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fs.ErrorDirNotFound
+		}
+		return nil, errors.Wrap(err, "failed to read disk usage")
+	}
+	bs := int64(s.Bsize)
+	total := bs * int64(s.Blocks)
+	free := bs * int64(s.Bavail)
+	used := bs * (int64(s.Blocks) - int64(s.Bfree))
+	usage := &fs.Usage{
+		Total: &total, // quota of bytes that can be used
+		Used:  &used,  // bytes in use
+		Free:  &free,  // bytes which can be uploaded before reaching the quota
+	}
+	return usage, nil
+}
+
+// check interface
+var _ fs.Abouter = &Fs{}
@@ -0,0 +1,44 @@
+// Quota reading functions
+
+// +build windows
+
+package local
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+var getFreeDiskSpace = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// About gets quota information
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	var available, total, free int64
+	root, err := syscall.UTF16PtrFromString(f.root)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read disk usage")
+	}
+	_, _, e1 := getFreeDiskSpace.Call(
+		uintptr(unsafe.Pointer(root)),
+		uintptr(unsafe.Pointer(&available)), // lpFreeBytesAvailable - for this user
+		uintptr(unsafe.Pointer(&total)),     // lpTotalNumberOfBytes
+		uintptr(unsafe.Pointer(&free)),      // lpTotalNumberOfFreeBytes
+	)
+	if e1 != syscall.Errno(0) {
+		return nil, errors.Wrap(e1, "failed to read disk usage")
+	}
+	used := total - free
+	usage := &fs.Usage{
+		Total: &total,     // quota of bytes that can be used
+		Used:  &used,      // bytes in use
+		Free:  &available, // bytes which can be uploaded before reaching the quota
+	}
+	return usage, nil
+}
+
+// check interface
+var _ fs.Abouter = &Fs{}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 	"google.golang.org/appengine/log"
 )
 
@@ -98,6 +99,9 @@ func NewFs(name, root string) (fs.Fs, error) {
 	f.features = (&fs.Features{
 		CaseInsensitive:         f.caseInsensitive(),
 		CanHaveEmptyDirectories: true,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
+		RangeRead:               true,
 	}).Fill(f)
 	if *followSymlinks {
 		f.lstat = os.Stat
@@ -186,7 +190,7 @@ func (f *Fs) newObjectWithInfo(remote, dstPath string, info os.FileInfo) (fs.Obj
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	return f.newObjectWithInfo(remote, "", nil)
 }
 
@@ -199,7 +203,7 @@ func (f *Fs) NewObject(remote string) (fs.Object, error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	dir = f.dirNames.Load(dir)
 	fsDirPath := f.cleanPath(filepath.Join(f.root, dir))
 	remote := f.cleanRemote(dir)
@@ -319,24 +323,45 @@ func (m *mapper) Save(in, out string) string {
 }
 
 // Put the Object to the local filesystem
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	remote := src.Remote()
 	// Temporary Object under construction - info filled in by Update()
 	o := f.newObject(remote, "")
-	err := o.Update(in, src, options...)
+	err := o.Update(ctx, in, src, options...)
 	if err != nil {
 		return nil, err
 	}
 	return o, nil
 }
 
+// OpenWriterAt opens with a handle for random access writes
+//
+// Pass in the remote desired and the size if known.
+//
+// It truncates any existing object
+func (f *Fs) OpenWriterAt(ctx context.Context, remote string, size int64) (fs.WriterAtCloser, error) {
+	// Temporary Object under construction
+	o := f.newObject(remote, "")
+
+	err := o.mkdirAll()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := os.OpenFile(o.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Mkdir creates the directory if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	// FIXME: https://github.com/syncthing/syncthing/blob/master/lib/osutil/mkdirall_windows.go
 	root := f.cleanPath(filepath.Join(f.root, dir))
 	err := os.MkdirAll(root, 0777)
@@ -356,7 +381,7 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir removes the directory
 //
 // If it isn't empty it will return an error
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	root := f.cleanPath(filepath.Join(f.root, dir))
 	return os.Remove(root)
 }
@@ -426,7 +451,7 @@ func (f *Fs) readPrecision() (precision time.Duration) {
 // Optional interface: Only implement this if you have a way of
 // deleting all the files quicker than just running Remove() on the
 // result of List()
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	fi, err := f.lstat(f.root)
 	if err != nil {
 		return err
@@ -446,7 +471,7 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantMove
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
@@ -505,7 +530,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
@@ -570,20 +595,32 @@ func (o *Object) Hash(r fs.HashType) (string, error) {
 		o.hashes = nil
 	}
 
+	if hash, found := o.hashes[r]; found {
+		return hash, nil
+	}
+	// Calculate the default hashes together, but any other hash
+	// only when it is asked for as they are slower
+	hashes := fs.NewHashSet(r)
+	if fs.DefaultHashes.Contains(r) {
+		hashes = fs.DefaultHashes
+	}
+	in, err := os.Open(o.path)
+	if err != nil {
+		return "", errors.Wrap(err, "hash: failed to open")
+	}
+	sums, err := fs.HashStreamTypes(in, hashes)
+	closeErr := in.Close()
+	if err != nil {
+		return "", errors.Wrap(err, "hash: failed to read")
+	}
+	if closeErr != nil {
+		return "", errors.Wrap(closeErr, "hash: failed to close")
+	}
 	if o.hashes == nil {
 		o.hashes = make(map[fs.HashType]string)
-		in, err := os.Open(o.path)
-		if err != nil {
-			return "", errors.Wrap(err, "hash: failed to open")
-		}
-		o.hashes, err = fs.HashStream(in)
-		closeErr := in.Close()
-		if err != nil {
-			return "", errors.Wrap(err, "hash: failed to read")
-		}
-		if closeErr != nil {
-			return "", errors.Wrap(closeErr, "hash: failed to close")
-		}
+	}
+	for hashType, hash := range sums {
+		o.hashes[hashType] = hash
 	}
 	return o.hashes[r], nil
 }
@@ -599,7 +636,7 @@ func (o *Object) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	err := os.Chtimes(o.path, modTime, modTime)
 	if err != nil {
 		return err
@@ -667,14 +704,33 @@ func (file *localOpenFile) Close() (err error) {
 	return err
 }
 
+// localLimitedFile reads a limited amount from an open file
+type localLimitedFile struct {
+	io.Reader
+	io.Closer
+}
+
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
-	var offset int64
-	hashes := fs.SupportedHashes
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
+	var offset, limit int64 = 0, -1
+	hashes := fs.DefaultHashes
 	for _, option := range options {
 		switch x := option.(type) {
 		case *fs.SeekOption:
 			offset = x.Offset
+		case *fs.RangeOption:
+			if x.Start >= 0 {
+				offset = x.Start
+				if x.End >= 0 {
+					limit = x.End - x.Start + 1
+				}
+			} else if x.End >= 0 {
+				// read the last End bytes
+				offset = o.size - x.End
+				if offset < 0 {
+					offset = 0
+				}
+			}
 		case *fs.HashesOption:
 			hashes = x.Hashes
 		default:
@@ -688,11 +744,16 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	if err != nil {
 		return
 	}
-	if offset != 0 {
+	if offset != 0 || limit >= 0 {
 		// seek the object
 		_, err = fd.Seek(offset, 0)
+		if limit >= 0 {
+			in = &localLimitedFile{Reader: io.LimitReader(fd, limit), Closer: fd}
+		} else {
+			in = fd
+		}
 		// don't attempt to make checksums
-		return fd, err
+		return in, err
 	}
 	hash, err := fs.NewMultiHasherTypes(hashes)
 	if err != nil {
@@ -714,8 +775,8 @@ func (o *Object) mkdirAll() error {
 }
 
 // Update the object from in with modTime and size
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	hashes := fs.SupportedHashes
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	hashes := fs.DefaultHashes
 	for _, option := range options {
 		switch x := option.(type) {
 		case *fs.HashesOption:
@@ -757,11 +818,19 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	o.hashes = hash.Sums()
 
 	// Set the mtime
-	err = o.SetModTime(src.ModTime())
+	err = o.SetModTime(ctx, src.ModTime())
 	if err != nil {
 		return err
 	}
 
+	// Set the metadata if required
+	if metadata := fs.GetMetadataOptions(options); metadata != nil {
+		err = o.writeMetadata(metadata)
+		if err != nil {
+			return err
+		}
+	}
+
 	// ReRead info now that we have finished
 	return o.lstat()
 }
@@ -791,7 +860,7 @@ func (o *Object) lstat() error {
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	return os.Remove(o.path)
 }
 
@@ -918,10 +987,12 @@ func cleanWindowsName(f *Fs, name string) string {
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.Purger      = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.Mover       = &Fs{}
-	_ fs.DirMover    = &Fs{}
-	_ fs.Object      = &Object{}
+	_ fs.Fs             = &Fs{}
+	_ fs.Purger         = &Fs{}
+	_ fs.PutStreamer    = &Fs{}
+	_ fs.OpenWriterAter = &Fs{}
+	_ fs.Mover          = &Fs{}
+	_ fs.DirMover       = &Fs{}
+	_ fs.Object         = &Object{}
+	_ fs.Metadataer     = &Object{}
 )
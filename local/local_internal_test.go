@@ -1,9 +1,17 @@
 package local
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ncw/rclone/fs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 func TestMapper(t *testing.T) {
@@ -18,3 +26,118 @@ func TestMapper(t *testing.T) {
 	assert.Equal(t, "potato", m.Load("potato"))
 	assert.Equal(t, "-r?'a´o¨", m.Load("-r'áö"))
 }
+
+func TestOpenRangeAndWriterAt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-local-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	fs.LoadConfig()
+	f, err := NewFs("local", dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Write the file out of order with OpenWriterAt
+	w, err := f.(*Fs).OpenWriterAt(ctx, "sub/file.txt", 10)
+	require.NoError(t, err)
+	_, err = w.WriteAt([]byte("56789"), 5)
+	require.NoError(t, err)
+	_, err = w.WriteAt([]byte("01234"), 0)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	o, err := f.NewObject(ctx, "sub/file.txt")
+	require.NoError(t, err)
+	read := func(options ...fs.OpenOption) string {
+		in, err := o.Open(ctx, options...)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(in)
+		require.NoError(t, err)
+		require.NoError(t, in.Close())
+		return string(data)
+	}
+	assert.Equal(t, "0123456789", read())
+	assert.Equal(t, "234", read(&fs.RangeOption{Start: 2, End: 4}))
+	assert.Equal(t, "23456789", read(&fs.RangeOption{Start: 2, End: -1}))
+	assert.Equal(t, "789", read(&fs.RangeOption{Start: -1, End: 3}))
+	assert.Equal(t, "56789", read(&fs.SeekOption{Offset: 5}))
+}
+
+func TestHashOnlyWhenAsked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-local-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	fs.LoadConfig()
+	f, err := NewFs("local", dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0600))
+	fo, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	o := fo.(*Object)
+
+	// Only the default hashes are read together
+	md5sum, err := o.Hash(fs.HashMD5)
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", md5sum)
+	assert.Len(t, o.hashes, fs.DefaultHashes.Count())
+
+	// Others are read when asked for and remembered
+	sha256sum, err := o.Hash(fs.HashSHA256)
+	require.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha256sum)
+	assert.Len(t, o.hashes, fs.DefaultHashes.Count()+1)
+	assert.Equal(t, sha256sum, o.hashes[fs.HashSHA256])
+}
+
+func TestAbout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-local-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	fs.LoadConfig()
+	f, err := NewFs("local", dir)
+	require.NoError(t, err)
+	doAbout := f.Features().About
+	if doAbout == nil {
+		t.Skip("About not supported on this OS")
+	}
+	usage, err := doAbout(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, usage.Total)
+	require.NotNil(t, usage.Used)
+	require.NotNil(t, usage.Free)
+	assert.True(t, *usage.Total > 0)
+	assert.True(t, *usage.Used <= *usage.Total)
+	assert.True(t, *usage.Free <= *usage.Total)
+}
+
+func TestWriteMetadataInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-local-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	fs.LoadConfig()
+	f, err := NewFs("local", dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// invalid values are skipped rather than failing the upload
+	src := fs.NewStaticObjectInfo("file.txt", time.Now(), 5, true, nil, nil)
+	metadata := fs.MetadataOption{"mode": "potato", "uid": "-1", "gid": "carrot"}
+	o, err := f.Put(ctx, strings.NewReader("hello"), src, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), o.Size())
+
+	// valid values are still set
+	o, err = f.Put(ctx, strings.NewReader("hello"), src, fs.MetadataOption{"mode": "0600"})
+	require.NoError(t, err)
+	fi, err := os.Stat(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
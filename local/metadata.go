@@ -0,0 +1,58 @@
+// Object metadata
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// Metadata keys which are stored in the file system rather than as
+// extended attributes
+var systemMetadataKeys = map[string]bool{
+	"mode": true,
+	"uid":  true,
+	"gid":  true,
+}
+
+// Metadata returns metadata for an object
+//
+// This is the file mode, owner and any user extended attributes
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	info, err := o.fs.lstat(o.path)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Set("mode", fmt.Sprintf("%04o", info.Mode().Perm()))
+	readOwner(info, &metadata)
+	err = readXattrs(o.path, &metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read extended attributes")
+	}
+	return metadata, nil
+}
+
+// writeMetadata sets the metadata passed in on the file
+func (o *Object) writeMetadata(metadata fs.Metadata) error {
+	if mode, ok := metadata["mode"]; ok {
+		perm, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			fs.Logf(o, "Ignoring invalid mode %q in metadata: %v", mode, err)
+		} else {
+			err = os.Chmod(o.path, os.FileMode(perm)&os.ModePerm)
+			if err != nil {
+				return errors.Wrap(err, "failed to set mode")
+			}
+		}
+	}
+	err := writeOwner(o.path, metadata)
+	if err != nil {
+		return err
+	}
+	return writeXattrs(o.path, metadata)
+}
@@ -0,0 +1,22 @@
+// Object metadata functions
+
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package local
+
+import (
+	"os"
+
+	"github.com/ncw/rclone/fs"
+)
+
+// readOwner reads the uid and gid from a valid os.FileInfo into
+// metadata - not supported on this OS
+func readOwner(fi os.FileInfo, metadata *fs.Metadata) {
+}
+
+// writeOwner sets the uid and gid from metadata on path - not
+// supported on this OS
+func writeOwner(path string, metadata fs.Metadata) error {
+	return nil
+}
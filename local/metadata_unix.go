@@ -0,0 +1,58 @@
+// Object metadata functions
+
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package local
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// readOwner reads the uid and gid from a valid os.FileInfo into
+// metadata
+func readOwner(fi os.FileInfo, metadata *fs.Metadata) {
+	statT, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		fs.Debugf(fi.Name(), "Type assertion fi.Sys().(*syscall.Stat_t) failed from: %#v", fi.Sys())
+		return
+	}
+	metadata.Set("uid", strconv.FormatUint(uint64(statT.Uid), 10))
+	metadata.Set("gid", strconv.FormatUint(uint64(statT.Gid), 10))
+}
+
+// writeOwner sets the uid and gid from metadata on path
+//
+// Not being allowed to change the owner isn't an error as only root
+// can do that.
+func writeOwner(path string, metadata fs.Metadata) error {
+	uid, gid := -1, -1
+	for key, id := range map[string]*int{"uid": &uid, "gid": &gid} {
+		value, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 {
+			fs.Logf(path, "Ignoring invalid %s %q in metadata", key, value)
+			continue
+		}
+		*id = i
+	}
+	if uid < 0 && gid < 0 {
+		return nil
+	}
+	err := os.Lchown(path, uid, gid)
+	if os.IsPermission(err) {
+		fs.Debugf(path, "Not allowed to set owner: %v", err)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to set owner")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Extended attribute functions
+
+// +build linux
+
+package local
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// xattrPrefix is the namespace of the extended attributes which are
+// read and written as metadata
+const xattrPrefix = "user."
+
+// xattrUnsupported returns whether err means the file system doesn't
+// support extended attributes
+func xattrUnsupported(err error) bool {
+	return err == unix.ENOTSUP || err == unix.EOPNOTSUPP
+}
+
+// readXattrs reads the user extended attributes of path into
+// metadata without the "user." prefix
+func readXattrs(path string, metadata *fs.Metadata) error {
+	// Find the size of the list first
+	size, err := unix.Llistxattr(path, nil)
+	if xattrUnsupported(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to list")
+	}
+	if size == 0 {
+		return nil
+	}
+	list := make([]byte, size)
+	size, err = unix.Llistxattr(path, list)
+	if err != nil {
+		return errors.Wrap(err, "failed to list")
+	}
+	for _, name := range bytes.Split(list[:size], []byte{0}) {
+		attr := string(name)
+		if !strings.HasPrefix(attr, xattrPrefix) {
+			continue
+		}
+		size, err := unix.Lgetxattr(path, attr, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", attr)
+		}
+		value := make([]byte, size)
+		if size > 0 {
+			size, err = unix.Lgetxattr(path, attr, value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read %q", attr)
+			}
+		}
+		metadata.Set(strings.TrimPrefix(attr, xattrPrefix), string(value[:size]))
+	}
+	return nil
+}
+
+// writeXattrs writes the non system metadata as user extended
+// attributes on path
+func writeXattrs(path string, metadata fs.Metadata) error {
+	for _, key := range metadata.Keys() {
+		if systemMetadataKeys[key] {
+			continue
+		}
+		err := unix.Setxattr(path, xattrPrefix+key, []byte(metadata[key]), 0)
+		if xattrUnsupported(err) {
+			fs.Debugf(path, "Can't set extended attributes: %v", err)
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to set extended attribute %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+// Extended attribute functions
+
+// +build !linux
+
+package local
+
+import "github.com/ncw/rclone/fs"
+
+// readXattrs reads the user extended attributes of path into
+// metadata - not supported on this OS
+func readXattrs(path string, metadata *fs.Metadata) error {
+	return nil
+}
+
+// writeXattrs writes the non system metadata as user extended
+// attributes on path - not supported on this OS
+func writeXattrs(path string, metadata fs.Metadata) error {
+	return nil
+}
@@ -0,0 +1,171 @@
+// Package metrics keeps counters, gauges and histograms for rclone
+// and writes them out in the Prometheus text exposition format.
+//
+// Packages which want to export metrics make them with NewCounterVec,
+// NewGaugeVec or NewHistogramVec, or implement a Collector, and
+// register them with Register.  The remote control server serves the
+// output of Write on /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Type is the type of a metric
+type Type string
+
+// Metric types
+const (
+	TypeCounter   Type = "counter"
+	TypeGauge     Type = "gauge"
+	TypeHistogram Type = "histogram"
+)
+
+// Label is a name and value to partition a metric by
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric
+type Sample struct {
+	Suffix string  // added to the Family name, eg "_bucket"
+	Labels []Label // labels for this value
+	Value  float64
+}
+
+// Family is a metric and all its current values
+type Family struct {
+	Name    string // name of the metric, eg rclone_bytes_transferred_total
+	Help    string // one line description
+	Type    Type
+	Samples []Sample
+}
+
+// Collector is implemented by anything which can return the current
+// values of some metrics
+type Collector interface {
+	Collect() []Family
+}
+
+// CollectorFunc is an adapter to allow the use of ordinary functions
+// as a Collector.
+type CollectorFunc func() []Family
+
+// Collect calls f()
+func (f CollectorFunc) Collect() []Family {
+	return f()
+}
+
+// Registry holds a list of Collectors
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// NewRegistry makes a new empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a collector to the registry
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Gather collects all the metrics in the registry sorted by name
+func (r *Registry) Gather() (families []Family) {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+	for _, c := range collectors {
+		families = append(families, c.Collect()...)
+	}
+	sort.Stable(familiesByName(families))
+	return families
+}
+
+// Write writes all the metrics in the registry in the Prometheus text
+// format
+func (r *Registry) Write(w io.Writer) error {
+	for _, family := range r.Gather() {
+		_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", family.Name, escapeHelp(family.Help), family.Name, family.Type)
+		if err != nil {
+			return err
+		}
+		for _, sample := range family.Samples {
+			_, err = fmt.Fprintf(w, "%s%s%s %s\n", family.Name, sample.Suffix, formatLabels(sample.Labels), formatValue(sample.Value))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// familiesByName sorts a slice of Family by Name
+type familiesByName []Family
+
+func (fs familiesByName) Len() int           { return len(fs) }
+func (fs familiesByName) Swap(i, j int)      { fs[i], fs[j] = fs[j], fs[i] }
+func (fs familiesByName) Less(i, j int) bool { return fs[i].Name < fs[j].Name }
+
+// Default is the global registry
+var Default = NewRegistry()
+
+// Register adds a collector to the global registry
+func Register(c Collector) {
+	Default.Register(c)
+}
+
+// Write writes all the metrics in the global registry in the
+// Prometheus text format
+func Write(w io.Writer) error {
+	return Default.Write(w)
+}
+
+// ContentType is the MIME type of the output of Write
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	valueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// escapeHelp escapes the help text of a metric
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+// formatLabels formats labels as {name="value",...}
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var out []string
+	for _, label := range labels {
+		out = append(out, fmt.Sprintf(`%s="%s"`, label.Name, valueEscaper.Replace(label.Value)))
+	}
+	return "{" + strings.Join(out, ",") + "}"
+}
+
+// formatValue formats a value as Prometheus expects
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
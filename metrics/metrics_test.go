@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatValue(t *testing.T) {
+	for _, test := range []struct {
+		in   float64
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{0.25, "0.25"},
+		{1e21, "1e+21"},
+		{math.Inf(1), "+Inf"},
+		{math.Inf(-1), "-Inf"},
+		{math.NaN(), "NaN"},
+	} {
+		assert.Equal(t, test.want, formatValue(test.in), test.in)
+	}
+}
+
+func TestFormatLabels(t *testing.T) {
+	assert.Equal(t, "", formatLabels(nil))
+	assert.Equal(t, `{a="1",b="x\"y\\z\n"}`, formatLabels([]Label{{"a", "1"}, {"b", "x\"y\\z\n"}}))
+}
+
+func TestCounterVec(t *testing.T) {
+	c := NewCounterVec("test_total", "Test counter.", "host", "code")
+	c.Inc("b.com", "200")
+	c.Add(2, "a.com", "404")
+	c.Inc("b.com", "200")
+	assert.Equal(t, 2.0, c.Value("b.com", "200"))
+	assert.Equal(t, 0.0, c.Value("c.com", "200"))
+	assert.Panics(t, func() { c.Inc("a.com") })
+
+	assert.Equal(t, []Family{{
+		Name: "test_total",
+		Help: "Test counter.",
+		Type: TypeCounter,
+		Samples: []Sample{
+			{Labels: []Label{{"host", "a.com"}, {"code", "404"}}, Value: 2},
+			{Labels: []Label{{"host", "b.com"}, {"code", "200"}}, Value: 2},
+		},
+	}}, c.Collect())
+}
+
+func TestGaugeVec(t *testing.T) {
+	g := NewGaugeVec("test_in_progress", "Test gauge.", "host")
+	g.Inc("a.com")
+	g.Add(3, "a.com")
+	g.Dec("a.com")
+	g.Inc("b.com")
+	g.Dec("b.com")
+	assert.Equal(t, 3.0, g.Value("a.com"))
+	assert.Panics(t, func() { g.Inc() })
+
+	// no labels starts at 0
+	assert.Equal(t, []Sample{{Value: 0}}, NewGaugeVec("test_gauge", "Test gauge.").Collect()[0].Samples)
+
+	assert.Equal(t, []Family{{
+		Name: "test_in_progress",
+		Help: "Test gauge.",
+		Type: TypeGauge,
+		Samples: []Sample{
+			{Labels: []Label{{"host", "a.com"}}, Value: 3},
+			{Labels: []Label{{"host", "b.com"}}, Value: 0},
+		},
+	}}, g.Collect())
+}
+
+func TestHistogramVec(t *testing.T) {
+	h := NewHistogramVec("test_seconds", "Test histogram.", []float64{1, 2}, "host")
+	h.Observe(0.5, "a.com")
+	h.Observe(1.5, "a.com")
+	h.Observe(3, "a.com")
+
+	r := NewRegistry()
+	r.Register(h)
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf))
+	assert.Equal(t, `# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{host="a.com",le="1"} 1
+test_seconds_bucket{host="a.com",le="2"} 2
+test_seconds_bucket{host="a.com",le="+Inf"} 3
+test_seconds_sum{host="a.com"} 5
+test_seconds_count{host="a.com"} 3
+`, buf.String())
+}
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	c := NewCounterVec("b_total", "B counter.")
+	c.Add(3)
+	r.Register(c)
+	r.Register(CollectorFunc(func() []Family {
+		return []Family{{
+			Name:    "a_gauge",
+			Help:    "A gauge\nwith two lines.",
+			Type:    TypeGauge,
+			Samples: []Sample{{Value: 1.5}},
+		}}
+	}))
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf))
+	assert.Equal(t, `# HELP a_gauge A gauge\nwith two lines.
+# TYPE a_gauge gauge
+a_gauge 1.5
+# HELP b_total B counter.
+# TYPE b_total counter
+b_total 3
+`, buf.String())
+}
@@ -0,0 +1,270 @@
+// Counters and histograms partitioned by labels
+
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// labelSep separates the label values in the map keys
+const labelSep = "\xff"
+
+// vec holds the label names and the values seen for a metric
+type vec struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.Mutex
+}
+
+// labels turns the key back into a []Label
+func (v *vec) labels(key string) (labels []Label) {
+	if len(v.labelNames) == 0 {
+		return nil
+	}
+	for i, value := range strings.Split(key, labelSep) {
+		labels = append(labels, Label{Name: v.labelNames[i], Value: value})
+	}
+	return labels
+}
+
+// key makes the map key from the label values
+//
+// It panics if the wrong number of label values is passed in.
+func (v *vec) key(labelValues []string) string {
+	if len(labelValues) != len(v.labelNames) {
+		panic("metrics: " + v.name + ": wrong number of label values")
+	}
+	return strings.Join(labelValues, labelSep)
+}
+
+// collect makes a Family of type typ from the values passed in
+//
+// A metric without labels is reported as 0 until it has a value.
+//
+// Call with mu held.
+func (v *vec) collect(typ Type, values map[string]float64) []Family {
+	family := Family{
+		Name: v.name,
+		Help: v.help,
+		Type: typ,
+	}
+	if len(v.labelNames) == 0 && len(values) == 0 {
+		family.Samples = []Sample{{Value: 0}}
+		return []Family{family}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		family.Samples = append(family.Samples, Sample{
+			Labels: v.labels(key),
+			Value:  values[key],
+		})
+	}
+	return []Family{family}
+}
+
+// CounterVec is a set of counters partitioned by label values
+type CounterVec struct {
+	vec
+	values map[string]float64
+}
+
+// NewCounterVec makes a new CounterVec with the labelNames given
+//
+// By convention the name should end in _total.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		vec: vec{
+			name:       name,
+			help:       help,
+			labelNames: labelNames,
+		},
+		values: make(map[string]float64),
+	}
+}
+
+// Add adds value to the counter with the label values given
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	key := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] += value
+}
+
+// Inc adds one to the counter with the label values given
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Value returns the value of the counter with the label values given
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	key := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+// Collect returns the values of the counters - satisfies Collector
+func (c *CounterVec) Collect() []Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.collect(TypeCounter, c.values)
+}
+
+// GaugeVec is a set of gauges partitioned by label values
+type GaugeVec struct {
+	vec
+	values map[string]float64
+}
+
+// NewGaugeVec makes a new GaugeVec with the labelNames given
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		vec: vec{
+			name:       name,
+			help:       help,
+			labelNames: labelNames,
+		},
+		values: make(map[string]float64),
+	}
+}
+
+// Add adds value, which may be negative, to the gauge with the label
+// values given
+func (g *GaugeVec) Add(value float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[key] += value
+}
+
+// Inc adds one to the gauge with the label values given
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec subtracts one from the gauge with the label values given
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Value returns the value of the gauge with the label values given
+func (g *GaugeVec) Value(labelValues ...string) float64 {
+	key := g.key(labelValues)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.values[key]
+}
+
+// Collect returns the values of the gauges - satisfies Collector
+func (g *GaugeVec) Collect() []Family {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.collect(TypeGauge, g.values)
+}
+
+// DefaultBuckets are the default upper bounds for a HistogramVec
+// suitable for measuring the latency of network calls in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// histogram is the value of one histogram in a HistogramVec
+type histogram struct {
+	counts []uint64 // number of observations <= each bucket
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is a set of histograms partitioned by label values
+type HistogramVec struct {
+	vec
+	buckets []float64
+	values  map[string]*histogram
+}
+
+// NewHistogramVec makes a new HistogramVec with the labelNames given
+//
+// buckets are the upper bounds of the buckets in increasing order -
+// if nil DefaultBuckets will be used.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return &HistogramVec{
+		vec: vec{
+			name:       name,
+			help:       help,
+			labelNames: labelNames,
+		},
+		buckets: buckets,
+		values:  make(map[string]*histogram),
+	}
+}
+
+// Observe adds value to the histogram with the label values given
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hist, found := h.values[key]
+	if !found {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+// Collect returns the values of the histograms - satisfies Collector
+func (h *HistogramVec) Collect() []Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	family := Family{
+		Name: h.name,
+		Help: h.help,
+		Type: TypeHistogram,
+	}
+	keys := make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hist := h.values[key]
+		labels := h.labels(key)
+		for i, upperBound := range h.buckets {
+			family.Samples = append(family.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: append(labels[:len(labels):len(labels)], Label{Name: "le", Value: formatValue(upperBound)}),
+				Value:  float64(hist.counts[i]),
+			})
+		}
+		family.Samples = append(family.Samples,
+			Sample{
+				Suffix: "_bucket",
+				Labels: append(labels[:len(labels):len(labels)], Label{Name: "le", Value: "+Inf"}),
+				Value:  float64(hist.count),
+			},
+			Sample{Suffix: "_sum", Labels: labels, Value: hist.sum},
+			Sample{Suffix: "_count", Labels: labels, Value: float64(hist.count)},
+		)
+	}
+	return []Family{family}
+}
+
+// Check interfaces
+var (
+	_ Collector = (*CounterVec)(nil)
+	_ Collector = (*GaugeVec)(nil)
+	_ Collector = (*HistogramVec)(nil)
+)
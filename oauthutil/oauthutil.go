@@ -253,7 +253,7 @@ func NewClientWithBaseClient(name string, config *oauth2.Config, baseClient *htt
 // NewClient gets a token from the config file and configures a Client
 // with it.  It returns the client and a TokenSource which Invalidate may need to be called on
 func NewClient(name string, config *oauth2.Config) (*http.Client, *TokenSource, error) {
-	return NewClientWithBaseClient(name, config, fs.Config.Client())
+	return NewClientWithBaseClient(name, config, fs.Config.ClientRemote(name))
 }
 
 // Config does the initial creation of the token
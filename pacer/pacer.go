@@ -7,8 +7,23 @@ import (
 	"time"
 
 	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/metrics"
+	"golang.org/x/net/context"
 )
 
+// Metrics for all the pacers
+var (
+	callsMetric   = metrics.NewCounterVec("rclone_pacer_calls_total", "Number of calls made through the pacers including retries.")
+	retriesMetric = metrics.NewCounterVec("rclone_pacer_retries_total", "Number of calls made through the pacers which needed a low level retry.")
+	sleepMetric   = metrics.NewCounterVec("rclone_pacer_sleep_seconds_total", "Total time the pacers have slept between calls.")
+)
+
+func init() {
+	metrics.Register(callsMetric)
+	metrics.Register(retriesMetric)
+	metrics.Register(sleepMetric)
+}
+
 // Pacer state
 type Pacer struct {
 	mu                 sync.Mutex    // Protecting read/writes
@@ -194,18 +209,38 @@ func (p *Pacer) SetPacer(t Type) *Pacer {
 //
 // This must be called as a pair with endCall
 //
-// This waits for the pacer token
-func (p *Pacer) beginCall() {
+// This waits for the pacer token.  It returns an error without
+// taking a token if ctx is cancelled while waiting.
+func (p *Pacer) beginCall(ctx context.Context) error {
 	// pacer starts with a token in and whenever we take one out
 	// XXX ms later we put another in.  We could do this with a
 	// Ticker more accurately, but then we'd have to work out how
 	// not to run it when it wasn't needed
-	<-p.pacer
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	start := time.Now()
+	select {
+	case <-p.pacer:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// Record how long we actually waited for the token which may be
+	// less than sleepTime if the caller was busy in between calls
+	slept := time.Since(start)
 	if p.maxConnections > 0 {
-		<-p.connTokens
+		select {
+		case <-p.connTokens:
+		case <-ctx.Done():
+			// give the pacer token back
+			p.pacer <- struct{}{}
+			return ctx.Err()
+		}
 	}
 
 	p.mu.Lock()
+	callsMetric.Inc()
+	sleepMetric.Add(slept.Seconds())
 	// Restart the timer
 	go func(t time.Duration) {
 		// fs.Debugf(f, "New sleep for %v at %v", t, time.Now())
@@ -213,6 +248,7 @@ func (p *Pacer) beginCall() {
 		p.pacer <- struct{}{}
 	}(p.sleepTime)
 	p.mu.Unlock()
+	return nil
 }
 
 // exponentialImplementation implements a exponentialImplementation up
@@ -318,6 +354,7 @@ func (p *Pacer) endCall(retry bool) {
 	}
 	p.mu.Lock()
 	if retry {
+		retriesMetric.Inc()
 		p.consecutiveRetries++
 	} else {
 		p.consecutiveRetries = 0
@@ -327,10 +364,12 @@ func (p *Pacer) endCall(retry bool) {
 }
 
 // call implements Call but with settable retries
-func (p *Pacer) call(fn Paced, retries int) (err error) {
+func (p *Pacer) call(ctx context.Context, fn Paced, retries int) (err error) {
 	var retry bool
 	for i := 1; i <= retries; i++ {
-		p.beginCall()
+		if ctxErr := p.beginCall(ctx); ctxErr != nil {
+			return ctxErr
+		}
 		retry, err = fn()
 		p.endCall(retry)
 		if !retry {
@@ -350,11 +389,14 @@ func (p *Pacer) call(fn Paced, retries int) (err error) {
 // This calls fn, expecting it to return a retry flag and an
 // error. This error may be returned wrapped in a RetryError if the
 // number of retries is exceeded.
-func (p *Pacer) Call(fn Paced) (err error) {
+//
+// If ctx is cancelled then no further calls or retries of fn will be
+// made and the context error will be returned.
+func (p *Pacer) Call(ctx context.Context, fn Paced) (err error) {
 	p.mu.Lock()
 	retries := p.retries
 	p.mu.Unlock()
-	return p.call(fn, retries)
+	return p.call(ctx, fn, retries)
 }
 
 // CallNoRetry paces the remote operations to not exceed the limits
@@ -362,6 +404,6 @@ func (p *Pacer) Call(fn Paced) (err error) {
 //
 // This calls fn and wraps the output in a RetryError if it would like
 // it to be retried
-func (p *Pacer) CallNoRetry(fn Paced) error {
-	return p.call(fn, 1)
+func (p *Pacer) CallNoRetry(ctx context.Context, fn Paced) error {
+	return p.call(ctx, fn, 1)
 }
@@ -0,0 +1,140 @@
+// Define the internal rc functions
+
+package rc
+
+import (
+	"sort"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+func init() {
+	Add(Call{
+		Path:  "rc/noop",
+		Fn:    rcNoop,
+		Title: "Echo the input to the output parameters",
+		Help: `
+This echoes the input parameters to the output parameters for testing
+purposes.  It can be used to check that rclone is still alive and to
+check that parameter passing is working properly.`,
+	})
+	Add(Call{
+		Path:  "rc/error",
+		Fn:    rcError,
+		Title: "This returns an error",
+		Help: `
+This returns an error with the input as part of its error string.
+Useful for testing error handling.`,
+	})
+	Add(Call{
+		Path:  "rc/list",
+		Fn:    rcList,
+		Title: "List all the registered remote control commands",
+		Help: `
+This lists all the registered remote control commands as a JSON map in
+the commands response.`,
+	})
+	Add(Call{
+		Path:  "core/stats",
+		Fn:    rcStats,
+		Title: "Returns stats about current transfers.",
+		Help: `
+This returns all available stats
+
+    rclone rc core/stats
+
+Returns the following values:
+
+    "bytes" - total transferred bytes
+    "speed" - average speed in bytes/sec
+    "errors" - number of errors
+    "lastError" - the last error string, if any
+    "checks" - number of checked files
+    "transfers" - number of transferred files
+    "deletes" - number of deleted files
+    "elapsedTime" - time in seconds since the start
+    "checking" - an array of names of currently active file checks
+    "transferring" - an array of currently active file transfers
+
+Each entry in "transferring" has the following values:
+
+    "name" - name of the file being transferred
+    "bytes" - bytes transferred so far
+    "size" - size of the file
+    "speed" - current speed in bytes/sec
+    "speedAvg" - exponentially weighted moving average of the speed
+    "percentage" - percentage of the file transferred
+    "eta" - estimated time to completion in seconds, if known`,
+	})
+	Add(Call{
+		Path:  "core/bwlimit",
+		Fn:    rcBwlimit,
+		Title: "Set the bandwidth limit.",
+		Help: `
+This sets the bandwidth limit to that passed in, eg
+
+    rclone rc core/bwlimit rate=1M
+    rclone rc core/bwlimit rate=off
+
+The rate is specified in the same way as --bwlimit without a
+timetable.  Use "off" to remove the limit.`,
+	})
+	Add(Call{
+		Path:  "config/listremotes",
+		Fn:    rcListRemotes,
+		Title: "Lists the remotes in the config file.",
+		Help: `
+Returns
+- remotes - array of remote names`,
+	})
+}
+
+// Echo the input to the output parameters
+func rcNoop(ctx context.Context, in Params) (out Params, err error) {
+	return in, nil
+}
+
+// Return an error regardless
+func rcError(ctx context.Context, in Params) (out Params, err error) {
+	return nil, errors.Errorf("arbitrary error on input %+v", in)
+}
+
+// List the registered commands
+func rcList(ctx context.Context, in Params) (out Params, err error) {
+	out = make(Params)
+	out["commands"] = Calls.List()
+	return out, nil
+}
+
+// Return the stats
+func rcStats(ctx context.Context, in Params) (out Params, err error) {
+	return Params(fs.Stats.RemoteStats()), nil
+}
+
+// Set the bandwidth limit
+func rcBwlimit(ctx context.Context, in Params) (out Params, err error) {
+	rate, err := in.GetString("rate")
+	if err != nil {
+		return nil, err
+	}
+	var bandwidth fs.SizeSuffix
+	err = bandwidth.Set(rate)
+	if err != nil {
+		return nil, ErrParamInvalid{errors.Wrap(err, "bad rate")}
+	}
+	fs.SetBwLimit(bandwidth)
+	out = make(Params)
+	out["rate"] = bandwidth.String()
+	return out, nil
+}
+
+// List the remotes in the config file
+func rcListRemotes(ctx context.Context, in Params) (out Params, err error) {
+	remotes := fs.ConfigFileSections()
+	sort.Strings(remotes)
+	out = make(Params)
+	out["remotes"] = remotes
+	return out, nil
+}
@@ -0,0 +1,329 @@
+// Manage background jobs that the rc is running
+
+package rc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+// Job describes an asynchronous task started via the rc package
+type Job struct {
+	mu        sync.Mutex
+	ID        int64         // unique ID of the job
+	StartTime time.Time     // when the job was started
+	EndTime   time.Time     // when the job finished, zero if running
+	Error     string        // error string if the job failed
+	Finished  bool          // set when the job has finished
+	Success   bool          // set if the job finished without error
+	Output    Params        // output of the job if it finished
+	Stats     *fs.StatsInfo // stats for the transfers done by this job
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// Jobs describes a collection of running tasks
+type Jobs struct {
+	mu            sync.RWMutex
+	jobs          map[int64]*Job
+	opt           *Options
+	expireRunning bool
+}
+
+var (
+	running = newJobs()
+	jobID   = int64(0)
+)
+
+// newJobs makes a new Jobs structure
+func newJobs() *Jobs {
+	return &Jobs{
+		jobs: map[int64]*Job{},
+		opt:  &DefaultOpt,
+	}
+}
+
+// setOpt sets the options when they are known
+func (jobs *Jobs) setOpt(opt *Options) {
+	jobs.mu.Lock()
+	jobs.opt = opt
+	jobs.mu.Unlock()
+}
+
+// kickExpire makes sure Expire is running
+func (jobs *Jobs) kickExpire() {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	if !jobs.expireRunning {
+		time.AfterFunc(jobs.opt.JobExpireInterval, jobs.Expire)
+		jobs.expireRunning = true
+	}
+}
+
+// Expire expires any jobs that haven't been collected
+func (jobs *Jobs) Expire() {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	now := time.Now()
+	for ID, job := range jobs.jobs {
+		job.mu.Lock()
+		if job.Finished && now.Sub(job.EndTime) > jobs.opt.JobExpireDuration {
+			delete(jobs.jobs, ID)
+		}
+		job.mu.Unlock()
+	}
+	if len(jobs.jobs) != 0 {
+		time.AfterFunc(jobs.opt.JobExpireInterval, jobs.Expire)
+		jobs.expireRunning = true
+	} else {
+		jobs.expireRunning = false
+	}
+}
+
+// IDs returns the IDs of the running jobs sorted
+func (jobs *Jobs) IDs() (IDs []int64) {
+	jobs.mu.RLock()
+	defer jobs.mu.RUnlock()
+	IDs = []int64{}
+	for ID := range jobs.jobs {
+		IDs = append(IDs, ID)
+	}
+	sort.Sort(int64s(IDs))
+	return IDs
+}
+
+// int64s sorts a slice of int64
+type int64s []int64
+
+func (is int64s) Len() int           { return len(is) }
+func (is int64s) Swap(i, j int)      { is[i], is[j] = is[j], is[i] }
+func (is int64s) Less(i, j int) bool { return is[i] < is[j] }
+
+// Get a job with a given ID or nil if it doesn't exist
+func (jobs *Jobs) Get(ID int64) *Job {
+	jobs.mu.RLock()
+	defer jobs.mu.RUnlock()
+	return jobs.jobs[ID]
+}
+
+// add a job to the collection
+func (jobs *Jobs) add(job *Job) {
+	jobs.mu.Lock()
+	jobs.jobs[job.ID] = job
+	jobs.mu.Unlock()
+	jobs.kickExpire()
+}
+
+// NewJob starts a new Job running fn with the input parameters in
+// the background.
+//
+// The job runs with its own StatsInfo so its transfers are accounted
+// separately from other jobs and the global Stats.
+func (jobs *Jobs) NewJob(fn Func, in Params) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{
+		ID:        atomic.AddInt64(&jobID, 1),
+		StartTime: time.Now(),
+		Stats:     fs.NewStats(),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	ctx = fs.WithStats(ctx, job.Stats)
+	jobs.add(job)
+	go job.run(ctx, fn, in)
+	return job
+}
+
+// StartJob starts a new job running fn with the input parameters in
+// the background and returns it.
+func StartJob(fn Func, in Params) *Job {
+	return running.NewJob(fn, in)
+}
+
+// GetJob gets the running or recently finished job with the ID
+// given or nil if it doesn't exist
+func GetJob(ID int64) *Job {
+	return running.Get(ID)
+}
+
+// finish marks the job as finished
+func (job *Job) finish(out Params, err error) {
+	job.mu.Lock()
+	job.EndTime = time.Now()
+	if out == nil {
+		out = make(Params)
+	}
+	job.Output = out
+	if err != nil {
+		job.Error = err.Error()
+		job.Success = false
+	} else {
+		job.Error = ""
+		job.Success = true
+	}
+	job.Finished = true
+	job.mu.Unlock()
+	job.cancel()
+	close(job.done)
+}
+
+// run the job until completion writing the return status
+func (job *Job) run(ctx context.Context, fn Func, in Params) {
+	defer func() {
+		if r := recover(); r != nil {
+			job.finish(nil, errors.Errorf("panic received: %v", r))
+		}
+	}()
+	job.finish(fn(ctx, in))
+}
+
+// Stop cancels the job
+//
+// The job will finish, with an error, once the operations it is
+// running notice the cancellation.
+func (job *Job) Stop() {
+	job.cancel()
+}
+
+// Wait blocks until the job has finished
+func (job *Job) Wait() {
+	<-job.done
+}
+
+// String describes the job for logging
+func (job *Job) String() string {
+	return fmt.Sprintf("job %d", job.ID)
+}
+
+// Status returns the status of the job as Params suitable for the
+// remote control
+func (job *Job) Status() Params {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	out := Params{
+		"id":        job.ID,
+		"startTime": job.StartTime,
+		"finished":  job.Finished,
+		"success":   job.Success,
+		"error":     job.Error,
+		"stats":     job.Stats.RemoteStats(),
+	}
+	if job.Finished {
+		out["endTime"] = job.EndTime
+		out["duration"] = job.EndTime.Sub(job.StartTime).Seconds()
+		out["output"] = job.Output
+	} else {
+		out["duration"] = time.Since(job.StartTime).Seconds()
+	}
+	return out
+}
+
+func init() {
+	Add(Call{
+		Path:  "job/status",
+		Fn:    rcJobStatus,
+		Title: "Reads the status of the job ID",
+		Help: `Parameters
+- jobid - id of the job (integer)
+
+Results
+- finished - boolean
+- duration - time in seconds that the job ran for
+- endTime - time the job finished (eg "2018-01-01T17:16:09.180557+00:00")
+- error - error from the job or empty string for no error
+- id - as passed in above
+- startTime - time the job started (eg "2018-01-01T17:16:09.180557+00:00")
+- success - boolean - true for success false otherwise
+- output - output of the job as would have been returned if called synchronously
+- stats - the stats for the transfers in this job - see core/stats
+`,
+	})
+	Add(Call{
+		Path:  "job/list",
+		Fn:    rcJobList,
+		Title: "Lists the IDs of the running jobs",
+		Help: `Parameters - None
+
+Results
+- jobids - array of integer job ids
+`,
+	})
+	Add(Call{
+		Path:  "job/stop",
+		Fn:    rcJobStop,
+		Title: "Stop the running job",
+		Help: `Parameters
+- jobid - id of the job (integer)
+`,
+	})
+	Add(Call{
+		Path:  "job/wait",
+		Fn:    rcJobWait,
+		Title: "Wait for the job to finish and return its status",
+		Help: `Parameters
+- jobid - id of the job (integer)
+
+Results are the same as job/status.
+`,
+	})
+}
+
+// getJob finds the job from the jobid parameter
+func getJob(in Params) (*Job, error) {
+	jobID, err := in.GetInt64("jobid")
+	if err != nil {
+		return nil, err
+	}
+	job := GetJob(jobID)
+	if job == nil {
+		return nil, errors.New("job not found")
+	}
+	return job, nil
+}
+
+// Returns the status of a job
+func rcJobStatus(ctx context.Context, in Params) (out Params, err error) {
+	job, err := getJob(in)
+	if err != nil {
+		return nil, err
+	}
+	return job.Status(), nil
+}
+
+// Returns list of job ids.
+func rcJobList(ctx context.Context, in Params) (out Params, err error) {
+	out = make(Params)
+	out["jobids"] = running.IDs()
+	return out, nil
+}
+
+// Stops the job
+func rcJobStop(ctx context.Context, in Params) (out Params, err error) {
+	job, err := getJob(in)
+	if err != nil {
+		return nil, err
+	}
+	job.Stop()
+	return nil, nil
+}
+
+// Waits for the job to finish
+func rcJobWait(ctx context.Context, in Params) (out Params, err error) {
+	job, err := getJob(in)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return job.Status(), nil
+}
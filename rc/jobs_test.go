@@ -0,0 +1,136 @@
+package rc
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+// a job function which accounts some bytes then waits to be cancelled
+func jobWaitForCancel(ctx context.Context, in Params) (out Params, err error) {
+	stats := fs.StatsFromContext(ctx)
+	stats.Transferring("potato")
+	stats.Bytes(42)
+	<-ctx.Done()
+	stats.DoneTransferring("potato", false)
+	return nil, ctx.Err()
+}
+
+func TestJobSuccess(t *testing.T) {
+	job := StartJob(rcNoop, Params{"a": "potato"})
+	job.Wait()
+	assert.True(t, job.Finished)
+	assert.True(t, job.Success)
+	assert.Equal(t, "", job.Error)
+	assert.Equal(t, Params{"a": "potato"}, job.Output)
+	assert.False(t, job.EndTime.Before(job.StartTime))
+	assert.Equal(t, job, GetJob(job.ID))
+	assert.Contains(t, running.IDs(), job.ID)
+}
+
+func TestJobError(t *testing.T) {
+	job := StartJob(rcError, Params{})
+	job.Wait()
+	assert.True(t, job.Finished)
+	assert.False(t, job.Success)
+	assert.Contains(t, job.Error, "arbitrary error")
+}
+
+func TestJobPanic(t *testing.T) {
+	job := StartJob(func(ctx context.Context, in Params) (Params, error) {
+		panic("boom")
+	}, Params{})
+	job.Wait()
+	assert.False(t, job.Success)
+	assert.Contains(t, job.Error, "boom")
+}
+
+func TestJobStopAndStats(t *testing.T) {
+	globalBytes := fs.Stats.RemoteStats()["bytes"]
+	job := StartJob(jobWaitForCancel, Params{})
+
+	// Wait for the job to start transferring
+	for i := 0; i < 100 && job.Stats.RemoteStats()["transferring"] == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	status := job.Status()
+	assert.Equal(t, false, status["finished"])
+	stats := status["stats"].(map[string]interface{})
+	assert.Equal(t, int64(42), stats["bytes"])
+	transferring := stats["transferring"].([]map[string]interface{})
+	require.Equal(t, 1, len(transferring))
+	assert.Equal(t, "potato", transferring[0]["name"])
+
+	// The global stats should be untouched
+	assert.Equal(t, globalBytes, fs.Stats.RemoteStats()["bytes"])
+
+	job.Stop()
+	job.Wait()
+	assert.False(t, job.Success)
+	assert.Equal(t, context.Canceled.Error(), job.Error)
+}
+
+func TestJobExpire(t *testing.T) {
+	jobs := newJobs()
+	jobs.opt = &Options{
+		JobExpireDuration: time.Millisecond,
+		JobExpireInterval: time.Millisecond,
+	}
+	job := jobs.NewJob(rcNoop, Params{})
+	job.Wait()
+	time.Sleep(10 * time.Millisecond)
+	jobs.mu.RLock()
+	defer jobs.mu.RUnlock()
+	assert.Equal(t, 0, len(jobs.jobs))
+	assert.False(t, jobs.expireRunning)
+}
+
+func TestJobCalls(t *testing.T) {
+	job := StartJob(jobWaitForCancel, Params{})
+
+	out, err := rcJobList(context.Background(), Params{})
+	require.NoError(t, err)
+	assert.Contains(t, out["jobids"], job.ID)
+
+	out, err = rcJobStatus(context.Background(), Params{"jobid": job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, out["id"])
+
+	_, err = rcJobStop(context.Background(), Params{"jobid": job.ID})
+	require.NoError(t, err)
+
+	out, err = rcJobWait(context.Background(), Params{"jobid": job.ID})
+	require.NoError(t, err)
+	assert.Equal(t, true, out["finished"])
+	assert.Equal(t, false, out["success"])
+
+	_, err = rcJobStatus(context.Background(), Params{"jobid": int64(-1)})
+	assert.EqualError(t, err, "job not found")
+
+	_, err = rcJobStatus(context.Background(), Params{})
+	assert.True(t, IsErrParamNotFound(err))
+}
+
+func TestServerAsync(t *testing.T) {
+	s := NewServer(&DefaultOpt)
+	status, out := doRequest(t, s, "POST", "rc/noop", "application/json", `{"a":"potato","_async":true}`)
+	assert.Equal(t, http.StatusOK, status)
+	jobID, err := out.GetInt64("jobid")
+	require.NoError(t, err)
+	job := GetJob(jobID)
+	require.NotNil(t, job)
+	job.Wait()
+	assert.Equal(t, Params{"a": "potato"}, job.Output)
+
+	status, _ = doRequest(t, s, "POST", "rc/noop", "application/json", `{"_async":"potato"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, out = doRequest(t, s, "POST", "job/status", "application/json", `{"jobid":-1}`)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, "job not found", out["error"])
+}
@@ -0,0 +1,140 @@
+// Define the rc functions for file operations and syncing
+
+package rc
+
+import (
+	"sync"
+
+	"github.com/ncw/rclone/fs"
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+func init() {
+	Add(Call{
+		Path:  "operations/copyfile",
+		Fn:    rcCopyFile,
+		Title: "Copy a file from source remote to destination remote",
+		Help: `This takes the following parameters
+
+- srcFs - a remote name string eg "drive:" for the source
+- srcRemote - a path within that remote eg "file.txt" for the source
+- dstFs - a remote name string eg "drive2:" for the destination
+- dstRemote - a path within that remote eg "file2.txt" for the destination`,
+	})
+	Add(Call{
+		Path:  "operations/movefile",
+		Fn:    rcMoveFile,
+		Title: "Move a file from source remote to destination remote",
+		Help: `This takes the following parameters
+
+- srcFs - a remote name string eg "drive:" for the source
+- srcRemote - a path within that remote eg "file.txt" for the source
+- dstFs - a remote name string eg "drive2:" for the destination
+- dstRemote - a path within that remote eg "file2.txt" for the destination`,
+	})
+	for _, name := range []string{"sync", "copy", "move"} {
+		name := name
+		Add(Call{
+			Path: "sync/" + name,
+			Fn: func(ctx context.Context, in Params) (out Params, err error) {
+				return rcSyncCopyMove(ctx, in, name)
+			},
+			Title: name + " a directory from source remote to destination remote",
+			Help: `This takes the following parameters
+
+- srcFs - a remote name string eg "drive:src" for the source
+- dstFs - a remote name string eg "drive:dst" for the destination
+
+See the [` + name + ` command](/commands/rclone_` + name + `/) for more information on the above.`,
+		})
+	}
+}
+
+// fsCache holds the Fs objects made by the remote control so state
+// like directory caches, tokens and connections are reused between
+// calls.
+var fsCache = struct {
+	mu sync.Mutex
+	fs map[string]fs.Fs
+}{
+	fs: make(map[string]fs.Fs),
+}
+
+// GetFs gets a fs.Fs named fsString either from the cache or creates it afresh
+func GetFs(fsString string) (f fs.Fs, err error) {
+	fsCache.mu.Lock()
+	defer fsCache.mu.Unlock()
+	f = fsCache.fs[fsString]
+	if f == nil {
+		f, err = fs.NewFs(fsString)
+		if err != nil {
+			return nil, err
+		}
+		fsCache.fs[fsString] = f
+	}
+	return f, nil
+}
+
+// GetFsNamed gets a fs.Fs named fsName from the input parameters
+func GetFsNamed(in Params, fsName string) (f fs.Fs, err error) {
+	fsString, err := in.GetString(fsName)
+	if err != nil {
+		return nil, err
+	}
+	return GetFs(fsString)
+}
+
+// Copy or move a single file
+func rcMoveOrCopyFile(ctx context.Context, in Params, cp bool) (out Params, err error) {
+	srcFs, err := GetFsNamed(in, "srcFs")
+	if err != nil {
+		return nil, err
+	}
+	srcRemote, err := in.GetString("srcRemote")
+	if err != nil {
+		return nil, err
+	}
+	dstFs, err := GetFsNamed(in, "dstFs")
+	if err != nil {
+		return nil, err
+	}
+	dstRemote, err := in.GetString("dstRemote")
+	if err != nil {
+		return nil, err
+	}
+	if cp {
+		return nil, fs.CopyFile(ctx, dstFs, srcFs, dstRemote, srcRemote)
+	}
+	return nil, fs.MoveFile(ctx, dstFs, srcFs, dstRemote, srcRemote)
+}
+
+// Copy a single file
+func rcCopyFile(ctx context.Context, in Params) (out Params, err error) {
+	return rcMoveOrCopyFile(ctx, in, true)
+}
+
+// Move a single file
+func rcMoveFile(ctx context.Context, in Params) (out Params, err error) {
+	return rcMoveOrCopyFile(ctx, in, false)
+}
+
+// Sync, copy or move a directory
+func rcSyncCopyMove(ctx context.Context, in Params, name string) (out Params, err error) {
+	srcFs, err := GetFsNamed(in, "srcFs")
+	if err != nil {
+		return nil, err
+	}
+	dstFs, err := GetFsNamed(in, "dstFs")
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "sync":
+		return nil, fs.Sync(ctx, dstFs, srcFs)
+	case "copy":
+		return nil, fs.CopyDir(ctx, dstFs, srcFs)
+	case "move":
+		return nil, fs.MoveDir(ctx, dstFs, srcFs)
+	}
+	panic("unknown rcSyncCopyMove type")
+}
@@ -0,0 +1,142 @@
+package rc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	fn := func(ctx context.Context, in Params) (Params, error) {
+		return in, nil
+	}
+	r.Add(Call{Path: "/b/call/", Fn: fn, Help: "\n  help  \n"})
+	r.Add(Call{Path: "a/call", Fn: fn})
+
+	call := r.Get("b/call")
+	require.NotNil(t, call)
+	assert.Equal(t, "b/call", call.Path)
+	assert.Equal(t, "help", call.Help)
+	assert.Nil(t, r.Get("potato"))
+
+	var paths []string
+	for _, call := range r.List() {
+		paths = append(paths, call.Path)
+	}
+	assert.Equal(t, []string{"a/call", "b/call"}, paths)
+}
+
+func TestErrParam(t *testing.T) {
+	err := error(ErrParamNotFound("key"))
+	assert.Equal(t, `Didn't find key "key" in input`, err.Error())
+	assert.True(t, IsErrParamNotFound(err))
+	assert.True(t, IsErrParamNotFound(errors.Wrap(err, "wrapped")))
+	assert.False(t, IsErrParamInvalid(err))
+
+	err = ErrParamInvalid{errors.New("bad")}
+	assert.Equal(t, "bad", err.Error())
+	assert.True(t, IsErrParamInvalid(err))
+	assert.False(t, IsErrParamNotFound(err))
+}
+
+func TestParamsGet(t *testing.T) {
+	in := Params{"ok": 1}
+	v1, e1 := in.Get("ok")
+	assert.NoError(t, e1)
+	assert.Equal(t, 1, v1)
+	v2, e2 := in.Get("notOK")
+	assert.Error(t, e2)
+	assert.Equal(t, nil, v2)
+	assert.True(t, IsErrParamNotFound(e2))
+}
+
+func TestParamsGetString(t *testing.T) {
+	in := Params{
+		"string":    "one",
+		"notString": 17,
+	}
+	v1, e1 := in.GetString("string")
+	assert.NoError(t, e1)
+	assert.Equal(t, "one", v1)
+	v2, e2 := in.GetString("notOK")
+	assert.Error(t, e2)
+	assert.Equal(t, "", v2)
+	assert.True(t, IsErrParamNotFound(e2))
+	v3, e3 := in.GetString("notString")
+	assert.Error(t, e3)
+	assert.Equal(t, "", v3)
+	assert.True(t, IsErrParamInvalid(e3))
+}
+
+func TestParamsGetInt64(t *testing.T) {
+	for _, test := range []struct {
+		value     interface{}
+		result    int64
+		errString string
+	}{
+		{"123", 123, ""},
+		{"123x", 0, "couldn't parse"},
+		{int(12), 12, ""},
+		{int64(13), 13, ""},
+		{float64(14), 14, ""},
+		{float64(9.3e18), 0, "overflows int64"},
+		{float64(-9.3e18), 0, "overflows int64"},
+		{true, 0, "expecting int64"},
+	} {
+		what := fmt.Sprintf("%T=%v", test.value, test.value)
+		in := Params{
+			"key": test.value,
+		}
+		v1, e1 := in.GetInt64("key")
+		if test.errString == "" {
+			require.NoError(t, e1, what)
+			assert.Equal(t, test.result, v1, what)
+		} else {
+			require.NotNil(t, e1, what)
+			assert.True(t, IsErrParamInvalid(e1), what)
+			assert.Contains(t, e1.Error(), test.errString, what)
+			assert.Equal(t, int64(0), v1, what)
+		}
+	}
+	in := Params{}
+	_, err := in.GetInt64("notOK")
+	assert.True(t, IsErrParamNotFound(err))
+}
+
+func TestParamsGetBool(t *testing.T) {
+	for _, test := range []struct {
+		value     interface{}
+		result    bool
+		errString string
+	}{
+		{true, true, ""},
+		{false, false, ""},
+		{"true", true, ""},
+		{"false", false, ""},
+		{"fasle", false, "couldn't parse"},
+		{int(12), false, "expecting bool"},
+	} {
+		what := fmt.Sprintf("%T=%v", test.value, test.value)
+		in := Params{
+			"key": test.value,
+		}
+		v1, e1 := in.GetBool("key")
+		if test.errString == "" {
+			require.NoError(t, e1, what)
+			assert.Equal(t, test.result, v1, what)
+		} else {
+			require.NotNil(t, e1, what)
+			assert.True(t, IsErrParamInvalid(e1), what)
+			assert.Contains(t, e1.Error(), test.errString, what)
+			assert.Equal(t, false, v1, what)
+		}
+	}
+	in := Params{}
+	_, err := in.GetBool("notOK")
+	assert.True(t, IsErrParamNotFound(err))
+}
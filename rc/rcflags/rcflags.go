@@ -0,0 +1,24 @@
+// Package rcflags implements command line flags to set up the remote control
+package rcflags
+
+import (
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/rc"
+	"github.com/spf13/pflag"
+)
+
+// Options set by command line flags
+var (
+	Opt = rc.DefaultOpt
+)
+
+// AddFlags adds the remote control flags to the flagSet
+func AddFlags(flagSet *pflag.FlagSet) {
+	fs.BoolVarP(flagSet, &Opt.Enabled, "rc", "", Opt.Enabled, "Enable the remote control server.")
+	fs.BoolVarP(flagSet, &Opt.EnableMetrics, "rc-enable-metrics", "", Opt.EnableMetrics, "Enable prometheus metrics on /metrics.")
+	fs.StringVarP(flagSet, &Opt.BindAddress, "rc-addr", "", Opt.BindAddress, "IPaddress:Port to bind server to.")
+	fs.StringVarP(flagSet, &Opt.User, "rc-user", "", Opt.User, "User name for authentication.")
+	fs.StringVarP(flagSet, &Opt.Pass, "rc-pass", "", Opt.Pass, "Password for authentication.")
+	fs.DurationVarP(flagSet, &Opt.JobExpireDuration, "rc-job-expire-duration", "", Opt.JobExpireDuration, "Expire finished async jobs older than this value.")
+	fs.DurationVarP(flagSet, &Opt.JobExpireInterval, "rc-job-expire-interval", "", Opt.JobExpireInterval, "Interval to check for expired async jobs.")
+}
@@ -0,0 +1,254 @@
+// Serve the remote control API over HTTP
+
+package rc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/metrics"
+	"github.com/pkg/errors"
+)
+
+// Options contains options for the remote control server
+type Options struct {
+	Enabled           bool          // set to enable the server
+	EnableMetrics     bool          // set to serve Prometheus metrics on /metrics
+	BindAddress       string        // IP address and port to bind to
+	User              string        // user name for basic auth - blank for none
+	Pass              string        // password for basic auth
+	JobExpireDuration time.Duration // how long finished jobs are kept
+	JobExpireInterval time.Duration // how often to check for expired jobs
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	BindAddress:       "localhost:5572",
+	JobExpireDuration: 60 * time.Second,
+	JobExpireInterval: 10 * time.Second,
+}
+
+// Server contains everything to run the remote control server
+type Server struct {
+	opt        Options
+	registry   *Registry
+	httpServer *http.Server
+	listener   net.Listener
+}
+
+// NewServer makes a new remote control server serving the calls in
+// the global registry
+func NewServer(opt *Options) *Server {
+	s := &Server{
+		opt:      *opt,
+		registry: Calls,
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", s)
+	s.httpServer = &http.Server{
+		Addr:           s.opt.BindAddress,
+		Handler:        mux,
+		MaxHeaderBytes: 1 << 20,
+	}
+	initServer(s.httpServer)
+	running.setOpt(&s.opt)
+	return s
+}
+
+// Start the remote control server if it is enabled in opt
+//
+// It returns once the server is listening and serves requests in
+// the background.
+func Start(opt *Options) error {
+	if !opt.Enabled {
+		return nil
+	}
+	s := NewServer(opt)
+	err := s.Listen()
+	if err != nil {
+		return err
+	}
+	go func() {
+		err := s.Serve()
+		if err != nil {
+			fs.Errorf(nil, "rc: server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Listen opens the listening socket for the server
+func (s *Server) Listen() (err error) {
+	s.listener, err = net.Listen("tcp", s.opt.BindAddress)
+	if err != nil {
+		return errors.Wrap(err, "failed to start remote control server")
+	}
+	fs.Logf(nil, "Serving remote control on http://%s/", s.listener.Addr())
+	if s.opt.EnableMetrics {
+		fs.Logf(nil, "Serving metrics on http://%s/metrics", s.listener.Addr())
+	}
+	return nil
+}
+
+// Serve runs the server until it fails - Listen must be called first
+func (s *Server) Serve() error {
+	return s.httpServer.Serve(s.listener)
+}
+
+// Addr returns the address the server is listening on
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// checkAuth checks the basic auth credentials if configured
+//
+// It sets the WWW-Authenticate header and returns false if they are
+// wrong
+func (s *Server) checkAuth(w http.ResponseWriter, r *http.Request) bool {
+	if s.opt.User == "" {
+		return true
+	}
+	user, pass, ok := r.BasicAuth()
+	if ok &&
+		subtle.ConstantTimeCompare([]byte(user), []byte(s.opt.User)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(pass), []byte(s.opt.Pass)) == 1 {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", `Basic realm="rclone"`)
+	return false
+}
+
+// writeJSON writes out as JSON with the status code given
+func writeJSON(w http.ResponseWriter, status int, out Params) {
+	buf, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		fs.Errorf(nil, "rc: failed to encode JSON output: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(append(buf, '\n'))
+	if err != nil {
+		fs.Errorf(nil, "rc: failed to write JSON output: %v", err)
+	}
+}
+
+// writeError writes a formatted error to the output
+func writeError(path string, in Params, w http.ResponseWriter, err error, status int) {
+	fs.Errorf(nil, "rc: %q: error: %v", path, err)
+	writeJSON(w, status, Params{
+		"status": status,
+		"error":  err.Error(),
+		"input":  in,
+		"path":   path,
+	})
+}
+
+// readParams reads the input parameters from the URL, form and JSON
+// body of the request
+//
+// The JSON body takes precedence over the URL and form parameters.
+func readParams(r *http.Request) (in Params, err error) {
+	in = make(Params)
+	err = r.ParseForm()
+	if err != nil {
+		return in, errors.Wrap(err, "failed to parse form/URL parameters")
+	}
+	for k, vs := range r.Form {
+		if len(vs) > 0 {
+			in[k] = vs[len(vs)-1]
+		}
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "application/json" {
+		err = json.NewDecoder(r.Body).Decode(&in)
+		if err != nil {
+			return in, errors.Wrap(err, "failed to read input JSON")
+		}
+	}
+	return in, nil
+}
+
+// ServeHTTP reads incoming requests and dispatches them to the
+// registered calls
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	w.Header().Set("Server", "rclone/"+fs.Version)
+
+	if !s.checkAuth(w, r) {
+		writeError(path, nil, w, errors.New("authentication failed"), http.StatusUnauthorized)
+		return
+	}
+	if path == "metrics" && s.opt.EnableMetrics {
+		s.serveMetrics(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		writeError(path, nil, w, errors.Errorf("method %q not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	call := s.registry.Get(path)
+	if call == nil {
+		writeError(path, nil, w, errors.Errorf("couldn't find method %q", path), http.StatusNotFound)
+		return
+	}
+	in, err := readParams(r)
+	if err != nil {
+		writeError(path, in, w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Check to see if the call should be run as a background job
+	isAsync, err := in.GetBool("_async")
+	if err != nil && !IsErrParamNotFound(err) {
+		writeError(path, in, w, err, http.StatusBadRequest)
+		return
+	}
+	delete(in, "_async")
+
+	fs.Debugf(nil, "rc: %q: with parameters %+v", path, in)
+	var out Params
+	if isAsync {
+		job := StartJob(call.Fn, in)
+		fs.Debugf(nil, "rc: %q: started %v", path, job)
+		out = Params{"jobid": job.ID}
+	} else {
+		out, err = call.Fn(requestContext(r), in)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if IsErrParamNotFound(err) || IsErrParamInvalid(err) {
+			status = http.StatusBadRequest
+		}
+		writeError(path, in, w, err, status)
+		return
+	}
+	if out == nil {
+		out = make(Params)
+	}
+	fs.Debugf(nil, "rc: %q: reply %+v", path, out)
+	writeJSON(w, http.StatusOK, out)
+}
+
+// serveMetrics writes the metrics in the Prometheus text format
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		writeError("metrics", nil, w, errors.Errorf("method %q not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", metrics.ContentType)
+	if r.Method == "HEAD" {
+		return
+	}
+	err := metrics.Write(w)
+	if err != nil {
+		fs.Errorf(nil, "rc: failed to write metrics: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+// HTTP parts go1.8+
+
+//+build go1.8
+
+package rc
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+// Initialise the http.Server for go1.8+
+func initServer(s *http.Server) {
+	s.ReadHeaderTimeout = 10 * time.Second // time to send the headers
+	s.IdleTimeout = 60 * time.Second       // time to keep idle connections open
+}
+
+// requestContext returns the context for calls made by r which is
+// cancelled if the client goes away
+func requestContext(r *http.Request) context.Context {
+	return r.Context()
+}
@@ -0,0 +1,37 @@
+//+build go1.8
+
+package rc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xcontext "golang.org/x/net/context"
+)
+
+// Check that synchronous calls are cancelled when the client goes away
+func TestServerRequestContext(t *testing.T) {
+	s := NewServer(&DefaultOpt)
+	s.registry = NewRegistry()
+	s.registry.Add(Call{
+		Path: "test/block",
+		Fn: func(ctx xcontext.Context, in Params) (Params, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequest("POST", "http://localhost:5572/test/block", strings.NewReader(""))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req.WithContext(ctx))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "context canceled")
+}
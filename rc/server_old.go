@@ -0,0 +1,21 @@
+// HTTP parts pre go1.8
+
+//+build !go1.8
+
+package rc
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+// Initialise the http.Server for pre go1.8
+func initServer(s *http.Server) {
+}
+
+// requestContext returns the context for calls made by r - pre
+// go1.8 this can't be cancelled when the client goes away
+func requestContext(r *http.Request) context.Context {
+	return context.Background()
+}
@@ -0,0 +1,126 @@
+package rc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// do a request against the server returning the status and the decoded output
+func doRequest(t *testing.T, s *Server, method, path, contentType, body string, auth ...string) (int, Params) {
+	req, err := http.NewRequest(method, "http://localhost:5572/"+path, strings.NewReader(body))
+	require.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(auth) == 2 {
+		req.SetBasicAuth(auth[0], auth[1])
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	out := make(Params)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+	return w.Code, out
+}
+
+func TestServerJSON(t *testing.T) {
+	s := NewServer(&DefaultOpt)
+	status, out := doRequest(t, s, "POST", "rc/noop", "application/json", `{"a":"potato","b":2}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, Params{"a": "potato", "b": 2.0}, out)
+}
+
+func TestServerForm(t *testing.T) {
+	s := NewServer(&DefaultOpt)
+	status, out := doRequest(t, s, "POST", "rc/noop?a=url", "application/x-www-form-urlencoded", "b=form")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, Params{"a": "url", "b": "form"}, out)
+}
+
+func TestServerErrors(t *testing.T) {
+	s := NewServer(&DefaultOpt)
+
+	status, out := doRequest(t, s, "GET", "rc/noop", "", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+	assert.Contains(t, out["error"], "not allowed")
+
+	status, out = doRequest(t, s, "POST", "not/found", "", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "not/found", out["path"])
+
+	status, _ = doRequest(t, s, "POST", "rc/noop", "application/json", "{not json")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, out = doRequest(t, s, "POST", "rc/error", "application/json", `{"a":"potato"}`)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(t, out["error"], "arbitrary error")
+	assert.Equal(t, map[string]interface{}{"a": "potato"}, out["input"])
+
+	status, out = doRequest(t, s, "POST", "core/bwlimit", "", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, out["error"], "rate")
+}
+
+func TestServerAuth(t *testing.T) {
+	opt := DefaultOpt
+	opt.User = "user"
+	opt.Pass = "pass"
+	s := NewServer(&opt)
+
+	status, _ := doRequest(t, s, "POST", "rc/noop", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = doRequest(t, s, "POST", "rc/noop", "", "", "user", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = doRequest(t, s, "POST", "rc/noop", "", "", "user", "pass")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestServerList(t *testing.T) {
+	s := NewServer(&DefaultOpt)
+	status, out := doRequest(t, s, "POST", "rc/list", "", "")
+	assert.Equal(t, http.StatusOK, status)
+	commands, ok := out["commands"].([]interface{})
+	require.True(t, ok)
+	var paths []string
+	for _, command := range commands {
+		paths = append(paths, command.(map[string]interface{})["Path"].(string))
+	}
+	assert.Contains(t, paths, "core/stats")
+	assert.Contains(t, paths, "sync/sync")
+	assert.Contains(t, paths, "operations/copyfile")
+}
+
+func TestServerMetrics(t *testing.T) {
+	do := func(s *Server, method string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, "http://localhost:5572/metrics", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+
+	// Not enabled
+	w := do(NewServer(&DefaultOpt), "GET")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	opt := DefaultOpt
+	opt.EnableMetrics = true
+	s := NewServer(&opt)
+
+	w = do(s, "GET")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "# TYPE rclone_bytes_transferred_total counter\n")
+	assert.Contains(t, w.Body.String(), "\nrclone_transfers_total ")
+
+	w = do(s, "POST")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/swift"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // Register with Fs
@@ -225,6 +226,7 @@ const (
 	maxRetries     = 10                            // number of retries to make of operations
 	maxSizeForCopy = 5 * 1024 * 1024 * 1024        // The maximum size of object we can COPY
 	maxFileSize    = 5 * 1024 * 1024 * 1024 * 1024 // largest possible upload file size
+	maxLinkExpire  = 7 * 24 * time.Hour            // longest a presigned URL can last
 )
 
 // Globals
@@ -264,6 +266,7 @@ type Object struct {
 	lastModified time.Time          // Last modified
 	meta         map[string]*string // The object metadata if known - may be nil
 	mimeType     string             // MimeType of object - may be ""
+	headers      fs.Metadata        // Standard HTTP headers other than Content-Type - may be nil
 }
 
 // ------------------------------------------------------------
@@ -369,7 +372,7 @@ func s3Connection(name string) (*s3.S3, *session.Session, error) {
 		WithMaxRetries(maxRetries).
 		WithCredentials(cred).
 		WithEndpoint(endpoint).
-		WithHTTPClient(fs.Config.Client()).
+		WithHTTPClient(fs.Config.ClientRemote(name)).
 		WithS3ForcePathStyle(true)
 	// awsConfig.WithLogLevel(aws.LogDebugWithSigning)
 	ses := session.New()
@@ -392,6 +395,7 @@ func s3Connection(name string) (*s3.S3, *session.Session, error) {
 
 // NewFs constructs an Fs from the path, bucket:path
 func NewFs(name, root string) (fs.Fs, error) {
+	ctx := context.Background()
 	bucket, directory, err := s3ParsePath(root)
 	if err != nil {
 		return nil, err
@@ -412,9 +416,13 @@ func NewFs(name, root string) (fs.Fs, error) {
 		storageClass:       fs.ConfigFileGet(name, "storage_class"),
 	}
 	f.features = (&fs.Features{
-		ReadMimeType:  true,
-		WriteMimeType: true,
-		BucketBased:   true,
+		ReadMimeType:            true,
+		WriteMimeType:           true,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
+		BucketBased:             true,
+		ServerSideAcrossConfigs: fs.ConfigFileGetBool(name, "server_side_across_configs", false),
+		RangeRead:               true,
 	}).Fill(f)
 	if *s3ACL != "" {
 		f.acl = *s3ACL
@@ -429,7 +437,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 			Bucket: &f.bucket,
 			Key:    &directory,
 		}
-		_, err = f.c.HeadObject(&req)
+		_, err = f.c.HeadObjectWithContext(ctx, &req)
 		if err == nil {
 			f.root = path.Dir(directory)
 			if f.root == "." {
@@ -448,7 +456,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 // Return an Object from a path
 //
 //If it can't be found it returns the error ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *s3.Object) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -464,7 +472,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error
 		o.etag = aws.StringValue(info.ETag)
 		o.bytes = aws.Int64Value(info.Size)
 	} else {
-		err := o.readMetaData() // reads info and meta, returning an error
+		err := o.readMetaData(ctx) // reads info and meta, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -474,8 +482,8 @@ func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // listFn is called from list to handle an object.
@@ -486,7 +494,7 @@ type listFn func(remote string, object *s3.Object, isDirectory bool) error
 // dir is the starting directory, "" for root
 //
 // Set recurse to read sub directories
-func (f *Fs) list(dir string, recurse bool, fn listFn) error {
+func (f *Fs) list(ctx context.Context, dir string, recurse bool, fn listFn) error {
 	root := f.root
 	if dir != "" {
 		root += dir + "/"
@@ -506,7 +514,7 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 			MaxKeys:   &maxKeys,
 			Marker:    marker,
 		}
-		resp, err := f.c.ListObjects(&req)
+		resp, err := f.c.ListObjectsWithContext(ctx, &req)
 		if err != nil {
 			if awsErr, ok := err.(awserr.RequestFailure); ok {
 				if awsErr.StatusCode() == http.StatusNotFound {
@@ -563,7 +571,7 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 }
 
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(remote string, object *s3.Object, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Object, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		size := int64(0)
 		if object.Size != nil {
@@ -572,7 +580,7 @@ func (f *Fs) itemToDirEntry(remote string, object *s3.Object, isDirectory bool)
 		d := fs.NewDir(remote, time.Time{}).SetSize(size)
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object)
 	if err != nil {
 		return nil, err
 	}
@@ -580,10 +588,10 @@ func (f *Fs) itemToDirEntry(remote string, object *s3.Object, isDirectory bool)
 }
 
 // listDir lists files and directories to out
-func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listDir(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	// List the objects and directories
-	err = f.list(dir, false, func(remote string, object *s3.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, false, func(remote string, object *s3.Object, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -599,12 +607,12 @@ func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
 }
 
 // listBuckets lists the buckets to out
-func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listBuckets(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if dir != "" {
 		return nil, fs.ErrorListBucketRequired
 	}
 	req := s3.ListBucketsInput{}
-	resp, err := f.c.ListBuckets(&req)
+	resp, err := f.c.ListBucketsWithContext(ctx, &req)
 	if err != nil {
 		return nil, err
 	}
@@ -624,11 +632,11 @@ func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.bucket == "" {
-		return f.listBuckets(dir)
+		return f.listBuckets(ctx, dir)
 	}
-	return f.listDir(dir)
+	return f.listDir(ctx, dir)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -647,13 +655,13 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.bucket == "" {
 		return fs.ErrorListBucketRequired
 	}
 	list := fs.NewListRHelper(callback)
-	err = f.list(dir, true, func(remote string, object *s3.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, true, func(remote string, object *s3.Object, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -666,28 +674,28 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 }
 
 // Put the Object into the bucket
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	fs := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return fs, fs.Update(in, src, options...)
+	return fs, fs.Update(ctx, in, src, options...)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Check if the bucket exists
 //
 // NB this can return incorrect results if called immediately after bucket deletion
-func (f *Fs) dirExists() (bool, error) {
+func (f *Fs) dirExists(ctx context.Context) (bool, error) {
 	req := s3.HeadBucketInput{
 		Bucket: &f.bucket,
 	}
-	_, err := f.c.HeadBucket(&req)
+	_, err := f.c.HeadBucketWithContext(ctx, &req)
 	if err == nil {
 		return true, nil
 	}
@@ -700,14 +708,14 @@ func (f *Fs) dirExists() (bool, error) {
 }
 
 // Mkdir creates the bucket if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.bucketOK {
 		return nil
 	}
 	if !f.bucketDeleted {
-		exists, err := f.dirExists()
+		exists, err := f.dirExists(ctx)
 		if err == nil {
 			f.bucketOK = exists
 		}
@@ -724,7 +732,7 @@ func (f *Fs) Mkdir(dir string) error {
 			LocationConstraint: &f.locationConstraint,
 		}
 	}
-	_, err := f.c.CreateBucket(&req)
+	_, err := f.c.CreateBucketWithContext(ctx, &req)
 	if err, ok := err.(awserr.Error); ok {
 		if err.Code() == "BucketAlreadyOwnedByYou" {
 			err = nil
@@ -740,7 +748,7 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir deletes the bucket if the fs is at the root
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.root != "" || dir != "" {
@@ -749,7 +757,7 @@ func (f *Fs) Rmdir(dir string) error {
 	req := s3.DeleteBucketInput{
 		Bucket: &f.bucket,
 	}
-	_, err := f.c.DeleteBucket(&req)
+	_, err := f.c.DeleteBucketWithContext(ctx, &req)
 	if err == nil {
 		f.bucketOK = false
 		f.bucketDeleted = true
@@ -768,11 +776,12 @@ func (f *Fs) Precision() time.Duration {
 //
 // It returns the destination Object and a possible error
 //
-// Will only be called if src.Fs().Name() == f.Name()
+// Will only be called if src.Fs().Name() == f.Name() or if
+// server_side_across_configs is set and src is another s3 remote
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -790,11 +799,34 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 		CopySource:        &source,
 		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
 	}
-	_, err = f.c.CopyObject(&req)
+	_, err = f.c.CopyObjectWithContext(ctx, &req)
 	if err != nil {
 		return nil, err
 	}
-	return f.NewObject(remote)
+	return f.NewObject(ctx, remote)
+}
+
+// PublicLink generates a presigned URL for the object at remote
+//
+// The URL lasts for expire or the maximum of 7 days if expire is 0
+func (f *Fs) PublicLink(ctx context.Context, remote string, expire time.Duration) (string, error) {
+	if expire > maxLinkExpire {
+		return "", errors.Errorf("presigned URLs can't last longer than %v", maxLinkExpire)
+	}
+	if expire <= 0 {
+		expire = maxLinkExpire
+	}
+	// Check the object exists
+	_, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+	key := f.root + remote
+	req, _ := f.c.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: &f.bucket,
+		Key:    &key,
+	})
+	return req.Presign(expire)
 }
 
 // Hashes returns the supported hash sets.
@@ -846,7 +878,7 @@ func (o *Object) Size() int64 {
 // readMetaData gets the metadata if it hasn't already been fetched
 //
 // it also sets the info
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if o.meta != nil {
 		return nil
 	}
@@ -855,7 +887,7 @@ func (o *Object) readMetaData() (err error) {
 		Bucket: &o.fs.bucket,
 		Key:    &key,
 	}
-	resp, err := o.fs.c.HeadObject(&req)
+	resp, err := o.fs.c.HeadObjectWithContext(ctx, &req)
 	if err != nil {
 		if awsErr, ok := err.(awserr.RequestFailure); ok {
 			if awsErr.StatusCode() == http.StatusNotFound {
@@ -880,6 +912,17 @@ func (o *Object) readMetaData() (err error) {
 		o.lastModified = *resp.LastModified
 	}
 	o.mimeType = aws.StringValue(resp.ContentType)
+	o.headers = nil
+	for k, v := range map[string]*string{
+		"cache-control":       resp.CacheControl,
+		"content-disposition": resp.ContentDisposition,
+		"content-encoding":    resp.ContentEncoding,
+		"content-language":    resp.ContentLanguage,
+	} {
+		if v != nil && *v != "" {
+			o.headers.Set(k, *v)
+		}
+	}
 	return nil
 }
 
@@ -888,7 +931,7 @@ func (o *Object) readMetaData() (err error) {
 // It attempts to read the objects mtime and if that isn't present the
 // LastModified returned in the http headers
 func (o *Object) ModTime() time.Time {
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		fs.Logf(o, "Failed to read metadata: %v", err)
 		return time.Now()
@@ -908,8 +951,8 @@ func (o *Object) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
-	err := o.readMetaData()
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
+	err := o.readMetaData(ctx)
 	if err != nil {
 		return err
 	}
@@ -936,7 +979,21 @@ func (o *Object) SetModTime(modTime time.Time) error {
 		Metadata:          o.meta,
 		MetadataDirective: &directive,
 	}
-	_, err = o.fs.c.CopyObject(&req)
+	// Keep the standard headers as these get replaced too
+	for k, v := range o.headers {
+		v := v
+		switch k {
+		case "cache-control":
+			req.CacheControl = &v
+		case "content-disposition":
+			req.ContentDisposition = &v
+		case "content-encoding":
+			req.ContentEncoding = &v
+		case "content-language":
+			req.ContentLanguage = &v
+		}
+	}
+	_, err = o.fs.c.CopyObjectWithContext(ctx, &req)
 	return err
 }
 
@@ -946,7 +1003,7 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	key := o.fs.root + o.remote
 	req := s3.GetObjectInput{
 		Bucket: &o.fs.bucket,
@@ -963,7 +1020,7 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 			}
 		}
 	}
-	resp, err := o.fs.c.GetObject(&req)
+	resp, err := o.fs.c.GetObjectWithContext(ctx, &req)
 	if err, ok := err.(awserr.RequestFailure); ok {
 		if err.Code() == "InvalidObjectState" {
 			return nil, errors.Errorf("Object in GLACIER, restore first: %v", key)
@@ -976,8 +1033,8 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 }
 
 // Update the Object from in with modTime and size
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	err := o.fs.Mkdir("")
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	err := o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
@@ -1021,37 +1078,58 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		Metadata:    metadata,
 		//ContentLength: &size,
 	}
+
+	// Apply any metadata passed in
+	for k, v := range fs.GetMetadataOptions(options) {
+		v := v
+		switch k {
+		case "content-type":
+			req.ContentType = &v
+		case "cache-control":
+			req.CacheControl = &v
+		case "content-disposition":
+			req.ContentDisposition = &v
+		case "content-encoding":
+			req.ContentEncoding = &v
+		case "content-language":
+			req.ContentLanguage = &v
+		case strings.ToLower(metaMtime):
+			// the modification time is set from src
+		default:
+			metadata[k] = &v
+		}
+	}
 	if o.fs.sse != "" {
 		req.ServerSideEncryption = &o.fs.sse
 	}
 	if o.fs.storageClass != "" {
 		req.StorageClass = &o.fs.storageClass
 	}
-	_, err = uploader.Upload(&req)
+	_, err = uploader.UploadWithContext(ctx, &req)
 	if err != nil {
 		return err
 	}
 
 	// Read the metadata from the newly created object
 	o.meta = nil // wipe old metadata
-	err = o.readMetaData()
+	err = o.readMetaData(ctx)
 	return err
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	key := o.fs.root + o.remote
 	req := s3.DeleteObjectInput{
 		Bucket: &o.fs.bucket,
 		Key:    &key,
 	}
-	_, err := o.fs.c.DeleteObject(&req)
+	_, err := o.fs.c.DeleteObjectWithContext(ctx, &req)
 	return err
 }
 
 // MimeType of an Object if known, "" otherwise
 func (o *Object) MimeType() string {
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		fs.Logf(o, "Failed to read metadata: %v", err)
 		return ""
@@ -1059,12 +1137,38 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the user metadata with lower case keys along with the
+// standard HTTP headers.  The mtime is returned by ModTime so isn't
+// included.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range o.meta {
+		k = strings.ToLower(k)
+		if k == strings.ToLower(metaMtime) || v == nil {
+			continue
+		}
+		metadata.Set(k, *v)
+	}
+	if o.mimeType != "" {
+		metadata.Set("content-type", o.mimeType)
+	}
+	metadata.Merge(o.headers)
+	return metadata, nil
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.Copier      = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.ListRer     = &Fs{}
-	_ fs.Object      = &Object{}
-	_ fs.MimeTyper   = &Object{}
+	_ fs.Fs           = &Fs{}
+	_ fs.Copier       = &Fs{}
+	_ fs.PutStreamer  = &Fs{}
+	_ fs.ListRer      = &Fs{}
+	_ fs.PublicLinker = &Fs{}
+	_ fs.Object       = &Object{}
+	_ fs.MimeTyper    = &Object{}
+	_ fs.Metadataer   = &Object{}
 )
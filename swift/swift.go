@@ -16,6 +16,7 @@ import (
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/swift"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // Constants
@@ -27,8 +28,26 @@ const (
 // Globals
 var (
 	chunkSize = fs.SizeSuffix(5 * 1024 * 1024 * 1024)
+
+	// HTTP headers which are stored as metadata and the metadata
+	// keys they map to
+	metadataHeaders = map[string]string{
+		"Cache-Control":       "cache-control",
+		"Content-Disposition": "content-disposition",
+		"Content-Encoding":    "content-encoding",
+		"Content-Language":    "content-language",
+	}
+
+	// The reverse of metadataHeaders
+	metadataKeys = map[string]string{}
 )
 
+func init() {
+	for header, key := range metadataHeaders {
+		metadataKeys[key] = header
+	}
+}
+
 // Register with Fs
 func init() {
 	fs.Register(&fs.RegInfo{
@@ -202,7 +221,7 @@ func swiftConnection(name string) (*swift.Connection, error) {
 		EndpointType:   swift.EndpointType(fs.ConfigFileGet(name, "endpoint_type", "public")),
 		ConnectTimeout: 10 * fs.Config.ConnectTimeout, // Use the timeouts in the transport
 		Timeout:        10 * fs.Config.Timeout,        // Use the timeouts in the transport
-		Transport:      fs.Config.Transport(),
+		Transport:      fs.Config.TransportRemote(name),
 	}
 	if fs.ConfigFileGetBool(name, "env_auth", false) {
 		err := c.ApplyEnvironment()
@@ -247,7 +266,10 @@ func NewFsWithConnection(name, root string, c *swift.Connection, noCheckContaine
 	f.features = (&fs.Features{
 		ReadMimeType:  true,
 		WriteMimeType: true,
+		ReadMetadata:  true,
+		WriteMetadata: true,
 		BucketBased:   true,
+		RangeRead:     true,
 	}).Fill(f)
 	// StorageURL overloading
 	storageURL := fs.ConfigFileGet(name, "storage_url")
@@ -311,7 +333,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *swift.Object) (fs.Object, er
 
 // NewObject finds the Object at remote.  If it can't be found it
 // returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	return f.newObjectWithInfo(remote, nil)
 }
 
@@ -434,7 +456,7 @@ func (f *Fs) listContainers(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.container == "" {
 		return f.listContainers(dir)
 	}
@@ -457,7 +479,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.container == "" {
 		return errors.New("container needed for recursive list")
 	}
@@ -476,22 +498,22 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	fs := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return fs, fs.Update(in, src, options...)
+	return fs, fs.Update(ctx, in, src, options...)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	if f.containerOK {
@@ -518,7 +540,7 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir deletes the container if the fs is at the root
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	if f.root != "" || dir != "" {
@@ -539,12 +561,12 @@ func (f *Fs) Precision() time.Duration {
 // Purge deletes all the files and directories
 //
 // Implemented here so we can make sure we delete directory markers
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	// Delete all the files including the directory markers
 	toBeDeleted := make(chan fs.Object, fs.Config.Transfers)
 	delErr := make(chan error, 1)
 	go func() {
-		delErr <- fs.DeleteFiles(toBeDeleted)
+		delErr <- fs.DeleteFiles(ctx, toBeDeleted)
 	}()
 	err := f.list("", true, func(entry fs.DirEntry) error {
 		if o, ok := entry.(*Object); ok {
@@ -560,7 +582,7 @@ func (f *Fs) Purge() error {
 	if err != nil {
 		return err
 	}
-	return f.Rmdir("")
+	return f.Rmdir(ctx, "")
 }
 
 // Copy src to this remote using server side copy operations.
@@ -572,8 +594,8 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -587,7 +609,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	if err != nil {
 		return nil, err
 	}
-	return f.NewObject(remote)
+	return f.NewObject(ctx, remote)
 }
 
 // Hashes returns the supported hash sets.
@@ -705,7 +727,7 @@ func (o *Object) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	err := o.readMetaData()
 	if err != nil {
 		return err
@@ -718,7 +740,7 @@ func (o *Object) SetModTime(modTime time.Time) error {
 	}
 	// Include any other metadata from request
 	for k, v := range *o.headers {
-		if strings.HasPrefix(k, "X-Object-") {
+		if strings.HasPrefix(k, "X-Object-") || metadataHeaders[k] != "" {
 			newHeaders[k] = v
 		}
 	}
@@ -734,7 +756,7 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	headers := fs.OpenOptionHeaders(options)
 	_, isRanging := headers["Range"]
 	in, _, err = o.fs.c.ObjectOpen(o.fs.container, o.fs.root+o.remote, !isRanging, headers)
@@ -848,11 +870,11 @@ func (o *Object) updateChunks(in0 io.Reader, headers swift.Headers, size int64,
 // Update the object with the contents of the io.Reader, modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	if o.fs.container == "" {
 		return fs.FatalError(errors.New("container name needed in remote"))
 	}
-	err := o.fs.Mkdir("")
+	err := o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
@@ -869,7 +891,22 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	m := swift.Metadata{}
 	m.SetModTime(modTime)
 	contentType := fs.MimeType(src)
+
+	// Apply any metadata passed in
+	extraHeaders := swift.Headers{}
+	for k, v := range fs.GetMetadataOptions(options) {
+		if k == "content-type" {
+			contentType = v
+		} else if header, ok := metadataKeys[k]; ok {
+			extraHeaders[header] = v
+		} else if k != "mtime" {
+			m[k] = v
+		}
+	}
 	headers := m.ObjectHeaders()
+	for k, v := range extraHeaders {
+		headers[k] = v
+	}
 	uniquePrefix := ""
 	if size > int64(chunkSize) || size == -1 {
 		uniquePrefix, err = o.updateChunks(in, headers, size, contentType)
@@ -898,7 +935,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	isDynamicLargeObject, err := o.isDynamicLargeObject()
 	if err != nil {
 		return err
@@ -923,6 +960,32 @@ func (o *Object) MimeType() string {
 	return o.info.ContentType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the X-Object-Meta- metadata with the prefix removed along
+// with the standard HTTP headers.  The mtime is returned by ModTime so
+// isn't included.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData()
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range o.headers.ObjectMetadata() {
+		if k != "mtime" {
+			metadata.Set(k, v)
+		}
+	}
+	for header, k := range metadataHeaders {
+		if v := (*o.headers)[header]; v != "" {
+			metadata.Set(k, v)
+		}
+	}
+	if o.info.ContentType != "" {
+		metadata.Set("content-type", o.info.ContentType)
+	}
+	return metadata, nil
+}
+
 // Check the interfaces are satisfied
 var (
 	_ fs.Fs          = &Fs{}
@@ -932,4 +995,5 @@ var (
 	_ fs.ListRer     = &Fs{}
 	_ fs.Object      = &Object{}
 	_ fs.MimeTyper   = &Object{}
+	_ fs.Metadataer  = &Object{}
 )
@@ -14,6 +14,7 @@ import (
 
 	"github.com/djherbis/times"
 	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/metrics"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
@@ -65,19 +66,23 @@ type cache struct {
 	f      fs.Fs                 // fs for the cache directory
 	opt    *Options              // vfs Options
 	root   string                // root of the cache directory
-	itemMu sync.Mutex            // protects the next two maps
+	remote string                // name of the remote being cached for the metrics
+	itemMu sync.Mutex            // protects the next three fields
 	item   map[string]*cacheItem // files in the cache
+	files  int64                 // number of items with a known size
+	bytes  int64                 // total of the known sizes
 }
 
 // cacheItem is stored in the item map
 type cacheItem struct {
 	opens int       // number of times file is open
 	atime time.Time // last time file was accessed
+	size  int64     // size of the file in the cache or -1 if not known
 }
 
 // newCacheItem returns an item for the cache
 func newCacheItem() *cacheItem {
-	return &cacheItem{atime: time.Now()}
+	return &cacheItem{atime: time.Now(), size: -1}
 }
 
 // newCache creates a new cache heirachy for f
@@ -94,6 +99,7 @@ func newCache(ctx context.Context, f fs.Fs, opt *Options) (*cache, error) {
 	}
 	root := filepath.Join(fs.CacheDir, "vfs", f.Name(), fRoot)
 	fs.Debugf(nil, "vfs cache root is %q", root)
+	remote := fmt.Sprintf("%s:%s", f.Name(), f.Root())
 
 	f, err := fs.NewFs(root)
 	if err != nil {
@@ -101,12 +107,14 @@ func newCache(ctx context.Context, f fs.Fs, opt *Options) (*cache, error) {
 	}
 
 	c := &cache{
-		f:    f,
-		opt:  opt,
-		root: root,
-		item: make(map[string]*cacheItem),
+		f:      f,
+		opt:    opt,
+		root:   root,
+		remote: remote,
+		item:   make(map[string]*cacheItem),
 	}
 
+	addCacheMetrics(c)
 	go c.cleaner(ctx)
 
 	return c, nil
@@ -160,6 +168,29 @@ func (c *cache) updateTime(name string, when time.Time) {
 	c.itemMu.Unlock()
 }
 
+// _setSize sets the size of item keeping the totals up to date - a
+// size of -1 means it isn't known
+//
+// must be called with itemMu held
+func (c *cache) _setSize(item *cacheItem, size int64) {
+	if item.size >= 0 {
+		c.files--
+		c.bytes -= item.size
+	}
+	item.size = size
+	if item.size >= 0 {
+		c.files++
+		c.bytes += item.size
+	}
+}
+
+// updateSize sets the size of name as found in the cache
+func (c *cache) updateSize(name string, size int64) {
+	c.itemMu.Lock()
+	c._setSize(c._get(name), size)
+	c.itemMu.Unlock()
+}
+
 // open marks name as open
 func (c *cache) open(name string) {
 	c.itemMu.Lock()
@@ -183,10 +214,15 @@ func (c *cache) close(name string) {
 
 // cleanUp empties the cache of everything
 func (c *cache) cleanUp() error {
+	c.itemMu.Lock()
+	for _, item := range c.item {
+		c._setSize(item, -1)
+	}
+	c.itemMu.Unlock()
 	return os.RemoveAll(c.root)
 }
 
-// updateAtimes walks the cache updating any atimes it finds
+// updateAtimes walks the cache updating any atimes and sizes it finds
 func (c *cache) updateAtimes() error {
 	return filepath.Walk(c.root, func(osPath string, fi os.FileInfo, err error) error {
 		if err != nil {
@@ -204,6 +240,7 @@ func (c *cache) updateAtimes() error {
 			// Update the atime with that of the file
 			atime := times.Get(fi).AccessTime()
 			c.updateTime(name, atime)
+			c.updateSize(name, fi.Size())
 		}
 		return nil
 	})
@@ -227,6 +264,7 @@ func (c *cache) purgeOld(maxAge time.Duration) {
 				fs.Debugf(name, "Removed from cache")
 			}
 			// Remove the entry
+			c._setSize(item, -1)
 			delete(c.item, name)
 		}
 	}
@@ -252,7 +290,7 @@ func (c *cache) clean() {
 	c.purgeOld(c.opt.CacheMaxAge)
 
 	// Now tidy up any empty directories
-	err = fs.Rmdirs(c.f, "")
+	err = fs.Rmdirs(context.Background(), c.f, "")
 	if err != nil {
 		fs.Errorf(c.f, "Failed to remove empty directories from cache: %v", err)
 	}
@@ -264,6 +302,7 @@ func (c *cache) clean() {
 func (c *cache) cleaner(ctx context.Context) {
 	timer := time.NewTicker(c.opt.CachePollInterval)
 	defer timer.Stop()
+	defer removeCacheMetrics(c)
 	for {
 		select {
 		case <-timer.C:
@@ -274,3 +313,55 @@ func (c *cache) cleaner(ctx context.Context) {
 		}
 	}
 }
+
+// The caches in use for the metrics
+var (
+	cachesMu sync.Mutex
+	caches   = map[*cache]struct{}{}
+)
+
+func init() {
+	metrics.Register(metrics.CollectorFunc(collectCacheMetrics))
+}
+
+// addCacheMetrics adds c to the caches reported in the metrics
+func addCacheMetrics(c *cache) {
+	cachesMu.Lock()
+	caches[c] = struct{}{}
+	cachesMu.Unlock()
+}
+
+// removeCacheMetrics removes c from the caches reported in the metrics
+func removeCacheMetrics(c *cache) {
+	cachesMu.Lock()
+	delete(caches, c)
+	cachesMu.Unlock()
+}
+
+// collectCacheMetrics returns the size of the caches in use
+//
+// This uses the sizes found when the cache was last cleaned rather
+// than reading the cache directories.
+func collectCacheMetrics() []metrics.Family {
+	filesFamily := metrics.Family{
+		Name: "rclone_vfs_cache_files",
+		Help: "Number of files in the VFS cache.",
+		Type: metrics.TypeGauge,
+	}
+	bytesFamily := metrics.Family{
+		Name: "rclone_vfs_cache_bytes",
+		Help: "Total size of the files in the VFS cache.",
+		Type: metrics.TypeGauge,
+	}
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
+	for c := range caches {
+		c.itemMu.Lock()
+		files, bytes := c.files, c.bytes
+		c.itemMu.Unlock()
+		labels := []metrics.Label{{Name: "remote", Value: c.remote}}
+		filesFamily.Samples = append(filesFamily.Samples, metrics.Sample{Labels: labels, Value: float64(files)})
+		bytesFamily.Samples = append(bytesFamily.Samples, metrics.Sample{Labels: labels, Value: float64(bytes)})
+	}
+	return []metrics.Family{filesFamily, bytesFamily}
+}
@@ -109,6 +109,9 @@ func TestCacheNew(t *testing.T) {
 	require.NoError(t, err)
 	item = c.get("potato")
 	assert.Equal(t, atime, item.atime)
+	assert.Equal(t, int64(5), item.size)
+	assert.Equal(t, int64(1), c.files)
+	assert.Equal(t, int64(5), c.bytes)
 
 	// try purging with file open
 	c.purgeOld(10 * time.Second)
@@ -132,6 +135,8 @@ func TestCacheNew(t *testing.T) {
 	c.purgeOld(-10 * time.Second)
 	_, err = os.Stat(p)
 	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, int64(0), c.files)
+	assert.Equal(t, int64(0), c.bytes)
 
 	// clean - have tested the internals already
 	c.clean()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // File represents a file
@@ -185,7 +186,7 @@ func (f *File) applyPendingModTime() error {
 		return errors.New("Cannot apply ModTime, file object is not available")
 	}
 
-	err := f.o.SetModTime(f.pendingModTime)
+	err := f.o.SetModTime(context.Background(), f.pendingModTime)
 	switch err {
 	case nil:
 		fs.Debugf(f.o, "File.applyPendingModTime OK")
@@ -306,7 +307,7 @@ func (f *File) Remove() error {
 		return EROFS
 	}
 	if f.o != nil {
-		err := f.o.Remove()
+		err := f.o.Remove(context.Background())
 		if err != nil {
 			fs.Errorf(f, "File.Remove file error: %v", err)
 			return err
@@ -0,0 +1,102 @@
+// Remote control for the VFS
+
+package vfs
+
+import (
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/rc"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context" // switch to "context" when we stop supporting go1.6
+)
+
+// addRC adds the remote control calls for this VFS
+//
+// Note that these overwrite the calls from any previous VFS so the
+// remote control works on the most recently created one.
+func (vfs *VFS) addRC() {
+	rc.Add(rc.Call{
+		Path: "vfs/refresh",
+		Fn: func(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+			dir, err := in.GetString("dir")
+			if rc.IsErrParamNotFound(err) {
+				dir = ""
+			} else if err != nil {
+				return nil, err
+			}
+			recursive, err := in.GetBool("recursive")
+			if rc.IsErrParamNotFound(err) {
+				recursive = false
+			} else if err != nil {
+				return nil, err
+			}
+			err = vfs.Refresh(dir, recursive)
+			if err != nil {
+				return nil, err
+			}
+			out = make(rc.Params)
+			out["result"] = "OK"
+			return out, nil
+		},
+		Title: "Refresh the directory cache.",
+		Help: `
+This reads the directories for the specified path and freshens the
+directory cache.
+
+If no path is passed in then it will refresh the root directory.
+
+    rclone rc vfs/refresh
+
+Otherwise pass the directory in as dir=path
+
+    rclone rc vfs/refresh dir=home/junk
+
+If the parameter recursive=true is given the whole directory tree
+will get refreshed.
+`,
+	})
+}
+
+// Refresh re-reads the directory at path from the remote, replacing
+// the cached entries.  If recursive is set then all the cached
+// subdirectories are refreshed too.
+func (vfs *VFS) Refresh(path string, recursive bool) error {
+	node, err := vfs.Stat(path)
+	if err != nil {
+		return err
+	}
+	dir, ok := node.(*Dir)
+	if !ok {
+		return errors.Errorf("%q is not a directory", path)
+	}
+	return dir.refresh(recursive)
+}
+
+// refresh forces a re-read of the directory and optionally of all
+// its subdirectories
+func (d *Dir) refresh(recursive bool) error {
+	fs.Debugf(d.path, "refreshing directory cache")
+	d.mu.Lock()
+	d.read = time.Time{}
+	err := d._readDir()
+	var dirs []*Dir
+	if err == nil && recursive {
+		for _, node := range d.items {
+			if dir, ok := node.(*Dir); ok {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		err = dir.refresh(recursive)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package vfs
+
+import (
+	"testing"
+
+	"github.com/ncw/rclone/fstest"
+	"github.com/ncw/rclone/rc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestVFSRefresh(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	vfs, dir, file1 := dirCreate(t, r)
+
+	// Make sure / and dir are in cache
+	_, err := vfs.Stat(file1.Path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(dir.items))
+
+	// Add a file behind the back of the VFS
+	file2 := r.WriteObject("dir/file2", "file2 contents", t2)
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+	assert.Equal(t, 1, len(dir.items))
+
+	err = vfs.Refresh("dir", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(dir.items))
+
+	err = vfs.Refresh("dir/file1", false)
+	assert.Error(t, err)
+
+	err = vfs.Refresh("notfound", false)
+	assert.Equal(t, ENOENT, err)
+}
+
+func TestVFSRefreshRC(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	vfs, dir, file1 := dirCreate(t, r)
+
+	// Make sure / and dir are in cache
+	_, err := vfs.Stat(file1.Path)
+	require.NoError(t, err)
+
+	file2 := r.WriteObject("dir/file2", "file2 contents", t2)
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+	assert.Equal(t, 1, len(dir.items))
+
+	call := rc.Calls.Get("vfs/refresh")
+	require.NotNil(t, call)
+
+	out, err := call.Fn(context.Background(), rc.Params{"recursive": "true"})
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params{"result": "OK"}, out)
+	assert.Equal(t, 2, len(dir.items))
+
+	_, err = call.Fn(context.Background(), rc.Params{"recursive": "potato"})
+	assert.True(t, rc.IsErrParamInvalid(err))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // ReadFileHandle is an open for read file handle on a File
@@ -60,11 +61,11 @@ func (fh *ReadFileHandle) openPending() (err error) {
 	if fh.opened {
 		return nil
 	}
-	r, err := fh.o.Open()
+	r, err := fh.o.Open(context.Background())
 	if err != nil {
 		return err
 	}
-	fh.r = fs.NewAccount(r, fh.o).WithBuffer() // account the transfer
+	fh.r = fs.NewAccount(context.Background(), r, fh.o).WithBuffer() // account the transfer
 	fh.opened = true
 	fs.Stats.Transferring(fh.o.Remote())
 	return nil
@@ -119,7 +120,7 @@ func (fh *ReadFileHandle) seek(offset int64, reopen bool) (err error) {
 			fs.Debugf(fh.o, "ReadFileHandle.Read seek close old failed: %v", err)
 		}
 		// re-open with a seek
-		r, err = fh.o.Open(&fs.SeekOption{Offset: offset})
+		r, err = fh.o.Open(context.Background(), &fs.SeekOption{Offset: offset})
 		if err != nil {
 			fs.Debugf(fh.o, "ReadFileHandle.Read seek failed: %v", err)
 			return err
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ncw/rclone/fs"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 )
 
 // RWFileHandle is a handle that can be open for read and write.
@@ -73,7 +74,7 @@ func (fh *RWFileHandle) openPending(truncate bool) (err error) {
 	if fh.flags&os.O_TRUNC == 0 && !truncate {
 		// Fetch the file if it hasn't changed
 		// FIXME retries
-		err = fs.CopyFile(fh.d.vfs.cache.f, fh.d.vfs.f, fh.remote, fh.remote)
+		err = fs.CopyFile(context.Background(), fh.d.vfs.cache.f, fh.d.vfs.f, fh.remote, fh.remote)
 		if err != nil {
 			// if the object wasn't found AND O_CREATE is set then...
 			cause := errors.Cause(err)
@@ -196,9 +197,9 @@ func (fh *RWFileHandle) close() (err error) {
 	// Transfer the temp file to the remote
 	// FIXME retries
 	if fh.d.vfs.Opt.CacheMode < CacheModeFull {
-		err = fs.MoveFile(fh.d.vfs.f, fh.d.vfs.cache.f, fh.remote, fh.remote)
+		err = fs.MoveFile(context.Background(), fh.d.vfs.f, fh.d.vfs.cache.f, fh.remote, fh.remote)
 	} else {
-		err = fs.CopyFile(fh.d.vfs.f, fh.d.vfs.cache.f, fh.remote, fh.remote)
+		err = fs.CopyFile(context.Background(), fh.d.vfs.f, fh.d.vfs.cache.f, fh.remote, fh.remote)
 	}
 	if err != nil {
 		err = errors.Wrap(err, "failed to transfer file from cache to remote")
@@ -207,7 +208,7 @@ func (fh *RWFileHandle) close() (err error) {
 	}
 
 	// FIXME get MoveFile to return this object
-	o, err := fh.d.vfs.f.NewObject(fh.remote)
+	o, err := fh.d.vfs.f.NewObject(context.Background(), fh.remote)
 	if err != nil {
 		err = errors.Wrap(err, "failed to find object after transfer to remote")
 		fs.Errorf(fh.remote, "%v", err)
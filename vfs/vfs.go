@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -169,6 +170,10 @@ type VFS struct {
 	Opt    Options
 	cache  *cache
 	cancel context.CancelFunc
+
+	usageMu   sync.Mutex
+	usageTime time.Time
+	usage     *fs.Usage
 }
 
 // Options is options for creating the vfs
@@ -232,6 +237,9 @@ func New(f fs.Fs, opt *Options) *VFS {
 		panic(fmt.Sprintf("failed to create local cache: %v", err))
 	}
 	vfs.cache = cache
+
+	// Add the remote control
+	vfs.addRC()
 	return vfs
 }
 
@@ -427,3 +435,36 @@ func (vfs *VFS) Rename(oldName, newName string) error {
 	}
 	return nil
 }
+
+// Statfs returns info about the filing system if known
+//
+// The values will be -1 if they aren't known
+//
+// This information is cached for the DirCacheTime interval
+func (vfs *VFS) Statfs() (total, used, free int64) {
+	vfs.usageMu.Lock()
+	defer vfs.usageMu.Unlock()
+	total, used, free = -1, -1, -1
+	doAbout := vfs.f.Features().About
+	if doAbout != nil && (vfs.usageTime.IsZero() || time.Since(vfs.usageTime) >= vfs.Opt.DirCacheTime) {
+		var err error
+		vfs.usage, err = doAbout(context.TODO())
+		vfs.usageTime = time.Now()
+		if err != nil {
+			fs.Errorf(vfs.f, "Statfs failed: %v", err)
+			return
+		}
+	}
+	if u := vfs.usage; u != nil {
+		if u.Total != nil {
+			total = *u.Total
+		}
+		if u.Free != nil {
+			free = *u.Free
+		}
+		if u.Used != nil {
+			used = *u.Used
+		}
+	}
+	return
+}
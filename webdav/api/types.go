@@ -58,6 +58,24 @@ type PropValue struct {
 	Value   string   `xml:",chardata"`
 }
 
+// Quota is used to read the bytes used and available as described
+// in RFC 4331
+//
+// <d:response>
+//  <d:href>/remote.php/webdav/</d:href>
+//  <d:propstat>
+//   <d:prop>
+//    <d:quota-available-bytes>-3</d:quota-available-bytes>
+//    <d:quota-used-bytes>376461895</d:quota-used-bytes>
+//   </d:prop>
+//   <d:status>HTTP/1.1 200 OK</d:status>
+//  </d:propstat>
+// </d:response>
+type Quota struct {
+	Available string `xml:"DAV: response>propstat>prop>quota-available-bytes"`
+	Used      string `xml:"DAV: response>propstat>prop>quota-used-bytes"`
+}
+
 // Error is used to desribe webdav errors
 //
 // <d:error xmlns:d="DAV:" xmlns:s="http://sabredav.org/ns">
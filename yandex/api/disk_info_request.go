@@ -14,10 +14,10 @@ func (req *DiskInfoRequest) request() *HTTPRequest {
 
 //DiskInfoResponse struct is returned by the API for DiskInfo request.
 type DiskInfoResponse struct {
-	TrashSize     uint64            `json:"TrashSize"`
-	TotalSpace    uint64            `json:"TotalSpace"`
-	UsedSpace     uint64            `json:"UsedSpace"`
-	SystemFolders map[string]string `json:"SystemFolders"`
+	TrashSize     int64             `json:"trash_size"`
+	TotalSpace    int64             `json:"total_space"`
+	UsedSpace     int64             `json:"used_space"`
+	SystemFolders map[string]string `json:"system_folders"`
 }
 
 //NewDiskInfoRequest create new DiskInfo Request
@@ -0,0 +1,46 @@
+package src
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Publish will make the specified file or folder on Yandex Disk
+// readable by anyone with the public URL
+func (c *Client) Publish(remotePath string) error {
+	values := url.Values{}
+	values.Add("path", remotePath)
+	fullURL := RootAddr
+	fullURL += "/v1/disk/resources/publish?" + values.Encode()
+
+	return c.PerformPublish(fullURL)
+}
+
+// PerformPublish does the actual publish via PUT request.
+func (c *Client) PerformPublish(url string) (err error) {
+	req, err := http.NewRequest("PUT", url, nil)
+	if err != nil {
+		return err
+	}
+
+	//set access token and headers
+	c.setRequestScope(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer CheckClose(resp.Body, &err)
+
+	if resp.StatusCode != 200 {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("publish error [%d]: %s", resp.StatusCode, string(body[:]))
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/ncw/rclone/oauthutil"
 	yandex "github.com/ncw/rclone/yandex/api"
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 )
 
@@ -127,7 +128,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 	}
 
 	//create new client
-	yandexDisk := yandex.NewClient(token.AccessToken, fs.Config.Client())
+	yandexDisk := yandex.NewClient(token.AccessToken, fs.Config.ClientRemote(name))
 
 	f := &Fs{
 		name: name,
@@ -137,6 +138,7 @@ func NewFs(name, root string) (fs.Fs, error) {
 		ReadMimeType:            true,
 		WriteMimeType:           true,
 		CanHaveEmptyDirectories: true,
+		RangeRead:               true,
 	}).Fill(f)
 	f.setRoot(root)
 
@@ -202,7 +204,7 @@ func (f *Fs) itemToDirEntry(remote string, object *yandex.ResourceInfoResponse)
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	//request object meta info
 	var opt yandex.ResourceInfoRequestOptions
 	root := f.diskRoot
@@ -267,7 +269,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	//request files list. list is divided into pages. We send request for each page
 	//items per page is limited by limit
 	//TODO may be add config parameter for the items per page limit
@@ -329,7 +331,7 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 
 // NewObject finds the Object at remote.  If it can't be found it
 // returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	return f.newObjectWithInfo(remote, nil)
 }
 
@@ -406,7 +408,7 @@ func (o *Object) readMetaData() (err error) {
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	remote := src.Remote()
 	size := src.Size()
 	modTime := src.ModTime()
@@ -418,16 +420,16 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 		modTime: modTime,
 	}
 	//TODO maybe read metadata after upload to check if file uploaded successfully
-	return o, o.Update(in, src, options...)
+	return o, o.Update(ctx, in, src, options...)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	root := f.diskRoot
 	if dir != "" {
 		root += dir + "/"
@@ -438,7 +440,7 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir deletes the container
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	return f.purgeCheck(dir, true)
 }
 
@@ -475,15 +477,53 @@ func (f *Fs) Precision() time.Duration {
 // Optional interface: Only implement this if you have a way of
 // deleting all the files quicker than just running Remove() on the
 // result of List()
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	return f.purgeCheck("", false)
 }
 
 // CleanUp permanently deletes all trashed files/folders
-func (f *Fs) CleanUp() error {
+func (f *Fs) CleanUp(ctx context.Context) error {
 	return f.yd.EmptyTrash()
 }
 
+// About gets quota information
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	info, err := f.yd.NewDiskInfoRequest().Exec()
+	if err != nil {
+		return nil, errors.Wrap(err, "about failed")
+	}
+	usage := &fs.Usage{
+		Total:   fs.NewUsageValue(info.TotalSpace),                  // quota of bytes that can be used
+		Used:    fs.NewUsageValue(info.UsedSpace),                   // bytes in use
+		Trashed: fs.NewUsageValue(info.TrashSize),                   // bytes in trash
+		Free:    fs.NewUsageValue(info.TotalSpace - info.UsedSpace), // bytes which can be uploaded before reaching the quota
+	}
+	return usage, nil
+}
+
+// PublicLink publishes the file or folder at remote and returns its
+// public URL
+//
+// Yandex doesn't support expiring public links so expire is ignored.
+func (f *Fs) PublicLink(ctx context.Context, remote string, expire time.Duration) (string, error) {
+	if expire > 0 {
+		fs.Debugf(f, "Ignoring expiry as yandex doesn't support it for public links")
+	}
+	remotePath := f.diskRoot + remote
+	err := f.yd.Publish(remotePath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to publish")
+	}
+	info, err := f.yd.NewResourceInfoRequest(remotePath).Exec()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read public URL")
+	}
+	if info.PublicURL == "" {
+		return "", errors.New("no public URL returned")
+	}
+	return info.PublicURL, nil
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() fs.HashSet {
 	return fs.HashSet(fs.HashMD5)
@@ -537,19 +577,19 @@ func (o *Object) ModTime() time.Time {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	return o.fs.yd.Download(o.remotePath(), fs.OpenOptionHeaders(options))
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	return o.fs.yd.Delete(o.remotePath(), true)
 }
 
 // SetModTime sets the modification time of the local fs object
 //
 // Commits the datastore
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	remote := o.remotePath()
 	// set custom_property 'rclone_modified' of object to modTime
 	err := o.fs.yd.SetCustomProperty(remote, "rclone_modified", modTime.Format(time.RFC3339Nano))
@@ -575,7 +615,7 @@ func (o *Object) remotePath() string {
 // Copy the reader into the object updating modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in0 io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *Object) Update(ctx context.Context, in0 io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	in := fs.NewCountingReader(in0)
 	modTime := src.ModTime()
 
@@ -595,7 +635,7 @@ func (o *Object) Update(in0 io.Reader, src fs.ObjectInfo, options ...fs.OpenOpti
 		o.modTime = modTime
 		o.md5sum = "" // according to unit tests after put the md5 is empty.
 		//and set modTime of uploaded file
-		err = o.SetModTime(modTime)
+		err = o.SetModTime(ctx, modTime)
 	}
 	return err
 }
@@ -658,11 +698,13 @@ func (o *Object) MimeType() string {
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = (*Fs)(nil)
-	_ fs.Purger      = (*Fs)(nil)
-	_ fs.CleanUpper  = (*Fs)(nil)
-	_ fs.PutStreamer = (*Fs)(nil)
-	_ fs.ListRer     = (*Fs)(nil)
+	_ fs.Fs           = (*Fs)(nil)
+	_ fs.Purger       = (*Fs)(nil)
+	_ fs.CleanUpper   = (*Fs)(nil)
+	_ fs.PutStreamer  = (*Fs)(nil)
+	_ fs.ListRer      = (*Fs)(nil)
+	_ fs.Abouter      = (*Fs)(nil)
+	_ fs.PublicLinker = (*Fs)(nil)
 	//_ fs.Copier = (*Fs)(nil)
 	_ fs.ListRer   = (*Fs)(nil)
 	_ fs.Object    = (*Object)(nil)